@@ -0,0 +1,100 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+)
+
+// ErrUnmappedAsset is returned when an L1 asset has no counterpart on L2 and therefore cannot be bridged
+var ErrUnmappedAsset = errors.New("asset is not mapped to an L2 asset")
+
+// AssetMapEntry pairs an asset on L1 with the asset representing it on L2
+type AssetMapEntry struct {
+	L1AssetAddress common.Address `toml:"l1AssetAddress" json:"l1AssetAddress"`
+	L2AssetAddress common.Address `toml:"l2AssetAddress" json:"l2AssetAddress"`
+}
+
+type assetMapFile struct {
+	Assets []AssetMapEntry `toml:"assets"`
+}
+
+// LoadAssetMap reads the L1 to L2 asset pairs from the [[assets]] tables of the given TOML file
+func LoadAssetMap(filePath string) ([]AssetMapEntry, error) {
+	var amf assetMapFile
+	if _, err := toml.DecodeFile(filePath, &amf); err != nil {
+		return nil, fmt.Errorf("could not load asset map from %s: %w", filePath, err)
+	}
+
+	return amf.Assets, nil
+}
+
+// getL2Asset returns the L2 asset which the given L1 asset is bridged to.
+// The native asset is mapped to the native asset of L2 unless explicitly mapped otherwise.
+func (b *Bridge) getL2Asset(l1Asset common.Address) (common.Address, error) {
+	l2Asset, err := b.bridgeStore.GetL2AssetAddress(l1Asset)
+	if err == nil {
+		return l2Asset, nil
+	}
+
+	if errors.Is(err, ErrUnmappedAsset) && l1Asset == (common.Address{}) {
+		return common.Address{}, nil
+	}
+
+	return common.Address{}, err
+}
+
+// checkAssetsMapped returns an error wrapping ErrUnmappedAsset if any asset in the outcome cannot be bridged to L2
+func (b *Bridge) checkAssetsMapped(o outcome.Exit) error {
+	for _, sae := range o {
+		if _, err := b.getL2Asset(sae.Asset); err != nil {
+			return fmt.Errorf("cannot bridge asset %s: %w", sae.Asset, err)
+		}
+	}
+
+	return nil
+}
+
+// mirrorOutcome creates the outcome of a mirrored L2 ledger channel from the outcome of an L1 ledger channel.
+// Every L1 asset is replaced with the L2 asset mapped to it, and the first two allocations are swapped
+// since the bridge is the first participant of the mirrored ledger channel.
+func (b *Bridge) mirrorOutcome(l1Outcome outcome.Exit) (outcome.Exit, error) {
+	mirroredOutcome := l1Outcome.Clone()
+
+	for i, sae := range mirroredOutcome {
+		l2Asset, err := b.getL2Asset(sae.Asset)
+		if err != nil {
+			return nil, fmt.Errorf("cannot bridge asset %s: %w", sae.Asset, err)
+		}
+		mirroredOutcome[i].Asset = l2Asset
+
+		// Swap the allocations in place
+		// Allocations have at least two elements before performing the swap
+		allocations := mirroredOutcome[i].Allocations
+		if len(allocations) >= 2 {
+			allocations[0], allocations[1] = allocations[1], allocations[0]
+		}
+	}
+
+	if err := checkDistinctAssets(mirroredOutcome); err != nil {
+		return nil, err
+	}
+
+	return mirroredOutcome, nil
+}
+
+// checkDistinctAssets returns an error if several L1 assets in the outcome are mapped to the same L2 asset
+func checkDistinctAssets(o outcome.Exit) error {
+	seen := make(map[common.Address]bool)
+	for _, sae := range o {
+		if seen[sae.Asset] {
+			return fmt.Errorf("multiple L1 assets are mapped to L2 asset %s", sae.Asset)
+		}
+		seen[sae.Asset] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package bridge
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/go-cmp/cmp"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/statechannels/go-nitro/internal/testhelpers"
+	"github.com/statechannels/go-nitro/types"
+	"github.com/tidwall/buntdb"
+)
+
+func TestLoadAssetMap(t *testing.T) {
+	got, err := LoadAssetMap("../cmd/test-configs/bridge-assets-map.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []AssetMapEntry{{
+		L1AssetAddress: common.HexToAddress("0x71d73E2F0908c003070b5d60F66Be8Bf4Ff0A54e"),
+		L2AssetAddress: common.HexToAddress("0xBca48057Da826cB2eb1258E2C679678b269dC262"),
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("loaded asset map different than expected %s", diff)
+	}
+}
+
+func TestMirrorOutcome(t *testing.T) {
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	ds, err := NewDurableStore(dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	b := &Bridge{bridgeStore: ds}
+
+	l1TokenA := common.HexToAddress("0x01")
+	l1TokenB := common.HexToAddress("0x02")
+	l2TokenA := common.HexToAddress("0x11")
+	l2TokenB := common.HexToAddress("0x12")
+	for l1, l2 := range map[common.Address]common.Address{l1TokenA: l2TokenA, l1TokenB: l2TokenB} {
+		if err := ds.SetL2AssetAddress(l1, l2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alice := types.AddressToDestination(common.HexToAddress("0xaa"))
+	bridge := types.AddressToDestination(common.HexToAddress("0xbb"))
+	ledgerExit := func(asset common.Address, first, second types.Destination, firstAmount, secondAmount int64) outcome.SingleAssetExit {
+		return outcome.SingleAssetExit{
+			Asset: asset,
+			Allocations: outcome.Allocations{
+				{Destination: first, Amount: big.NewInt(firstAmount)},
+				{Destination: second, Amount: big.NewInt(secondAmount)},
+			},
+		}
+	}
+
+	t.Run("maps every asset", func(t *testing.T) {
+		l1Outcome := outcome.Exit{
+			ledgerExit(common.Address{}, alice, bridge, 1, 2),
+			ledgerExit(l1TokenA, alice, bridge, 3, 4),
+			ledgerExit(l1TokenB, alice, bridge, 5, 6),
+		}
+		original := l1Outcome.Clone()
+
+		got, err := b.mirrorOutcome(l1Outcome)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := outcome.Exit{
+			ledgerExit(common.Address{}, bridge, alice, 2, 1),
+			ledgerExit(l2TokenA, bridge, alice, 4, 3),
+			ledgerExit(l2TokenB, bridge, alice, 6, 5),
+		}
+		if !got.Equal(want) {
+			t.Fatalf("mirrored outcome different than expected: got %+v, wanted %+v", got, want)
+		}
+		if !l1Outcome.Equal(original) {
+			t.Fatal("expected the L1 outcome to be left unchanged")
+		}
+	})
+
+	t.Run("rejects unmapped assets", func(t *testing.T) {
+		l1Outcome := outcome.Exit{
+			ledgerExit(l1TokenA, alice, bridge, 3, 4),
+			ledgerExit(common.HexToAddress("0x03"), alice, bridge, 5, 6),
+		}
+
+		if _, err := b.mirrorOutcome(l1Outcome); !errors.Is(err, ErrUnmappedAsset) {
+			t.Fatalf("expected ErrUnmappedAsset, got %v", err)
+		}
+		if err := b.checkAssetsMapped(l1Outcome); !errors.Is(err, ErrUnmappedAsset) {
+			t.Fatalf("expected ErrUnmappedAsset, got %v", err)
+		}
+	})
+}
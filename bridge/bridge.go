@@ -2,16 +2,22 @@ package bridge
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"path/filepath"
+	"sort"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/statechannels/go-nitro/channel/state"
 	nodeutils "github.com/statechannels/go-nitro/internal/node"
-	"github.com/statechannels/go-nitro/internal/safesync"
 	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
 	p2pms "github.com/statechannels/go-nitro/node/engine/messageservice/p2p-message-service"
+	"github.com/statechannels/go-nitro/node/notifier"
 	"github.com/statechannels/go-nitro/node/query"
 	"github.com/statechannels/go-nitro/protocols/bridgeddefund"
 	"github.com/statechannels/go-nitro/protocols/bridgedfund"
@@ -29,20 +35,6 @@ const (
 	L2_DURABLE_STORE_SUB_DIR = "l2-node"
 )
 
-const RETRY_TX_LIMIT = 1
-
-type SentTx struct {
-	Tx                  protocols.ChainTransaction `json:"tx"`
-	NumOfRetries        uint                       `json:"num_of_retries"`
-	IsRetryLimitReached bool                       `json:"is_retry_limit_reached"`
-	IsL2                bool                       `json:"is_l2"`
-}
-
-type PendingTx struct {
-	SentTx
-	TxHash string `json:"tx_hash"`
-}
-
 type Bridge struct {
 	bridgeStore *DurableStore
 
@@ -57,17 +49,23 @@ type Bridge struct {
 	cancel                context.CancelFunc
 	mirrorChannelMap      map[types.Destination]MirrorChannelDetails
 	createdMirrorChannels chan types.Destination
-	sentTxs               safesync.Map[SentTx]
 }
 
 type BridgeConfig struct {
-	L1ChainUrl         string
-	L1ChainStartBlock  uint64
-	ChainPK            string
-	StateChannelPK     string
-	NaAddress          string
-	VpaAddress         string
-	CaAddress          string
+	L1ChainUrl        string
+	L1ChainStartBlock uint64
+	L2ChainUrl        string
+	L2ChainStartBlock uint64
+	ChainPK           string
+	StateChannelPK    string
+	NaAddress         string
+	VpaAddress        string
+	CaAddress         string
+	// L2ChainPK, L2NaAddress, L2VpaAddress and L2CaAddress are the chain key and contract addresses used by the L2 node
+	L2ChainPK          string
+	L2NaAddress        string
+	L2VpaAddress       string
+	L2CaAddress        string
 	BridgeAddress      string
 	DurableStoreDir    string
 	BridgePublicIp     string
@@ -75,6 +73,17 @@ type BridgeConfig struct {
 	NodeL2ExtMultiAddr string
 	NodeL1MsgPort      int
 	NodeL2MsgPort      int
+	// L1ConfirmationPolicy and L2ConfirmationPolicy determine when the chain events of each chain are processed.
+	// The fields which are not set are taken from the preset for the chain ID
+	L1ConfirmationPolicy chainservice.ConfirmationPolicyConfig
+	L2ConfirmationPolicy chainservice.ConfirmationPolicyConfig
+	// RetryPolicy is used to resubmit dropped transactions on both chains, chainservice.DefaultRetryPolicy is used if it is not set
+	RetryPolicy chainservice.RetryPolicy
+	// Assets maps the L1 assets which can be bridged to their L2 counterparts, it is persisted in the bridge store.
+	// The bridge contract does not emit asset map updates, so the mapping is only changed through this configuration
+	Assets []AssetMapEntry
+	// EngineOpts configures the engines of both nodes
+	EngineOpts engine.EngineOpts
 }
 
 func New() *Bridge {
@@ -88,8 +97,14 @@ func New() *Bridge {
 
 func (b *Bridge) Start(configOpts BridgeConfig) (nodeL1 *node.Node, nodeL2 *node.Node, nodeL1MultiAddress string, nodeL2MultiAddress string, err error) {
 	chainOptsL2 := chainservice.LaconicdChainOpts{
-		VpaAddress: common.HexToAddress(configOpts.VpaAddress),
-		CaAddress:  common.HexToAddress(configOpts.CaAddress),
+		ChainUrl:           configOpts.L2ChainUrl,
+		ChainStartBlockNum: configOpts.L2ChainStartBlock,
+		ChainPk:            configOpts.L2ChainPK,
+		NaAddress:          common.HexToAddress(configOpts.L2NaAddress),
+		VpaAddress:         common.HexToAddress(configOpts.L2VpaAddress),
+		CaAddress:          common.HexToAddress(configOpts.L2CaAddress),
+		ConfirmationPolicy: configOpts.L2ConfirmationPolicy,
+		RetryPolicy:        configOpts.RetryPolicy,
 	}
 
 	chainOptsL1 := chainservice.ChainOpts{
@@ -99,6 +114,8 @@ func (b *Bridge) Start(configOpts BridgeConfig) (nodeL1 *node.Node, nodeL2 *node
 		NaAddress:          common.HexToAddress(configOpts.NaAddress),
 		VpaAddress:         common.HexToAddress(configOpts.VpaAddress),
 		CaAddress:          common.HexToAddress(configOpts.CaAddress),
+		ConfirmationPolicy: configOpts.L1ConfirmationPolicy,
+		RetryPolicy:        configOpts.RetryPolicy,
 	}
 
 	storeOptsL1 := store.StoreOpts{
@@ -129,13 +146,27 @@ func (b *Bridge) Start(configOpts BridgeConfig) (nodeL1 *node.Node, nodeL2 *node
 		ExtMultiAddr: configOpts.NodeL2ExtMultiAddr,
 	}
 
+	ds, err := NewDurableStore(configOpts.DurableStoreDir, buntdb.Config{})
+	if err != nil {
+		return nil, nil, nodeL1MultiAddress, nodeL2MultiAddress, err
+	}
+
+	b.bridgeStore = ds
+
+	for _, asset := range configOpts.Assets {
+		err = b.bridgeStore.SetL2AssetAddress(asset.L1AssetAddress, asset.L2AssetAddress)
+		if err != nil {
+			return nil, nil, nodeL1MultiAddress, nodeL2MultiAddress, err
+		}
+	}
+
 	// Initialize nodes
-	nodeL1, storeL1, msgServiceL1, chainServiceL1, err := nodeutils.InitializeNode(chainOptsL1, storeOptsL1, messageOptsL1, &NodeL1PermissivePolicy{})
+	nodeL1, storeL1, msgServiceL1, chainServiceL1, err := nodeutils.InitializeNode(chainOptsL1, storeOptsL1, messageOptsL1, &NodeL1PermissivePolicy{bridge: b}, configOpts.EngineOpts)
 	if err != nil {
 		return nil, nil, nodeL1MultiAddress, nodeL2MultiAddress, err
 	}
 
-	nodeL2, storeL2, msgServiceL2, chainServiceL2, err := nodeutils.InitializeL2Node(chainOptsL2, storeOptsL2, messageOptsL2)
+	nodeL2, storeL2, msgServiceL2, chainServiceL2, err := nodeutils.InitializeL2Node(chainOptsL2, storeOptsL2, messageOptsL2, &engine.PermissivePolicy{}, configOpts.EngineOpts)
 	if err != nil {
 		return nil, nil, nodeL1MultiAddress, nodeL2MultiAddress, err
 	}
@@ -150,13 +181,11 @@ func (b *Bridge) Start(configOpts BridgeConfig) (nodeL1 *node.Node, nodeL2 *node
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	b.cancel = cancelFunc
 
-	ds, err := NewDurableStore(configOpts.DurableStoreDir, buntdb.Config{})
+	err = b.reconcileSentTxs()
 	if err != nil {
 		return nil, nil, nodeL1MultiAddress, nodeL2MultiAddress, err
 	}
 
-	b.bridgeStore = ds
-
 	go b.listenForDroppedEvents(ctx)
 	go b.run(ctx)
 
@@ -166,6 +195,8 @@ func (b *Bridge) Start(configOpts BridgeConfig) (nodeL1 *node.Node, nodeL2 *node
 func (b *Bridge) run(ctx context.Context) {
 	completedObjectivesInNodeL1 := b.nodeL1.CompletedObjectives()
 	completedObjectivesInNodeL2 := b.nodeL2.CompletedObjectives()
+	failedObjectivesInNodeL1 := b.nodeL1.SubscribeFailedObjectives(ctx, notifier.SubscriptionOpts{})
+	failedObjectivesInNodeL2 := b.nodeL2.SubscribeFailedObjectives(ctx, notifier.SubscriptionOpts{})
 
 	for {
 		var err error
@@ -182,6 +213,16 @@ func (b *Bridge) run(ctx context.Context) {
 				b.checkError(err)
 			}
 
+		case failed, ok := <-failedObjectivesInNodeL1:
+			if ok {
+				slog.Error("Objective failed in L1 node", "objectiveId", failed.Id, "reason", failed.Reason)
+			}
+
+		case failed, ok := <-failedObjectivesInNodeL2:
+			if ok {
+				slog.Error("Objective failed in L2 node", "objectiveId", failed.Id, "reason", failed.Reason)
+			}
+
 		case <-ctx.Done():
 			return
 		}
@@ -209,24 +250,16 @@ func (b *Bridge) processCompletedObjectivesFromL1(objId protocols.ObjectiveId) e
 		return err
 	}
 
-	l1ledgerChannelState := l1LedgerChannel.SupportedSignedState()
-	l1ledgerChannelStateClone := l1ledgerChannelState.Clone()
-	// Put NodeBPrime's allocation at index 0 as it creates mirrored ledger channel
-	// Swap the allocations to be set in mirrored ledger channel
-	for _, outcome := range l1ledgerChannelStateClone.State().Outcome {
-		// Swap the allocations in place
-		// Allocations have at least two elements before performing the swap
-		if len(outcome.Allocations) >= 2 {
-			outcome.Allocations[0], outcome.Allocations[1] = outcome.Allocations[1], outcome.Allocations[0]
-		}
-	}
+	l1ledgerChannelState := l1LedgerChannel.SupportedSignedState().State()
 
-	// Create extended state outcome based on l1ChannelState
-	l1ChannelCloneOutcome := l1ledgerChannelStateClone.State().Outcome
+	// Create outcome for mirrored ledger channel with the L2 counterparts of every L1 asset
+	l2Outcome, err := b.mirrorOutcome(l1ledgerChannelState.Outcome)
+	if err != nil {
+		return fmt.Errorf("could not mirror ledger channel %s: %w", channelId, err)
+	}
 
 	// Create mirrored ledger channel between node BPrime and APrime
-	// TODO: Support mirrored ledger channel creation with multiple assets
-	l2LedgerChannelResponse, err := b.nodeL2.CreateBridgeChannel(l1ledgerChannelStateClone.State().Participants[0], l1ledgerChannelStateClone.State().ChallengeDuration, l1ChannelCloneOutcome)
+	l2LedgerChannelResponse, err := b.nodeL2.CreateBridgeChannel(l1ledgerChannelState.Participants[0], l1ledgerChannelState.ChallengeDuration, l2Outcome)
 	if err != nil {
 		return err
 	}
@@ -268,7 +301,10 @@ func (b *Bridge) processCompletedObjectivesFromL2(objId protocols.ObjectiveId) e
 			return fmt.Errorf("error in send transaction %w", err)
 		}
 
-		b.sentTxs.Store(setL2ToL1Tx.Hash().String(), SentTx{setL2ToL1TxToSubmit, 0, false, false})
+		err = b.bridgeStore.SetSentTx(setL2ToL1Tx.Hash(), NewSentTx(setL2ToL1TxToSubmit, false))
+		if err != nil {
+			return err
+		}
 
 		// use a nonblocking send in case no one is listening
 		select {
@@ -312,8 +348,8 @@ func (b *Bridge) MirrorBridgedDefund(l1ChannelId types.Destination, l2SignedStat
 	return b.nodeL1.MirrorBridgedDefund(l1ChannelId, l2SignedState, isChallenge)
 }
 
-func (b *Bridge) CounterChallenge(id types.Destination, action types.CounterChallengeAction, payload state.SignedState) {
-	b.nodeL1.CounterChallenge(id, action, payload)
+func (b *Bridge) CounterChallenge(id types.Destination, action types.CounterChallengeAction, payload state.SignedState) error {
+	return b.nodeL1.CounterChallenge(id, action, payload)
 }
 
 func (b *Bridge) GetL2ChannelIdByL1ChannelId(l1ChannelId types.Destination) (l2ChannelId types.Destination, isCreated bool) {
@@ -364,40 +400,36 @@ func (b *Bridge) CreatedMirrorChannels() <-chan types.Destination {
 
 func (b *Bridge) RetryObjectiveTx(objectiveId protocols.ObjectiveId) error {
 	if bridgedfund.IsBridgedFundObjective(objectiveId) {
-		b.nodeL2.RetryObjectiveTx(objectiveId)
-		return nil
+		return b.nodeL2.RetryObjectiveTx(objectiveId)
 	}
 	if directfund.IsDirectFundObjective(objectiveId) {
-		b.nodeL1.RetryObjectiveTx(objectiveId)
-		return nil
+		return b.nodeL1.RetryObjectiveTx(objectiveId)
 	}
 	return fmt.Errorf("objective with given Id is not supported for retrying")
 }
 
 func (b *Bridge) RetryTx(txHash common.Hash) error {
-	var chainService chainservice.ChainService
-
-	txToRetry, ok := b.sentTxs.Load(txHash.String())
-	if !ok {
+	txToRetry, err := b.bridgeStore.GetSentTx(txHash)
+	if errors.Is(err, buntdb.ErrNotFound) {
 		return fmt.Errorf("tx with given hash %s was either complete or cannot be found", txHash)
 	}
-
-	if !txToRetry.IsRetryLimitReached {
-		return fmt.Errorf("tx with given hash %s is pending confirmation and connot be retried", txHash)
+	if err != nil {
+		return err
 	}
 
-	if txToRetry.IsL2 {
-		chainService = b.chainServiceL2
-	} else {
-		chainService = b.chainServiceL1
+	if txToRetry.Status == SentTxPending {
+		return fmt.Errorf("tx with given hash %s is pending confirmation and connot be retried", txHash)
 	}
 
-	_, err := chainService.SendTransaction(txToRetry.Tx)
+	retriedTx, err := b.chainServiceFor(txToRetry.IsL2).SendTransaction(txToRetry.Tx)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	txToRetry.NumOfRetries++
+	txToRetry.Status = SentTxPending
+	txToRetry.UpdatedAt = time.Now()
+	return b.bridgeStore.ReplaceSentTx(txHash, retriedTx.Hash(), txToRetry)
 }
 
 func (b *Bridge) Close() error {
@@ -426,16 +458,16 @@ func (b *Bridge) listenForDroppedEvents(ctx context.Context) {
 	for {
 		select {
 		case l1DroppedEvent := <-b.chainServiceL1.DroppedEventFeed():
-			err = b.checkAndRetryDroppedTxs(l1DroppedEvent, b.chainServiceL1, false)
+			err = b.checkAndRetryDroppedTxs(ctx, l1DroppedEvent, b.chainServiceL1)
 
 		case l2DroppedEvent := <-b.chainServiceL2.DroppedEventFeed():
-			err = b.checkAndRetryDroppedTxs(l2DroppedEvent, b.chainServiceL2, true)
+			err = b.checkAndRetryDroppedTxs(ctx, l2DroppedEvent, b.chainServiceL2)
 
 		case l1ConfirmedEvent := <-b.chainServiceL1.EventFeed():
-			b.sentTxs.Delete(l1ConfirmedEvent.TxHash().String())
+			err = b.handleConfirmedEvent(l1ConfirmedEvent)
 
 		case l2ConfirmedEvent := <-b.chainServiceL2.EventFeed():
-			b.sentTxs.Delete(l2ConfirmedEvent.TxHash().String())
+			err = b.handleConfirmedEvent(l2ConfirmedEvent)
 
 		case <-ctx.Done():
 			return
@@ -445,38 +477,150 @@ func (b *Bridge) listenForDroppedEvents(ctx context.Context) {
 	}
 }
 
-func (b *Bridge) checkAndRetryDroppedTxs(droppedEvent protocols.DroppedEventInfo, chainservice chainservice.ChainService, isL2 bool) error {
-	txToRetry, ok := b.sentTxs.Load(droppedEvent.TxHash.String())
-	if !ok {
+// handleConfirmedEvent stops tracking the transaction which emitted the event
+func (b *Bridge) handleConfirmedEvent(event chainservice.Event) error {
+	return b.bridgeStore.DestroySentTx(event.TxHash())
+}
+
+// checkAndRetryDroppedTxs schedules the resubmission of a dropped bridge transaction after the backoff of the chain service's retry policy
+func (b *Bridge) checkAndRetryDroppedTxs(ctx context.Context, droppedEvent protocols.DroppedEventInfo, chainService chainservice.ChainService) error {
+	txToRetry, err := b.bridgeStore.GetSentTx(droppedEvent.TxHash)
+	if errors.Is(err, buntdb.ErrNotFound) {
 		return nil
 	}
+	if err != nil {
+		return err
+	}
 
-	if txToRetry.NumOfRetries >= RETRY_TX_LIMIT {
-		txToRetry.IsRetryLimitReached = true
-		b.sentTxs.Store(droppedEvent.TxHash.String(), txToRetry)
-		return nil
+	retryPolicy := chainService.GetRetryPolicy()
+	if !retryPolicy.CanRetry(txToRetry.NumOfRetries) {
+		return b.markTxDropped(droppedEvent.TxHash, txToRetry)
 	}
 
-	retriedTx, err := chainservice.SendTransaction(txToRetry.Tx)
+	go func() {
+		select {
+		case <-time.After(retryPolicy.BackoffFor(txToRetry.NumOfRetries)):
+		case <-ctx.Done():
+			return
+		}
+
+		// The tx may have been confirmed while backing off, e.g. if it was included again after the reorg
+		txToRetry, err := b.bridgeStore.GetSentTx(droppedEvent.TxHash)
+		if errors.Is(err, buntdb.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			b.checkError(err)
+			return
+		}
+
+		b.checkError(b.retryDroppedTx(droppedEvent.TxHash, txToRetry, chainService))
+	}()
+
+	return nil
+}
+
+// retryDroppedTx resubmits a dropped transaction, or marks it as dropped once the retry limit is reached so that it can be retried manually
+func (b *Bridge) retryDroppedTx(txHash common.Hash, txToRetry SentTx, chainService chainservice.ChainService) error {
+	retriedTx, err := chainService.ResendTransaction(txToRetry.Tx, txHash, txToRetry.NumOfRetries)
+	if errors.Is(err, chainservice.ErrRetryLimitReached) {
+		return b.markTxDropped(txHash, txToRetry)
+	}
+	if err != nil {
+		return err
+	}
+
+	txToRetry.NumOfRetries++
+	txToRetry.Status = SentTxPending
+	txToRetry.UpdatedAt = time.Now()
+	return b.bridgeStore.ReplaceSentTx(txHash, retriedTx.Hash(), txToRetry)
+}
+
+func (b *Bridge) markTxDropped(txHash common.Hash, sentTx SentTx) error {
+	sentTx.Status = SentTxDropped
+	sentTx.UpdatedAt = time.Now()
+	return b.bridgeStore.SetSentTx(txHash, sentTx)
+}
+
+// reconcileSentTxs resumes tracking of the transactions persisted by a previous run of the bridge.
+// Pending transactions are checked against their chain receipts: confirmed ones are forgotten, reverted ones are marked as failed
+// and ones unknown to the chain (e.g. dropped while the bridge was offline) are retried.
+func (b *Bridge) reconcileSentTxs() error {
+	sentTxs, err := b.bridgeStore.GetSentTxs()
 	if err != nil {
 		return err
 	}
 
-	b.sentTxs.Delete(droppedEvent.TxHash.String())
-	b.sentTxs.Store(retriedTx.Hash().String(), SentTx{txToRetry.Tx, txToRetry.NumOfRetries + 1, false, isL2})
+	for txHash, sentTx := range sentTxs {
+		if sentTx.Status != SentTxPending {
+			continue
+		}
+
+		chainService := b.chainServiceFor(sentTx.IsL2)
+		receipt, err := chainService.GetTransactionReceipt(txHash)
+		if errors.Is(err, ethereum.NotFound) {
+			slog.Info("Retrying bridge tx not found on chain", "txHash", txHash)
+			err = b.retryDroppedTx(txHash, sentTx, chainService)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if receipt == nil {
+			continue
+		}
+
+		if receipt.Status == ethTypes.ReceiptStatusFailed {
+			sentTx.Status = SentTxFailed
+			sentTx.UpdatedAt = time.Now()
+			err = b.bridgeStore.SetSentTx(txHash, sentTx)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Transactions mined recently are left pending, their confirmation is picked up from the event feed
+		if receipt.BlockNumber.Uint64() <= chainService.GetLastConfirmedBlockNum() {
+			err = b.bridgeStore.DestroySentTx(txHash)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
-func (b *Bridge) GetPendingBridgeTxs(channelId types.Destination) []PendingTx {
+func (b *Bridge) chainServiceFor(isL2 bool) chainservice.ChainService {
+	if isL2 {
+		return b.chainServiceL2
+	}
+	return b.chainServiceL1
+}
+
+func (b *Bridge) GetPendingBridgeTxs(channelId types.Destination) ([]PendingTx, error) {
 	var foundPendingTx []PendingTx
-	b.sentTxs.Range(func(txHash string, sentTxInfo SentTx) bool {
-		if sentTxInfo.Tx.ChannelId() == channelId {
-			foundPendingTx = append(foundPendingTx, PendingTx{sentTxInfo, txHash})
+
+	sentTxs, err := b.bridgeStore.GetSentTxs()
+	if err != nil {
+		return nil, err
+	}
+
+	for txHash, sentTx := range sentTxs {
+		if sentTx.Tx.ChannelId() == channelId {
+			foundPendingTx = append(foundPendingTx, PendingTx{sentTx, txHash.String()})
 		}
-		return true
+	}
+
+	sort.Slice(foundPendingTx, func(i, j int) bool {
+		return foundPendingTx[i].CreatedAt.Before(foundPendingTx[j].CreatedAt)
 	})
 
-	return foundPendingTx
+	return foundPendingTx, nil
 }
 
 func (b *Bridge) GetNodeInfo() types.NodeInfo {
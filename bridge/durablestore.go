@@ -2,6 +2,7 @@ package bridge
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -20,6 +21,8 @@ type MirrorChannelDetails struct {
 
 type DurableStore struct {
 	mirrorChannels *buntdb.DB
+	sentTxs        *buntdb.DB
+	assetMap       *buntdb.DB
 	folder         string // the folder where the store's data is stored
 }
 
@@ -39,6 +42,16 @@ func NewDurableStore(folder string, config buntdb.Config) (*DurableStore, error)
 		return nil, err
 	}
 
+	ds.sentTxs, err = ds.openDB("sent_txs", config)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.assetMap, err = ds.openDB("asset_map", config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ds, nil
 }
 
@@ -112,6 +125,150 @@ func (ds *DurableStore) GetMirrorChannelDetailsByL1Channel(l1ChannelId types.Des
 	return l2ChannelId, isCreated, nil
 }
 
+// SetSentTx stores the given transaction keyed by its hash
+func (ds *DurableStore) SetSentTx(txHash common.Hash, sentTx SentTx) error {
+	sentTxJson, err := json.Marshal(sentTx)
+	if err != nil {
+		return err
+	}
+
+	return ds.sentTxs.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(txHash.String(), string(sentTxJson), nil)
+		return err
+	})
+}
+
+// ReplaceSentTx atomically replaces the transaction stored under oldTxHash with the one under newTxHash
+func (ds *DurableStore) ReplaceSentTx(oldTxHash common.Hash, newTxHash common.Hash, sentTx SentTx) error {
+	sentTxJson, err := json.Marshal(sentTx)
+	if err != nil {
+		return err
+	}
+
+	return ds.sentTxs.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(oldTxHash.String())
+		if err != nil && !errors.Is(err, buntdb.ErrNotFound) {
+			return err
+		}
+
+		_, _, err = tx.Set(newTxHash.String(), string(sentTxJson), nil)
+		return err
+	})
+}
+
+func (ds *DurableStore) GetSentTx(txHash common.Hash) (sentTx SentTx, err error) {
+	var sentTxJson string
+
+	err = ds.sentTxs.View(func(tx *buntdb.Tx) error {
+		var err error
+		sentTxJson, err = tx.Get(txHash.String())
+		return err
+	})
+	if err != nil {
+		return sentTx, err
+	}
+
+	err = json.Unmarshal([]byte(sentTxJson), &sentTx)
+	if err != nil {
+		return sentTx, err
+	}
+
+	return sentTx, nil
+}
+
+// GetSentTxs returns all the stored transactions keyed by their hash
+func (ds *DurableStore) GetSentTxs() (map[common.Hash]SentTx, error) {
+	sentTxs := make(map[common.Hash]SentTx)
+	var unmarshErr error
+
+	err := ds.sentTxs.View(func(tx *buntdb.Tx) error {
+		return tx.Ascend("", func(key, sentTxJson string) bool {
+			var sentTx SentTx
+			unmarshErr = json.Unmarshal([]byte(sentTxJson), &sentTx)
+			if unmarshErr != nil {
+				return false
+			}
+
+			sentTxs[common.HexToHash(key)] = sentTx
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if unmarshErr != nil {
+		return nil, unmarshErr
+	}
+
+	return sentTxs, nil
+}
+
+// DestroySentTx deletes the transaction with the given hash, it is a no-op if no such transaction exists
+func (ds *DurableStore) DestroySentTx(txHash common.Hash) error {
+	return ds.sentTxs.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(txHash.String())
+		if errors.Is(err, buntdb.ErrNotFound) {
+			return nil
+		}
+		return err
+	})
+}
+
+// SetL2AssetAddress maps the given L1 asset to the given L2 asset, replacing any previous mapping of the L1 asset
+func (ds *DurableStore) SetL2AssetAddress(l1AssetAddress common.Address, l2AssetAddress common.Address) error {
+	return ds.assetMap.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(l1AssetAddress.String(), l2AssetAddress.String(), nil)
+		return err
+	})
+}
+
+// GetL2AssetAddress returns the L2 asset mapped to the given L1 asset, or an error wrapping ErrUnmappedAsset if there is none
+func (ds *DurableStore) GetL2AssetAddress(l1AssetAddress common.Address) (common.Address, error) {
+	var l2AssetAddress string
+
+	err := ds.assetMap.View(func(tx *buntdb.Tx) error {
+		var err error
+		l2AssetAddress, err = tx.Get(l1AssetAddress.String())
+		return err
+	})
+	if errors.Is(err, buntdb.ErrNotFound) {
+		return common.Address{}, fmt.Errorf("%s: %w", l1AssetAddress, ErrUnmappedAsset)
+	}
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return common.HexToAddress(l2AssetAddress), nil
+}
+
+// GetAssetMap returns all the stored L1 to L2 asset pairs
+func (ds *DurableStore) GetAssetMap() ([]AssetMapEntry, error) {
+	assetMap := []AssetMapEntry{}
+
+	err := ds.assetMap.View(func(tx *buntdb.Tx) error {
+		return tx.Ascend("", func(key, value string) bool {
+			assetMap = append(assetMap, AssetMapEntry{L1AssetAddress: common.HexToAddress(key), L2AssetAddress: common.HexToAddress(value)})
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return assetMap, nil
+}
+
 func (ds *DurableStore) Close() error {
+	err := ds.assetMap.Close()
+	if err != nil {
+		return err
+	}
+
+	err = ds.sentTxs.Close()
+	if err != nil {
+		return err
+	}
+
 	return ds.mirrorChannels.Close()
 }
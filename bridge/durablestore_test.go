@@ -1,12 +1,14 @@
 package bridge_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/go-cmp/cmp"
 	"github.com/statechannels/go-nitro/bridge"
 	"github.com/statechannels/go-nitro/internal/testhelpers"
+	"github.com/statechannels/go-nitro/protocols"
 	"github.com/statechannels/go-nitro/types"
 	"github.com/tidwall/buntdb"
 )
@@ -50,3 +52,111 @@ func TestSetGetMirrorChannels(t *testing.T) {
 		t.Fatalf("fetched result different than expected %s", diff)
 	}
 }
+
+func TestSetGetSentTxs(t *testing.T) {
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	durableStore, err := bridge.NewDurableStore(dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l1ChannelId := types.Destination(common.HexToHash("0x59846efc80336b0961e4f84a0d974967bfeb04a9b17d90b4610b1a968f00efcd"))
+	l2ChannelId := types.Destination(common.HexToHash("0x53494de9354193e864d545e7edfb3915e8b1e210fe4b57585055dff17b2abd30"))
+	txHash := common.HexToHash("0x01")
+	retriedTxHash := common.HexToHash("0x02")
+
+	sentTx := bridge.NewSentTx(protocols.NewSetL2ToL1Transaction(l1ChannelId, l2ChannelId), false)
+	// Round to drop the monotonic clock reading, which is not serialized
+	sentTx.CreatedAt = sentTx.CreatedAt.Round(0)
+	sentTx.UpdatedAt = sentTx.UpdatedAt.Round(0)
+
+	err = durableStore.SetSentTx(txHash, sentTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := durableStore.GetSentTx(txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(sentTx, got, cmp.AllowUnexported(protocols.ChainTransactionBase{})); diff != "" {
+		t.Fatalf("fetched result different than expected %s", diff)
+	}
+
+	sentTx.NumOfRetries = 1
+	err = durableStore.ReplaceSentTx(txHash, retriedTxHash, sentTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = durableStore.GetSentTx(txHash)
+	if !errors.Is(err, buntdb.ErrNotFound) {
+		t.Fatalf("expected replaced tx to be removed, got error %v", err)
+	}
+
+	sentTxs, err := durableStore.GetSentTxs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[common.Hash]bridge.SentTx{retriedTxHash: sentTx}
+	if diff := cmp.Diff(want, sentTxs, cmp.AllowUnexported(protocols.ChainTransactionBase{})); diff != "" {
+		t.Fatalf("fetched result different than expected %s", diff)
+	}
+
+	err = durableStore.DestroySentTx(retriedTxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sentTxs, err = durableStore.GetSentTxs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sentTxs) != 0 {
+		t.Fatalf("expected no sent txs, got %d", len(sentTxs))
+	}
+}
+
+func TestSetGetAssetMap(t *testing.T) {
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	durableStore, err := bridge.NewDurableStore(dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l1AssetAddress := common.HexToAddress("0x71d73E2F0908c003070b5d60F66Be8Bf4Ff0A54e")
+	l2AssetAddress := common.HexToAddress("0xBca48057Da826cB2eb1258E2C679678b269dC262")
+
+	_, err = durableStore.GetL2AssetAddress(l1AssetAddress)
+	if !errors.Is(err, bridge.ErrUnmappedAsset) {
+		t.Fatalf("expected ErrUnmappedAsset, got %v", err)
+	}
+
+	err = durableStore.SetL2AssetAddress(l1AssetAddress, l2AssetAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := durableStore.GetL2AssetAddress(l1AssetAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != l2AssetAddress {
+		t.Fatalf("expected L2 asset %s, got %s", l2AssetAddress, got)
+	}
+
+	assetMap, err := durableStore.GetAssetMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []bridge.AssetMapEntry{{L1AssetAddress: l1AssetAddress, L2AssetAddress: l2AssetAddress}}
+	if diff := cmp.Diff(want, assetMap); diff != "" {
+		t.Fatalf("fetched result different than expected %s", diff)
+	}
+}
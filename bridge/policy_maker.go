@@ -1,13 +1,18 @@
 package bridge
 
 import (
+	"log/slog"
+
 	"github.com/statechannels/go-nitro/protocols"
 	"github.com/statechannels/go-nitro/protocols/directdefund"
+	"github.com/statechannels/go-nitro/protocols/directfund"
 	"github.com/statechannels/go-nitro/protocols/virtualdefund"
 	"github.com/statechannels/go-nitro/protocols/virtualfund"
 )
 
-type NodeL1PermissivePolicy struct{}
+type NodeL1PermissivePolicy struct {
+	bridge *Bridge
+}
 
 func (pp *NodeL1PermissivePolicy) ShouldApprove(o protocols.Objective) bool {
 	// L1 node rejects objectives if they involve virtual funding, virtual defunding, or direct defunding
@@ -15,5 +20,13 @@ func (pp *NodeL1PermissivePolicy) ShouldApprove(o protocols.Objective) bool {
 		return false
 	}
 
+	// L1 node rejects ledger channels holding assets which cannot be mirrored on L2
+	if dfo, ok := o.(*directfund.Objective); ok && pp.bridge != nil {
+		if err := pp.bridge.checkAssetsMapped(dfo.C.PreFundState().Outcome); err != nil {
+			slog.Info("Rejecting ledger channel", "objectiveId", o.Id(), "error", err)
+			return false
+		}
+	}
+
 	return o.GetStatus() == protocols.Unapproved
 }
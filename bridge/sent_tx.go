@@ -0,0 +1,133 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+type SentTxStatus string
+
+const (
+	// SentTxPending is the status of a transaction awaiting confirmation on chain
+	SentTxPending SentTxStatus = "Pending"
+	// SentTxDropped is the status of a transaction that was dropped from chain after exhausting automatic retries
+	SentTxDropped SentTxStatus = "Dropped"
+	// SentTxFailed is the status of a transaction that was mined but reverted
+	SentTxFailed SentTxStatus = "Failed"
+)
+
+const setL2ToL1TxType = "SetL2ToL1"
+
+// SentTx is a transaction submitted by the bridge which is tracked until it is confirmed on chain
+type SentTx struct {
+	Tx           protocols.ChainTransaction
+	NumOfRetries uint
+	Status       SentTxStatus
+	IsL2         bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewSentTx returns a pending SentTx for a transaction submitted just now
+func NewSentTx(tx protocols.ChainTransaction, isL2 bool) SentTx {
+	now := time.Now()
+	return SentTx{
+		Tx:        tx,
+		Status:    SentTxPending,
+		IsL2:      isL2,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// jsonChainTransaction is a serialization-friendly representation of the chain transactions sent by the bridge
+type jsonChainTransaction struct {
+	Type            string            `json:"type"`
+	ChannelId       types.Destination `json:"channel_id"`
+	MirrorChannelId types.Destination `json:"mirror_channel_id"`
+}
+
+type jsonSentTx struct {
+	Tx           jsonChainTransaction `json:"tx"`
+	NumOfRetries uint                 `json:"num_of_retries"`
+	Status       SentTxStatus         `json:"status"`
+	IsL2         bool                 `json:"is_l2"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+// PendingTx is a SentTx along with the hash it was last submitted with
+type PendingTx struct {
+	SentTx
+	TxHash string `json:"tx_hash"`
+}
+
+// IsRetryLimitReached returns whether the transaction was dropped after exhausting its automatic retries
+func (st SentTx) IsRetryLimitReached() bool {
+	return st.Status == SentTxDropped
+}
+
+func (st SentTx) toJson() (jsonSentTx, error) {
+	var jsonTx jsonChainTransaction
+
+	switch tx := st.Tx.(type) {
+	case protocols.SetL2ToL1Transaction:
+		jsonTx = jsonChainTransaction{Type: setL2ToL1TxType, ChannelId: tx.ChannelId(), MirrorChannelId: tx.MirrorChannelId}
+	default:
+		return jsonSentTx{}, fmt.Errorf("unsupported transaction type %T", st.Tx)
+	}
+
+	return jsonSentTx{jsonTx, st.NumOfRetries, st.Status, st.IsL2, st.CreatedAt, st.UpdatedAt}, nil
+}
+
+// MarshalJSON returns a JSON representation of the SentTx
+func (st SentTx) MarshalJSON() ([]byte, error) {
+	jsonST, err := st.toJson()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonST)
+}
+
+// MarshalJSON returns a JSON representation of the PendingTx
+func (pt PendingTx) MarshalJSON() ([]byte, error) {
+	jsonST, err := pt.SentTx.toJson()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		jsonSentTx
+		TxHash              string `json:"tx_hash"`
+		IsRetryLimitReached bool   `json:"is_retry_limit_reached"`
+	}{jsonST, pt.TxHash, pt.IsRetryLimitReached()})
+}
+
+// UnmarshalJSON populates the receiver with the JSON-encoded data
+func (st *SentTx) UnmarshalJSON(data []byte) error {
+	var jsonST jsonSentTx
+	err := json.Unmarshal(data, &jsonST)
+	if err != nil {
+		return err
+	}
+
+	switch jsonST.Tx.Type {
+	case setL2ToL1TxType:
+		st.Tx = protocols.NewSetL2ToL1Transaction(jsonST.Tx.ChannelId, jsonST.Tx.MirrorChannelId)
+	default:
+		return fmt.Errorf("unsupported transaction type %s", jsonST.Tx.Type)
+	}
+
+	st.NumOfRetries = jsonST.NumOfRetries
+	st.Status = jsonST.Status
+	st.IsL2 = jsonST.IsL2
+	st.CreatedAt = jsonST.CreatedAt
+	st.UpdatedAt = jsonST.UpdatedAt
+
+	return nil
+}
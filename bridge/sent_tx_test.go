@@ -0,0 +1,33 @@
+package bridge_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/statechannels/go-nitro/bridge"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestPendingTxMarshalJSON(t *testing.T) {
+	sentTx := bridge.NewSentTx(protocols.NewSetL2ToL1Transaction(types.Destination{1}, types.Destination{2}), false)
+
+	for _, status := range []bridge.SentTxStatus{bridge.SentTxPending, bridge.SentTxDropped, bridge.SentTxFailed} {
+		sentTx.Status = status
+		data, err := json.Marshal(bridge.PendingTx{SentTx: sentTx, TxHash: "0x01"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got map[string]any
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got["tx_hash"] != "0x01" || got["status"] != string(status) {
+			t.Fatalf("expected tx_hash 0x01 and status %s, got %s", status, data)
+		}
+		if want := status == bridge.SentTxDropped; got["is_retry_limit_reached"] != want {
+			t.Fatalf("expected is_retry_limit_reached to be %t for a %s tx, got %s", want, status, data)
+		}
+	}
+}
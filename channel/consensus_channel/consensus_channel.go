@@ -27,6 +27,7 @@ const (
 	ErrDuplicateGuarantee = types.ConstError("duplicate guarantee detected")
 	ErrGuaranteeNotFound  = types.ConstError("guarantee not found")
 	ErrInvalidAmount      = types.ConstError("left amount is greater than the guarantee amount")
+	ErrInvalidResize      = types.ConstError("resize must credit a positive amount to a participant")
 )
 
 const (
@@ -178,6 +179,24 @@ func (c *ConsensusChannel) IncludesTarget(target types.Destination) bool {
 	return c.current.Outcome.includesTarget(target)
 }
 
+// HasSignedGuaranteeFor returns whether or not we have signed a state including a guarantee for the target:
+// either the consensus state includes it, or we are the leader and have proposed to add it. The follower may
+// countersign the leader's proposals at any time, so such a guarantee may already be supported.
+func (c *ConsensusChannel) HasSignedGuaranteeFor(target types.Destination) bool {
+	if c.IncludesTarget(target) {
+		return true
+	}
+	if !c.IsLeader() {
+		return false
+	}
+	for _, p := range c.proposalQueue {
+		if p.Proposal.Type() == AddProposal && p.Proposal.ToAdd.Target() == target {
+			return true
+		}
+	}
+	return false
+}
+
 // HasRemovalBeenProposed returns whether or not a proposal exists to remove the guarantee for the target.
 func (c *ConsensusChannel) HasRemovalBeenProposed(target types.Destination, assetAddress common.Address) bool {
 	for _, p := range c.proposalQueue {
@@ -201,6 +220,26 @@ func (c *ConsensusChannel) HasRemovalBeenProposedNext(target types.Destination,
 	return p.Proposal.Type() == RemoveProposal && p.Proposal.ToRemove.Target == target && p.Proposal.ToRemove.AssetAddress == assetAddress
 }
 
+// HasResizeBeenProposed returns whether or not a proposal exists for the resize with the given target.
+func (c *ConsensusChannel) HasResizeBeenProposed(target types.Destination) bool {
+	for _, p := range c.proposalQueue {
+		if p.Proposal.Type() == ResizeProposal && p.Proposal.ToResize.Target == target {
+			return true
+		}
+	}
+	return false
+}
+
+// HasResizeBeenProposedNext returns whether or not the next proposal in the queue is a proposal for the resize with the given target.
+func (c *ConsensusChannel) HasResizeBeenProposedNext(target types.Destination) bool {
+	if len(c.proposalQueue) == 0 {
+		return false
+	}
+
+	p := c.proposalQueue[0]
+	return p.Proposal.Type() == ResizeProposal && p.Proposal.ToResize.Target == target
+}
+
 // IsLeader returns true if the calling client is the leader of the channel,
 // and false otherwise.
 func (c *ConsensusChannel) IsLeader() bool {
@@ -264,6 +303,31 @@ func (c *ConsensusChannel) ProposalQueue() []SignedProposal {
 	return c.proposalQueue
 }
 
+// RetractProposals removes the trailing proposals in the queue which add a guarantee for the given target,
+// and returns the number of proposals removed. Proposals followed by a proposal for a different target
+// cannot be retracted, since later proposals are built on top of them.
+//
+// Only proposals we never signed are retracted, so only the follower retracts proposals. The leader signs and sends
+// its proposals as it makes them: the follower may countersign them at any time, so the leader keeps them
+// rather than sign a different state with the same TurnNum.
+func (c *ConsensusChannel) RetractProposals(target types.Destination) int {
+	if c.MyIndex != Follower {
+		return 0
+	}
+
+	retracted := 0
+	for len(c.proposalQueue) > 0 {
+		last := c.proposalQueue[len(c.proposalQueue)-1].Proposal
+		if last.Type() != AddProposal || last.ToAdd.Target() != target {
+			break
+		}
+		c.proposalQueue = c.proposalQueue[:len(c.proposalQueue)-1]
+		retracted++
+	}
+
+	return retracted
+}
+
 // latestProposedVars returns the latest proposed vars in a consensus channel
 // by cloning its current vars and applying each proposal in the queue.
 func (c *ConsensusChannel) latestProposedVars() (Vars, error) {
@@ -651,9 +715,9 @@ func (sv *SignedVars) clone() SignedVars {
 	}
 }
 
-// Proposal is a proposal either to add or to remove a guarantee.
+// Proposal is a proposal either to add or to remove a guarantee, or to resize the ledger channel.
 //
-// Exactly one of {toAdd, toRemove} should be non nil.
+// Exactly one of {toAdd, toRemove, toResize} should be non nil.
 type Proposal struct {
 	// LedgerID is the ChannelID of the ConsensusChannel which should receive the proposal.
 	//
@@ -661,6 +725,7 @@ type Proposal struct {
 	LedgerID types.Destination
 	ToAdd    Add
 	ToRemove Remove
+	ToResize Resize
 }
 
 // Clone returns a deep copy of the receiver.
@@ -669,21 +734,26 @@ func (p *Proposal) Clone() Proposal {
 		p.LedgerID,
 		p.ToAdd.Clone(),
 		p.ToRemove.Clone(),
+		p.ToResize.Clone(),
 	}
 }
 
 const (
 	AddProposal    ProposalType = "AddProposal"
 	RemoveProposal ProposalType = "RemoveProposal"
+	ResizeProposal ProposalType = "ResizeProposal"
 )
 
 type ProposalType string
 
-// Type returns the type of the proposal based on whether it contains an Add or a Remove proposal.
+// Type returns the type of the proposal based on whether it contains an Add, a Remove or a Resize proposal.
 func (p *Proposal) Type() ProposalType {
 	zeroAdd := Add{}
+	zeroResize := Resize{}
 	if p.ToAdd != zeroAdd {
 		return AddProposal
+	} else if p.ToResize != zeroResize {
+		return ResizeProposal
 	} else {
 		return RemoveProposal
 	}
@@ -691,7 +761,7 @@ func (p *Proposal) Type() ProposalType {
 
 // Equal returns true if the supplied Proposal is deeply equal to the receiver, false otherwise.
 func (p *Proposal) Equal(q *Proposal) bool {
-	return p.LedgerID == q.LedgerID && p.ToAdd.equal(q.ToAdd) && p.ToRemove.equal(q.ToRemove)
+	return p.LedgerID == q.LedgerID && p.ToAdd.equal(q.ToAdd) && p.ToRemove.equal(q.ToRemove) && p.ToResize.equal(q.ToResize)
 }
 
 // ChannelID returns the id of the ConsensusChannel which receive the proposal.
@@ -717,6 +787,10 @@ func (p *Proposal) Target() types.Destination {
 		{
 			return p.ToRemove.Target
 		}
+	case "ResizeProposal":
+		{
+			return p.ToResize.Target
+		}
 	default:
 		{
 			panic(fmt.Errorf("invalid proposal type %T", p))
@@ -745,6 +819,9 @@ type Add struct {
 	// The right participant's deduction is computed as the difference between the guarantee amount and LeftDeposit.
 	LeftDeposit  *big.Int
 	AssetAddress common.Address
+	// Fee is paid by the left participant to the right participant when the guarantee is added, on top of the deposits.
+	// A nil Fee is no fee.
+	Fee *big.Int
 }
 
 // Clone returns a deep copy of the receiver.
@@ -752,10 +829,15 @@ func (a *Add) Clone() Add {
 	if a == nil || a.LeftDeposit == nil {
 		return Add{}
 	}
+	var fee *big.Int
+	if a.Fee != nil {
+		fee = big.NewInt(0).Set(a.Fee)
+	}
 	return Add{
 		a.Guarantee.Clone(),
 		big.NewInt(0).Set(a.LeftDeposit),
 		a.AssetAddress,
+		fee,
 	}
 }
 
@@ -792,8 +874,16 @@ func (a Add) RightDeposit() *big.Int {
 	return result
 }
 
+// fee returns the fee paid by the left participant to the right participant, which is zero if none is set.
+func (a Add) fee() *big.Int {
+	if a.Fee == nil {
+		return big.NewInt(0)
+	}
+	return a.Fee
+}
+
 func (a Add) equal(a2 Add) bool {
-	return a.Guarantee.equal(a2.Guarantee) && types.Equal(a.LeftDeposit, a2.LeftDeposit) && a.AssetAddress == a2.AssetAddress
+	return a.Guarantee.equal(a2.Guarantee) && types.Equal(a.LeftDeposit, a2.LeftDeposit) && a.AssetAddress == a2.AssetAddress && types.Equal(a.fee(), a2.fee())
 }
 
 func (r Remove) equal(r2 Remove) bool {
@@ -801,8 +891,12 @@ func (r Remove) equal(r2 Remove) bool {
 		types.Equal(r.LeftAmount, r2.LeftAmount)
 }
 
-// HandleProposal handles a proposal to add or remove a guarantee.
-// It will mutate Vars by calling Add or Remove for the proposal.
+func (r Resize) equal(r2 Resize) bool {
+	return r.Target == r2.Target && r.Destination == r2.Destination && types.Equal(r.Amount, r2.Amount) && r.AssetAddress == r2.AssetAddress
+}
+
+// HandleProposal handles a proposal to add or remove a guarantee, or to resize the ledger channel.
+// It will mutate Vars by calling Add, Remove or Resize for the proposal.
 func (vars *Vars) HandleProposal(p Proposal) error {
 	switch p.Type() {
 	case AddProposal:
@@ -813,9 +907,13 @@ func (vars *Vars) HandleProposal(p Proposal) error {
 		{
 			return vars.Remove(p.ToRemove)
 		}
+	case ResizeProposal:
+		{
+			return vars.Resize(p.ToResize)
+		}
 	default:
 		{
-			return fmt.Errorf("invalid proposal: a proposal must be either an add or a remove proposal")
+			return fmt.Errorf("invalid proposal: a proposal must be either an add, a remove or a resize proposal")
 		}
 	}
 }
@@ -823,11 +921,11 @@ func (vars *Vars) HandleProposal(p Proposal) error {
 // Add mutates Vars by
 //   - increasing the turn number by 1
 //   - including the guarantee
-//   - adjusting balances accordingly
+//   - adjusting balances accordingly, including the payment of the fee
 //
 // An error is returned if:
 //   - the turn number is not incremented
-//   - the balances are incorrectly adjusted, or the deposits are too large
+//   - the balances are incorrectly adjusted, or the deposits and fee are too large
 //   - the guarantee is already included in vars.Outcome
 //
 // If an error is returned, the original vars is not mutated.
@@ -861,11 +959,19 @@ func (vars *Vars) Add(p Add) error {
 		return ErrInvalidDeposit
 	}
 
-	if types.Gt(p.LeftDeposit, left.amount) {
+	if p.fee().Sign() < 0 {
+		return ErrInvalidDeposit
+	}
+
+	// The fee moves from the left participant's balance to the right participant's
+	leftDeposit := big.NewInt(0).Add(p.LeftDeposit, p.fee())
+	rightDeposit := big.NewInt(0).Sub(p.RightDeposit(), p.fee())
+
+	if types.Gt(leftDeposit, left.amount) {
 		return ErrInsufficientFunds
 	}
 
-	if types.Gt(p.RightDeposit(), right.amount) {
+	if types.Gt(rightDeposit, right.amount) {
 		return ErrInsufficientFunds
 	}
 
@@ -874,14 +980,12 @@ func (vars *Vars) Add(p Add) error {
 	// Increase the turn number
 	vars.TurnNum += 1
 
-	rightDeposit := p.RightDeposit()
-
 	// Adjust balances
 	if o.leader.destination == p.Guarantee.left {
-		o.leader.amount.Sub(o.leader.amount, p.LeftDeposit)
+		o.leader.amount.Sub(o.leader.amount, leftDeposit)
 		o.follower.amount.Sub(o.follower.amount, rightDeposit)
 	} else {
-		o.follower.amount.Sub(o.follower.amount, p.LeftDeposit)
+		o.follower.amount.Sub(o.follower.amount, leftDeposit)
 		o.leader.amount.Sub(o.leader.amount, rightDeposit)
 	}
 
@@ -943,6 +1047,77 @@ func (vars *Vars) Remove(p Remove) error {
 	return nil
 }
 
+// Resize mutates Vars by
+//   - increasing the turn number by 1
+//   - crediting the Amount to the balance of the Destination
+//
+// An error is returned if:
+//   - the Destination is neither the leader nor the follower
+//   - the Amount is not positive
+//
+// If an error is returned, the original vars is not mutated.
+func (vars *Vars) Resize(p Resize) error {
+	// CHECKS
+
+	var o LedgerOutcome
+	for _, outcome := range vars.Outcome {
+		if outcome.assetAddress == p.AssetAddress {
+			o = outcome
+		}
+	}
+
+	if o.leader.destination != p.Destination && o.follower.destination != p.Destination {
+		return ErrInvalidResize
+	}
+
+	if p.Amount == nil || p.Amount.Sign() <= 0 {
+		return ErrInvalidResize
+	}
+
+	// EFFECTS
+
+	// Increase the turn number
+	vars.TurnNum += 1
+
+	// Adjust balances
+	if o.leader.destination == p.Destination {
+		o.leader.amount.Add(o.leader.amount, p.Amount)
+	} else {
+		o.follower.amount.Add(o.follower.amount, p.Amount)
+	}
+
+	return nil
+}
+
+// Resize is a proposal to credit a participant with funds they have deposited into the ledger channel on chain,
+// without otherwise changing its outcome.
+type Resize struct {
+	// Target identifies the resize, so that the proposal can be routed to the objective which requested it
+	Target types.Destination
+	// Destination is the participant whose balance is credited
+	Destination  types.Destination
+	Amount       *big.Int
+	AssetAddress common.Address
+}
+
+// NewResizeProposal constructs a proposal with a valid Resize proposal and empty Add and Remove proposals.
+func NewResizeProposal(ledgerID types.Destination, target types.Destination, destination types.Destination, amount *big.Int, assetAddress common.Address) Proposal {
+	return Proposal{ToResize: Resize{Target: target, Destination: destination, Amount: amount, AssetAddress: assetAddress}, LedgerID: ledgerID}
+}
+
+// Clone returns a deep copy of the receiver
+func (r *Resize) Clone() Resize {
+	if r == nil || r.Amount == nil {
+		return Resize{}
+	}
+	return Resize{
+		Target:       r.Target,
+		Destination:  r.Destination,
+		Amount:       big.NewInt(0).Set(r.Amount),
+		AssetAddress: r.AssetAddress,
+	}
+}
+
 // Remove is a proposal to remove a guarantee for the given virtual channel.
 type Remove struct {
 	// Target is the address of the virtual channel being defunded
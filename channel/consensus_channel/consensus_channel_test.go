@@ -151,6 +151,49 @@ func TestConsensusChannel(t *testing.T) {
 		}
 	}
 
+	testApplyingResizeProposalToVars := func(t *testing.T) {
+		startingTurnNum := uint64(9)
+		resizeAmount := uint64(50)
+
+		vars := Vars{TurnNum: startingTurnNum, Outcome: outcome()}
+		proposal := Resize{Target: targetChannel, Destination: bob.Destination(), Amount: big.NewInt(int64(resizeAmount))}
+
+		err := vars.Resize(proposal)
+		if err != nil {
+			t.Fatalf("unable to compute next state: %v", err)
+		}
+
+		if vars.TurnNum != startingTurnNum+1 {
+			t.Fatalf("incorrect state calculation: %v", err)
+		}
+
+		expected := LedgerOutcomes{makeOutcome(
+			allocation(alice, aBal),
+			allocation(bob, bBal+resizeAmount),
+			guarantee(vAmount, existingChannel, alice, bob),
+		)}
+
+		if diff := cmp.Diff(vars.Outcome, expected, cmp.AllowUnexported(LedgerOutcome{}, Balance{}, big.Int{}, Guarantee{})); diff != "" {
+			t.Fatalf("incorrect outcome: %v", diff)
+		}
+
+		// Resizing to credit someone other than the participants should fail
+		vars = Vars{TurnNum: startingTurnNum, Outcome: outcome()}
+		err = vars.Resize(Resize{Target: targetChannel, Destination: ivan.Destination(), Amount: big.NewInt(1)})
+		if !errors.Is(err, ErrInvalidResize) {
+			t.Fatalf("expected error when crediting a non-participant: %v", err)
+		}
+
+		// Resizing by a non-positive amount should fail
+		err = vars.Resize(Resize{Target: targetChannel, Destination: alice.Destination(), Amount: big.NewInt(0)})
+		if !errors.Is(err, ErrInvalidResize) {
+			t.Fatalf("expected error when crediting nothing: %v", err)
+		}
+		if vars.TurnNum != startingTurnNum {
+			t.Fatalf("vars mutated by an invalid resize")
+		}
+	}
+
 	initialVars := Vars{Outcome: outcome(), TurnNum: 0}
 	aliceSig, _ := initialVars.AsState(fp()).Sign(alice.PrivateKey)
 	bobsSig, _ := initialVars.AsState(fp()).Sign(bob.PrivateKey)
@@ -203,5 +246,42 @@ func TestConsensusChannel(t *testing.T) {
 	t.Run(`TestEmptyProposalClone`, testEmptyProposalClone)
 	t.Run(`TestApplyingAddProposalToVars`, testApplyingAddProposalToVars)
 	t.Run(`TestApplyingRemoveProposalToVars`, testApplyingRemoveProposalToVars)
+	t.Run(`TestApplyingResizeProposalToVars`, testApplyingResizeProposalToVars)
 	t.Run(`TestConsensusChannelFunctionality`, testConsensusChannelFunctionality)
 }
+
+func TestAddWithFee(t *testing.T) {
+	fee := uint64(2)
+	outcome := func() LedgerOutcomes {
+		return LedgerOutcomes{makeOutcome(allocation(alice, aBal), allocation(bob, bBal))}
+	}
+
+	// Bob is the left participant of the guarantee, so he pays the fee to Alice
+	proposal := add(vAmount, targetChannel, bob, alice)
+	proposal.Fee = big.NewInt(int64(fee))
+	vars := Vars{TurnNum: 9, Outcome: outcome()}
+	if err := vars.Add(proposal); err != nil {
+		t.Fatalf("unable to compute next state: %v", err)
+	}
+
+	o := vars.Outcome[0]
+	if o.leader.amount.Uint64() != aBal+fee || o.follower.amount.Uint64() != bBal-vAmount-fee {
+		t.Fatalf("expected balances %d and %d, got %s and %s", aBal+fee, bBal-vAmount-fee, o.leader.amount, o.follower.amount)
+	}
+	if !o.includes(guarantee(vAmount, targetChannel, bob, alice)) {
+		t.Fatal("expected the guarantee to be included, without the fee")
+	}
+
+	// The left participant must afford the fee on top of its deposit
+	vars = Vars{TurnNum: 9, Outcome: outcome()}
+	proposal.Fee = big.NewInt(int64(bBal - vAmount + 1))
+	if err := vars.Add(proposal); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("expected error when the fee exceeds the left participant's balance: %v", err)
+	}
+
+	// Proposals which only differ by their fee are different proposals
+	withoutFee := add(vAmount, targetChannel, bob, alice)
+	if withoutFee.equal(proposal) || !proposal.equal(proposal.Clone()) {
+		t.Fatal("expected the fee to be compared and cloned")
+	}
+}
@@ -478,3 +478,101 @@ func TestRestrictedFollowerMethods(t *testing.T) {
 		t.Errorf("Expected error when calling Receive as a leader, but found none")
 	}
 }
+
+func TestRetractProposals(t *testing.T) {
+	o := ledgerOutcome()
+	initialVars := Vars{Outcome: o.clone(), TurnNum: 0}
+	aliceSig, _ := initialVars.AsState(fp()).Sign(alice.PrivateKey)
+	bobsSig, _ := initialVars.AsState(fp()).Sign(bob.PrivateKey)
+	sigs := [2]state.Signature{aliceSig, bobsSig}
+
+	leader, err := NewLeaderChannel(fp(), 0, o.clone(), sigs)
+	if err != nil {
+		t.Fatal("unable to construct channel")
+	}
+	follower, err := NewFollowerChannel(fp(), 0, o.clone(), sigs)
+	if err != nil {
+		t.Fatal("unable to construct channel")
+	}
+
+	first, second := types.Destination{3}, types.Destination{4}
+	for _, target := range []types.Destination{first, second} {
+		sp, err := leader.Propose(Proposal{LedgerID: leader.Id, ToAdd: add(vAmount, target, alice, bob)}, alice.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := follower.Receive(sp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The leader has signed and sent its proposals, so it keeps them
+	if n := leader.RetractProposals(second); n != 0 {
+		t.Fatalf("the leader retracted %d proposals which it signed", n)
+	}
+	if len(leader.ProposalQueue()) != 2 {
+		t.Fatalf("expected the leader to keep 2 proposals, got %d proposals", len(leader.ProposalQueue()))
+	}
+
+	if n := follower.RetractProposals(first); n != 0 {
+		t.Fatalf("retracted %d proposals which are followed by another proposal", n)
+	}
+	if n := follower.RetractProposals(second); n != 1 {
+		t.Fatalf("expected to retract 1 proposal, retracted %d", n)
+	}
+	if n := follower.RetractProposals(first); n != 1 {
+		t.Fatalf("expected to retract 1 proposal, retracted %d", n)
+	}
+	if len(follower.ProposalQueue()) != 0 {
+		t.Fatalf("expected an empty proposal queue, got %d proposals", len(follower.ProposalQueue()))
+	}
+	if follower.ConsensusTurnNum() != 0 {
+		t.Fatalf("expected the follower not to sign a retracted proposal, got consensus TurnNum %d", follower.ConsensusTurnNum())
+	}
+}
+
+func TestHasSignedGuaranteeFor(t *testing.T) {
+	o := ledgerOutcome()
+	initialVars := Vars{Outcome: o.clone(), TurnNum: 0}
+	aliceSig, _ := initialVars.AsState(fp()).Sign(alice.PrivateKey)
+	bobsSig, _ := initialVars.AsState(fp()).Sign(bob.PrivateKey)
+	sigs := [2]state.Signature{aliceSig, bobsSig}
+
+	leader, err := NewLeaderChannel(fp(), 0, o.clone(), sigs)
+	if err != nil {
+		t.Fatal("unable to construct channel")
+	}
+	follower, err := NewFollowerChannel(fp(), 0, o.clone(), sigs)
+	if err != nil {
+		t.Fatal("unable to construct channel")
+	}
+
+	target := types.Destination{3}
+	if leader.HasSignedGuaranteeFor(target) || follower.HasSignedGuaranteeFor(target) {
+		t.Fatal("expected no signed guarantee before it is proposed")
+	}
+
+	proposal := Proposal{LedgerID: leader.Id, ToAdd: add(vAmount, target, alice, bob)}
+	sp, err := leader.Propose(proposal, alice.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := follower.Receive(sp); err != nil {
+		t.Fatal(err)
+	}
+
+	// The follower may countersign the leader's proposal at any time
+	if !leader.HasSignedGuaranteeFor(target) {
+		t.Fatal("expected the leader to have signed the guarantee it proposed")
+	}
+	if follower.HasSignedGuaranteeFor(target) {
+		t.Fatal("expected the follower not to have signed a guarantee it has not countersigned")
+	}
+
+	if _, err := follower.SignNextProposal(proposal, bob.PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+	if !follower.HasSignedGuaranteeFor(target) {
+		t.Fatal("expected the follower to have signed the guarantee it countersigned")
+	}
+}
@@ -17,12 +17,13 @@ type jsonAdd struct {
 	Guarantee    Guarantee
 	LeftDeposit  *big.Int
 	AssetAddress common.Address
+	Fee          *big.Int `json:",omitempty"`
 }
 
 // MarshalJSON returns a JSON representation of the Add
 func (a Add) MarshalJSON() ([]byte, error) {
 	jsonA := jsonAdd{
-		a.Guarantee, a.LeftDeposit, a.AssetAddress,
+		a.Guarantee, a.LeftDeposit, a.AssetAddress, a.Fee,
 	}
 	return json.Marshal(jsonA)
 }
@@ -39,6 +40,7 @@ func (a *Add) UnmarshalJSON(data []byte) error {
 	a.Guarantee = jsonA.Guarantee
 	a.LeftDeposit = jsonA.LeftDeposit
 	a.AssetAddress = jsonA.AssetAddress
+	a.Fee = jsonA.Fee
 
 	return nil
 }
@@ -81,6 +83,7 @@ type jsonProposal struct {
 	LedgerID types.Destination
 	ToAdd    Add
 	ToRemove Remove
+	ToResize Resize
 }
 
 // MarshalJSON returns a JSON representation of the Proposal
@@ -102,6 +105,7 @@ func (p *Proposal) UnmarshalJSON(data []byte) error {
 	p.LedgerID = jsonP.LedgerID
 	p.ToAdd = jsonP.ToAdd
 	p.ToRemove = jsonP.ToRemove
+	p.ToResize = jsonP.ToResize
 
 	return nil
 }
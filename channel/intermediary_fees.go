@@ -0,0 +1,112 @@
+package channel
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// IntermediaryFee is the amount an intermediary of a virtual channel charges for locking its ledger funds as a guarantee.
+//
+// Fees are charged in the asset of the virtual channel, and are paid by Alice (participant 0) when the channel is funded:
+// each ledger channel along the path shifts the fees of the intermediaries to its right from its left participant to its
+// right participant, in the same update which adds the guarantee for the virtual channel. The fees are therefore part
+// of the supported outcome of the ledger channels, and are settled whether the guarantees are removed off chain or
+// reclaimed on chain. The outcome of the virtual channel, and Alice's balance in it, are unaffected.
+type IntermediaryFee struct {
+	Intermediary types.Address
+	Amount       *big.Int
+}
+
+// intermediaryFeesTy describes the shape of a list of IntermediaryFees, so that the abi encoder knows how to encode it
+var intermediaryFeesTy, _ = abi.NewType("tuple[]", "struct IntermediaryFee[]", []abi.ArgumentMarshaling{
+	{Name: "Intermediary", Type: "address"},
+	{Name: "Amount", Type: "uint256"},
+})
+
+// EncodeIntermediaryFees returns the abi encoded fees, suitable for the AppData of the initial state of a virtual channel.
+// No fees are encoded as empty AppData.
+func EncodeIntermediaryFees(fees []IntermediaryFee) (types.Bytes, error) {
+	if len(fees) == 0 {
+		return nil, nil
+	}
+
+	return abi.Arguments{{Type: intermediaryFeesTy}}.Pack(fees)
+}
+
+// rawIntermediaryFeesType is an alias to the type returned when using the github.com/ethereum/go-ethereum/accounts/abi Unpack method with intermediaryFeesTy
+type rawIntermediaryFeesType = []struct {
+	Intermediary common.Address "json:\"Intermediary\""
+	Amount       *big.Int       "json:\"Amount\""
+}
+
+// DecodeIntermediaryFees returns the fees from their abi encoding. Empty data decodes to no fees.
+func DecodeIntermediaryFees(data []byte) ([]IntermediaryFee, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	unpacked, err := abi.Arguments{{Type: intermediaryFeesTy}}.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode intermediary fees: %w", err)
+	}
+
+	raw := unpacked[0].(rawIntermediaryFeesType)
+	fees := make([]IntermediaryFee, len(raw))
+	for i, r := range raw {
+		fees[i] = IntermediaryFee{Intermediary: r.Intermediary, Amount: r.Amount}
+	}
+
+	return fees, nil
+}
+
+// IntermediaryFees returns the fees the intermediaries of the virtual channel charge, which are carried in the AppData of its initial state
+func (v *VirtualChannel) IntermediaryFees() ([]IntermediaryFee, error) {
+	return DecodeIntermediaryFees(v.PreFundState().AppData)
+}
+
+// ValidateIntermediaryFees checks that the fees are charged by distinct intermediaries of the virtual channel.
+// Whether the ledger channels can afford them is checked as the guarantees are added.
+func (v *VirtualChannel) ValidateIntermediaryFees(fees []IntermediaryFee) error {
+	intermediaries := v.Participants[1 : len(v.Participants)-1]
+	charged := make(map[types.Address]bool)
+
+	for _, fee := range fees {
+		isIntermediary := false
+		for _, intermediary := range intermediaries {
+			isIntermediary = isIntermediary || intermediary == fee.Intermediary
+		}
+		if !isIntermediary {
+			return fmt.Errorf("fee charged by %s, which is not an intermediary", fee.Intermediary)
+		}
+		if charged[fee.Intermediary] {
+			return fmt.Errorf("more than one fee charged by %s", fee.Intermediary)
+		}
+		charged[fee.Intermediary] = true
+
+		if fee.Amount == nil || fee.Amount.Sign() <= 0 {
+			return fmt.Errorf("fee charged by %s must be positive", fee.Intermediary)
+		}
+	}
+
+	return nil
+}
+
+// FeesToTheRightOf returns the total fee charged by the intermediaries of the virtual channel after the participant with the given index.
+// The ledger channel whose left participant has that index pays those fees to its right participant when the virtual channel is funded.
+func FeesToTheRightOf(participants []types.Address, fees []IntermediaryFee, index uint) *big.Int {
+	total := big.NewInt(0)
+	for _, fee := range fees {
+		for i := int(index) + 1; i < len(participants); i++ {
+			if participants[i] == fee.Intermediary {
+				total.Add(total, fee.Amount)
+				break
+			}
+		}
+	}
+
+	return total
+}
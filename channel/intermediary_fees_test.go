@@ -0,0 +1,49 @@
+package channel
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/go-cmp/cmp"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestIntermediaryFees(t *testing.T) {
+	alice := common.HexToAddress("0xaaaa")
+	irene := common.HexToAddress("0x1111")
+	ivan := common.HexToAddress("0x2222")
+	bob := common.HexToAddress("0xbbbb")
+	participants := []types.Address{alice, irene, ivan, bob}
+
+	fees := []IntermediaryFee{
+		{Intermediary: irene, Amount: big.NewInt(3)},
+		{Intermediary: ivan, Amount: big.NewInt(2)},
+	}
+
+	encoded, err := EncodeIntermediaryFees(fees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeIntermediaryFees(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(fees, decoded, cmp.AllowUnexported(big.Int{})); diff != "" {
+		t.Fatalf("DecodeIntermediaryFees: mismatch (-want +got):\n%s", diff)
+	}
+
+	if encoded, _ := EncodeIntermediaryFees(nil); encoded != nil {
+		t.Fatalf("expected no fees to encode as empty AppData, got %x", encoded)
+	}
+	if decoded, err := DecodeIntermediaryFees(nil); err != nil || decoded != nil {
+		t.Fatalf("expected empty AppData to decode as no fees, got %v, %v", decoded, err)
+	}
+
+	// The ledger between Alice and Irene pays both fees, the ledger between Irene and Ivan pays Ivan's, and the ledger between Ivan and Bob pays none
+	for leftIndex, want := range []int64{5, 2, 0} {
+		if got := FeesToTheRightOf(participants, fees, uint(leftIndex)); got.Cmp(big.NewInt(want)) != 0 {
+			t.Errorf("FeesToTheRightOf(%d): expected %d, got %s", leftIndex, want, got)
+		}
+	}
+}
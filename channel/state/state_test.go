@@ -144,6 +144,12 @@ func TestClone(t *testing.T) {
 	if TestState.ChannelNonce != 37140676580 || TestState.Outcome[0].Allocations[0].Amount.Cmp(big.NewInt(5)) != 0 {
 		t.Fatalf(`State.Clone(): original is modified when clone is modified `)
 	}
+
+	withAppData := TestState.Clone()
+	withAppData.AppData = types.Bytes{0x01, 0x02, 0x03}
+	if diff := cmp.Diff(withAppData, withAppData.Clone()); diff != "" {
+		t.Fatalf("Clone: AppData mismatch (-want +got):\n%s", diff)
+	}
 }
 
 func TestRecoverSigner(t *testing.T) {
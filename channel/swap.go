@@ -64,6 +64,11 @@ func (v *SwapChannel) AddSignedSwapChannelState(ss state.SignedState) bool {
 
 // LatestSupportedSwapChannelState fetches the lalest supported swap channel state
 func (v *SwapChannel) LatestSupportedSwapChannelState() state.State {
+	return v.LatestSupportedSignedSwapChannelState().State()
+}
+
+// LatestSupportedSignedSwapChannelState fetches the latest supported swap channel state along with its signatures
+func (v *SwapChannel) LatestSupportedSignedSwapChannelState() state.SignedState {
 	latestTurn := v.Channel.OffChain.LatestSupportedStateTurnNum
 
 	for turnNum, ss := range v.Channel.OffChain.SignedStateForTurnNum {
@@ -72,6 +77,5 @@ func (v *SwapChannel) LatestSupportedSwapChannelState() state.State {
 		}
 	}
 
-	ss := v.Channel.OffChain.SignedStateForTurnNum[latestTurn]
-	return ss.State()
+	return v.Channel.OffChain.SignedStateForTurnNum[latestTurn]
 }
@@ -5,11 +5,15 @@ import (
 	"log"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/statechannels/go-nitro/bridge"
 	"github.com/statechannels/go-nitro/cmd/utils"
 	"github.com/statechannels/go-nitro/internal/logging"
 	"github.com/statechannels/go-nitro/internal/rpc"
+	"github.com/statechannels/go-nitro/node/engine"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
 	"github.com/statechannels/go-nitro/paymentsmanager"
 	"github.com/urfave/cli/v2"
 	"github.com/urfave/cli/v2/altsrc"
@@ -20,13 +24,24 @@ const (
 
 	L1_CHAIN_URL         = "l1chainurl"
 	L1_CHAIN_START_BLOCK = "l1chainstartblock"
+	L2_CHAIN_URL         = "l2chainurl"
+	L2_CHAIN_START_BLOCK = "l2chainstartblock"
+
+	L1_CHAIN_CONFIRMATIONS = "l1chainconfirmations"
+	L1_CHAIN_USE_FINALIZED = "l1chainusefinalized"
+	L2_CHAIN_CONFIRMATIONS = "l2chainconfirmations"
+	L2_CHAIN_USE_FINALIZED = "l2chainusefinalized"
 
 	CHAIN_PK         = "chainpk"
+	L2_CHAIN_PK      = "l2chainpk"
 	STATE_CHANNEL_PK = "statechannelpk"
 
 	NA_ADDRESS         = "naaddress"
+	L2_NA_ADDRESS      = "l2naaddress"
 	VPA_ADDRESS        = "vpaaddress"
+	L2_VPA_ADDRESS     = "l2vpaaddress"
 	CA_ADDRESS         = "caaddress"
+	L2_CA_ADDRESS      = "l2caaddress"
 	BRIDGE_ADDRESS     = "bridgeaddress"
 	ASSET_MAP_FILEPATH = "assetmapfilepath"
 
@@ -44,6 +59,15 @@ const (
 
 	TLS_CERT_FILEPATH = "tlscertfilepath"
 	TLS_KEY_FILEPATH  = "tlskeyfilepath"
+
+	OBJECTIVE_DEADLINES       = "objectivedeadlines"
+	MANUAL_COUNTER_CHALLENGES = "manualcounterchallenges"
+
+	TX_MAX_RETRIES       = "txmaxretries"
+	TX_RETRY_BACKOFF     = "txretrybackoff"
+	TX_RETRY_MAX_BACKOFF = "txretrymaxbackoff"
+	TX_FEE_BUMP          = "txfeebump"
+	TX_REPLACE_BY_NONCE  = "txreplacebynonce"
 )
 
 const (
@@ -52,11 +76,17 @@ const (
 )
 
 func main() {
-	var l1chainurl, chainpk, statechannelpk, naaddress, vpaaddress, caaddress, bridgeaddress, durableStoreDir, bridgepublicip, nodel1ExtMultiAddr, nodel2ExtMultiAddr string
+	var l1chainurl, l2chainurl, chainpk, l2chainpk, statechannelpk, naaddress, l2naaddress, vpaaddress, l2vpaaddress, caaddress, l2caaddress, bridgeaddress, durableStoreDir, bridgepublicip, nodel1ExtMultiAddr, nodel2ExtMultiAddr string
 	var nodel1msgport, nodel2msgport, rpcport int
-	var l1chainstartblock uint64
+	var l1chainstartblock, l2chainstartblock, l1chainconfirmations, l2chainconfirmations uint64
+	var l1chainusefinalized, l2chainusefinalized bool
+	var txmaxretries uint
+	var txretrybackoff, txretrymaxbackoff time.Duration
+	var txfeebump float64
+	var txreplacebynonce bool
 
-	var tlscertfilepath, tlskeyfilepath string
+	var tlscertfilepath, tlskeyfilepath, assetmapfilepath string
+	var objectiveDeadlines, manualCounterChallenges string
 
 	// urfave default precedence for flag value sources (highest to lowest):
 	// 1. Command line flag value
@@ -79,35 +109,68 @@ func main() {
 			Usage:       "Specifies the chain URL of L1",
 			Destination: &l1chainurl,
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        L2_CHAIN_URL,
+			Usage:       "Specifies the chain URL of L2",
+			Destination: &l2chainurl,
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:        CHAIN_PK,
 			Usage:       "Specifies the chain private key of bridge",
 			Destination: &chainpk,
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        L2_CHAIN_PK,
+			Usage:       "Specifies the chain private key of bridge on L2",
+			Destination: &l2chainpk,
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:        NA_ADDRESS,
 			Usage:       "Specifies the nitro adjudicator contract address",
 			Destination: &naaddress,
 			EnvVars:     []string{"NA_ADDRESS"},
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        L2_NA_ADDRESS,
+			Usage:       "Specifies the nitro adjudicator contract address on L2",
+			Destination: &l2naaddress,
+			EnvVars:     []string{"L2_NA_ADDRESS"},
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:        CA_ADDRESS,
 			Usage:       "Specifies the consensus app contract address",
 			Destination: &caaddress,
 			EnvVars:     []string{"CA_ADDRESS"},
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        L2_CA_ADDRESS,
+			Usage:       "Specifies the consensus app contract address on L2",
+			Destination: &l2caaddress,
+			EnvVars:     []string{"L2_CA_ADDRESS"},
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:        VPA_ADDRESS,
 			Usage:       "Specifies the virtual payment app contract address",
 			Destination: &vpaaddress,
 			EnvVars:     []string{"VPA_ADDRESS"},
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        L2_VPA_ADDRESS,
+			Usage:       "Specifies the virtual payment app contract address on L2",
+			Destination: &l2vpaaddress,
+			EnvVars:     []string{"L2_VPA_ADDRESS"},
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:        BRIDGE_ADDRESS,
 			Usage:       "Specifies the bridge contract address",
 			Destination: &bridgeaddress,
 			EnvVars:     []string{"BRIDGE_ADDRESS"},
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        ASSET_MAP_FILEPATH,
+			Usage:       "Filepath to the TOML file mapping L1 assets to L2 assets. Relative paths are resolved from the directory of the config file.",
+			Destination: &assetmapfilepath,
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:        DURABLE_STORE_DIR,
 			Usage:       "Specifies the durable store location of nodes",
@@ -156,6 +219,36 @@ func main() {
 			Value:       0,
 			Destination: &l1chainstartblock,
 		}),
+		altsrc.NewUint64Flag(&cli.Uint64Flag{
+			Name:        L2_CHAIN_START_BLOCK,
+			Usage:       "Specifies the block number to start looking for nitro adjudicator events of nodeL2",
+			Value:       0,
+			Destination: &l2chainstartblock,
+		}),
+		altsrc.NewUint64Flag(&cli.Uint64Flag{
+			Name:        L1_CHAIN_CONFIRMATIONS,
+			Usage:       "Specifies how many blocks must be mined on L1 before a chain event is processed. If not specified, the preset for the chain ID is used.",
+			Value:       0,
+			Destination: &l1chainconfirmations,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:        L1_CHAIN_USE_FINALIZED,
+			Usage:       "Specifies whether L1 chain events are processed once their block is finalized according to the `finalized` block tag, instead of after a number of confirmations.",
+			Value:       false,
+			Destination: &l1chainusefinalized,
+		}),
+		altsrc.NewUint64Flag(&cli.Uint64Flag{
+			Name:        L2_CHAIN_CONFIRMATIONS,
+			Usage:       "Specifies how many blocks must be mined on L2 before a chain event is processed. If not specified, the preset for the chain ID is used.",
+			Value:       0,
+			Destination: &l2chainconfirmations,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:        L2_CHAIN_USE_FINALIZED,
+			Usage:       "Specifies whether L2 chain events are processed once their block is finalized according to the `finalized` block tag, instead of after a number of confirmations.",
+			Value:       false,
+			Destination: &l2chainusefinalized,
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:        TLS_CERT_FILEPATH,
 			Usage:       "Filepath to the TLS certificate. If not specified, TLS will not be used with the RPC transport.",
@@ -168,6 +261,48 @@ func main() {
 			Value:       "./tls/statechannels.org_key.pem",
 			Destination: &tlskeyfilepath,
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        OBJECTIVE_DEADLINES,
+			Usage:       "Comma-delimited list of <objective type>=<duration> pairs, setting how long objectives of that type may take before they are abandoned by either node. Objectives are never abandoned by default, nor once their guarantees are signed. A duration of 0 disables the deadline.",
+			Value:       "",
+			Destination: &objectiveDeadlines,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        MANUAL_COUNTER_CHALLENGES,
+			Usage:       "Comma-delimited list of channel types whose challenges registered with a stale state are not countered automatically by either node. Only ledger channels are countered automatically, so only ledger may be listed.",
+			Value:       "",
+			Destination: &manualCounterChallenges,
+		}),
+		altsrc.NewUintFlag(&cli.UintFlag{
+			Name:        TX_MAX_RETRIES,
+			Usage:       "Specifies how many times a dropped transaction is resubmitted on either chain. If not specified, the default retry policy is used.",
+			Value:       0,
+			Destination: &txmaxretries,
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:        TX_RETRY_BACKOFF,
+			Usage:       "Specifies the delay, such as 10s, before a dropped transaction is first resubmitted. It doubles on every subsequent resubmission.",
+			Value:       0,
+			Destination: &txretrybackoff,
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:        TX_RETRY_MAX_BACKOFF,
+			Usage:       "Specifies the maximum delay before a dropped transaction is resubmitted.",
+			Value:       0,
+			Destination: &txretrymaxbackoff,
+		}),
+		altsrc.NewFloat64Flag(&cli.Float64Flag{
+			Name:        TX_FEE_BUMP,
+			Usage:       "Specifies the multiplier applied to the fees of a dropped transaction when it is resubmitted.",
+			Value:       0,
+			Destination: &txfeebump,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:        TX_REPLACE_BY_NONCE,
+			Usage:       "Specifies whether a dropped transaction is resubmitted with the nonce of the transaction it replaces, so that at most one of them is mined.",
+			Value:       false,
+			Destination: &txreplacebynonce,
+		}),
 	}
 
 	app := &cli.App{
@@ -177,16 +312,80 @@ func main() {
 		Before: altsrc.InitInputSourceWithContext(flags, altsrc.NewTomlSourceFromFlagFunc(CONFIG)),
 		Action: func(cCtx *cli.Context) error {
 			chainpk = utils.TrimHexPrefix(chainpk)
+			l2chainpk = utils.TrimHexPrefix(l2chainpk)
 			statechannelpk = utils.TrimHexPrefix(statechannelpk)
 
+			var assets []bridge.AssetMapEntry
+			if assetmapfilepath != "" {
+				if !filepath.IsAbs(assetmapfilepath) && cCtx.String(CONFIG) != "" {
+					assetmapfilepath = filepath.Join(filepath.Dir(cCtx.String(CONFIG)), assetmapfilepath)
+				}
+
+				var err error
+				assets, err = bridge.LoadAssetMap(assetmapfilepath)
+				if err != nil {
+					return err
+				}
+			}
+
+			var l1ConfirmationPolicy, l2ConfirmationPolicy chainservice.ConfirmationPolicyConfig
+			if cCtx.IsSet(L1_CHAIN_CONFIRMATIONS) {
+				l1ConfirmationPolicy.RequiredBlockConfirmations = &l1chainconfirmations
+			}
+			if cCtx.IsSet(L1_CHAIN_USE_FINALIZED) {
+				l1ConfirmationPolicy.UseFinalizedTag = &l1chainusefinalized
+			}
+			if cCtx.IsSet(L2_CHAIN_CONFIRMATIONS) {
+				l2ConfirmationPolicy.RequiredBlockConfirmations = &l2chainconfirmations
+			}
+			if cCtx.IsSet(L2_CHAIN_USE_FINALIZED) {
+				l2ConfirmationPolicy.UseFinalizedTag = &l2chainusefinalized
+			}
+
+			// The retry policy fields which are not set are taken from the default retry policy
+			retryPolicy := chainservice.RetryPolicy{}
+			if cCtx.IsSet(TX_MAX_RETRIES) || cCtx.IsSet(TX_RETRY_BACKOFF) || cCtx.IsSet(TX_RETRY_MAX_BACKOFF) || cCtx.IsSet(TX_FEE_BUMP) || cCtx.IsSet(TX_REPLACE_BY_NONCE) {
+				retryPolicy = chainservice.DefaultRetryPolicy()
+			}
+			if cCtx.IsSet(TX_MAX_RETRIES) {
+				retryPolicy.MaxAttempts = txmaxretries
+			}
+			if cCtx.IsSet(TX_RETRY_BACKOFF) {
+				retryPolicy.Backoff = txretrybackoff
+			}
+			if cCtx.IsSet(TX_RETRY_MAX_BACKOFF) {
+				retryPolicy.MaxBackoff = txretrymaxbackoff
+			}
+			if cCtx.IsSet(TX_FEE_BUMP) {
+				retryPolicy.FeeBumpMultiplier = txfeebump
+			}
+			if cCtx.IsSet(TX_REPLACE_BY_NONCE) {
+				retryPolicy.ReplaceByNonce = txreplacebynonce
+			}
+
+			deadlines, err := engine.ParseObjectiveDeadlines(objectiveDeadlines)
+			if err != nil {
+				return err
+			}
+			autoCounterChallenge, err := engine.ParseManualCounterChallenges(manualCounterChallenges)
+			if err != nil {
+				return err
+			}
+
 			bridgeConfig := bridge.BridgeConfig{
 				L1ChainUrl:         l1chainurl,
 				L1ChainStartBlock:  l1chainstartblock,
+				L2ChainUrl:         l2chainurl,
+				L2ChainStartBlock:  l2chainstartblock,
 				ChainPK:            chainpk,
 				StateChannelPK:     statechannelpk,
 				NaAddress:          naaddress,
 				VpaAddress:         vpaaddress,
 				CaAddress:          caaddress,
+				L2ChainPK:          l2chainpk,
+				L2NaAddress:        l2naaddress,
+				L2VpaAddress:       l2vpaaddress,
+				L2CaAddress:        l2caaddress,
 				BridgeAddress:      bridgeaddress,
 				DurableStoreDir:    durableStoreDir,
 				BridgePublicIp:     bridgepublicip,
@@ -194,6 +393,12 @@ func main() {
 				NodeL2ExtMultiAddr: nodel2ExtMultiAddr,
 				NodeL1MsgPort:      nodel1msgport,
 				NodeL2MsgPort:      nodel2msgport,
+				Assets:             assets,
+
+				L1ConfirmationPolicy: l1ConfirmationPolicy,
+				L2ConfirmationPolicy: l2ConfirmationPolicy,
+				RetryPolicy:          retryPolicy,
+				EngineOpts:           engine.EngineOpts{ObjectiveDeadlines: deadlines, AutoCounterChallenge: autoCounterChallenge},
 			}
 
 			logging.SetupDefaultLogger(os.Stdout, slog.LevelDebug)
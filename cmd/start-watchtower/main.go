@@ -0,0 +1,170 @@
+package main
+
+import (
+	"crypto/tls"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/cmd/utils"
+	"github.com/statechannels/go-nitro/internal/logging"
+	nodeutils "github.com/statechannels/go-nitro/internal/node"
+	"github.com/statechannels/go-nitro/internal/rpc"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
+)
+
+const (
+	CONFIG = "config"
+
+	CHAIN_URL         = "chainurl"
+	CHAIN_AUTH_TOKEN  = "chainauthtoken"
+	CHAIN_START_BLOCK = "chainstartblock"
+	CHAIN_PK          = "chainpk"
+
+	NA_ADDRESS  = "naaddress"
+	VPA_ADDRESS = "vpaaddress"
+	CA_ADDRESS  = "caaddress"
+
+	DURABLE_STORE_DIR = "durablestorefolder"
+
+	RPC_PORT = "rpcport"
+
+	TLS_CERT_FILEPATH = "tlscertfilepath"
+	TLS_KEY_FILEPATH  = "tlskeyfilepath"
+)
+
+func main() {
+	var chainurl, chainauthtoken, chainpk, naaddress, vpaaddress, caaddress, durableStoreDir string
+	var rpcport int
+	var chainstartblock uint64
+
+	var tlscertfilepath, tlskeyfilepath string
+
+	// urfave default precedence for flag value sources (highest to lowest):
+	// 1. Command line flag value
+	// 2. Environment variable (if specified)
+	// 3. Configuration file (if specified)
+	// 4. Default defined on the flag
+
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  CONFIG,
+			Usage: "Load config options from `config.toml`",
+		},
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        CHAIN_URL,
+			Usage:       "Specifies the url of a RPC endpoint for the chain.",
+			Value:       "ws://127.0.0.1:8545",
+			Destination: &chainurl,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        CHAIN_AUTH_TOKEN,
+			Usage:       "The bearer token used for auth when making requests to the chain's RPC endpoint.",
+			Destination: &chainauthtoken,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        CHAIN_PK,
+			Usage:       "Specifies the private key of the account used by the watchtower to submit transactions.",
+			Destination: &chainpk,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        NA_ADDRESS,
+			Usage:       "Specifies the nitro adjudicator contract address",
+			Destination: &naaddress,
+			EnvVars:     []string{"NA_ADDRESS"},
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        CA_ADDRESS,
+			Usage:       "Specifies the consensus app contract address",
+			Destination: &caaddress,
+			EnvVars:     []string{"CA_ADDRESS"},
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        VPA_ADDRESS,
+			Usage:       "Specifies the virtual payment app contract address",
+			Destination: &vpaaddress,
+			EnvVars:     []string{"VPA_ADDRESS"},
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        DURABLE_STORE_DIR,
+			Usage:       "Specifies the location of the durable store of the watchtower",
+			Destination: &durableStoreDir,
+			Value:       "./data/watchtower-store",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:        RPC_PORT,
+			Usage:       "Specifies the tcp port for the rpc server.",
+			Value:       4008,
+			Destination: &rpcport,
+		}),
+		altsrc.NewUint64Flag(&cli.Uint64Flag{
+			Name:        CHAIN_START_BLOCK,
+			Usage:       "Specifies the block number to start looking for nitro adjudicator events",
+			Value:       0,
+			Destination: &chainstartblock,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        TLS_CERT_FILEPATH,
+			Usage:       "Filepath to the TLS certificate. If not specified, TLS will not be used with the RPC transport.",
+			Value:       "./tls/statechannels.org.pem",
+			Destination: &tlscertfilepath,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        TLS_KEY_FILEPATH,
+			Usage:       "Filepath to the TLS private key. If not specified, TLS will not be used with the RPC transport.",
+			Value:       "./tls/statechannels.org_key.pem",
+			Destination: &tlskeyfilepath,
+		}),
+	}
+
+	app := &cli.App{
+		Name:   "watchtower",
+		Usage:  "Defends the channels registered by nitro nodes on chain while the nodes are offline.",
+		Flags:  flags,
+		Before: altsrc.InitInputSourceWithContext(flags, altsrc.NewTomlSourceFromFlagFunc(CONFIG)),
+		Action: func(cCtx *cli.Context) error {
+			chainOpts := chainservice.ChainOpts{
+				ChainUrl:           chainurl,
+				ChainStartBlockNum: chainstartblock,
+				ChainAuthToken:     chainauthtoken,
+				ChainPk:            utils.TrimHexPrefix(chainpk),
+				NaAddress:          common.HexToAddress(naaddress),
+				VpaAddress:         common.HexToAddress(vpaaddress),
+				CaAddress:          common.HexToAddress(caaddress),
+			}
+
+			logging.SetupDefaultLogger(os.Stdout, slog.LevelDebug)
+
+			watchtower, err := nodeutils.InitializeWatchtower(chainOpts, durableStoreDir)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var cert tls.Certificate
+
+			if tlscertfilepath != "" && tlskeyfilepath != "" {
+				cert, err = tls.LoadX509KeyPair(tlscertfilepath, tlskeyfilepath)
+				if err != nil {
+					panic(err)
+				}
+			}
+
+			rpcServer, err := rpc.InitializeWatchtowerRpcServer(watchtower, rpcport, false, &cert)
+			if err != nil {
+				return err
+			}
+
+			slog.Info("Watchtower started", "address", watchtower.GetAddress())
+			utils.WaitForKillSignal()
+
+			return rpcServer.Close()
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -11,7 +11,7 @@ import (
 	"github.com/statechannels/go-nitro/node/engine/store"
 )
 
-func InitializeL2Node(chainOpts chainservice.LaconicdChainOpts, storeOpts store.StoreOpts, messageOpts p2pms.MessageOpts) (*node.Node, *store.Store, *p2pms.P2PMessageService, chainservice.ChainService, error) {
+func InitializeL2Node(chainOpts chainservice.LaconicdChainOpts, storeOpts store.StoreOpts, messageOpts p2pms.MessageOpts, policymaker engine.PolicyMaker, engineOpts engine.EngineOpts) (*node.Node, *store.Store, *p2pms.P2PMessageService, chainservice.ChainService, error) {
 	ourStore, err := store.NewStore(storeOpts)
 	if err != nil {
 		return nil, nil, nil, nil, err
@@ -21,18 +21,39 @@ func InitializeL2Node(chainOpts chainservice.LaconicdChainOpts, storeOpts store.
 	messageOpts.SCAddr = *ourStore.GetAddress()
 	messageService := p2pms.NewMessageService(messageOpts)
 
-	// TODO: Implement laconicd chain service
+	// The chain service resumes after the last chain event handled by this node. Older stores only have the
+	// lastBlockNum seen, which is compared to chainOpts.ChainStartBlock. The larger of the two
+	// gets passed as an argument when creating NewLaconicdChainService
+	chainOpts.EventCursor, err = ourStore.GetEventCursor()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if chainOpts.EventCursor.IsZero() {
+		storeBlockNum, err := ourStore.GetLastBlockNumSeen()
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if storeBlockNum > chainOpts.ChainStartBlockNum {
+			chainOpts.ChainStartBlockNum = storeBlockNum
+		}
+	}
+
+	slog.Info("Initializing laconicd chain service...")
 	ourChain, err := chainservice.NewLaconicdChainService(chainOpts)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
 
-	node := node.New(
+	node, err := node.New(
 		messageService,
 		ourChain,
 		ourStore,
-		&engine.PermissivePolicy{},
+		policymaker,
+		engineOpts,
 	)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	return &node, &ourStore, messageService, ourChain, nil
 }
@@ -11,7 +11,7 @@ import (
 	p2pms "github.com/statechannels/go-nitro/node/engine/messageservice/p2p-message-service"
 )
 
-func InitializeNode(chainOpts chainservice.ChainOpts, storeOpts store.StoreOpts, messageOpts p2pms.MessageOpts, policymaker engine.PolicyMaker) (*node.Node, *store.Store, *p2pms.P2PMessageService, chainservice.ChainService, error) {
+func InitializeNode(chainOpts chainservice.ChainOpts, storeOpts store.StoreOpts, messageOpts p2pms.MessageOpts, policymaker engine.PolicyMaker, engineOpts engine.EngineOpts) (*node.Node, *store.Store, *p2pms.P2PMessageService, chainservice.ChainService, error) {
 	ourStore, err := store.NewStore(storeOpts)
 	if err != nil {
 		return nil, nil, nil, nil, err
@@ -21,14 +21,21 @@ func InitializeNode(chainOpts chainservice.ChainOpts, storeOpts store.StoreOpts,
 	messageOpts.SCAddr = *ourStore.GetAddress()
 	messageService := p2pms.NewMessageService(messageOpts)
 
-	// Compare chainOpts.ChainStartBlock to lastBlockNum seen in store. The larger of the two
+	// The chain service resumes after the last chain event handled by this node. Older stores only have the
+	// lastBlockNum seen, which is compared to chainOpts.ChainStartBlock. The larger of the two
 	// gets passed as an argument when creating NewEthChainService
-	storeBlockNum, err := ourStore.GetLastBlockNumSeen()
+	chainOpts.EventCursor, err = ourStore.GetEventCursor()
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
-	if storeBlockNum > chainOpts.ChainStartBlockNum {
-		chainOpts.ChainStartBlockNum = storeBlockNum
+	if chainOpts.EventCursor.IsZero() {
+		storeBlockNum, err := ourStore.GetLastBlockNumSeen()
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if storeBlockNum > chainOpts.ChainStartBlockNum {
+			chainOpts.ChainStartBlockNum = storeBlockNum
+		}
 	}
 
 	slog.Info("Initializing chain service...")
@@ -37,12 +44,16 @@ func InitializeNode(chainOpts chainservice.ChainOpts, storeOpts store.StoreOpts,
 		return nil, nil, nil, nil, err
 	}
 
-	node := node.New(
+	node, err := node.New(
 		messageService,
 		ourChain,
 		ourStore,
 		policymaker,
+		engineOpts,
 	)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	return &node, &ourStore, messageService, ourChain, nil
 }
@@ -0,0 +1,37 @@
+package node
+
+import (
+	"log/slog"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/crypto"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/watchtower"
+	"github.com/tidwall/buntdb"
+)
+
+func InitializeWatchtower(chainOpts chainservice.ChainOpts, durableStoreFolder string) (*watchtower.Watchtower, error) {
+	watchtowerStore, err := watchtower.NewDurableStore(durableStoreFolder, buntdb.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare chainOpts.ChainStartBlock to lastBlockNum seen in store. The larger of the two
+	// gets passed as an argument when creating NewEthChainService
+	storeBlockNum, err := watchtowerStore.GetLastBlockNumSeen()
+	if err != nil {
+		return nil, err
+	}
+	if storeBlockNum > chainOpts.ChainStartBlockNum {
+		chainOpts.ChainStartBlockNum = storeBlockNum
+	}
+
+	slog.Info("Initializing chain service...")
+	ourChain, err := chainservice.NewEthChainService(chainOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	address := crypto.GetAddressFromSecretKeyBytes(common.Hex2Bytes(chainOpts.ChainPk))
+	return watchtower.New(address, ourChain, watchtowerStore), nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/statechannels/go-nitro/rpc/transport"
 	httpTransport "github.com/statechannels/go-nitro/rpc/transport/http"
 	"github.com/statechannels/go-nitro/rpc/transport/nats"
+	"github.com/statechannels/go-nitro/watchtower"
 )
 
 func InitializeNodeRpcServer(node *node.Node, paymentManager paymentsmanager.PaymentsManager, rpcPort int, useNats bool, cert *tls.Certificate) (*rpc.NodeRpcServer, error) {
@@ -44,6 +45,21 @@ func InitializeBridgeRpcServer(bridge *bridge.Bridge, rpcPort int, useNats bool,
 	return rpcServer, nil
 }
 
+func InitializeWatchtowerRpcServer(watchtower *watchtower.Watchtower, rpcPort int, useNats bool, cert *tls.Certificate) (*rpc.WatchtowerRpcServer, error) {
+	transport, err := initializeTransport(rpcPort, useNats, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcServer, err := rpc.NewWatchtowerRpcServer(watchtower, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Completed RPC server initialization", "url", rpcServer.Url())
+	return rpcServer, nil
+}
+
 func initializeTransport(rpcPort int, useNats bool, cert *tls.Certificate) (transport.Responder, error) {
 	var transport transport.Responder
 	var err error
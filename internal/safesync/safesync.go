@@ -34,6 +34,17 @@ func (o *Map[T]) Delete(key string) {
 	o.m.Delete(key)
 }
 
+// LoadAndDelete deletes the value for a key, returning the previous value if any.
+// The loaded result reports whether the key was present.
+func (o *Map[T]) LoadAndDelete(key string) (value T, loaded bool) {
+	data, loaded := o.m.LoadAndDelete(key)
+	if !loaded {
+		return value, false
+	}
+
+	return data.(T), true
+}
+
 // Range calls f sequentially for each key and value present in the map.
 // If f returns false, range stops the iteration.
 //
@@ -75,6 +75,17 @@ var Irene Actor = Actor{
 	WS_START_PORT + 3,
 }
 
+// Brian has the address 0xB1A45D021bEB3b51870De2B1DA52074a5C9CD563
+// peerId: 16Uiu2HAmEcxajPKsUf9JTPYb4TB7s8meaDjejkaCYJeqyKDW2KUs
+var Brian Actor = Actor{
+	common.Hex2Bytes(`29377ff9178d5bc3c93346587a2ea5042793ea050fead56255bffd053a29f031`),
+	3,
+	"brian",
+	4,
+	START_PORT + 6,
+	WS_START_PORT + 6,
+}
+
 // Actors for L2
 
 // AlicePrime has the address 0xAAA6628Ec44A8a742987EF3A114dDFE2D4F7aDCE
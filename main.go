@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"log"
 	"log/slog"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/statechannels/go-nitro/cmd/utils"
@@ -35,6 +37,9 @@ func main() {
 		CHAIN_URL             = "chainurl"
 		CHAIN_START_BLOCK     = "chainstartblock"
 		CHAIN_AUTH_TOKEN      = "chainauthtoken"
+		CHAIN_POLL_INTERVAL   = "chainpollinterval"
+		CHAIN_CONFIRMATIONS   = "chainconfirmations"
+		CHAIN_USE_FINALIZED   = "chainusefinalized"
 		NA_ADDRESS            = "naaddress"
 		VPA_ADDRESS           = "vpaaddress"
 		CA_ADDRESS            = "caaddress"
@@ -62,11 +67,30 @@ func main() {
 		TLS_CATEGORY      = "TLS:"
 		TLS_CERT_FILEPATH = "tlscertfilepath"
 		TLS_KEY_FILEPATH  = "tlskeyfilepath"
+
+		// Objectives
+		OBJECTIVES_CATEGORY = "Objectives:"
+		OBJECTIVE_DEADLINES = "objectivedeadlines"
+
+		// Challenges
+		CHALLENGES_CATEGORY       = "Challenges:"
+		MANUAL_COUNTER_CHALLENGES = "manualcounterchallenges"
+
+		// Transactions
+		TRANSACTIONS_CATEGORY = "Transactions:"
+		TX_MAX_RETRIES        = "txmaxretries"
+		TX_RETRY_BACKOFF      = "txretrybackoff"
+		TX_RETRY_MAX_BACKOFF  = "txretrymaxbackoff"
+		TX_FEE_BUMP           = "txfeebump"
+		TX_REPLACE_BY_NONCE   = "txreplacebynonce"
 	)
-	var pkString, chainUrl, chainAuthToken, naAddress, vpaAddress, caAddress, bridgeAddress, chainPk, durableStoreFolder, bootPeers, publicIp, extMultiAddr string
+	var pkString, chainUrl, chainAuthToken, naAddress, vpaAddress, caAddress, bridgeAddress, chainPk, durableStoreFolder, bootPeers, publicIp, extMultiAddr, objectiveDeadlines, manualCounterChallenges string
 	var msgPort, wsMsgPort, rpcPort, guiPort int
-	var chainStartBlock uint64
-	var useNats, useDurableStore, l2 bool
+	var chainStartBlock, chainConfirmations uint64
+	var chainPollInterval, txRetryBackoff, txRetryMaxBackoff time.Duration
+	var useNats, useDurableStore, l2, chainUseFinalized, txReplaceByNonce bool
+	var txMaxRetries uint
+	var txFeeBump float64
 
 	var tlsCertFilepath, tlsKeyFilepath string
 
@@ -142,6 +166,27 @@ func main() {
 			Destination: &chainStartBlock,
 			EnvVars:     []string{"CHAIN_START_BLOCK"},
 		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:        CHAIN_POLL_INTERVAL,
+			Usage:       "Specifies an interval, such as 5s, at which to poll the chain with eth_getLogs and eth_blockNumber instead of subscribing to it. Use for RPC endpoints which do not support subscriptions.",
+			Value:       0,
+			Category:    CONNECTIVITY_CATEGORY,
+			Destination: &chainPollInterval,
+		}),
+		altsrc.NewUint64Flag(&cli.Uint64Flag{
+			Name:        CHAIN_CONFIRMATIONS,
+			Usage:       "Specifies how many blocks must be mined before a chain event is processed. If not specified, the preset for the chain ID is used.",
+			Value:       0,
+			Category:    CONNECTIVITY_CATEGORY,
+			Destination: &chainConfirmations,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:        CHAIN_USE_FINALIZED,
+			Usage:       "Specifies whether chain events are processed once their block is finalized according to the `finalized` block tag, instead of after a number of confirmations.",
+			Value:       false,
+			Category:    CONNECTIVITY_CATEGORY,
+			Destination: &chainUseFinalized,
+		}),
 		altsrc.NewStringFlag(&cli.StringFlag{
 			Name:        NA_ADDRESS,
 			Usage:       "Specifies the address of the nitro adjudicator contract.",
@@ -237,6 +282,55 @@ func main() {
 			Category:    TLS_CATEGORY,
 			Destination: &tlsKeyFilepath,
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        OBJECTIVE_DEADLINES,
+			Usage:       "Comma-delimited list of <objective type>=<duration> pairs, setting how long objectives of that type may take before they are abandoned. Objectives are never abandoned by default, nor once their guarantees are signed. A duration of 0 disables the deadline.",
+			Value:       "",
+			Category:    OBJECTIVES_CATEGORY,
+			Destination: &objectiveDeadlines,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        MANUAL_COUNTER_CHALLENGES,
+			Usage:       "Comma-delimited list of channel types whose challenges registered with a stale state are not countered automatically, but left to be countered with the counter_challenge RPC. Only ledger channels are countered automatically, so only ledger may be listed.",
+			Value:       "",
+			Category:    CHALLENGES_CATEGORY,
+			Destination: &manualCounterChallenges,
+		}),
+		altsrc.NewUintFlag(&cli.UintFlag{
+			Name:        TX_MAX_RETRIES,
+			Usage:       "Specifies how many times a dropped transaction is resubmitted. If not specified, the default retry policy is used.",
+			Value:       0,
+			Category:    TRANSACTIONS_CATEGORY,
+			Destination: &txMaxRetries,
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:        TX_RETRY_BACKOFF,
+			Usage:       "Specifies the delay, such as 10s, before a dropped transaction is first resubmitted. It doubles on every subsequent resubmission.",
+			Value:       0,
+			Category:    TRANSACTIONS_CATEGORY,
+			Destination: &txRetryBackoff,
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:        TX_RETRY_MAX_BACKOFF,
+			Usage:       "Specifies the maximum delay before a dropped transaction is resubmitted.",
+			Value:       0,
+			Category:    TRANSACTIONS_CATEGORY,
+			Destination: &txRetryMaxBackoff,
+		}),
+		altsrc.NewFloat64Flag(&cli.Float64Flag{
+			Name:        TX_FEE_BUMP,
+			Usage:       "Specifies the multiplier applied to the fees of a dropped transaction when it is resubmitted.",
+			Value:       0,
+			Category:    TRANSACTIONS_CATEGORY,
+			Destination: &txFeeBump,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:        TX_REPLACE_BY_NONCE,
+			Usage:       "Specifies whether a dropped transaction is resubmitted with the nonce of the transaction it replaces, so that at most one of them is mined.",
+			Value:       false,
+			Category:    TRANSACTIONS_CATEGORY,
+			Destination: &txReplaceByNonce,
+		}),
 	}
 	app := &cli.App{
 		Name:   "go-nitro",
@@ -267,14 +361,92 @@ func main() {
 				ExtMultiAddr: extMultiAddr,
 			}
 
+			deadlines, err := engine.ParseObjectiveDeadlines(objectiveDeadlines)
+			if err != nil {
+				return err
+			}
+			autoCounterChallenge, err := engine.ParseManualCounterChallenges(manualCounterChallenges)
+			if err != nil {
+				return err
+			}
+			engineOpts := engine.EngineOpts{ObjectiveDeadlines: deadlines, AutoCounterChallenge: autoCounterChallenge}
+
+			// The policy is read from the [policy] table of the config file, and reloaded when the file changes
+			var policymaker engine.PolicyMaker = &engine.PermissivePolicy{}
+			if configFilePath := cCtx.String(CONFIG); configFilePath != "" {
+				rules, err := engine.LoadPolicyRules(configFilePath)
+				if err != nil {
+					return err
+				}
+				rulePolicy, err := engine.NewRulePolicy(rules)
+				if err != nil {
+					return err
+				}
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go rulePolicy.WatchFile(ctx, configFilePath)
+
+				policymaker = rulePolicy
+
+				// Swaps are accepted by the quotes in the [swapPolicy] table, and left to be confirmed through the API otherwise
+				swapRules, err := engine.LoadSwapPolicyRules(configFilePath)
+				if err != nil {
+					return err
+				}
+				quotePolicy, err := engine.NewQuotePolicy(swapRules)
+				if err != nil {
+					return err
+				}
+				go quotePolicy.WatchFile(ctx, configFilePath)
+
+				engineOpts.SwapPolicy = quotePolicy
+			}
+
+			confirmationPolicy := chainservice.ConfirmationPolicyConfig{}
+			if cCtx.IsSet(CHAIN_CONFIRMATIONS) {
+				confirmationPolicy.RequiredBlockConfirmations = &chainConfirmations
+			}
+			if cCtx.IsSet(CHAIN_USE_FINALIZED) {
+				confirmationPolicy.UseFinalizedTag = &chainUseFinalized
+			}
+
+			// The retry policy fields which are not set are taken from the default retry policy
+			retryPolicy := chainservice.RetryPolicy{}
+			if cCtx.IsSet(TX_MAX_RETRIES) || cCtx.IsSet(TX_RETRY_BACKOFF) || cCtx.IsSet(TX_RETRY_MAX_BACKOFF) || cCtx.IsSet(TX_FEE_BUMP) || cCtx.IsSet(TX_REPLACE_BY_NONCE) {
+				retryPolicy = chainservice.DefaultRetryPolicy()
+			}
+			if cCtx.IsSet(TX_MAX_RETRIES) {
+				retryPolicy.MaxAttempts = txMaxRetries
+			}
+			if cCtx.IsSet(TX_RETRY_BACKOFF) {
+				retryPolicy.Backoff = txRetryBackoff
+			}
+			if cCtx.IsSet(TX_RETRY_MAX_BACKOFF) {
+				retryPolicy.MaxBackoff = txRetryMaxBackoff
+			}
+			if cCtx.IsSet(TX_FEE_BUMP) {
+				retryPolicy.FeeBumpMultiplier = txFeeBump
+			}
+			if cCtx.IsSet(TX_REPLACE_BY_NONCE) {
+				retryPolicy.ReplaceByNonce = txReplaceByNonce
+			}
+
 			var node *node.Node
-			var err error
 			if l2 {
 				chainOpts := chainservice.LaconicdChainOpts{
-					VpaAddress: common.HexToAddress(vpaAddress),
-					CaAddress:  common.HexToAddress(caAddress),
+					ChainUrl:           chainUrl,
+					ChainStartBlockNum: chainStartBlock,
+					ChainAuthToken:     chainAuthToken,
+					ChainPk:            chainPk,
+					NaAddress:          common.HexToAddress(naAddress),
+					VpaAddress:         common.HexToAddress(vpaAddress),
+					CaAddress:          common.HexToAddress(caAddress),
+					PollInterval:       chainPollInterval,
+					ConfirmationPolicy: confirmationPolicy,
+					RetryPolicy:        retryPolicy,
 				}
-				node, _, _, _, err = nodeUtils.InitializeL2Node(chainOpts, storeOpts, messageOpts)
+				node, _, _, _, err = nodeUtils.InitializeL2Node(chainOpts, storeOpts, messageOpts, policymaker, engineOpts)
 			} else {
 				chainOpts := chainservice.ChainOpts{
 					ChainUrl:           chainUrl,
@@ -284,9 +456,12 @@ func main() {
 					NaAddress:          common.HexToAddress(naAddress),
 					VpaAddress:         common.HexToAddress(vpaAddress),
 					CaAddress:          common.HexToAddress(caAddress),
+					PollInterval:       chainPollInterval,
+					ConfirmationPolicy: confirmationPolicy,
+					RetryPolicy:        retryPolicy,
 				}
 
-				node, _, _, _, err = nodeUtils.InitializeNode(chainOpts, storeOpts, messageOpts, &engine.PermissivePolicy{})
+				node, _, _, _, err = nodeUtils.InitializeNode(chainOpts, storeOpts, messageOpts, policymaker, engineOpts)
 			}
 
 			logging.SetupDefaultLogger(os.Stdout, slog.LevelDebug)
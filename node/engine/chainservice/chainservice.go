@@ -19,6 +19,7 @@ type Event interface {
 	Block() Block
 	TxIndex() uint
 	TxHash() common.Hash
+	Cursor() EventCursor
 }
 
 // commonEvent declares fields shared by all chain events
@@ -27,6 +28,7 @@ type commonEvent struct {
 	block     Block
 	txIndex   uint
 	txHash    common.Hash
+	cursor    EventCursor
 }
 
 func (ce commonEvent) ChannelID() types.Destination {
@@ -45,6 +47,11 @@ func (ce commonEvent) TxHash() common.Hash {
 	return ce.txHash
 }
 
+// Cursor returns the position of the event in the chain, which is zero for events which were not read from an adjudicator log
+func (ce commonEvent) Cursor() EventCursor {
+	return ce.cursor
+}
+
 type assetAndAmount struct {
 	AssetAddress common.Address
 	AssetAmount  *big.Int
@@ -145,11 +152,11 @@ func (cr ChallengeRegisteredEvent) String() string {
 }
 
 func NewDepositedEvent(channelId types.Destination, block Block, txIndex uint, assetAddress common.Address, nowHeld *big.Int, txhash common.Hash) DepositedEvent {
-	return DepositedEvent{commonEvent{channelId, block, txIndex, txhash}, assetAddress, nowHeld}
+	return DepositedEvent{commonEvent{channelId, block, txIndex, txhash, EventCursor{}}, assetAddress, nowHeld}
 }
 
 func NewAllocationUpdatedEvent(channelId types.Destination, block Block, txIndex uint, assetAddress common.Address, assetAmount *big.Int, txhash common.Hash) AllocationUpdatedEvent {
-	return AllocationUpdatedEvent{commonEvent{channelId, block, txIndex, txhash}, assetAndAmount{AssetAddress: assetAddress, AssetAmount: assetAmount}}
+	return AllocationUpdatedEvent{commonEvent{channelId, block, txIndex, txhash, EventCursor{}}, assetAndAmount{AssetAddress: assetAddress, AssetAmount: assetAmount}}
 }
 
 type ChallengeClearedEvent struct {
@@ -208,6 +215,10 @@ type ChainService interface {
 	DroppedEventFeed() <-chan protocols.DroppedEventInfo
 	// SendTransaction is for sending transactions with the chain service
 	SendTransaction(protocols.ChainTransaction) (*ethTypes.Transaction, error)
+	// ResendTransaction resubmits a transaction which was dropped from chain as per the chain service's retry policy
+	ResendTransaction(tx protocols.ChainTransaction, droppedTxHash common.Hash, numOfRetries uint) (*ethTypes.Transaction, error)
+	// GetRetryPolicy returns the policy used to resubmit dropped transactions
+	GetRetryPolicy() RetryPolicy
 	// GetConsensusAppAddress returns the address of a deployed ConsensusApp (for ledger channels)
 	GetConsensusAppAddress() types.Address
 	// GetVirtualPaymentAppAddress returns the address of a deployed VirtualPaymentApp
@@ -218,9 +229,15 @@ type ChainService interface {
 	GetLastConfirmedBlockNum() uint64
 	// GetBlockByNumber returns the block for given block number
 	GetBlockByNumber(blockNum *big.Int) (*ethTypes.Block, error)
+	// GetTransactionReceipt returns the receipt of a mined transaction, or ethereum.NotFound if the transaction is unknown or still pending
+	GetTransactionReceipt(txHash common.Hash) (*ethTypes.Receipt, error)
 	// TODO: Implement method for eth calls
 	// GetL1ChannelFromL2 returns the L1 ledger channel ID from the L2 ledger channel by making a contract call to the l2ToL1 map of the Nitro Adjudicator contract
 	GetL1ChannelFromL2(l2Channel types.Destination) (types.Destination, error)
+	// StateIsSupported returns whether the adjudicator accepts the candidate state along with the proof, and the reason if it does not
+	StateIsSupported(candidate state.SignedState, proof []state.SignedState) (bool, string, error)
+	// GetChallengeStatus returns the turn number recorded by the adjudicator for the channel, and the time at which its challenge finalizes, which is zero if there is none
+	GetChallengeStatus(channelId types.Destination) (turnNum uint64, finalizesAt uint64, err error)
 	// Close closes the ChainService
 	Close() error
 }
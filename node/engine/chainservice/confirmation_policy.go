@@ -0,0 +1,116 @@
+package chainservice
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ConfirmationPolicy determines when chain events are final enough to be processed, which differs hugely between networks
+type ConfirmationPolicy struct {
+	// RequiredBlockConfirmations is how many blocks must be mined before an emitted event is processed
+	RequiredBlockConfirmations uint64
+	// UseFinalizedTag processes an event once its block is at or below the block with the `finalized` tag, instead of after RequiredBlockConfirmations blocks
+	UseFinalizedTag bool
+	// BlocksWithoutEventThreshold is the maximum number of blocks the node will wait for an event to confirm a transaction was mined, before resubmitting it
+	BlocksWithoutEventThreshold uint64
+	// MaxEpochs is the maximum range of old blocks queried with a single FilterLogs request when searching for missed events
+	MaxEpochs uint64
+}
+
+// DefaultConfirmationPolicy returns the confirmation policy of chains without a preset, such as local devnets
+func DefaultConfirmationPolicy() ConfirmationPolicy {
+	return ConfirmationPolicy{
+		RequiredBlockConfirmations:  REQUIRED_BLOCK_CONFIRMATIONS,
+		BlocksWithoutEventThreshold: BLOCKS_WITHOUT_EVENT_THRESHOLD,
+		MaxEpochs:                   MAX_EPOCHS,
+	}
+}
+
+// confirmationPolicyPresets are the confirmation policies of well known chains, by chain ID
+var confirmationPolicyPresets = map[uint64]ConfirmationPolicy{
+	// Ethereum mainnet and testnets, with 12 second slots
+	1:        {RequiredBlockConfirmations: 12, BlocksWithoutEventThreshold: 25, MaxEpochs: 10_000},
+	17000:    {RequiredBlockConfirmations: 6, BlocksWithoutEventThreshold: 25, MaxEpochs: 10_000},
+	11155111: {RequiredBlockConfirmations: 6, BlocksWithoutEventThreshold: 25, MaxEpochs: 10_000},
+	// Filecoin mainnet and calibration, with 30 second epochs. Lotus refuses FilterLogs requests over more than 2880 epochs.
+	// The finalized tag is not used by default: without fast finality Lotus resolves it 900 epochs (7.5 hours) behind the head,
+	// which is longer than the challenge duration of most channels.
+	314:    {RequiredBlockConfirmations: FILECOIN_BLOCK_CONFIRMATIONS, BlocksWithoutEventThreshold: BLOCKS_WITHOUT_EVENT_THRESHOLD, MaxEpochs: 2880},
+	314159: {RequiredBlockConfirmations: FILECOIN_BLOCK_CONFIRMATIONS, BlocksWithoutEventThreshold: BLOCKS_WITHOUT_EVENT_THRESHOLD, MaxEpochs: 2880},
+}
+
+// FILECOIN_BLOCK_CONFIRMATIONS is how many epochs must be mined on Filecoin before an emitted event is processed, which is 15 minutes
+const FILECOIN_BLOCK_CONFIRMATIONS = 30
+
+// FINALIZED_TAG_MAX_LAG is how far behind the head the finalized block may be, which is 7.5 hours on Filecoin without fast finality
+const FINALIZED_TAG_MAX_LAG = 900
+
+// ConfirmationPolicyConfig configures the confirmation policy of a chain. The fields which are not set are taken from the preset for the chain ID.
+// The confirmation depth and the finalized tag are pointers, so that zero confirmations can be configured.
+type ConfirmationPolicyConfig struct {
+	RequiredBlockConfirmations  *uint64
+	UseFinalizedTag             *bool
+	BlocksWithoutEventThreshold uint64
+	MaxEpochs                   uint64
+}
+
+// ConfirmationPolicyForChain returns the preset confirmation policy of the chain, or the default policy if there is none
+func ConfirmationPolicyForChain(chainId *big.Int) ConfirmationPolicy {
+	if chainId.IsUint64() {
+		if preset, ok := confirmationPolicyPresets[chainId.Uint64()]; ok {
+			return preset
+		}
+	}
+
+	return DefaultConfirmationPolicy()
+}
+
+// IsZero returns true if the policy has not been configured
+func (cp ConfirmationPolicy) IsZero() bool {
+	return cp == ConfirmationPolicy{}
+}
+
+// Override returns the policy with the configured fields of the given config.
+// Configuring a confirmation depth stops the finalized tag of the preset being used, unless the finalized tag is configured too.
+func (cp ConfirmationPolicy) Override(configured ConfirmationPolicyConfig) ConfirmationPolicy {
+	if configured.RequiredBlockConfirmations != nil {
+		cp.RequiredBlockConfirmations = *configured.RequiredBlockConfirmations
+		cp.UseFinalizedTag = false
+	}
+	if configured.UseFinalizedTag != nil {
+		cp.UseFinalizedTag = *configured.UseFinalizedTag
+	}
+	if configured.BlocksWithoutEventThreshold != 0 {
+		cp.BlocksWithoutEventThreshold = configured.BlocksWithoutEventThreshold
+	}
+	if configured.MaxEpochs != 0 {
+		cp.MaxEpochs = configured.MaxEpochs
+	}
+
+	return cp
+}
+
+// confirmedBlockNum returns the highest block with enough confirmations, given the latest block
+func (cp ConfirmationPolicy) confirmedBlockNum(latestBlockNum uint64) uint64 {
+	if latestBlockNum < cp.RequiredBlockConfirmations {
+		return 0
+	}
+	return latestBlockNum - cp.RequiredBlockConfirmations
+}
+
+// fetchConfirmedBlockNum returns the highest block whose events are final enough to be processed, given the latest block.
+// It is the block with the finalized tag if the policy uses it.
+func (ecs *EthChainService) fetchConfirmedBlockNum(ctx context.Context, latestBlockNum uint64) (uint64, error) {
+	if !ecs.confirmationPolicy.UseFinalizedTag {
+		return ecs.confirmationPolicy.confirmedBlockNum(latestBlockNum), nil
+	}
+
+	finalized, err := ecs.chain.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil {
+		return 0, err
+	}
+
+	return min(finalized.Number.Uint64(), latestBlockNum), nil
+}
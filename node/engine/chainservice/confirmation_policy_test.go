@@ -0,0 +1,113 @@
+package chainservice
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestConfirmationPolicy(t *testing.T) {
+	zero, yes := uint64(0), true
+	testCases := []struct {
+		name       string
+		chainId    int64
+		configured ConfirmationPolicyConfig
+		want       ConfirmationPolicy
+	}{
+		{"chains without a preset use the default policy", TEST_CHAIN_ID, ConfirmationPolicyConfig{}, DefaultConfirmationPolicy()},
+		{"ethereum mainnet waits for more confirmations", 1, ConfirmationPolicyConfig{}, ConfirmationPolicy{RequiredBlockConfirmations: 12, BlocksWithoutEventThreshold: 25, MaxEpochs: 10_000}},
+		{"filecoin waits for a number of epochs", 314, ConfirmationPolicyConfig{}, ConfirmationPolicy{RequiredBlockConfirmations: FILECOIN_BLOCK_CONFIRMATIONS, BlocksWithoutEventThreshold: BLOCKS_WITHOUT_EVENT_THRESHOLD, MaxEpochs: 2880}},
+		{
+			"zero confirmations can be configured", 1,
+			ConfirmationPolicyConfig{RequiredBlockConfirmations: &zero},
+			ConfirmationPolicy{RequiredBlockConfirmations: 0, BlocksWithoutEventThreshold: 25, MaxEpochs: 10_000},
+		},
+		{
+			"the finalized tag can be configured on any chain", TEST_CHAIN_ID,
+			ConfirmationPolicyConfig{UseFinalizedTag: &yes, MaxEpochs: 100},
+			ConfirmationPolicy{RequiredBlockConfirmations: REQUIRED_BLOCK_CONFIRMATIONS, UseFinalizedTag: true, BlocksWithoutEventThreshold: BLOCKS_WITHOUT_EVENT_THRESHOLD, MaxEpochs: 100},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ConfirmationPolicyForChain(big.NewInt(tc.chainId)).Override(tc.configured)
+			if got != tc.want {
+				t.Fatalf("expected policy %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+// finalizingChain is a simulated chain on which the test decides which block is finalized
+type finalizingChain struct {
+	SimulatedChain
+	finalizedBlockNum atomic.Uint64
+}
+
+func (fc *finalizingChain) HeaderByNumber(ctx context.Context, number *big.Int) (*ethTypes.Header, error) {
+	if number != nil && number.Int64() == int64(rpc.FinalizedBlockNumber) {
+		number = new(big.Int).SetUint64(fc.finalizedBlockNum.Load())
+	}
+	return fc.SimulatedChain.HeaderByNumber(ctx, number)
+}
+
+func TestFinalizedTag(t *testing.T) {
+	sim, bindings, ethAccounts, err := SetupSimulatedBackend(1)
+	defer closeSimulatedChain(t, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := &finalizingChain{SimulatedChain: sim}
+
+	useFinalizedTag := true
+	policy := DefaultConfirmationPolicy().Override(ConfirmationPolicyConfig{UseFinalizedTag: &useFinalizedTag})
+	ecs, err := newPollingEthChainService(chain, 0, EventCursor{}, policy,
+		bindings.Adjudicator.Contract,
+		bindings.Adjudicator.Address,
+		bindings.ConsensusApp.Address,
+		bindings.VirtualPaymentApp.Address,
+		ethAccounts[0],
+		testPollInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := &SimulatedBackendChainService{sim: chain, EthChainService: ecs}
+	defer closeChainService(t, cs)
+
+	_, err = cs.SendTransaction(protocols.NewDepositTransaction(types.Destination{1}, types.Funds{common.Address{}: big.NewInt(1)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// More blocks than the default confirmations are mined, but none of them is finalized
+	for i := 0; i < REQUIRED_BLOCK_CONFIRMATIONS+1; i++ {
+		chain.Commit()
+	}
+
+	select {
+	case event := <-cs.EventEngineFeed():
+		t.Fatalf("expected no event to be processed before its block is finalized, got %+v", event)
+	case <-time.After(10 * testPollInterval):
+	}
+
+	latestBlockNum, err := chain.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.finalizedBlockNum.Store(latestBlockNum)
+
+	if _, ok := receiveEvent(t, cs.EventEngineFeed()).(DepositedEvent); !ok {
+		t.Fatal("expected the deposit to be processed once its block is finalized")
+	}
+	if blockNum := cs.GetLastConfirmedBlockNum(); blockNum != latestBlockNum {
+		t.Fatalf("expected the last confirmed block to be the finalized block %d, got %d", latestBlockNum, blockNum)
+	}
+}
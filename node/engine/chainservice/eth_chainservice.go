@@ -2,6 +2,7 @@ package chainservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/big"
@@ -14,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/statechannels/go-nitro/channel/state"
 	"github.com/statechannels/go-nitro/internal/logging"
@@ -33,6 +35,15 @@ type ChainOpts struct {
 	NaAddress          common.Address
 	VpaAddress         common.Address
 	CaAddress          common.Address
+	// RetryPolicy is used to resubmit dropped transactions, DefaultRetryPolicy is used if it is not set
+	RetryPolicy RetryPolicy
+	// PollInterval, if set, makes the chain service poll the chain for new blocks and events at this interval
+	// instead of subscribing to them, for RPC endpoints which do not support subscriptions
+	PollInterval time.Duration
+	// EventCursor is the position of the last event handled by the engine. The events up to it are not delivered again.
+	EventCursor EventCursor
+	// ConfirmationPolicy determines when chain events are processed. The fields which are not set are taken from the preset for the chain ID
+	ConfirmationPolicy ConfirmationPolicyConfig
 }
 
 var (
@@ -75,6 +86,7 @@ type ethChain interface {
 	bind.ContractBackend
 	ethereum.TransactionReader
 	ethereum.ChainReader
+	ethereum.BlockNumberReader
 	ChainID(ctx context.Context) (*big.Int, error)
 	TransactionSender(ctx context.Context, tx *ethTypes.Transaction, block common.Hash, index uint) (common.Address, error)
 }
@@ -99,6 +111,10 @@ type EthChainService struct {
 	eventSub                 ethereum.Subscription
 	newBlockSub              ethereum.Subscription
 	sentTxToChannelIdMap     *safesync.Map[types.Destination]
+	retryPolicy              RetryPolicy
+	pollInterval             time.Duration // pollInterval is how often the chain is polled, or 0 if the chain service subscribes to it
+	eventCursor              EventCursor   // eventCursor is the position of the last event dispatched to the engine, guarded by the event tracker mutex
+	confirmationPolicy       ConfirmationPolicy
 }
 
 // MAX_QUERY_BLOCK_RANGE is the maximum range of blocks we query for events at once.
@@ -114,14 +130,15 @@ const MAX_QUERY_BLOCK_RANGE = 2000
 // This has been reduced to 15 seconds to support local devnets with much shorter timeouts.
 const RESUB_INTERVAL = 15 * time.Second
 
-// REQUIRED_BLOCK_CONFIRMATIONS is how many blocks must be mined before an emitted event is processed
+// REQUIRED_BLOCK_CONFIRMATIONS is how many blocks must be mined before an emitted event is processed, on chains without a ConfirmationPolicy preset
 const REQUIRED_BLOCK_CONFIRMATIONS = 3
 
-// MAX_EPOCHS is the maximum range of old epochs we can query with a single "FilterLogs" request
+// MAX_EPOCHS is the maximum range of old epochs we can query with a single "FilterLogs" request, on chains without a ConfirmationPolicy preset
 // This is a restriction enforced by the rpc provider
 const MAX_EPOCHS = 60480
 
-// BLOCKS_WITHOUT_EVENT_THRESHOLD is the maximum number of blocks the node will wait for an event to confirm transaction to be mined
+// BLOCKS_WITHOUT_EVENT_THRESHOLD is the maximum number of blocks the node will wait for an event to confirm transaction to be mined,
+// on chains without a ConfirmationPolicy preset
 const BLOCKS_WITHOUT_EVENT_THRESHOLD = 16
 
 // GAS_LIMIT_MULTIPLIER is the multiplier for updating gas limit
@@ -151,28 +168,69 @@ func NewEthChainService(chainOpts ChainOpts) (ChainService, error) {
 		panic(err)
 	}
 
-	return newEthChainService(ethClient, chainOpts.ChainStartBlockNum, na, chainOpts.NaAddress, chainOpts.CaAddress, chainOpts.VpaAddress, txSigner)
+	chainId, err := ethClient.ChainID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	confirmationPolicy := ConfirmationPolicyForChain(chainId).Override(chainOpts.ConfirmationPolicy)
+
+	var ecs *EthChainService
+	if chainOpts.PollInterval > 0 {
+		ecs, err = newPollingEthChainService(ethClient, chainOpts.ChainStartBlockNum, chainOpts.EventCursor, confirmationPolicy, na, chainOpts.NaAddress, chainOpts.CaAddress, chainOpts.VpaAddress, txSigner, chainOpts.PollInterval)
+	} else {
+		ecs, err = newEthChainService(ethClient, chainOpts.ChainStartBlockNum, chainOpts.EventCursor, confirmationPolicy, na, chainOpts.NaAddress, chainOpts.CaAddress, chainOpts.VpaAddress, txSigner)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !chainOpts.RetryPolicy.IsZero() {
+		ecs.retryPolicy = chainOpts.RetryPolicy
+	}
+
+	return ecs, nil
 }
 
 // newEthChainService constructs a chain service that submits transactions to a NitroAdjudicator
 // and listens to events from an eventSource
-func newEthChainService(chain ethChain, startBlockNum uint64, na *NitroAdjudicator.NitroAdjudicator,
+func newEthChainService(chain ethChain, startBlockNum uint64, cursor EventCursor, confirmationPolicy ConfirmationPolicy, na *NitroAdjudicator.NitroAdjudicator,
 	naAddress, caAddress, vpaAddress common.Address, txSigner *bind.TransactOpts,
 ) (*EthChainService, error) {
-	ctx, cancelCtx := context.WithCancel(context.Background())
-
-	logger := logging.LoggerWithAddress(slog.Default(), txSigner.From)
+	ecs, err := initEthChainService(chain, startBlockNum, cursor, confirmationPolicy, na, naAddress, caAddress, vpaAddress, txSigner)
+	if err != nil {
+		return nil, err
+	}
 
-	block, err := chain.BlockByNumber(ctx, new(big.Int).SetUint64(startBlockNum))
+	errChan, newBlockChan, eventChan, eventQuery, err := ecs.subscribeForLogs()
 	if err != nil {
-		cancelCtx()
 		return nil, err
 	}
-	startBlock := Block{
-		BlockNum:  block.NumberU64(),
-		Timestamp: block.Time(),
+
+	// Prevent go routines from processing events before checkForMissedEvents completes
+	ecs.eventTracker.mu.Lock()
+	defer ecs.eventTracker.mu.Unlock()
+
+	ecs.wg.Add(3)
+	go ecs.listenForEventLogs(errChan, eventChan, eventQuery)
+	go ecs.listenForNewBlocks(errChan, newBlockChan)
+	go ecs.listenForErrors(errChan)
+
+	// Search for any missed events emitted while this node was offline
+	err = ecs.checkForMissedEvents(ecs.eventTracker.latestBlock.BlockNum)
+	if err != nil {
+		return nil, err
 	}
-	tracker := NewEventTracker(startBlock)
+
+	return ecs, nil
+}
+
+// initEthChainService constructs a chain service which does not listen to the chain yet, starting from the given block or after the given event
+func initEthChainService(chain ethChain, startBlockNum uint64, cursor EventCursor, confirmationPolicy ConfirmationPolicy, na *NitroAdjudicator.NitroAdjudicator,
+	naAddress, caAddress, vpaAddress common.Address, txSigner *bind.TransactOpts,
+) (*EthChainService, error) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+
+	logger := logging.LoggerWithAddress(slog.Default(), txSigner.From)
 	sentTxToChannelIdMap := safesync.Map[types.Destination]{}
 
 	// Use a buffered channel so we don't have to worry about blocking on writing to the channel.
@@ -188,29 +246,38 @@ func newEthChainService(chain ethChain, startBlockNum uint64, na *NitroAdjudicat
 		make(chan protocols.DroppedEventInfo, 10),
 		make(chan protocols.DroppedEventInfo, 10),
 		logger, ctx, cancelCtx, &sync.WaitGroup{},
-		tracker,
+		nil,
 		nil,
 		nil,
 		&sentTxToChannelIdMap,
+		DefaultRetryPolicy(),
+		0,
+		EventCursor{},
+		confirmationPolicy,
 	}
 
-	errChan, newBlockChan, eventChan, eventQuery, err := ecs.subscribeForLogs()
+	if confirmationPolicy.UseFinalizedTag {
+		ecs.logger.Warn("chain events are processed once their block is finalized, which may be long after they are emitted. Channels must use challenge durations longer than the finalization lag",
+			"maxLagBlocks", FINALIZED_TAG_MAX_LAG)
+	}
+
+	startBlockNum, err := ecs.resumeFrom(cursor, startBlockNum)
 	if err != nil {
+		cancelCtx()
 		return nil, err
 	}
-
-	// Prevent go routines from processing events before checkForMissedEvents completes
-	ecs.eventTracker.mu.Lock()
-	defer ecs.eventTracker.mu.Unlock()
-
-	ecs.wg.Add(3)
-	go ecs.listenForEventLogs(errChan, eventChan, eventQuery)
-	go ecs.listenForNewBlocks(errChan, newBlockChan)
-	go ecs.listenForErrors(errChan)
-
-	// Search for any missed events emitted while this node was offline
-	err = ecs.checkForMissedEvents(startBlock.BlockNum)
+	block, err := chain.BlockByNumber(ctx, new(big.Int).SetUint64(startBlockNum))
 	if err != nil {
+		cancelCtx()
+		return nil, err
+	}
+	ecs.eventTracker = NewEventTracker(Block{
+		BlockNum:  block.NumberU64(),
+		Timestamp: block.Time(),
+	})
+	ecs.eventTracker.confirmedBlockNum, err = ecs.fetchConfirmedBlockNum(ctx, block.NumberU64())
+	if err != nil {
+		cancelCtx()
 		return nil, err
 	}
 
@@ -227,9 +294,9 @@ func (ecs *EthChainService) checkForMissedEvents(startBlock uint64) error {
 	latestBlockNum := latestBlock.NumberU64()
 	ecs.logger.Info("checking for missed chain events", "startBlock", startBlock, "currentBlock", latestBlockNum)
 
-	// Loop through in chunks of MAX_EPOCHS
+	// Loop through in chunks of MaxEpochs
 	for currentStart := startBlock; currentStart <= latestBlockNum; {
-		currentEnd := currentStart + MAX_EPOCHS
+		currentEnd := currentStart + ecs.confirmationPolicy.MaxEpochs - 1
 		if currentEnd > latestBlockNum {
 			currentEnd = latestBlockNum
 		}
@@ -254,7 +321,7 @@ func (ecs *EthChainService) checkForMissedEvents(startBlock uint64) error {
 		ecs.logger.Info("finished checking for missed chain events in range", "fromBlock", currentStart, "toBlock", currentEnd, "numMissedEvents", len(missedEvents))
 
 		for _, event := range missedEvents {
-			ecs.eventTracker.Push(event)
+			ecs.trackEvent(event)
 		}
 
 		currentStart = currentEnd + 1 // Move to the next chunk
@@ -301,12 +368,101 @@ func (ecs *EthChainService) defaultCallOpts() *bind.CallOpts {
 
 // SendTransaction sends the transaction and blocks until it has been submitted.
 func (ecs *EthChainService) SendTransaction(tx protocols.ChainTransaction) (*ethTypes.Transaction, error) {
+	return ecs.sendTransaction(tx, ecs.defaultTxOpts)
+}
+
+// ResendTransaction resubmits a transaction which was dropped from chain as per the chain service's RetryPolicy.
+// The fees of the dropped transaction are bumped and, if the policy allows it, its nonce is reused so that the new transaction replaces it.
+// numOfRetries is the number of times the transaction has already been resubmitted.
+func (ecs *EthChainService) ResendTransaction(tx protocols.ChainTransaction, droppedTxHash common.Hash, numOfRetries uint) (*ethTypes.Transaction, error) {
+	if !ecs.retryPolicy.CanRetry(numOfRetries) {
+		return nil, ErrRetryLimitReached
+	}
+
+	droppedTx, _, err := ecs.chain.TransactionByHash(ecs.ctx, droppedTxHash)
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return nil, err
+	}
+
+	txOpts, err := ecs.replacementTxOpts(droppedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A deposit may be submitted as several transactions, so they cannot all replace the dropped one
+	if _, isDeposit := tx.(protocols.DepositTransaction); isDeposit {
+		txOpts.Nonce = nil
+	}
+
+	ecs.logger.Info("Resubmitting dropped transaction", "droppedTxHash", droppedTxHash, "numOfRetries", numOfRetries, "gasTipCap", txOpts.GasTipCap, "gasFeeCap", txOpts.GasFeeCap, "gasPrice", txOpts.GasPrice)
+	return ecs.sendTransaction(tx, func() *bind.TransactOpts {
+		opts := *txOpts
+		return &opts
+	})
+}
+
+// replacementTxOpts returns transaction options with the fees of the replaced transaction (or the currently suggested ones, whichever is higher) bumped as per the retry policy
+func (ecs *EthChainService) replacementTxOpts(replacedTx *ethTypes.Transaction) (*bind.TransactOpts, error) {
+	txOpts := ecs.defaultTxOpts()
+
+	if replacedTx != nil && ecs.retryPolicy.ReplaceByNonce {
+		txOpts.Nonce = new(big.Int).SetUint64(replacedTx.Nonce())
+	}
+
+	header, err := ecs.chain.HeaderByNumber(ecs.ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chains without EIP-1559 only have a gas price to bump
+	if header.BaseFee == nil {
+		gasPrice, err := ecs.chain.SuggestGasPrice(ecs.ctx)
+		if err != nil {
+			return nil, err
+		}
+		if replacedTx != nil {
+			gasPrice = bigMax(gasPrice, replacedTx.GasPrice())
+		}
+
+		txOpts.GasPrice = ecs.retryPolicy.BumpFee(gasPrice)
+		txOpts.GasTipCap = nil
+		txOpts.GasFeeCap = nil
+		return txOpts, nil
+	}
+
+	gasTipCap, err := ecs.chain.SuggestGasTipCap(ecs.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same fee cap as the one go-ethereum's bind package uses by default
+	gasFeeCap := new(big.Int).Add(gasTipCap, new(big.Int).Mul(header.BaseFee, big.NewInt(2)))
+	if replacedTx != nil {
+		gasTipCap = bigMax(gasTipCap, replacedTx.GasTipCap())
+		gasFeeCap = bigMax(gasFeeCap, replacedTx.GasFeeCap())
+	}
+
+	txOpts.GasTipCap = ecs.retryPolicy.BumpFee(gasTipCap)
+	txOpts.GasFeeCap = ecs.retryPolicy.BumpFee(gasFeeCap)
+	txOpts.GasPrice = nil
+	return txOpts, nil
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// sendTransaction sends the transaction using the transaction options returned by newTxOpts
+func (ecs *EthChainService) sendTransaction(tx protocols.ChainTransaction, newTxOpts func() *bind.TransactOpts) (*ethTypes.Transaction, error) {
 	switch tx := tx.(type) {
 	case protocols.DepositTransaction:
 		var tokenApprovalLog ethTypes.Log
 
 		for tokenAddress, amount := range tx.Deposit {
-			txOpts := ecs.defaultTxOpts()
+			txOpts := newTxOpts()
 			ethTokenAddress := common.Address{}
 			if tokenAddress == ethTokenAddress {
 				txOpts.Value = amount
@@ -370,19 +526,19 @@ func (ecs *EthChainService) SendTransaction(tx protocols.ChainTransaction) (*eth
 			Sigs:         nitroSignatures,
 		}
 
-		withdrawAllTx, err := ecs.na.ConcludeAndTransferAllAssets(ecs.defaultTxOpts(), nitroFixedPart, candidate)
+		withdrawAllTx, err := ecs.na.ConcludeAndTransferAllAssets(newTxOpts(), nitroFixedPart, candidate)
 		ecs.sentTxToChannelIdMap.Store(withdrawAllTx.Hash().String(), tx.ChannelId())
 		return withdrawAllTx, err
 	case protocols.ChallengeTransaction:
 		fp, candidate := NitroAdjudicator.ConvertSignedStateToFixedPartAndSignedVariablePart(tx.Candidate)
 		proof := NitroAdjudicator.ConvertSignedStatesToProof(tx.Proof)
 		challengerSig := NitroAdjudicator.ConvertSignature(tx.ChallengerSig)
-		challengeTx, err := ecs.na.Challenge(ecs.defaultTxOpts(), fp, proof, candidate, challengerSig)
+		challengeTx, err := ecs.na.Challenge(newTxOpts(), fp, proof, candidate, challengerSig)
 		return challengeTx, err
 	case protocols.CheckpointTransaction:
 		fp, candidate := NitroAdjudicator.ConvertSignedStateToFixedPartAndSignedVariablePart(tx.Candidate)
 		proof := NitroAdjudicator.ConvertSignedStatesToProof(tx.Proof)
-		checkpointTx, err := ecs.na.Checkpoint(ecs.defaultTxOpts(), fp, proof, candidate)
+		checkpointTx, err := ecs.na.Checkpoint(newTxOpts(), fp, proof, candidate)
 		return checkpointTx, err
 	case protocols.TransferAllTransaction:
 		transferState := tx.TransferState.State()
@@ -394,10 +550,10 @@ func (ecs *EthChainService) SendTransaction(tx protocols.ChainTransaction) (*eth
 
 		nitroVariablePart := NitroAdjudicator.ConvertVariablePart(transferState.VariablePart())
 
-		transferAllTx, er := ecs.na.TransferAllAssets(ecs.defaultTxOpts(), channelId, nitroVariablePart.Outcome, stateHash)
+		transferAllTx, er := ecs.na.TransferAllAssets(newTxOpts(), channelId, nitroVariablePart.Outcome, stateHash)
 		return transferAllTx, er
 	case protocols.ReclaimTransaction:
-		reclaimTx, err := ecs.na.Reclaim(ecs.defaultTxOpts(), tx.ReclaimArgs)
+		reclaimTx, err := ecs.na.Reclaim(newTxOpts(), tx.ReclaimArgs)
 		return reclaimTx, err
 	case protocols.MirrorTransferAllTransaction:
 		transferState := tx.TransferState.State()
@@ -409,10 +565,10 @@ func (ecs *EthChainService) SendTransaction(tx protocols.ChainTransaction) (*eth
 
 		nitroVariablePart := NitroAdjudicator.ConvertVariablePart(transferState.VariablePart())
 
-		mirrorTransferAllTx, err := ecs.na.MirrorTransferAllAssets(ecs.defaultTxOpts(), channelId, nitroVariablePart.Outcome, stateHash)
+		mirrorTransferAllTx, err := ecs.na.MirrorTransferAllAssets(newTxOpts(), channelId, nitroVariablePart.Outcome, stateHash)
 		return mirrorTransferAllTx, err
 	case protocols.SetL2ToL1Transaction:
-		setL2ToL1Tx, err := ecs.na.SetL2ToL1(ecs.defaultTxOpts(), tx.ChannelId(), tx.MirrorChannelId)
+		setL2ToL1Tx, err := ecs.na.SetL2ToL1(newTxOpts(), tx.ChannelId(), tx.MirrorChannelId)
 		if err != nil {
 			return nil, err
 		}
@@ -431,7 +587,7 @@ func (ecs *EthChainService) SendTransaction(tx protocols.ChainTransaction) (*eth
 			VariablePart: nitroVariablePart,
 			Sigs:         nitroSignatures,
 		}
-		MirrorWithdrawAllTx, err := ecs.na.MirrorConcludeAndTransferAllAssets(ecs.defaultTxOpts(), nitroFixedPart, candidate)
+		MirrorWithdrawAllTx, err := ecs.na.MirrorConcludeAndTransferAllAssets(newTxOpts(), nitroFixedPart, candidate)
 		return MirrorWithdrawAllTx, err
 	default:
 		return nil, fmt.Errorf("unexpected transaction type %T", tx)
@@ -443,6 +599,27 @@ func (ecs *EthChainService) GetL1ChannelFromL2(l2Channel types.Destination) (typ
 	return ecs.na.L2Tol1(ecs.defaultCallOpts(), l2Channel)
 }
 
+// StateIsSupported returns whether the adjudicator accepts the candidate state along with the proof, by making a call to its stateIsSupported function.
+// Apps may revert rather than return false, in which case the revert reason is returned.
+func (ecs *EthChainService) StateIsSupported(candidate state.SignedState, proof []state.SignedState) (bool, string, error) {
+	fp, signedCandidate := NitroAdjudicator.ConvertSignedStateToFixedPartAndSignedVariablePart(candidate)
+	supported, reason, err := ecs.na.StateIsSupported(ecs.defaultCallOpts(), fp, NitroAdjudicator.ConvertSignedStatesToProof(proof), signedCandidate)
+	var revertErr rpc.DataError
+	if errors.As(err, &revertErr) {
+		return false, revertErr.Error(), nil
+	}
+	return supported, reason, err
+}
+
+// GetChallengeStatus returns the turn number recorded by the adjudicator for the channel, and the time at which its challenge finalizes
+func (ecs *EthChainService) GetChallengeStatus(channelId types.Destination) (uint64, uint64, error) {
+	status, err := ecs.na.UnpackStatus(ecs.defaultCallOpts(), channelId)
+	if err != nil {
+		return 0, 0, err
+	}
+	return status.TurnNumRecord.Uint64(), status.FinalizesAt.Uint64(), nil
+}
+
 // dispatchChainEvents takes in a collection of event logs from the chain
 // and dispatches events to the out channel
 func (ecs *EthChainService) dispatchChainEvents(logs []ethTypes.Log) error {
@@ -461,6 +638,7 @@ func (ecs *EthChainService) dispatchChainEvents(logs []ethTypes.Log) error {
 			}
 
 			event := NewDepositedEvent(nad.Destination, Block{BlockNum: l.BlockNumber, Timestamp: block.Time()}, l.TxIndex, nad.Asset, nad.DestinationHoldings, l.TxHash)
+			event.cursor = cursorOf(l)
 			ecs.eventEngineOut <- event
 
 		case allocationUpdatedTopic:
@@ -479,6 +657,7 @@ func (ecs *EthChainService) dispatchChainEvents(logs []ethTypes.Log) error {
 			}
 
 			event := NewAllocationUpdatedEvent(au.ChannelId, Block{BlockNum: l.BlockNumber, Timestamp: block.Time()}, l.TxIndex, au.Asset, au.FinalHoldings, l.TxHash)
+			event.cursor = cursorOf(l)
 			ecs.eventEngineOut <- event
 
 		case concludedTopic:
@@ -489,6 +668,7 @@ func (ecs *EthChainService) dispatchChainEvents(logs []ethTypes.Log) error {
 			}
 
 			event := ConcludedEvent{commonEvent: commonEvent{channelID: ce.ChannelId, block: Block{BlockNum: l.BlockNumber, Timestamp: block.Time()}, txIndex: l.TxIndex, txHash: l.TxHash}}
+			event.cursor = cursorOf(l)
 			ecs.eventEngineOut <- event
 
 		case challengeRegisteredTopic:
@@ -528,6 +708,7 @@ func (ecs *EthChainService) dispatchChainEvents(logs []ethTypes.Log) error {
 				isInitiatedByMe,
 				l.TxHash,
 			)
+			event.cursor = cursorOf(l)
 			ecs.eventEngineOut <- event
 		case challengeClearedTopic:
 			ecs.logger.Debug("Processing Challenge Cleared event")
@@ -536,6 +717,7 @@ func (ecs *EthChainService) dispatchChainEvents(logs []ethTypes.Log) error {
 				return fmt.Errorf("error in ParseCheckpointed: %w", err)
 			}
 			event := NewChallengeClearedEvent(cp.ChannelId, Block{BlockNum: l.BlockNumber, Timestamp: block.Time()}, l.TxIndex, cp.NewTurnNumRecord, l.TxHash)
+			event.cursor = cursorOf(l)
 			ecs.eventEngineOut <- event
 
 		case reclaimedTopic:
@@ -546,6 +728,7 @@ func (ecs *EthChainService) dispatchChainEvents(logs []ethTypes.Log) error {
 			}
 
 			event := ReclaimedEvent{commonEvent: commonEvent{channelID: ce.ChannelId, block: Block{BlockNum: l.BlockNumber, Timestamp: block.Time()}, txIndex: l.TxIndex, txHash: l.TxHash}}
+			event.cursor = cursorOf(l)
 			ecs.eventEngineOut <- event
 
 		case L2ToL1MapUpdatedTopic:
@@ -558,6 +741,8 @@ func (ecs *EthChainService) dispatchChainEvents(logs []ethTypes.Log) error {
 
 			event := L2ToL1MapUpdated{commonEvent: commonEvent{block: Block{BlockNum: l.BlockNumber, Timestamp: block.Time()}, txIndex: l.TxIndex, txHash: l.TxHash}, l1ChannelId: channelMapUpdatedEvent.L1ChannelId, l2ChannelId: channelMapUpdatedEvent.L2ChannelId}
 
+			event.cursor = cursorOf(l)
+
 			// Use non-blocking send incase no-one is listening
 			select {
 			case ecs.eventOut <- event:
@@ -642,7 +827,11 @@ func (ecs *EthChainService) listenForEventLogs(errorChan chan<- error, eventChan
 
 		case chainEvent := <-eventChan:
 			ecs.logger.Debug("queueing new chainEvent", "block-num", chainEvent.BlockNumber)
-			ecs.updateEventTracker(errorChan, nil, &chainEvent)
+			err := ecs.updateEventTracker(errorChan, nil, &chainEvent)
+			if err != nil {
+				// The queued events are retried with the next block
+				ecs.logger.Warn("failed to dispatch chain events", "error", err)
+			}
 		}
 	}
 }
@@ -700,36 +889,67 @@ func (ecs *EthChainService) listenForNewBlocks(errorChan chan<- error, newBlockC
 		case newBlock := <-newBlockChan:
 			block := Block{BlockNum: newBlock.Number.Uint64(), Timestamp: newBlock.Time}
 			ecs.logger.Log(ecs.ctx, logging.LevelTrace, "detected new block", "block-num", block.BlockNum)
-			ecs.updateEventTracker(errorChan, &block, nil)
+			err := ecs.updateEventTracker(errorChan, &block, nil)
+			if err != nil {
+				// The queued events are retried with the next block
+				ecs.logger.Warn("failed to dispatch chain events", "error", err)
+			}
 		}
 	}
 }
 
-// updateEventTracker accepts a new block number and/or new event and dispatches a chain event if there are enough block confirmations
-func (ecs *EthChainService) updateEventTracker(errorChan chan<- error, block *Block, chainEvent *ethTypes.Log) {
+// trackEvent queues the event until it has enough confirmations. The eventTracker mutex must be held.
+// The same event may be seen twice, when the chain is polled again after a re-org or a subscription is re-established,
+// or when the chain service restarts from the block of the last handled event. Such events are only queued and delivered once.
+func (ecs *EthChainService) trackEvent(l ethTypes.Log) {
+	if ecs.eventCursor.covers(l) || ecs.eventTracker.Contains(l) {
+		return
+	}
+
+	ecs.eventTracker.Push(l)
+	ecs.logger.Debug("event added to queue", "updated-queue-length", ecs.eventTracker.events.Len())
+}
+
+// updateEventTracker accepts a new block number and/or new event and dispatches a chain event if there are enough block confirmations.
+// It returns an error if the block of a confirmed event cannot be fetched, in which case the event is queued again.
+func (ecs *EthChainService) updateEventTracker(errorChan chan<- error, block *Block, chainEvent *ethTypes.Log) error {
+	var confirmedBlockNum uint64
+	if block != nil {
+		var err error
+		confirmedBlockNum, err = ecs.fetchConfirmedBlockNum(ecs.ctx, block.BlockNum)
+		if err != nil {
+			// The events are processed once the confirmed block is fetched with a later block
+			ecs.logger.Warn("failed to fetch the confirmed block", "blockNumber", block.BlockNum, "error", err)
+		}
+	}
+
 	// lock the mutex for the shortest amount of time. The mutex only need to be locked to update the eventTracker data structure
 	ecs.eventTracker.mu.Lock()
 
 	if block != nil && block.BlockNum > ecs.eventTracker.latestBlock.BlockNum {
 		ecs.eventTracker.latestBlock = *block
 	}
+	if confirmedBlockNum > ecs.eventTracker.confirmedBlockNum {
+		ecs.eventTracker.confirmedBlockNum = confirmedBlockNum
+	}
 
 	if chainEvent != nil {
-		ecs.eventTracker.Push(*chainEvent)
-		ecs.logger.Debug("event added to queue", "updated-queue-length", ecs.eventTracker.events.Len())
+		ecs.trackEvent(*chainEvent)
 	}
 
 	eventsToDispatch := []ethTypes.Log{}
-	for ecs.eventTracker.events.Len() > 0 && ecs.eventTracker.latestBlock.BlockNum >= (ecs.eventTracker.events)[0].BlockNumber+REQUIRED_BLOCK_CONFIRMATIONS {
+	var fetchErr error
+	for ecs.eventTracker.events.Len() > 0 && (ecs.eventTracker.events)[0].BlockNumber <= ecs.eventTracker.confirmedBlockNum {
 		chainEvent := ecs.eventTracker.Pop()
 		ecs.logger.Debug("event popped from queue", "updated-queue-length", ecs.eventTracker.events.Len())
 
 		// Ensure event & associated tx is still in the chain before adding to eventsToDispatch
 		oldBlock, err := ecs.chain.BlockByNumber(context.Background(), new(big.Int).SetUint64(chainEvent.BlockNumber))
 		if err != nil {
-			ecs.logger.Error("failed to fetch block", "err", err)
-			errorChan <- fmt.Errorf("failed to fetch block: %v", err)
-			return
+			// The event is queued again, so that it is dispatched once its block can be fetched
+			ecs.eventTracker.Push(chainEvent)
+			fetchErr = fmt.Errorf("failed to fetch block %d: %w", chainEvent.BlockNumber, err)
+			break
 		}
 
 		if oldBlock.Hash() != chainEvent.BlockHash {
@@ -763,6 +983,7 @@ func (ecs *EthChainService) updateEventTracker(errorChan chan<- error, block *Bl
 		}
 
 		ecs.sentTxToChannelIdMap.Delete(chainEvent.TxHash.String())
+		ecs.eventCursor = cursorOf(chainEvent)
 		eventsToDispatch = append(eventsToDispatch, chainEvent)
 	}
 	ecs.eventTracker.mu.Unlock()
@@ -770,8 +991,10 @@ func (ecs *EthChainService) updateEventTracker(errorChan chan<- error, block *Bl
 	err := ecs.dispatchChainEvents(eventsToDispatch)
 	if err != nil {
 		errorChan <- fmt.Errorf("failed dispatchChainEvents: %w", err)
-		return
+		return nil
 	}
+
+	return fetchErr
 }
 
 // subscribeForLogs subscribes for logs and pushes them to the out channel.
@@ -822,19 +1045,10 @@ func (ecs *EthChainService) GetChainId() (*big.Int, error) {
 }
 
 func (ecs *EthChainService) GetLastConfirmedBlockNum() uint64 {
-	var confirmedBlockNum uint64
-
 	ecs.eventTracker.mu.Lock()
 	defer ecs.eventTracker.mu.Unlock()
 
-	// Check for potential underflow
-	if ecs.eventTracker.latestBlock.BlockNum >= REQUIRED_BLOCK_CONFIRMATIONS {
-		confirmedBlockNum = ecs.eventTracker.latestBlock.BlockNum - REQUIRED_BLOCK_CONFIRMATIONS
-	} else {
-		confirmedBlockNum = 0
-	}
-
-	return confirmedBlockNum
+	return ecs.eventTracker.confirmedBlockNum
 }
 
 func (ecs *EthChainService) GetBlockByNumber(blockNum *big.Int) (*ethTypes.Block, error) {
@@ -846,6 +1060,10 @@ func (ecs *EthChainService) GetBlockByNumber(blockNum *big.Int) (*ethTypes.Block
 	return block, nil
 }
 
+func (ecs *EthChainService) GetTransactionReceipt(txHash common.Hash) (*ethTypes.Receipt, error) {
+	return ecs.chain.TransactionReceipt(ecs.ctx, txHash)
+}
+
 func (ecs *EthChainService) Close() error {
 	ecs.cancel()
 	ecs.wg.Wait()
@@ -886,6 +1104,10 @@ func (ecs *EthChainService) handleApproveTx(tokenAddress common.Address, amount
 		return ethTypes.Log{}, err
 	}
 
+	if ecs.pollInterval > 0 {
+		return ecs.pollForApproval(token, tokenAddress, amount)
+	}
+
 	approvalLogsChan := make(chan *Token.TokenApproval)
 
 	newBlockChan := make(chan *ethTypes.Header)
@@ -907,10 +1129,7 @@ func (ecs *EthChainService) handleApproveTx(tokenAddress common.Address, amount
 	// Get current block
 	currentBlock := <-newBlockChan
 
-	isApproveTxRetried := false
-
-	// Transaction hash of retried Approve transaction
-	var retryApproveTxHash common.Hash
+	var numOfRetries uint
 
 	// Wait for the Approve transaction to be mined before continuing
 	for {
@@ -923,37 +1142,52 @@ func (ecs *EthChainService) handleApproveTx(tokenAddress common.Address, amount
 		case err := <-approvalSubscription.Err():
 			return ethTypes.Log{}, err
 		case newBlock := <-newBlockChan:
-			if (newBlock.Number.Int64() - currentBlock.Number.Int64()) > BLOCKS_WITHOUT_EVENT_THRESHOLD {
-				if isApproveTxRetried {
-					err := fmt.Errorf("approve transaction was retried with higher gas and event Approval was not emitted till latest block, txHash: %s, latestBlock: %s", retryApproveTxHash, newBlock.Number.String())
+			if newBlock.Number.Uint64()-currentBlock.Number.Uint64() > ecs.confirmationPolicy.BlocksWithoutEventThreshold {
+				reApproveTx, err := ecs.resubmitApproveTx(token, tokenAddress, approveTx, amount, numOfRetries, newBlock.Number.Uint64())
+				if err != nil {
 					return ethTypes.Log{}, err
 				}
 
-				slog.Error("event Approval was not emitted", "approveTxHash", approveTx.Hash().String())
+				numOfRetries++
+				currentBlock = newBlock
+				approveTx = reApproveTx
+			}
+		}
+	}
+}
 
-				// Estimate gas for new Approve transaction
-				estimatedGasLimit, err := ecs.estimateGasForApproveTx(tokenAddress, amount)
-				if err != nil {
-					return ethTypes.Log{}, err
-				}
+// resubmitApproveTx replaces an Approve transaction whose Approval event was not emitted with one paying higher fees, as per the retry policy,
+// and with GAS_LIMIT_MULTIPLIER times the estimated gas limit
+func (ecs *EthChainService) resubmitApproveTx(token *Token.Token, tokenAddress common.Address, approveTx *ethTypes.Transaction, amount *big.Int, numOfRetries uint, latestBlockNum uint64) (*ethTypes.Transaction, error) {
+	if !ecs.retryPolicy.CanRetry(numOfRetries) {
+		return nil, fmt.Errorf("approve transaction was resubmitted %d times and event Approval was not emitted till latest block, txHash: %s, latestBlock: %d: %w", numOfRetries, approveTx.Hash(), latestBlockNum, ErrRetryLimitReached)
+	}
 
-				approveTxOpts := ecs.defaultTxOpts()
+	slog.Error("event Approval was not emitted", "approveTxHash", approveTx.Hash().String())
 
-				// Multiply estimated gas limit with set multiplier
-				approveTxOpts.GasLimit = uint64(float64(estimatedGasLimit) * GAS_LIMIT_MULTIPLIER)
-				reApproveTx, err := token.Approve(approveTxOpts, ecs.naAddress, amount)
-				if err != nil {
-					return ethTypes.Log{}, err
-				}
+	approveTxOpts, err := ecs.replacementTxOpts(approveTx)
+	if err != nil {
+		return nil, err
+	}
 
-				isApproveTxRetried = true
-				currentBlock = newBlock
-				retryApproveTxHash = reApproveTx.Hash()
+	// Estimate gas for new Approve transaction
+	estimatedGasLimit, err := ecs.estimateGasForApproveTx(tokenAddress, amount)
+	if err != nil {
+		return nil, err
+	}
+	approveTxOpts.GasLimit = uint64(float64(estimatedGasLimit) * GAS_LIMIT_MULTIPLIER)
 
-				slog.Info("Resubmitted transaction with higher gas limit", "gasLimit", approveTxOpts.GasLimit, "approveTxHash", reApproveTx.Hash().String())
-			}
-		}
+	reApproveTx, err := token.Approve(approveTxOpts, ecs.naAddress, amount)
+	if err != nil {
+		return nil, err
 	}
+
+	slog.Info("Resubmitted approve transaction with bumped fees", "gasTipCap", reApproveTx.GasTipCap(), "gasFeeCap", reApproveTx.GasFeeCap(), "gasLimit", reApproveTx.Gas(), "approveTxHash", reApproveTx.Hash().String())
+	return reApproveTx, nil
+}
+
+func (ecs *EthChainService) GetRetryPolicy() RetryPolicy {
+	return ecs.retryPolicy
 }
 
 func (ecs *EthChainService) GetChain() ethChain {
@@ -0,0 +1,74 @@
+package chainservice
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// EventCursor is the position in the chain of an adjudicator event.
+// The cursor of the last event handled by the engine is persisted, so that a restarted chain service
+// does not deliver the events up to the cursor again.
+//
+// Events are delivered at least once, not exactly once: the cursor is stored after the engine has handled the event,
+// and not atomically with the changes the handler made, so the last event may be delivered again after a crash.
+// Events are also delivered again after a re-org of the block of the cursor. Handlers must be idempotent, which they are
+// since events set the on chain holdings rather than adding to them, and channels reject events older than their last chain update.
+type EventCursor struct {
+	BlockNum  uint64
+	BlockHash common.Hash
+	LogIndex  uint
+}
+
+// IsZero returns true if the cursor does not point to an event, for instance because no event has been handled yet
+func (c EventCursor) IsZero() bool {
+	return c == EventCursor{}
+}
+
+// covers returns true if the log is at or before the position of the cursor, so it has been delivered already
+func (c EventCursor) covers(l ethTypes.Log) bool {
+	if c.IsZero() {
+		return false
+	}
+	if l.BlockNumber == c.BlockNum {
+		return l.Index <= c.LogIndex
+	}
+	return l.BlockNumber < c.BlockNum
+}
+
+// cursorOf returns the position of the log in the chain
+func cursorOf(l ethTypes.Log) EventCursor {
+	return EventCursor{BlockNum: l.BlockNumber, BlockHash: l.BlockHash, LogIndex: l.Index}
+}
+
+// resumeFrom sets the cursor of the last delivered event, and returns the block from which to search for events.
+//
+// If the block of the cursor is still in the chain, so are all the blocks before it. Otherwise the chain forked at an
+// unknown depth before that block, so the events of the last MaxEpochs blocks before it are searched and delivered again.
+func (ecs *EthChainService) resumeFrom(cursor EventCursor, startBlockNum uint64) (uint64, error) {
+	if cursor.IsZero() {
+		return startBlockNum, nil
+	}
+
+	header, err := ecs.chain.HeaderByNumber(context.Background(), new(big.Int).SetUint64(cursor.BlockNum))
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return 0, err
+	}
+	if err == nil && header.Hash() == cursor.BlockHash {
+		ecs.eventCursor = cursor
+		return max(startBlockNum, cursor.BlockNum), nil
+	}
+
+	resumeBlockNum := startBlockNum
+	if cursor.BlockNum > ecs.confirmationPolicy.MaxEpochs {
+		resumeBlockNum = max(startBlockNum, cursor.BlockNum-ecs.confirmationPolicy.MaxEpochs)
+	}
+	ecs.logger.Warn("the block of the last handled event is no longer in the chain (possible re-org), delivering the events since an earlier block",
+		"blockNumber", cursor.BlockNum, "blockHash", cursor.BlockHash, "resumeBlockNumber", resumeBlockNum)
+
+	return resumeBlockNum, nil
+}
@@ -0,0 +1,63 @@
+package chainservice
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestEventCursor(t *testing.T) {
+	sim, bindings, ethAccounts, err := SetupSimulatedBackend(1)
+	defer closeSimulatedChain(t, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := newPollingSimulatedBackendChainService(t, sim, bindings, ethAccounts[0], EventCursor{})
+	defer closeChainService(t, cs)
+
+	deposits := []Event{}
+	for _, channelId := range []types.Destination{{1}, {2}} {
+		_, err = cs.SendTransaction(protocols.NewDepositTransaction(channelId, types.Funds{common.Address{}: big.NewInt(1)}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		deposits = append(deposits, receiveEvent(t, cs.EventEngineFeed()))
+	}
+
+	// resume restarts the chain service from the cursor, and checks that it delivers the expected events and nothing else
+	resume := func(t *testing.T, cursor EventCursor, expected ...Event) {
+		restarted := newPollingSimulatedBackendChainService(t, sim, bindings, ethAccounts[0], cursor)
+		defer closeChainService(t, restarted)
+
+		for _, want := range expected {
+			got := receiveEvent(t, restarted.EventEngineFeed())
+			if got.TxHash() != want.TxHash() || got.Cursor() != want.Cursor() {
+				t.Fatalf("expected the event at %+v, got the event at %+v", want.Cursor(), got.Cursor())
+			}
+		}
+		select {
+		case event := <-restarted.EventEngineFeed():
+			t.Fatalf("expected no more events, got the event at %+v", event.Cursor())
+		case <-time.After(10 * testPollInterval):
+		}
+	}
+
+	t.Run("the events after the cursor are delivered", func(t *testing.T) {
+		resume(t, deposits[0].Cursor(), deposits[1])
+	})
+
+	t.Run("no event is delivered again", func(t *testing.T) {
+		resume(t, deposits[1].Cursor())
+	})
+
+	t.Run("the events of a re-orged block and the blocks before it are delivered again", func(t *testing.T) {
+		reorged := deposits[1].Cursor()
+		reorged.BlockHash = common.HexToHash("0x1")
+		resume(t, reorged, deposits[0], deposits[1])
+	})
+}
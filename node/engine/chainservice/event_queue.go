@@ -8,9 +8,10 @@ import (
 )
 
 type eventTracker struct {
-	latestBlock Block
-	events      eventQueue
-	mu          sync.Mutex
+	latestBlock       Block
+	confirmedBlockNum uint64 // confirmedBlockNum is the highest block whose events are final enough to be processed
+	events            eventQueue
+	mu                sync.Mutex
 }
 
 type Block struct {
@@ -32,12 +33,24 @@ func (eT *eventTracker) Pop() types.Log {
 	return heap.Pop(&eT.events).(types.Log)
 }
 
+// Contains returns whether the log is already queued
+func (eT *eventTracker) Contains(l types.Log) bool {
+	for _, queued := range eT.events {
+		if queued.BlockHash == l.BlockHash && queued.Index == l.Index {
+			return true
+		}
+	}
+	return false
+}
+
 type eventQueue []types.Log
 
 func (q eventQueue) Len() int { return len(q) }
+
+// Less orders the events by their position in the chain, so that they are dispatched in the order they were emitted
 func (q eventQueue) Less(i, j int) bool {
 	if q[i].BlockNumber == q[j].BlockNumber {
-		return i < j
+		return q[i].Index < q[j].Index
 	}
 	return q[i].BlockNumber < q[j].BlockNumber
 }
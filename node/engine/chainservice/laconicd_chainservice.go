@@ -1,75 +1,100 @@
 package chainservice
 
 import (
-	"math/big"
+	"context"
+	"fmt"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	ethTypes "github.com/ethereum/go-ethereum/core/types"
 
-	"github.com/statechannels/go-nitro/protocols"
-	"github.com/statechannels/go-nitro/types"
+	NitroAdjudicator "github.com/statechannels/go-nitro/node/engine/chainservice/adjudicator"
+	chainutils "github.com/statechannels/go-nitro/node/engine/chainservice/utils"
 )
 
 type LaconicdChainOpts struct {
-	VpaAddress common.Address
-	CaAddress  common.Address
+	ChainUrl           string
+	ChainStartBlockNum uint64
+	ChainAuthToken     string
+	ChainPk            string
+	NaAddress          common.Address
+	VpaAddress         common.Address
+	CaAddress          common.Address
+	// RetryPolicy is used to resubmit dropped transactions, DefaultRetryPolicy is used if it is not set
+	RetryPolicy RetryPolicy
+	// PollInterval, if set, makes the chain service poll laconicd for new blocks and events at this interval
+	// instead of subscribing to them, for RPC endpoints which do not support subscriptions
+	PollInterval time.Duration
+	// EventCursor is the position of the last event handled by the engine. The events up to it are not delivered again.
+	EventCursor EventCursor
+	// ConfirmationPolicy determines when chain events are processed. The fields which are not set are taken from the preset for the chain ID
+	ConfirmationPolicy ConfirmationPolicyConfig
 }
+
+// LaconicdChainService is the chain service of L2 nodes. Laconicd exposes an Ethereum compatible json-rpc endpoint,
+// so adjudicator transactions are submitted and adjudicator events are watched the same way as on L1.
+// This gives mirrored bridge channels on L2 the same dispute guarantees as their L1 counterparts.
 type LaconicdChainService struct {
-	consensusAppAddress      common.Address
-	virtualPaymentAppAddress common.Address
+	*EthChainService
 }
 
+// NewLaconicdChainService connects to the laconicd json-rpc endpoint and returns a chain service watching the adjudicator deployed on it
 func NewLaconicdChainService(chainOpts LaconicdChainOpts) (*LaconicdChainService, error) {
-	return &LaconicdChainService{
-		chainOpts.CaAddress,
-		chainOpts.VpaAddress,
-	}, nil
-}
-
-func (lcs *LaconicdChainService) SendTransaction(tx protocols.ChainTransaction) (*ethTypes.Transaction, error) {
-	return nil, nil
-}
-
-func (lcs *LaconicdChainService) DroppedEventEngineFeed() <-chan protocols.DroppedEventInfo {
-	return nil
-}
-
-func (lcs *LaconicdChainService) DroppedEventFeed() <-chan protocols.DroppedEventInfo {
-	return nil
-}
-
-func (lcs *LaconicdChainService) EventEngineFeed() <-chan Event {
-	return nil
-}
-
-func (lcs *LaconicdChainService) EventFeed() <-chan Event {
-	return nil
-}
-
-func (lcs *LaconicdChainService) GetConsensusAppAddress() common.Address {
-	return lcs.consensusAppAddress
-}
-
-func (lcs *LaconicdChainService) GetVirtualPaymentAppAddress() common.Address {
-	return lcs.virtualPaymentAppAddress
-}
-
-func (lcs *LaconicdChainService) GetChainId() (*big.Int, error) {
-	return nil, nil
-}
-
-func (lcs *LaconicdChainService) GetLastConfirmedBlockNum() uint64 {
-	return 0
-}
-
-func (lcs *LaconicdChainService) GetBlockByNumber(blockNum *big.Int) (*ethTypes.Block, error) {
-	return &ethTypes.Block{}, nil
-}
-
-func (lcs *LaconicdChainService) GetL1ChannelFromL2(l2Channel types.Destination) (types.Destination, error) {
-	return types.Destination{}, nil
+	if chainOpts.ChainPk == "" {
+		return nil, fmt.Errorf("chainpk must be set")
+	}
+	if chainOpts.VpaAddress == chainOpts.CaAddress {
+		return nil, fmt.Errorf("virtual payment app address and consensus app address cannot be the same: %s", chainOpts.VpaAddress.String())
+	}
+
+	ethClient, txSigner, err := chainutils.ConnectToChain(
+		context.Background(),
+		chainOpts.ChainUrl,
+		chainOpts.ChainAuthToken,
+		common.Hex2Bytes(chainOpts.ChainPk),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to laconicd: %w", err)
+	}
+
+	na, err := NitroAdjudicator.NewNitroAdjudicator(chainOpts.NaAddress, ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	chainId, err := ethClient.ChainID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	confirmationPolicy := ConfirmationPolicyForChain(chainId).Override(chainOpts.ConfirmationPolicy)
+
+	lcs, err := newLaconicdChainService(ethClient, chainOpts.ChainStartBlockNum, chainOpts.EventCursor, confirmationPolicy, na, chainOpts.NaAddress, chainOpts.CaAddress, chainOpts.VpaAddress, txSigner, chainOpts.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	if !chainOpts.RetryPolicy.IsZero() {
+		lcs.retryPolicy = chainOpts.RetryPolicy
+	}
+
+	return lcs, nil
 }
 
-func (lcs *LaconicdChainService) Close() error {
-	return nil
+// newLaconicdChainService constructs a chain service that submits transactions to a NitroAdjudicator deployed on laconicd
+// and listens to events from it, polling for them if pollInterval is set
+func newLaconicdChainService(chain ethChain, startBlockNum uint64, cursor EventCursor, confirmationPolicy ConfirmationPolicy, na *NitroAdjudicator.NitroAdjudicator,
+	naAddress, caAddress, vpaAddress common.Address, txSigner *bind.TransactOpts, pollInterval time.Duration,
+) (*LaconicdChainService, error) {
+	var ecs *EthChainService
+	var err error
+	if pollInterval > 0 {
+		ecs, err = newPollingEthChainService(chain, startBlockNum, cursor, confirmationPolicy, na, naAddress, caAddress, vpaAddress, txSigner, pollInterval)
+	} else {
+		ecs, err = newEthChainService(chain, startBlockNum, cursor, confirmationPolicy, na, naAddress, caAddress, vpaAddress, txSigner)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &LaconicdChainService{ecs}, nil
 }
@@ -0,0 +1,210 @@
+package chainservice
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	NitroAdjudicator "github.com/statechannels/go-nitro/node/engine/chainservice/adjudicator"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestLaconicdChainService(t *testing.T) {
+	sim, bindings, ethAccounts, err := SetupSimulatedBackend(1)
+	defer closeSimulatedChain(t, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs, err := newLaconicdChainService(sim, 0, EventCursor{}, DefaultConfirmationPolicy(),
+		bindings.Adjudicator.Contract,
+		bindings.Adjudicator.Address,
+		bindings.ConsensusApp.Address,
+		bindings.VirtualPaymentApp.Address,
+		ethAccounts[0],
+		0)
+	defer closeChainService(t, cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainId, err := cs.GetChainId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainId.Cmp(big.NewInt(TEST_CHAIN_ID)) != 0 {
+		t.Fatalf("unexpected chain id: got %s wanted %d", chainId, TEST_CHAIN_ID)
+	}
+
+	// mineConfirmed mines the submitted transaction along with enough blocks for its events to be confirmed
+	mineConfirmed := func() {
+		for i := 0; i < 1+REQUIRED_BLOCK_CONFIRMATIONS; i++ {
+			sim.Commit()
+		}
+	}
+
+	s := state.State{
+		Participants:      []types.Address{Alice.Address(), Bob.Address()},
+		ChannelNonce:      37140676581,
+		AppDefinition:     bindings.ConsensusApp.Address,
+		ChallengeDuration: CHALLENGE_DURATION,
+		AppData:           []byte{},
+		Outcome:           concludeOutcome,
+		TurnNum:           uint64(2),
+	}
+	channelId := s.ChannelId()
+
+	challengerSig, err := NitroAdjudicator.SignChallengeMessage(s, Alice.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedState := state.NewSignedState(s)
+	for _, pk := range [][]byte{Alice.PrivateKey, Bob.PrivateKey} {
+		sig, err := s.Sign(pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = signedState.AddSignature(sig)
+	}
+
+	_, err = cs.SendTransaction(protocols.NewDepositTransaction(channelId, types.Funds{common.Address{}: big.NewInt(2)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mineConfirmed()
+
+	_, err = cs.SendTransaction(protocols.NewChallengeTransaction(channelId, signedState, make([]state.SignedState, 0), challengerSig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mineConfirmed()
+
+	receiveEvent := func() Event {
+		select {
+		case event := <-cs.EventEngineFeed():
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for chain event")
+			return nil
+		}
+	}
+
+	depositedEvent, ok := receiveEvent().(DepositedEvent)
+	if !ok {
+		t.Fatalf("expected a DepositedEvent")
+	}
+	if depositedEvent.ChannelID() != channelId || depositedEvent.NowHeld.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("unexpected DepositedEvent %+v", depositedEvent)
+	}
+
+	crEvent, ok := receiveEvent().(ChallengeRegisteredEvent)
+	if !ok {
+		t.Fatalf("expected a ChallengeRegisteredEvent")
+	}
+	if crEvent.ChannelID() != channelId {
+		t.Fatalf("unexpected ChallengeRegisteredEvent %+v", crEvent)
+	}
+
+	if cs.GetLastConfirmedBlockNum() < crEvent.Block().BlockNum {
+		t.Fatalf("expected block %d to be confirmed, last confirmed block is %d", crEvent.Block().BlockNum, cs.GetLastConfirmedBlockNum())
+	}
+}
+
+func TestLaconicdChainServiceConclude(t *testing.T) {
+	sim, bindings, ethAccounts, err := SetupSimulatedBackend(1)
+	defer closeSimulatedChain(t, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs, err := newLaconicdChainService(sim, 0, EventCursor{}, DefaultConfirmationPolicy(),
+		bindings.Adjudicator.Contract,
+		bindings.Adjudicator.Address,
+		bindings.ConsensusApp.Address,
+		bindings.VirtualPaymentApp.Address,
+		ethAccounts[0],
+		0)
+	defer closeChainService(t, cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mineConfirmed mines the submitted transaction along with enough blocks for its events to be confirmed
+	mineConfirmed := func() {
+		for i := 0; i < 1+REQUIRED_BLOCK_CONFIRMATIONS; i++ {
+			sim.Commit()
+		}
+	}
+
+	s := state.State{
+		Participants:      []types.Address{Alice.Address(), Bob.Address()},
+		ChannelNonce:      37140676582,
+		AppDefinition:     bindings.ConsensusApp.Address,
+		ChallengeDuration: CHALLENGE_DURATION,
+		AppData:           []byte{},
+		Outcome:           concludeOutcome,
+		TurnNum:           uint64(2),
+		IsFinal:           true,
+	}
+	channelId := s.ChannelId()
+
+	signedState := state.NewSignedState(s)
+	for _, pk := range [][]byte{Alice.PrivateKey, Bob.PrivateKey} {
+		sig, err := s.Sign(pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = signedState.AddSignature(sig)
+	}
+
+	_, err = cs.SendTransaction(protocols.NewDepositTransaction(channelId, types.Funds{common.Address{}: big.NewInt(2)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mineConfirmed()
+
+	_, err = cs.SendTransaction(protocols.NewWithdrawAllTransaction(channelId, signedState))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mineConfirmed()
+
+	receiveEvent := func() Event {
+		select {
+		case event := <-cs.EventEngineFeed():
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for chain event")
+			return nil
+		}
+	}
+
+	if _, ok := receiveEvent().(DepositedEvent); !ok {
+		t.Fatalf("expected a DepositedEvent")
+	}
+
+	concludedEvent, ok := receiveEvent().(ConcludedEvent)
+	if !ok {
+		t.Fatalf("expected a ConcludedEvent")
+	}
+	if concludedEvent.ChannelID() != channelId {
+		t.Fatalf("unexpected ConcludedEvent %+v", concludedEvent)
+	}
+
+	if _, ok := receiveEvent().(AllocationUpdatedEvent); !ok {
+		t.Fatalf("expected an AllocationUpdatedEvent")
+	}
+
+	// The channel is finalized and its funds are transferred out, so the adjudicator no longer holds any status for it
+	statusOnChain, err := bindings.Adjudicator.Contract.StatusOf(&bind.CallOpts{}, channelId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statusOnChain != [32]byte{} {
+		t.Fatalf("expected the channel status to be cleared, got %s", common.Bytes2Hex(statusOnChain[:]))
+	}
+}
@@ -53,6 +53,14 @@ func (mc *MockChain) SubmitTransaction(tx protocols.ChainTransaction) error {
 			event := NewAllocationUpdatedEvent(tx.ChannelId(), Block{BlockNum: mc.BlockNum}, 0, assetAddress, common.Big0, common.Hash{})
 			eventsToBroadcast = append(eventsToBroadcast, event)
 		}
+		// Like the adjudicator, credit allocations to other channels to their holdings (without an event)
+		for _, assetExit := range tx.SignedState.State().Outcome {
+			for _, allocation := range assetExit.Allocations {
+				if !allocation.Destination.IsExternal() && allocation.Amount.Sign() > 0 {
+					mc.holdings[allocation.Destination] = mc.holdings[allocation.Destination].Add(types.Funds{assetExit.Asset: allocation.Amount})
+				}
+			}
+		}
 		mc.holdings[tx.ChannelId()] = types.Funds{}
 	default:
 		return fmt.Errorf("unexpected transaction type %T", tx)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/statechannels/go-nitro/channel/state"
 	"github.com/statechannels/go-nitro/protocols"
 	"github.com/statechannels/go-nitro/types"
 )
@@ -28,6 +29,18 @@ func (mc *MockChainService) SendTransaction(tx protocols.ChainTransaction) (*eth
 	return nil, err
 }
 
+// ResendTransaction responds to the given tx, as transactions are never dropped from the mock chain.
+func (mc *MockChainService) ResendTransaction(tx protocols.ChainTransaction, droppedTxHash common.Hash, numOfRetries uint) (*ethTypes.Transaction, error) {
+	if !mc.GetRetryPolicy().CanRetry(numOfRetries) {
+		return nil, ErrRetryLimitReached
+	}
+	return mc.SendTransaction(tx)
+}
+
+func (mc *MockChainService) GetRetryPolicy() RetryPolicy {
+	return DefaultRetryPolicy()
+}
+
 // GetConsensusAppAddress returns the zero address, since the mock chain will not run any application logic.
 func (mc *MockChainService) GetConsensusAppAddress() types.Address {
 	return types.Address{}
@@ -42,6 +55,19 @@ func (mc *MockChainService) GetL1ChannelFromL2(l2Channel types.Destination) (typ
 	return types.Destination{}, nil
 }
 
+// StateIsSupported returns whether every participant has signed the candidate state, since the mock chain will not run any application logic.
+func (mc *MockChainService) StateIsSupported(candidate state.SignedState, proof []state.SignedState) (bool, string, error) {
+	if !candidate.HasAllSignatures() {
+		return false, "!unanimous", nil
+	}
+	return true, "", nil
+}
+
+// GetChallengeStatus returns no challenge, since the mock chain does not record the status of channels.
+func (mc *MockChainService) GetChallengeStatus(channelId types.Destination) (uint64, uint64, error) {
+	return 0, 0, nil
+}
+
 func (mc *MockChainService) EventEngineFeed() <-chan Event {
 	return mc.eventFeed
 }
@@ -74,6 +100,15 @@ func (mc *MockChainService) GetBlockByNumber(blockNum *big.Int) (*ethTypes.Block
 	return &ethTypes.Block{}, nil
 }
 
+// GetTransactionReceipt returns a successful receipt at the current block, since the mock chain executes transactions as soon as they are submitted.
+func (mc *MockChainService) GetTransactionReceipt(txHash common.Hash) (*ethTypes.Receipt, error) {
+	return &ethTypes.Receipt{
+		TxHash:      txHash,
+		Status:      ethTypes.ReceiptStatusSuccessful,
+		BlockNumber: new(big.Int).SetUint64(mc.GetLastConfirmedBlockNum()),
+	}, nil
+}
+
 func (mc *MockChainService) Close() error {
 	return nil
 }
@@ -0,0 +1,298 @@
+package chainservice
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+
+	NitroAdjudicator "github.com/statechannels/go-nitro/node/engine/chainservice/adjudicator"
+	Token "github.com/statechannels/go-nitro/node/engine/chainservice/erc20"
+)
+
+// logPoller tracks which blocks a polling chain service has searched for adjudicator events
+type logPoller struct {
+	// nextBlockNum is the first block which has not been searched yet
+	nextBlockNum uint64
+	// unconfirmedBlocks holds the hashes of the searched blocks which do not have enough confirmations yet, so that re-orgs can be detected
+	unconfirmedBlocks map[uint64]common.Hash
+}
+
+// newPollingEthChainService constructs a chain service that submits transactions to a NitroAdjudicator
+// and polls the chain for its events with eth_getLogs and eth_blockNumber, for RPC endpoints which do not support subscriptions.
+// Events are dispatched with the same confirmations, and dropped on the same re-orgs, as with the subscribing chain service.
+func newPollingEthChainService(chain ethChain, startBlockNum uint64, cursor EventCursor, confirmationPolicy ConfirmationPolicy, na *NitroAdjudicator.NitroAdjudicator,
+	naAddress, caAddress, vpaAddress common.Address, txSigner *bind.TransactOpts, pollInterval time.Duration,
+) (*EthChainService, error) {
+	ecs, err := initEthChainService(chain, startBlockNum, cursor, confirmationPolicy, na, naAddress, caAddress, vpaAddress, txSigner)
+	if err != nil {
+		return nil, err
+	}
+	ecs.pollInterval = pollInterval
+
+	poller := &logPoller{
+		nextBlockNum:      ecs.eventTracker.latestBlock.BlockNum,
+		unconfirmedBlocks: make(map[uint64]common.Hash),
+	}
+	errChan := make(chan error)
+
+	ecs.wg.Add(2)
+	go ecs.pollForEventLogs(errChan, poller)
+	go ecs.listenForErrors(errChan)
+
+	return ecs, nil
+}
+
+// pollForEventLogs polls the chain for new blocks and events until the chain service is closed.
+// The first poll searches for any missed events emitted while this node was offline.
+func (ecs *EthChainService) pollForEventLogs(errorChan chan<- error, poller *logPoller) {
+	ticker := time.NewTicker(ecs.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := ecs.poll(errorChan, poller)
+		if err != nil {
+			ecs.logger.Warn("failed to poll the chain, retrying", "error", err, "pollInterval", ecs.pollInterval)
+		}
+
+		select {
+		case <-ecs.ctx.Done():
+			ecs.wg.Done()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll searches the blocks mined since the last poll for adjudicator events, in ranges of at most MAX_QUERY_BLOCK_RANGE blocks,
+// and passes the events and the latest block to the event tracker.
+func (ecs *EthChainService) poll(errorChan chan<- error, poller *logPoller) error {
+	err := ecs.rewindReorgedBlocks(poller)
+	if err != nil {
+		return err
+	}
+
+	latestBlockNum, err := ecs.chain.BlockNumber(ecs.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the latest block number: %w", err)
+	}
+	confirmedBlockNum, err := ecs.fetchConfirmedBlockNum(ecs.ctx, latestBlockNum)
+	if err != nil {
+		return fmt.Errorf("failed to get the confirmed block number: %w", err)
+	}
+
+	for poller.nextBlockNum <= latestBlockNum {
+		from := poller.nextBlockNum
+		to := min(from+MAX_QUERY_BLOCK_RANGE-1, latestBlockNum)
+
+		// The hashes are recorded before searching the blocks, so that a re-org in between is detected by the next poll
+		latestBlock, err := ecs.recordUnconfirmedBlocks(poller, from, to, confirmedBlockNum)
+		if err != nil {
+			return err
+		}
+
+		logs, err := ecs.chain.FilterLogs(ecs.ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{ecs.naAddress},
+			Topics:    [][]common.Hash{topicsToWatch},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get the logs of blocks %d to %d: %w", from, to, err)
+		}
+		ecs.logger.Debug("polled chain events", "fromBlock", from, "toBlock", to, "numEvents", len(logs))
+
+		for i := range logs {
+			err = ecs.updateEventTracker(errorChan, nil, &logs[i])
+			if err != nil {
+				return err
+			}
+		}
+		err = ecs.updateEventTracker(errorChan, &latestBlock, nil)
+		if err != nil {
+			return err
+		}
+
+		poller.nextBlockNum = to + 1
+	}
+
+	// The finalized block may move on without new blocks being mined, so the events it finalizes are processed here.
+	// The latest block has already been passed to the event tracker, which ignores blocks it has seen.
+	if ecs.confirmationPolicy.UseFinalizedTag {
+		return ecs.updateEventTracker(errorChan, &Block{BlockNum: latestBlockNum}, nil)
+	}
+
+	return nil
+}
+
+// recordUnconfirmedBlocks records the hashes of the blocks from..to which are not confirmed yet,
+// forgets the blocks which have since been confirmed, and returns block to.
+func (ecs *EthChainService) recordUnconfirmedBlocks(poller *logPoller, from, to, confirmedBlockNum uint64) (Block, error) {
+	for blockNum := range poller.unconfirmedBlocks {
+		if blockNum < confirmedBlockNum {
+			delete(poller.unconfirmedBlocks, blockNum)
+		}
+	}
+
+	var header *ethTypes.Header
+	for blockNum := max(from, min(confirmedBlockNum, to)); blockNum <= to; blockNum++ {
+		var err error
+		header, err = ecs.chain.HeaderByNumber(ecs.ctx, new(big.Int).SetUint64(blockNum))
+		if err != nil {
+			return Block{}, fmt.Errorf("failed to get block %d: %w", blockNum, err)
+		}
+		if blockNum >= confirmedBlockNum {
+			poller.unconfirmedBlocks[blockNum] = header.Hash()
+		}
+	}
+
+	return Block{BlockNum: to, Timestamp: header.Time}, nil
+}
+
+// rewindReorgedBlocks moves the poller back to the first searched block which is no longer part of the chain, so that the blocks
+// which replaced it are searched too. The events found in the re-orged blocks are dropped by the event tracker.
+func (ecs *EthChainService) rewindReorgedBlocks(poller *logPoller) error {
+	if poller.nextBlockNum == 0 {
+		return nil
+	}
+	lastBlockNum := poller.nextBlockNum - 1
+	lastBlockHash, ok := poller.unconfirmedBlocks[lastBlockNum]
+	if !ok {
+		return nil
+	}
+
+	// A re-org replaces every block after the fork, so checking the last searched block is enough to detect one
+	inChain, err := ecs.isInChain(lastBlockNum, lastBlockHash)
+	if err != nil || inChain {
+		return err
+	}
+
+	firstBlockNum := lastBlockNum
+	for blockNum := range poller.unconfirmedBlocks {
+		firstBlockNum = min(firstBlockNum, blockNum)
+	}
+
+	forkBlockNum := lastBlockNum
+	for blockNum := firstBlockNum; blockNum < lastBlockNum; blockNum++ {
+		hash, ok := poller.unconfirmedBlocks[blockNum]
+		if !ok {
+			continue
+		}
+		inChain, err := ecs.isInChain(blockNum, hash)
+		if err != nil {
+			return err
+		}
+		if !inChain {
+			forkBlockNum = blockNum
+			break
+		}
+	}
+
+	for blockNum := range poller.unconfirmedBlocks {
+		if blockNum >= forkBlockNum {
+			delete(poller.unconfirmedBlocks, blockNum)
+		}
+	}
+
+	ecs.logger.Warn("searching blocks again for events after a re-org", "fromBlock", forkBlockNum, "toBlock", lastBlockNum)
+	poller.nextBlockNum = forkBlockNum
+	return nil
+}
+
+// isInChain returns whether the block with the given number and hash is part of the chain
+func (ecs *EthChainService) isInChain(blockNum uint64, hash common.Hash) (bool, error) {
+	header, err := ecs.chain.HeaderByNumber(ecs.ctx, new(big.Int).SetUint64(blockNum))
+	if errors.Is(err, ethereum.NotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get block %d: %w", blockNum, err)
+	}
+
+	return header.Hash() == hash, nil
+}
+
+// pollForApproval submits an Approve transaction and polls for the Approval event it emits.
+// The transaction is resubmitted as per the retry policy if it is not mined within the BlocksWithoutEventThreshold of the confirmation policy.
+//
+// As in the subscription path, the Approval is detected by its owner rather than by transaction hash, since any of the submitted transactions may be mined.
+// Only the Approvals emitted by a transaction with the nonce of one of the submitted transactions are considered, so that earlier approvals are ignored.
+func (ecs *EthChainService) pollForApproval(token *Token.Token, tokenAddress common.Address, amount *big.Int) (ethTypes.Log, error) {
+	firstSubmittedAt, err := ecs.chain.BlockNumber(ecs.ctx)
+	if err != nil {
+		return ethTypes.Log{}, err
+	}
+	approveTx, err := token.Approve(ecs.defaultTxOpts(), ecs.naAddress, amount)
+	if err != nil {
+		return ethTypes.Log{}, err
+	}
+	submittedAt := firstSubmittedAt
+	approveNonces := map[uint64]bool{approveTx.Nonce(): true}
+
+	ticker := time.NewTicker(ecs.pollInterval)
+	defer ticker.Stop()
+
+	var numOfRetries uint
+	for {
+		select {
+		case <-ecs.ctx.Done():
+			return ethTypes.Log{}, ecs.ctx.Err()
+		case <-ticker.C:
+		}
+
+		approvalLog, found, err := ecs.findApproval(token, firstSubmittedAt, approveNonces)
+		if err != nil {
+			return ethTypes.Log{}, err
+		}
+		if found {
+			return approvalLog, nil
+		}
+
+		latestBlockNum, err := ecs.chain.BlockNumber(ecs.ctx)
+		if err != nil {
+			return ethTypes.Log{}, err
+		}
+		if latestBlockNum-submittedAt > ecs.confirmationPolicy.BlocksWithoutEventThreshold {
+			reApproveTx, err := ecs.resubmitApproveTx(token, tokenAddress, approveTx, amount, numOfRetries, latestBlockNum)
+			if err != nil {
+				return ethTypes.Log{}, err
+			}
+
+			numOfRetries++
+			submittedAt = latestBlockNum
+			approveTx = reApproveTx
+			approveNonces[approveTx.Nonce()] = true
+		}
+	}
+}
+
+// findApproval returns the Approval of the nitro adjudicator by the chain service's account emitted since fromBlock by a transaction with one of the given nonces, if any
+func (ecs *EthChainService) findApproval(token *Token.Token, fromBlock uint64, nonces map[uint64]bool) (ethTypes.Log, bool, error) {
+	approvals, err := token.FilterApproval(&bind.FilterOpts{Start: fromBlock, Context: ecs.ctx}, []common.Address{ecs.txSigner.From}, []common.Address{ecs.naAddress})
+	if err != nil {
+		return ethTypes.Log{}, false, err
+	}
+	defer approvals.Close()
+
+	for approvals.Next() {
+		l := approvals.Event.Raw
+		if l.Removed {
+			continue
+		}
+
+		tx, _, err := ecs.chain.TransactionByHash(ecs.ctx, l.TxHash)
+		if err != nil {
+			return ethTypes.Log{}, false, err
+		}
+		if nonces[tx.Nonce()] {
+			return l, true, nil
+		}
+	}
+
+	return ethTypes.Log{}, false, approvals.Error()
+}
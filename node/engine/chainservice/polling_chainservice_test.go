@@ -0,0 +1,226 @@
+package chainservice
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+const testPollInterval = 10 * time.Millisecond
+
+// newPollingSimulatedBackendChainService constructs a chain service which polls the simulated chain, starting after the cursor,
+// and mines a block for every transaction
+func newPollingSimulatedBackendChainService(t *testing.T, sim SimulatedChain, bindings Bindings, txSigner *bind.TransactOpts, cursor EventCursor) *SimulatedBackendChainService {
+	ecs, err := newPollingEthChainService(sim, 0, cursor, DefaultConfirmationPolicy(),
+		bindings.Adjudicator.Contract,
+		bindings.Adjudicator.Address,
+		bindings.ConsensusApp.Address,
+		bindings.VirtualPaymentApp.Address,
+		txSigner,
+		testPollInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &SimulatedBackendChainService{sim: sim, EthChainService: ecs}
+}
+
+// receiveEvent returns the next event from the feed, or fails the test if there is none within a few seconds
+func receiveEvent[T any](t *testing.T, feed <-chan T) T {
+	select {
+	case event := <-feed:
+		return event
+	case <-time.After(5 * time.Second):
+		var event T
+		t.Fatalf("timed out waiting for a %T", event)
+		return event
+	}
+}
+
+func TestPollingChainService(t *testing.T) {
+	sim, bindings, ethAccounts, err := SetupSimulatedBackend(2)
+	defer closeSimulatedChain(t, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := newPollingSimulatedBackendChainService(t, sim, bindings, ethAccounts[0], EventCursor{})
+	defer closeChainService(t, cs)
+
+	channelId := types.Destination{1}
+	_, err = cs.SendTransaction(protocols.NewDepositTransaction(channelId, types.Funds{common.Address{}: big.NewInt(3)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deposited, ok := receiveEvent(t, cs.EventEngineFeed()).(DepositedEvent)
+	if !ok || deposited.ChannelID() != channelId || deposited.NowHeld.Cmp(big.NewInt(3)) != 0 {
+		t.Fatalf("expected a deposit of 3 into channel %s, got %+v", channelId, deposited)
+	}
+
+	// A chain service started later finds the events emitted before it was running
+	cs2 := newPollingSimulatedBackendChainService(t, sim, bindings, ethAccounts[1], EventCursor{})
+	defer closeChainService(t, cs2)
+
+	missed, ok := receiveEvent(t, cs2.EventEngineFeed()).(DepositedEvent)
+	if !ok || missed.TxHash() != deposited.TxHash() {
+		t.Fatalf("expected the missed deposit %s, got %+v", deposited.TxHash(), missed)
+	}
+	if blockNum := cs2.GetLastConfirmedBlockNum(); blockNum != deposited.Block().BlockNum {
+		t.Fatalf("expected the last confirmed block to be %d, got %d", deposited.Block().BlockNum, blockNum)
+	}
+}
+
+func TestPollingChainServiceReorg(t *testing.T) {
+	sim, bindings, ethAccounts, err := SetupSimulatedBackend(1)
+	defer closeSimulatedChain(t, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := newPollingSimulatedBackendChainService(t, sim, bindings, ethAccounts[0], EventCursor{})
+	defer closeChainService(t, cs)
+
+	forkPoint, err := sim.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The deposit is mined without waiting for confirmations, so that it can be re-orged out of the chain
+	channelId := types.Destination{1}
+	_, err = cs.EthChainService.SendTransaction(protocols.NewDepositTransaction(channelId, types.Funds{common.Address{}: big.NewInt(1)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	time.Sleep(10 * testPollInterval)
+
+	// A longer chain without the deposit replaces the block it was mined in
+	err = sim.(*BackendWrapper).Fork(forkPoint.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sim.(*BackendWrapper).AdjustTime(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < REQUIRED_BLOCK_CONFIRMATIONS+1; i++ {
+		sim.Commit()
+	}
+
+	dropped := receiveEvent(t, cs.DroppedEventEngineFeed())
+	if dropped.ChannelId != channelId || dropped.EventName != "Deposited" {
+		t.Fatalf("expected the deposit into channel %s to be dropped, got %+v", channelId, dropped)
+	}
+	select {
+	case event := <-cs.EventEngineFeed():
+		t.Fatalf("expected no event to be dispatched, got %+v", event)
+	case <-time.After(10 * testPollInterval):
+	}
+}
+
+// unreachableChain is a simulated chain whose blocks cannot be fetched while it is unreachable
+type unreachableChain struct {
+	SimulatedChain
+	unreachable atomic.Bool
+}
+
+func (uc *unreachableChain) BlockByNumber(ctx context.Context, number *big.Int) (*ethTypes.Block, error) {
+	if uc.unreachable.Load() {
+		return nil, errors.New("connection refused")
+	}
+	return uc.SimulatedChain.BlockByNumber(ctx, number)
+}
+
+func TestPollingChainServiceRetriesEvents(t *testing.T) {
+	sim, bindings, ethAccounts, err := SetupSimulatedBackend(1)
+	defer closeSimulatedChain(t, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := &unreachableChain{SimulatedChain: sim}
+
+	cs := newPollingSimulatedBackendChainService(t, chain, bindings, ethAccounts[0], EventCursor{})
+	defer closeChainService(t, cs)
+
+	// The deposit is confirmed while its block cannot be fetched, so it is queued again instead of being dispatched
+	chain.unreachable.Store(true)
+	channelId := types.Destination{1}
+	_, err = cs.SendTransaction(protocols.NewDepositTransaction(channelId, types.Funds{common.Address{}: big.NewInt(2)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * testPollInterval)
+	select {
+	case event := <-cs.EventEngineFeed():
+		t.Fatalf("expected no event while the chain is unreachable, got %+v", event)
+	default:
+	}
+
+	chain.unreachable.Store(false)
+	deposited, ok := receiveEvent(t, cs.EventEngineFeed()).(DepositedEvent)
+	if !ok || deposited.ChannelID() != channelId || deposited.NowHeld.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("expected a deposit of 2 into channel %s, got %+v", channelId, deposited)
+	}
+}
+
+func TestPollingChainServicePollsForApproval(t *testing.T) {
+	sim, bindings, ethAccounts, err := SetupSimulatedBackend(1)
+	defer closeSimulatedChain(t, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := newPollingSimulatedBackendChainService(t, sim, bindings, ethAccounts[0], EventCursor{})
+	defer closeChainService(t, cs)
+
+	// An earlier approval is not mistaken for the one being polled for
+	_, err = bindings.Token.Contract.Approve(ethAccounts[0], bindings.Adjudicator.Address, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	type result struct {
+		log ethTypes.Log
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		l, err := cs.pollForApproval(bindings.Token.Contract, bindings.Token.Address, big.NewInt(5))
+		done <- result{l, err}
+	}()
+
+	ticker := time.NewTicker(testPollInterval)
+	defer ticker.Stop()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case r := <-done:
+			if r.err != nil {
+				t.Fatal(r.err)
+			}
+			approval, err := bindings.Token.Contract.ParseApproval(r.log)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if approval.Owner != ethAccounts[0].From || approval.Spender != bindings.Adjudicator.Address || approval.Value.Cmp(big.NewInt(5)) != 0 {
+				t.Fatalf("expected an approval of 5 by %s, got %+v", ethAccounts[0].From, approval)
+			}
+			return
+		case <-ticker.C:
+			sim.Commit()
+		case <-timeout:
+			t.Fatal("timed out polling for the approval")
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package chainservice
+
+import (
+	"errors"
+	"math/big"
+	"time"
+)
+
+// ErrRetryLimitReached is returned when a transaction has already been resubmitted the maximum number of times allowed by the RetryPolicy
+var ErrRetryLimitReached = errors.New("transaction retry limit reached")
+
+// RetryPolicy determines how transactions which were dropped or not mined in time are resubmitted
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a transaction is resubmitted
+	MaxAttempts uint
+	// Backoff is the delay before the first resubmission, it doubles on every subsequent resubmission up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// FeeBumpMultiplier is applied to the GasTipCap and GasFeeCap (or GasPrice on legacy chains) of the replaced transaction.
+	// Most nodes only accept a replacement transaction if its fees are bumped by at least 10%
+	FeeBumpMultiplier float64
+	// ReplaceByNonce resubmits a transaction with the nonce of the transaction it replaces, so that at most one of them is mined
+	ReplaceByNonce bool
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       3,
+		Backoff:           MIN_BACKOFF_TIME,
+		MaxBackoff:        MAX_BACKOFF_TIME,
+		FeeBumpMultiplier: 1.2,
+		ReplaceByNonce:    true,
+	}
+}
+
+// IsZero returns true if the policy has not been configured
+func (rp RetryPolicy) IsZero() bool {
+	return rp == RetryPolicy{}
+}
+
+// CanRetry returns true if a transaction which has already been resubmitted numOfRetries times can be resubmitted again
+func (rp RetryPolicy) CanRetry(numOfRetries uint) bool {
+	return numOfRetries < rp.MaxAttempts
+}
+
+// BackoffFor returns how long to wait before resubmitting a transaction which has already been resubmitted numOfRetries times
+func (rp RetryPolicy) BackoffFor(numOfRetries uint) time.Duration {
+	backoff := rp.Backoff
+	for i := uint(0); i < numOfRetries && backoff < rp.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > rp.MaxBackoff {
+		return rp.MaxBackoff
+	}
+	return backoff
+}
+
+// BumpFee returns the given fee multiplied by the policy's FeeBumpMultiplier
+func (rp RetryPolicy) BumpFee(fee *big.Int) *big.Int {
+	if fee == nil {
+		return nil
+	}
+
+	bumpedFee, _ := new(big.Float).Mul(new(big.Float).SetInt(fee), big.NewFloat(rp.FeeBumpMultiplier)).Int(nil)
+
+	// Make sure tiny fees are still bumped despite rounding
+	if bumpedFee.Cmp(fee) <= 0 {
+		bumpedFee.Add(fee, big.NewInt(1))
+	}
+	return bumpedFee
+}
@@ -0,0 +1,47 @@
+package chainservice
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	rp := RetryPolicy{
+		MaxAttempts:       2,
+		Backoff:           time.Second,
+		MaxBackoff:        3 * time.Second,
+		FeeBumpMultiplier: 1.5,
+		ReplaceByNonce:    true,
+	}
+
+	t.Run("CanRetry", func(t *testing.T) {
+		if !rp.CanRetry(0) || !rp.CanRetry(1) {
+			t.Fatal("expected retries below MaxAttempts to be allowed")
+		}
+		if rp.CanRetry(2) {
+			t.Fatal("expected retries at MaxAttempts to be rejected")
+		}
+	})
+
+	t.Run("BackoffFor", func(t *testing.T) {
+		want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+		for numOfRetries, w := range want {
+			if got := rp.BackoffFor(uint(numOfRetries)); got != w {
+				t.Fatalf("backoff for %d retries: expected %s, got %s", numOfRetries, w, got)
+			}
+		}
+	})
+
+	t.Run("BumpFee", func(t *testing.T) {
+		if got := rp.BumpFee(big.NewInt(100)); got.Cmp(big.NewInt(150)) != 0 {
+			t.Fatalf("expected bumped fee 150, got %s", got)
+		}
+		if got := rp.BumpFee(big.NewInt(1)); got.Cmp(big.NewInt(2)) != 0 {
+			t.Fatalf("expected tiny fee to be bumped to 2, got %s", got)
+		}
+		if got := rp.BumpFee(nil); got != nil {
+			t.Fatalf("expected nil fee to stay nil, got %s", got)
+		}
+	})
+}
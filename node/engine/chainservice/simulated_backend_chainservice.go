@@ -79,7 +79,7 @@ type SimulatedBackendChainService struct {
 func NewSimulatedBackendChainService(sim SimulatedChain, bindings Bindings,
 	txSigner *bind.TransactOpts,
 ) (ChainService, error) {
-	ethChainService, err := newEthChainService(sim, 0,
+	ethChainService, err := newEthChainService(sim, 0, EventCursor{}, DefaultConfirmationPolicy(),
 		bindings.Adjudicator.Contract,
 		bindings.Adjudicator.Address,
 		bindings.ConsensusApp.Address,
@@ -100,14 +100,28 @@ func (sbcs *SimulatedBackendChainService) SendTransaction(tx protocols.ChainTran
 	}
 	sbcs.sim.Commit()
 
-	// Mint additional blocks to satisfy REQUIRED_BLOCK_CONFIRMATIONS.
-	for i := 0; i < REQUIRED_BLOCK_CONFIRMATIONS; i++ {
+	// Mint additional blocks to satisfy the RequiredBlockConfirmations of the confirmation policy.
+	for i := uint64(0); i < sbcs.confirmationPolicy.RequiredBlockConfirmations; i++ {
 		sbcs.sim.Commit()
 	}
 
 	return nil, nil
 }
 
+// ResendTransaction resubmits the dropped transaction and blocks until it has been mined.
+func (sbcs *SimulatedBackendChainService) ResendTransaction(tx protocols.ChainTransaction, droppedTxHash common.Hash, numOfRetries uint) (*ethTypes.Transaction, error) {
+	resentTx, err := sbcs.EthChainService.ResendTransaction(tx, droppedTxHash, numOfRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := uint64(0); i < 1+sbcs.confirmationPolicy.RequiredBlockConfirmations; i++ {
+		sbcs.sim.Commit()
+	}
+
+	return resentTx, nil
+}
+
 // SetupSimulatedBackend creates a new SimulatedBackend with the supplied number of transacting accounts, deploys the Nitro Adjudicator and returns both.
 func SetupSimulatedBackend(numAccounts uint64) (SimulatedChain, Bindings, []*bind.TransactOpts, error) {
 	accounts := make([]*bind.TransactOpts, numAccounts)
@@ -125,5 +125,8 @@ func deployContract[T contractBackend](ctx context.Context, name string, ethClie
 }
 
 type LaconicdChain struct {
+	ChainUrl          string
+	ChainAuthToken    string
+	ChainPks          []string
 	ContractAddresses ContractAddresses
 }
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/directdefund"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// channelTypeNames are the names of the channel types which may be configured in EngineOpts.
+// Only ledger channels are countered automatically, since their challenges are handled by the direct defund protocol.
+// Stale challenges in virtual and swap channels are not countered automatically, whatever the configuration.
+var channelTypeNames = map[string]types.ChannelType{
+	"ledger": types.Ledger,
+}
+
+// autoCounterChallenge returns whether challenges registered by a counterparty with a stale state are countered automatically in channels of the given type
+func (opts EngineOpts) autoCounterChallenge(channelType types.ChannelType) bool {
+	if enabled, ok := opts.AutoCounterChallenge[channelType]; ok {
+		return enabled
+	}
+
+	return true
+}
+
+// ParseManualCounterChallenges parses a comma-delimited list of channel types, such as "ledger",
+// whose challenges are left to be countered through the API rather than automatically.
+func ParseManualCounterChallenges(s string) (map[types.ChannelType]bool, error) {
+	autoCounterChallenge := make(map[types.ChannelType]bool)
+	if strings.TrimSpace(s) == "" {
+		return autoCounterChallenge, nil
+	}
+
+	for _, name := range strings.Split(s, ",") {
+		channelType, ok := channelTypeNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("invalid channel type %q: only ledger channels are countered automatically", name)
+		}
+
+		autoCounterChallenge[channelType] = false
+	}
+
+	return autoCounterChallenge, nil
+}
+
+// counterStaleChallenge prepares the direct defund objective of a channel to counter a challenge which a counterparty
+// has registered with a state older than our latest supported state, so that it cannot finalize the channel with an outdated outcome.
+//
+// The challenge is cleared with a checkpoint of our latest supported state. A channel which funds virtual channels is challenged
+// with that state instead, since their guarantees could no longer be reclaimed once the channel is open again.
+// The objective is left untouched if it is already countering the challenge, for instance after a request through the API.
+func (e *Engine) counterStaleChallenge(objective protocols.Objective, c *channel.Channel, chainEvent chainservice.Event) {
+	challenge, ok := chainEvent.(chainservice.ChallengeRegisteredEvent)
+	if !ok || challenge.IsInitiatedByMe || challenge.ChannelID() != c.Id {
+		return
+	}
+
+	ddfo, ok := objective.(*directdefund.Objective)
+	if !ok || ddfo.IsChallenge || ddfo.IsCheckpoint || c.OnChain.ChannelMode != channel.Challenge {
+		return
+	}
+
+	if !e.opts.autoCounterChallenge(c.Type) {
+		e.logger.Info("Leaving the challenge to be countered manually", "channelId", c.Id, "channelType", c.Type)
+		return
+	}
+
+	candidate, err := challenge.SignedState(c.FixedPart)
+	if err != nil {
+		e.logger.Error("Could not read the state of the registered challenge", "channelId", c.Id, "error", err)
+		return
+	}
+	latestSupportedState, err := c.LatestSupportedState()
+	if err != nil || candidate.State().TurnNum >= latestSupportedState.TurnNum {
+		return
+	}
+
+	ddfo.IsChallenge = len(ddfo.FundedChannels) != 0
+	ddfo.IsCheckpoint = !ddfo.IsChallenge
+
+	e.logger.Info("Countering stale challenge", "channelId", c.Id, "challengeTurnNum", candidate.State().TurnNum, "latestTurnNum", latestSupportedState.TurnNum, "checkpoint", ddfo.IsCheckpoint)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/statechannels/go-nitro/channel/consensus_channel"
 	"github.com/statechannels/go-nitro/channel/state"
 	"github.com/statechannels/go-nitro/internal/logging"
+	"github.com/statechannels/go-nitro/internal/safesync"
 	"github.com/statechannels/go-nitro/node/engine/chainservice"
 	"github.com/statechannels/go-nitro/node/engine/messageservice"
 	p2pms "github.com/statechannels/go-nitro/node/engine/messageservice/p2p-message-service"
@@ -30,12 +31,17 @@ import (
 	"github.com/statechannels/go-nitro/protocols/bridgedfund"
 	"github.com/statechannels/go-nitro/protocols/directdefund"
 	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/ledgertopup"
+	"github.com/statechannels/go-nitro/protocols/ledgerwithdraw"
 	"github.com/statechannels/go-nitro/protocols/mirrorbridgeddefund"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/rebalance"
 	"github.com/statechannels/go-nitro/protocols/swap"
 	"github.com/statechannels/go-nitro/protocols/swapdefund"
 	"github.com/statechannels/go-nitro/protocols/swapfund"
 	"github.com/statechannels/go-nitro/protocols/virtualdefund"
 	"github.com/statechannels/go-nitro/protocols/virtualfund"
+	"github.com/statechannels/go-nitro/routing"
 	"github.com/statechannels/go-nitro/types"
 )
 
@@ -61,16 +67,34 @@ func (e *ErrGetObjective) Error() string {
 	return fmt.Sprintf("unexpected error getting/creating objective %s: %v", e.objectiveId, e.wrappedError)
 }
 
-// nonFatalErrors is a list of errors for which the engine should not panic
-var nonFatalErrors = []error{
-	&ErrGetObjective{},
-	store.ErrLoadVouchers,
-	directfund.ErrLedgerChannelExists,
-	virtualfund.ErrUpdatingLedgerFunding,
-	swapfund.ErrUpdatingLedgerFunding,
-	swapfund.ErrZeroFunds,
-	errEmptyDroppedEvent,
-	swap.ErrInvalidSwap,
+// ErrObjectiveFailed is an engine error when an objective cannot make progress under its protocol. The objective is marked as failed.
+type ErrObjectiveFailed struct {
+	wrappedError error
+	objectiveId  protocols.ObjectiveId
+}
+
+func (e *ErrObjectiveFailed) Error() string {
+	return fmt.Sprintf("objective %s failed: %v", e.objectiveId, e.wrappedError)
+}
+
+func (e *ErrObjectiveFailed) Unwrap() error {
+	return e.wrappedError
+}
+
+// newErrObjectiveFailed attributes err to the objective with the given id, unless it is already attributed to an objective
+func newErrObjectiveFailed(objectiveId protocols.ObjectiveId, err error) error {
+	var objectiveErr *ErrObjectiveFailed
+	if err == nil || errors.As(err, &objectiveErr) {
+		return err
+	}
+
+	return &ErrObjectiveFailed{wrappedError: err, objectiveId: objectiveId}
+}
+
+// fatalErrors is a list of errors for which the engine should panic, as continuing would risk acting on corrupted data.
+// Other errors fail the objective they are attributed to, if any, and the engine keeps running.
+var fatalErrors = []error{
+	store.ErrCorruptedData,
 }
 
 // Engine is the imperative part of the core business logic of a go-nitro Node
@@ -81,8 +105,9 @@ type Engine struct {
 	ObjectiveRequestsFromAPI        chan protocols.ObjectiveRequest
 	PaymentRequestsFromAPI          chan PaymentRequest
 	CounterChallengeRequestsFromAPI chan CounterChallengeRequest
-	RetryObjectiveTxRequestFromAPI  chan types.RetryObjectiveTxRequest
-	ConfirmSwapRequestFromAPI       chan types.ConfirmSwapRequest
+	RetryObjectiveTxRequestFromAPI  chan RetryObjectiveTxRequest
+	ConfirmSwapRequestFromAPI       chan ConfirmSwapRequest
+	CancelSwapRequestFromAPI        chan CancelSwapRequest
 
 	fromChain             <-chan chainservice.Event
 	droppedEventFromChain <-chan protocols.DroppedEventInfo
@@ -97,8 +122,19 @@ type Engine struct {
 
 	store       store.Store // A Store for persisting and restoring important data
 	policymaker PolicyMaker // A PolicyMaker decides whether to approve or reject objectives
+	opts        EngineOpts
 	logger      *slog.Logger
 	vm          *payments.VoucherManager
+	graph       *routing.Graph // A Graph of the ledger channels in the network, for finding routes for virtual channels
+
+	// numOfTxRetries counts how many times the dropped transaction of an objective has been resubmitted
+	numOfTxRetries map[protocols.ObjectiveId]uint
+	// txsToResend holds the dropped transactions to be replaced, keyed by channel, when the next transaction for that channel is submitted
+	txsToResend map[types.Destination]txRetry
+	// objectiveRequestErrors holds the errors from handling objective requests, until they are taken by the API caller
+	objectiveRequestErrors *safesync.Map[error]
+
+	chainId *big.Int
 
 	wg     *sync.WaitGroup
 	cancel context.CancelFunc
@@ -108,6 +144,8 @@ type Engine struct {
 type PaymentRequest struct {
 	ChannelId types.Destination
 	Amount    *big.Int
+	// ErrChan receives the outcome of handling the request, if it is set. It should be buffered.
+	ErrChan chan error
 }
 
 // CounterChallengeRequest represents a request from the API to initiate a counter challenge against registered challenge
@@ -115,6 +153,36 @@ type CounterChallengeRequest struct {
 	ChannelId types.Destination
 	Action    types.CounterChallengeAction
 	Payload   state.SignedState
+	// ErrChan receives the outcome of handling the request, if it is set. It should be buffered.
+	ErrChan chan error
+}
+
+// RetryObjectiveTxRequest represents a request from the API to resubmit the dropped transaction of an objective
+type RetryObjectiveTxRequest struct {
+	types.RetryObjectiveTxRequest
+	// ErrChan receives the outcome of handling the request, if it is set. It should be buffered.
+	ErrChan chan error
+}
+
+// ConfirmSwapRequest represents a request from the API to accept or reject a swap
+type ConfirmSwapRequest struct {
+	types.ConfirmSwapRequest
+	// ErrChan receives the outcome of handling the request, if it is set. It should be buffered.
+	ErrChan chan error
+}
+
+// CancelSwapRequest represents a request from the API to cancel a swap we initiated which has not been accepted yet
+type CancelSwapRequest struct {
+	SwapId types.Destination
+	// ErrChan receives the outcome of handling the request, if it is set. It should be buffered.
+	ErrChan chan error
+}
+
+// respond sends the outcome of handling an API request to the caller, if the caller is waiting for it
+func respond(errChan chan<- error, err error) {
+	if errChan != nil {
+		errChan <- err
+	}
 }
 
 // EngineEvent is a struct that contains a list of changes caused by handling a message/chain event/api event
@@ -122,7 +190,7 @@ type EngineEvent struct {
 	// These are objectives that are now completed
 	CompletedObjectives []protocols.Objective
 	// These are objectives that have failed
-	FailedObjectives []protocols.ObjectiveId
+	FailedObjectives []query.FailedObjectiveInfo
 	// ReceivedVouchers are vouchers we've received from other participants
 	ReceivedVouchers []payments.Voucher
 
@@ -132,6 +200,8 @@ type EngineEvent struct {
 	PaymentChannelUpdates []query.PaymentChannelInfo
 	// SwapUpdates contains info for updates in swap
 	SwapUpdates []query.SwapInfo
+	// SwapChannelUpdates contains channel info for swap channels that have been updated
+	SwapChannelUpdates []query.SwapChannelInfo
 }
 
 // IsEmpty returns true if the EngineEvent contains no changes
@@ -141,7 +211,8 @@ func (ee *EngineEvent) IsEmpty() bool {
 		len(ee.ReceivedVouchers) == 0 &&
 		len(ee.LedgerChannelUpdates) == 0 &&
 		len(ee.PaymentChannelUpdates) == 0 &&
-		len(ee.SwapUpdates) == 0
+		len(ee.SwapUpdates) == 0 &&
+		len(ee.SwapChannelUpdates) == 0
 }
 
 func (ee *EngineEvent) Merge(other EngineEvent) {
@@ -151,6 +222,13 @@ func (ee *EngineEvent) Merge(other EngineEvent) {
 	ee.LedgerChannelUpdates = append(ee.LedgerChannelUpdates, other.LedgerChannelUpdates...)
 	ee.PaymentChannelUpdates = append(ee.PaymentChannelUpdates, other.PaymentChannelUpdates...)
 	ee.SwapUpdates = append(ee.SwapUpdates, other.SwapUpdates...)
+	ee.SwapChannelUpdates = append(ee.SwapChannelUpdates, other.SwapChannelUpdates...)
+}
+
+// txRetry holds the information needed to replace a dropped transaction as per the chain service's retry policy
+type txRetry struct {
+	droppedTxHash common.Hash
+	numOfRetries  uint
 }
 
 type CompletedObjectiveEvent struct {
@@ -161,7 +239,7 @@ type CompletedObjectiveEvent struct {
 type Response struct{}
 
 // NewEngine is the constructor for an Engine
-func New(vm *payments.VoucherManager, msg messageservice.MessageService, chain chainservice.ChainService, store store.Store, policymaker PolicyMaker, eventHandler func(EngineEvent)) Engine {
+func New(vm *payments.VoucherManager, msg messageservice.MessageService, chain chainservice.ChainService, store store.Store, policymaker PolicyMaker, opts EngineOpts, eventHandler func(EngineEvent)) (Engine, error) {
 	e := Engine{}
 	e.logger = logging.LoggerWithAddress(slog.Default(), *store.GetAddress())
 	e.store = store
@@ -171,8 +249,9 @@ func New(vm *payments.VoucherManager, msg messageservice.MessageService, chain c
 	e.ObjectiveRequestsFromAPI = make(chan protocols.ObjectiveRequest)
 	e.PaymentRequestsFromAPI = make(chan PaymentRequest)
 	e.CounterChallengeRequestsFromAPI = make(chan CounterChallengeRequest)
-	e.RetryObjectiveTxRequestFromAPI = make(chan types.RetryObjectiveTxRequest)
-	e.ConfirmSwapRequestFromAPI = make(chan types.ConfirmSwapRequest)
+	e.RetryObjectiveTxRequestFromAPI = make(chan RetryObjectiveTxRequest)
+	e.ConfirmSwapRequestFromAPI = make(chan ConfirmSwapRequest)
+	e.CancelSwapRequestFromAPI = make(chan CancelSwapRequest)
 
 	e.fromChain = chain.EventEngineFeed()
 	e.droppedEventFromChain = chain.DroppedEventEngineFeed()
@@ -182,11 +261,29 @@ func New(vm *payments.VoucherManager, msg messageservice.MessageService, chain c
 	e.chain = chain
 	e.msg = msg
 
+	chainId, err := chain.GetChainId()
+	if err != nil {
+		return Engine{}, fmt.Errorf("could not get chain id from chain service: %w", err)
+	}
+	e.chainId = chainId
+
 	e.eventHandler = eventHandler
 
 	e.policymaker = policymaker
+	if rp, ok := policymaker.(*RulePolicy); ok {
+		rp.bindStore(store)
+	}
+	if qp, ok := opts.SwapPolicy.(*QuotePolicy); ok {
+		qp.bindStore(store)
+	}
+	e.opts = opts
 
 	e.vm = vm
+	e.graph = routing.NewGraph()
+
+	e.numOfTxRetries = make(map[protocols.ObjectiveId]uint)
+	e.txsToResend = make(map[types.Destination]txRetry)
+	e.objectiveRequestErrors = &safesync.Map[error]{}
 
 	e.logger.Info("Constructed Engine")
 
@@ -198,7 +295,7 @@ func New(vm *payments.VoucherManager, msg messageservice.MessageService, chain c
 	e.wg.Add(1)
 	go e.run(ctx)
 
-	return e
+	return e, nil
 }
 
 func (e *Engine) Close() error {
@@ -214,13 +311,18 @@ func (e *Engine) Close() error {
 // run kicks of an infinite loop that waits for communications on the supplied channels, and handles them accordingly
 // The loop exits when the context is cancelled.
 func (e *Engine) run(ctx context.Context) {
+	deadlineTicker := time.NewTicker(OBJECTIVE_DEADLINE_CHECK_INTERVAL)
+	defer deadlineTicker.Stop()
+	announcementTicker := time.NewTicker(e.opts.capacityAnnouncementInterval())
+	defer announcementTicker.Stop()
+
 	for {
 		var res EngineEvent
 		var err error
 
 		var blockTicker <-chan time.Time
-		_, isEthChainService := e.chain.(*chainservice.EthChainService)
-		if isEthChainService {
+		switch e.chain.(type) {
+		case *chainservice.EthChainService, *chainservice.LaconicdChainService:
 			blockTicker = time.NewTicker(5 * time.Second).C
 		}
 
@@ -230,8 +332,13 @@ func (e *Engine) run(ctx context.Context) {
 			res, err = e.handleObjectiveRequest(or)
 		case pr := <-e.PaymentRequestsFromAPI:
 			res, err = e.handlePaymentRequest(pr)
+			respond(pr.ErrChan, err)
 		case chainEvent := <-e.fromChain:
 			res, err = e.handleChainEvent(chainEvent)
+			if err == nil && !chainEvent.Cursor().IsZero() {
+				// The chain service does not deliver the event again once it has been handled, even after a restart
+				err = e.store.SetEventCursor(chainEvent.Cursor())
+			}
 		case droppedEventTxInfo := <-e.droppedEventFromChain:
 			err = e.handleDroppedChainEvent(droppedEventTxInfo)
 		case message := <-e.fromMsg:
@@ -241,15 +348,23 @@ func (e *Engine) run(ctx context.Context) {
 		case signReq := <-e.signRequests:
 			err = e.handleSignRequest(signReq)
 		case counterChallengeReq := <-e.CounterChallengeRequestsFromAPI:
-			err = e.handleCounterChallengeRequest(counterChallengeReq)
+			res, err = e.handleCounterChallengeRequest(counterChallengeReq)
+			respond(counterChallengeReq.ErrChan, err)
 		case retryObjectiveTxReq := <-e.RetryObjectiveTxRequestFromAPI:
-			err = e.handleRetryObjectiveTxRequest(retryObjectiveTxReq)
+			err = e.handleRetryObjectiveTxRequest(retryObjectiveTxReq.RetryObjectiveTxRequest)
+			respond(retryObjectiveTxReq.ErrChan, err)
 		case confirmSwapReq := <-e.ConfirmSwapRequestFromAPI:
-			res, err = e.handleConfirmSwapRequest(confirmSwapReq)
+			res, err = e.handleConfirmSwapRequest(confirmSwapReq.ConfirmSwapRequest)
+			respond(confirmSwapReq.ErrChan, err)
+		case cancelSwapReq := <-e.CancelSwapRequestFromAPI:
+			res, err = e.handleCancelSwapRequest(cancelSwapReq)
+			respond(cancelSwapReq.ErrChan, err)
 		case <-blockTicker:
 			blockNum := e.chain.GetLastConfirmedBlockNum()
 			err = e.store.SetLastBlockNumSeen(blockNum)
-			e.checkError(err)
+			if err != nil {
+				break
+			}
 
 			var block *ethTypes.Block
 
@@ -267,19 +382,34 @@ func (e *Engine) run(ctx context.Context) {
 
 				err = e.processStoreChannels(chainServiceBlock)
 			}
+		case <-deadlineTicker.C:
+			res, err = e.abandonExpiredObjectives()
+			if err == nil {
+				var swapRes EngineEvent
+				swapRes, err = e.rejectExpiredSwaps()
+				res.Merge(swapRes)
+			}
+			if err == nil {
+				var withdrawRes EngineEvent
+				withdrawRes, err = e.submitOverdueWithdrawals()
+				res.Merge(withdrawRes)
+			}
+		case <-announcementTicker.C:
+			err = e.announceCapacity()
 		case <-ctx.Done():
 			e.wg.Done()
 			return
 		}
 
 		// Handle errors
-		e.checkError(err)
+		res.Merge(e.handleError(err))
 
 		// Only send out an event if there are changes
 		if !res.IsEmpty() {
 
 			for _, obj := range res.CompletedObjectives {
 				e.logger.Info("Objective is complete & returned to API", logging.WithObjectiveIdAttribute(obj.Id()))
+				e.settleSwapPolicy(obj)
 			}
 			e.eventHandler(res)
 		}
@@ -302,6 +432,9 @@ func (e *Engine) handleProposal(proposal consensus_channel.Proposal) (EngineEven
 		e.logger.Info("Ignoring proposal for completed objective", logging.WithObjectiveIdAttribute(id))
 		return EngineEvent{}, nil
 	}
+	if ro, ok := obj.(*rebalance.Objective); ok && ro.GetStatus() == protocols.Rejected {
+		return EngineEvent{}, e.unlockRebalance(ro)
+	}
 	return e.attemptProgress(obj)
 }
 
@@ -368,6 +501,13 @@ func (e *Engine) handleMessage(message protocols.Message) (EngineEvent, error) {
 			}
 		}
 
+		if so, ok := objective.(*swap.Objective); ok {
+			if reinstated, ok := so.Reinstate(payload); ok {
+				e.logger.Info("Reinstating swap which was accepted before it was cancelled", logging.WithObjectiveIdAttribute(objective.Id()))
+				objective = reinstated
+			}
+		}
+
 		if objective.GetStatus() == protocols.Completed {
 			e.logger.Info("Ignoring payload for completed objective", logging.WithObjectiveIdAttribute(objective.Id()))
 
@@ -378,9 +518,16 @@ func (e *Engine) handleMessage(message protocols.Message) (EngineEvent, error) {
 			continue
 		}
 
+		if _, failed := e.store.GetObjectiveFailure(objective.Id()); failed {
+			e.logger.Info("Ignoring payload for failed objective", logging.WithObjectiveIdAttribute(objective.Id()))
+			continue
+		}
+
 		updatedObjective, err := objective.Update(payload)
 		if err != nil {
-			return EngineEvent{}, err
+			// A payload which does not apply to the objective is rejected, so that a peer cannot fail an objective it shares with us
+			e.logger.Error("Rejecting invalid payload", logging.WithObjectiveIdAttribute(objective.Id()), "err", err)
+			continue
 		}
 
 		progressEvent, err := e.attemptProgress(updatedObjective)
@@ -426,7 +573,16 @@ func (e *Engine) handleMessage(message protocols.Message) (EngineEvent, error) {
 
 		updatedObjective, err := objective.ReceiveProposal(entry)
 		if err != nil {
-			return EngineEvent{}, err
+			e.logger.Error("Rejecting invalid proposal", logging.WithObjectiveIdAttribute(objective.Id()), "err", err)
+			continue
+		}
+
+		if ro, ok := updatedObjective.(*rebalance.Objective); ok && ro.GetStatus() == protocols.Rejected {
+			err = e.unlockRebalance(ro)
+			if err != nil {
+				return EngineEvent{}, err
+			}
+			continue
 		}
 
 		progressEvent, err := e.attemptProgress(updatedObjective)
@@ -443,24 +599,75 @@ func (e *Engine) handleMessage(message protocols.Message) (EngineEvent, error) {
 		if err != nil {
 			return EngineEvent{}, err
 		}
+		if so, ok := objective.(*swap.Objective); ok {
+			if acknowledged, ok := so.AcknowledgeCancellation(); ok {
+				// the counterparty rejected a swap we cancelled, so it can no longer accept it
+				err = e.store.SetObjective(acknowledged)
+				if err != nil {
+					return EngineEvent{}, err
+				}
+				continue
+			}
+		}
 		if objective.GetStatus() == protocols.Rejected {
 			e.logger.Info("Ignoring payload for rejected objective", logging.WithObjectiveIdAttribute(objective.Id()))
 
 			continue
 		}
+		if objective.GetStatus() == protocols.Completed {
+			// e.g. a swap which we accepted before the counterparty cancelled it. The counterparty applies the swap when our acceptance arrives.
+			e.logger.Info("Ignoring rejection of completed objective", logging.WithObjectiveIdAttribute(objective.Id()))
+
+			continue
+		}
+		if ro, ok := objective.(*rebalance.Objective); ok && ro.Committed() {
+			// the settlement may be supported by the other participants, so we can only complete the rebalance, or resolve it on chain
+			e.logger.Info("Ignoring rejection of rebalance with a signed settlement", logging.WithObjectiveIdAttribute(objective.Id()))
+
+			continue
+		}
 
 		// we are rejecting due to a counterparty message notifying us of their rejection. We
 		// do not need to send a message back to that counterparty, and furthermore we assume that
-		// counterparty has already notified all other interested parties. We can therefore ignore the side effects
-		objective, _ = objective.Reject()
+		// counterparty has already notified all other interested parties. We can therefore ignore the side effects.
+		// The exception is a swap cancelled by its sender: our rejection acknowledges the cancellation.
+		objective, sideEffects := objective.Reject()
+		if _, ok := objective.(*swap.Objective); ok {
+			err = e.executeSideEffects(sideEffects)
+			if err != nil {
+				return EngineEvent{}, err
+			}
+		}
+		retractGuarantees(objective)
 		err = e.store.SetObjective(objective)
 		if err != nil {
 			return EngineEvent{}, err
 		}
+		if channelId := objective.OwnsChannel(); !channelId.IsZero() {
+			err = e.store.ReleaseChannelFromOwnership(channelId)
+			if err != nil {
+				return EngineEvent{}, err
+			}
+		}
+		if ro, ok := objective.(*rebalance.Objective); ok {
+			err = e.unlockRebalance(ro)
+			if err != nil {
+				return EngineEvent{}, err
+			}
+		}
+		err = e.restoreLedgerIfWithdrawalAbandoned(objective)
+		if err != nil {
+			return EngineEvent{}, err
+		}
 
 		allCompleted.CompletedObjectives = append(allCompleted.CompletedObjectives, objective)
 	}
 
+	err := e.handleCapacityAnnouncements(message.From, message.CapacityAnnouncements)
+	if err != nil {
+		return EngineEvent{}, err
+	}
+
 	for _, voucher := range message.Payments {
 
 		// TODO: return the amount we paid?
@@ -525,6 +732,13 @@ func (e *Engine) handleChainEvent(chainEvent chainservice.Event) (EngineEvent, e
 		}
 	}
 
+	// Deposits into a ledger channel governed by a ConsensusChannel (such as a top up) update its on chain funding
+	if deposited, isDeposited := chainEvent.(chainservice.DepositedEvent); isDeposited {
+		if ledger, err := e.store.GetConsensusChannelById(channelId); err == nil {
+			return e.updateLedgerFunding(ledger, deposited)
+		}
+	}
+
 	c, ok := e.store.GetChannelById(channelId)
 	if !ok {
 		// If channel doesn't exist and chain event is ChallengeRegistered then create a new direct defund objective
@@ -559,6 +773,14 @@ func (e *Engine) handleChainEvent(chainEvent chainservice.Event) (EngineEvent, e
 		}
 	}
 
+	if allocationUpdated, isAllocationUpdated := chainEvent.(chainservice.AllocationUpdatedEvent); isAllocationUpdated {
+		// c still holds the holdings from before the payout
+		err = e.fundSuccessorIfLedgerWithdrawn(c, allocationUpdated)
+		if err != nil {
+			return EngineEvent{}, err
+		}
+	}
+
 	updatedChannel, err := c.UpdateWithChainEvent(chainEvent)
 	if err != nil {
 		return EngineEvent{}, err
@@ -573,6 +795,7 @@ func (e *Engine) handleChainEvent(chainEvent chainservice.Event) (EngineEvent, e
 	objective, ok := e.store.GetObjectiveByChannelId(updatedChannel.Id)
 
 	if ok {
+		e.counterStaleChallenge(objective, updatedChannel, chainEvent)
 		return e.attemptProgress(objective)
 	}
 
@@ -671,140 +894,211 @@ func (e *Engine) checkAndProcessL2Channel(chainEvent chainservice.Event, isChall
 	return types.Destination{}, nil
 }
 
-// handleObjectiveRequest handles an ObjectiveRequest (triggered by a client API call).
-// It will attempt to spawn a new, approved objective.
-func (e *Engine) handleObjectiveRequest(or protocols.ObjectiveRequest) (EngineEvent, error) {
-	myAddress := *e.store.GetAddress()
+// updateLedgerFunding records a deposit into a ledger channel governed by a ConsensusChannel, and attempts progress
+// on the objective which owns the ledger channel (if any).
+func (e *Engine) updateLedgerFunding(ledger *consensus_channel.ConsensusChannel, event chainservice.DepositedEvent) (EngineEvent, error) {
+	if ledger.OnChainFunding == nil {
+		ledger.OnChainFunding = types.Funds{}
+	}
+	ledger.OnChainFunding[event.Asset] = new(big.Int).Set(event.NowHeld)
 
-	chainId, err := e.chain.GetChainId()
+	err := e.store.SetConsensusChannel(ledger)
 	if err != nil {
-		return EngineEvent{}, fmt.Errorf("could not get chain id from chain service: %w", err)
+		return EngineEvent{}, err
+	}
+
+	objective, ok := e.store.GetObjectiveByChannelId(ledger.Id)
+	if !ok {
+		return EngineEvent{}, nil
 	}
+	return e.attemptProgress(objective)
+}
+
+// handleObjectiveRequest handles an ObjectiveRequest (triggered by a client API call).
+// It will attempt to spawn a new, approved objective.
+func (e *Engine) handleObjectiveRequest(or protocols.ObjectiveRequest) (ee EngineEvent, err error) {
+	defer or.SignalObjectiveStarted()
+
+	myAddress := *e.store.GetAddress()
+	chainId := e.chainId
 
 	objectiveId := or.Id(myAddress, chainId)
-	failedEngineEvent := EngineEvent{FailedObjectives: []protocols.ObjectiveId{objectiveId}}
 	e.logger.Info("handling new objective request", logging.WithObjectiveIdAttribute(objectiveId))
-	defer or.SignalObjectiveStarted()
+
+	// Errors from creating the objective reject the request, without failing an objective with the same id, such as the objective
+	// of an earlier request to close the same channel. Once the objective is created, attemptProgress attributes protocol errors to it.
+	start := func(objective protocols.Objective) (EngineEvent, error) {
+		// A new request supersedes a previous failure of the objective with the same id
+		err := e.store.ClearObjectiveFailure(objectiveId)
+		if err != nil {
+			return EngineEvent{}, err
+		}
+
+		return e.attemptProgress(objective)
+	}
+
+	// Keep any error for the API caller before signalling that the objective has started
+	defer func() {
+		if err == nil {
+			return
+		}
+		e.objectiveRequestErrors.Store(string(objectiveId), err)
+	}()
+
 	switch request := or.(type) {
 
 	case virtualfund.ObjectiveRequest:
 		vfo, err := virtualfund.NewObjective(request, true, myAddress, chainId, e.store.GetConsensusChannel)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create virtualfund objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create virtualfund objective for %+v: %w", request, err)
 		}
 		// Only Alice or Bob care about registering the objective and keeping track of vouchers
 		lastParticipant := uint(len(vfo.V.Participants) - 1)
 		if vfo.MyRole == lastParticipant || vfo.MyRole == payments.PAYER_INDEX {
 			err = e.registerPaymentChannel(vfo)
 			if err != nil {
-				return failedEngineEvent, fmt.Errorf("could not register channel with payment/receipt manager: %w", err)
+				return EngineEvent{}, fmt.Errorf("could not register channel with payment/receipt manager: %w", err)
 			}
 		}
 
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("could not register channel with payment/receipt manager: %w", err)
+			return EngineEvent{}, fmt.Errorf("could not register channel with payment/receipt manager: %w", err)
 		}
-		return e.attemptProgress(&vfo)
+		return start(&vfo)
 
 	case swap.ObjectiveRequest:
 		so, err := swap.NewObjective(request, true, true, e.store.GetChannelById)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create swap objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create swap objective for %+v: %w", request, err)
 		}
 
-		return e.attemptProgress(&so)
+		return start(&so)
+
+	case multiswap.ObjectiveRequest:
+		mso, err := multiswap.NewObjective(request, true, myAddress, e.store.GetChannelById)
+		if err != nil {
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create multiswap objective for %+v: %w", request, err)
+		}
+
+		return start(&mso)
 
 	case swapfund.ObjectiveRequest:
 		sfo, err := swapfund.NewObjective(request, true, myAddress, chainId, e.store.GetConsensusChannel)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create swapfund objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create swapfund objective for %+v: %w", request, err)
 		}
 
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("could not register channel with swap manager: %w", err)
+			return EngineEvent{}, fmt.Errorf("could not register channel with swap manager: %w", err)
 		}
-		return e.attemptProgress(&sfo)
+		return start(&sfo)
 
 	case virtualdefund.ObjectiveRequest:
 		minAmount := big.NewInt(0)
 		if e.vm.ChannelRegistered(request.ChannelId) {
 			paid, err := e.vm.Paid(request.ChannelId)
 			if err != nil {
-				return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create virtualdefund objective for %+v: %w", request, err)
+				return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create virtualdefund objective for %+v: %w", request, err)
 			}
 			minAmount = paid
 		}
 		vdfo, err := virtualdefund.NewObjective(request, true, myAddress, minAmount, e.store.GetChannelById, e.store.GetConsensusChannel)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create virtualdefund objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create virtualdefund objective for %+v: %w", request, err)
 		}
-		return e.attemptProgress(&vdfo)
+		return start(&vdfo)
 
 	case swapdefund.ObjectiveRequest:
 		sdfo, err := swapdefund.NewObjective(request, true, myAddress, e.store.GetChannelById, e.store.GetConsensusChannel)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create swapdefund objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create swapdefund objective for %+v: %w", request, err)
 		}
-		return e.attemptProgress(&sdfo)
+		return start(&sdfo)
 
 	case directfund.ObjectiveRequest:
 		dfo, err := directfund.NewObjective(request, true, myAddress, chainId, e.store.GetChannelsByParticipant, e.store.GetConsensusChannel)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create directfund objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create directfund objective for %+v: %w", request, err)
 		}
-		return e.attemptProgress(&dfo)
+		return start(&dfo)
 
 	case directdefund.ObjectiveRequest:
 		ddfo, err := directdefund.NewObjective(request, true, e.store.GetConsensusChannelById, e.store.GetChannelById, e.vm.GetVoucherIfAmountPresent, false)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create directdefund objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create directdefund objective for %+v: %w", request, err)
 		}
 
 		// Retaining the consensus channel only if ddfo is with challenge since it's needed to process a challenge-registered event for a virtual channel and obtain the ledger channel ID.
 		if !ddfo.IsChallenge {
 			err = e.store.DestroyConsensusChannel(request.ChannelId)
 			if err != nil {
-				return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not destroy consensus channel for %+v: %w", request, err)
+				return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not destroy consensus channel for %+v: %w", request, err)
 			}
 		}
 
-		return e.attemptProgress(&ddfo)
+		return start(&ddfo)
 	case bridgedfund.ObjectiveRequest:
 		bfo, err := bridgedfund.NewObjective(request, true, myAddress, chainId, e.store.GetChannelsByParticipant, e.store.GetConsensusChannel)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create bridgedfund objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create bridgedfund objective for %+v: %w", request, err)
 		}
-		return e.attemptProgress(&bfo)
+		return start(&bfo)
 
 	case bridgeddefund.ObjectiveRequest:
 		bdfo, err := bridgeddefund.NewObjective(request, true, e.store.GetConsensusChannelById)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create bridgeddefund objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create bridgeddefund objective for %+v: %w", request, err)
 		}
 
 		// Destroy the consensus channel to prevent it being used (Channel will now take over governance)
 		err = e.store.DestroyConsensusChannel(bdfo.C.Id)
 		if err != nil {
-			return failedEngineEvent, err
+			return EngineEvent{}, err
 		}
 
-		return e.attemptProgress(&bdfo)
+		return start(&bdfo)
 	case mirrorbridgeddefund.ObjectiveRequest:
 		mbdfo, err := mirrorbridgeddefund.NewObjective(request, true, e.store.GetConsensusChannelById, true)
 		if err != nil {
-			return failedEngineEvent, fmt.Errorf("handleAPIEvent: Could not create mirrorbridgeddefund objective for %+v: %w", request, err)
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create mirrorbridgeddefund objective for %+v: %w", request, err)
 		}
 
 		// Destroy the consensus channel to prevent it being used (Channel will now take over governance)
 		err = e.store.DestroyConsensusChannel(mbdfo.C.Id)
 		if err != nil {
-			return failedEngineEvent, err
+			return EngineEvent{}, err
+		}
+
+		return start(&mbdfo)
+
+	case ledgertopup.ObjectiveRequest:
+		lto, err := ledgertopup.NewObjective(request, true, myAddress, e.store.GetConsensusChannelById)
+		if err != nil {
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create ledgertopup objective for %+v: %w", request, err)
+		}
+		return start(&lto)
+
+	case rebalance.ObjectiveRequest:
+		ro, err := rebalance.NewObjective(request, true, myAddress, e.store.GetConsensusChannel)
+		if err != nil {
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create rebalance objective for %+v: %w", request, err)
 		}
+		return start(&ro)
 
-		return e.attemptProgress(&mbdfo)
+	case ledgerwithdraw.ObjectiveRequest:
+		lwo, err := ledgerwithdraw.NewObjective(request, true, myAddress, e.store.GetConsensusChannelById)
+		if err != nil {
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create ledgerwithdraw objective for %+v: %w", request, err)
+		}
+		err = e.checkLedgerNotInUse(request.ChannelId)
+		if err != nil {
+			return EngineEvent{}, fmt.Errorf("handleAPIEvent: Could not create ledgerwithdraw objective for %+v: %w", request, err)
+		}
+
+		return start(&lwo)
 
 	default:
-		return failedEngineEvent, fmt.Errorf("handleAPIEvent: Unknown objective type %T", request)
+		return EngineEvent{}, fmt.Errorf("handleAPIEvent: Unknown objective type %T", request)
 	}
 }
 
@@ -812,7 +1106,7 @@ func (e *Engine) handleObjectiveRequest(or protocols.ObjectiveRequest) (EngineEv
 // It prepares and dispatches a payment message to the counterparty.
 func (e *Engine) handlePaymentRequest(request PaymentRequest) (EngineEvent, error) {
 	ee := EngineEvent{}
-	if (request == PaymentRequest{}) {
+	if request.ChannelId.IsZero() && request.Amount == nil {
 		return ee, fmt.Errorf("handleAPIEvent: Empty payment request")
 	}
 	cId := request.ChannelId
@@ -842,7 +1136,7 @@ func (e *Engine) handlePaymentRequest(request PaymentRequest) (EngineEvent, erro
 }
 
 // handleCounterChallengeRequest handles a counter challenge request for the given channel.
-func (e *Engine) handleCounterChallengeRequest(request CounterChallengeRequest) error {
+func (e *Engine) handleCounterChallengeRequest(request CounterChallengeRequest) (EngineEvent, error) {
 	channelId := request.ChannelId
 	isCheckPoint := false
 	isChallenge := false
@@ -853,12 +1147,12 @@ func (e *Engine) handleCounterChallengeRequest(request CounterChallengeRequest)
 	case types.Challenge:
 		isChallenge = true
 	default:
-		return fmt.Errorf("unknown counter challenge action")
+		return EngineEvent{}, fmt.Errorf("unknown counter challenge action")
 	}
 
 	obj, ok := e.store.GetObjectiveByChannelId(channelId)
 	if !ok {
-		return fmt.Errorf("objective to process the counter challenge request for channel Id %s could not be found", channelId.String())
+		return EngineEvent{}, fmt.Errorf("objective to process the counter challenge request for channel Id %s could not be found", channelId.String())
 	}
 
 	switch objective := obj.(type) {
@@ -868,7 +1162,7 @@ func (e *Engine) handleCounterChallengeRequest(request CounterChallengeRequest)
 
 	case *mirrorbridgeddefund.Objective:
 		if request.Payload.State().ChannelId().IsZero() {
-			return fmt.Errorf("invalid L2 signed state")
+			return EngineEvent{}, fmt.Errorf("invalid L2 signed state")
 		}
 
 		objective.L2SignedState = request.Payload
@@ -876,18 +1170,34 @@ func (e *Engine) handleCounterChallengeRequest(request CounterChallengeRequest)
 		objective.IsChallenge = isChallenge
 
 	default:
-		return fmt.Errorf("unknown objective type %T", objective)
+		return EngineEvent{}, fmt.Errorf("unknown objective type %T", objective)
 	}
 
-	_, err := e.attemptProgress(obj)
+	return e.attemptProgress(obj)
+}
+
+func (e *Engine) handleConfirmSwapRequest(request types.ConfirmSwapRequest) (EngineEvent, error) {
+	objective, err := e.store.GetObjectiveById(protocols.ObjectiveId(swap.ObjectivePrefix + request.SwapId.String()))
 	if err != nil {
-		return err
+		return EngineEvent{}, err
+	}
+	o, ok := objective.(*swap.Objective)
+	if !ok {
+		return EngineEvent{}, fmt.Errorf("not a swap objective")
 	}
 
-	return nil
+	o.SwapStatus = request.Action
+
+	// An expired swap is rejected when it is cranked, whatever the action
+	res, err := e.attemptProgress(o)
+	if err == nil && request.Action == types.Accepted && o.Swap.IsExpired(time.Now()) {
+		err = fmt.Errorf("could not accept swap %s: %w", request.SwapId, swap.ErrSwapExpired)
+	}
+
+	return res, err
 }
 
-func (e *Engine) handleConfirmSwapRequest(request types.ConfirmSwapRequest) (EngineEvent, error) {
+func (e *Engine) handleCancelSwapRequest(request CancelSwapRequest) (EngineEvent, error) {
 	objective, err := e.store.GetObjectiveById(protocols.ObjectiveId(swap.ObjectivePrefix + request.SwapId.String()))
 	if err != nil {
 		return EngineEvent{}, err
@@ -897,9 +1207,33 @@ func (e *Engine) handleConfirmSwapRequest(request types.ConfirmSwapRequest) (Eng
 		return EngineEvent{}, fmt.Errorf("not a swap objective")
 	}
 
-	o.SwapStatus = request.Action
+	return e.cancelSwap(o)
+}
+
+// cancelSwap rejects a swap we initiated which has not been accepted yet, notifying the counterparty
+func (e *Engine) cancelSwap(o *swap.Objective) (EngineEvent, error) {
+	cancelled, sideEffects, err := o.Cancel()
+	if err != nil {
+		return EngineEvent{}, err
+	}
+
+	err = e.store.SetObjective(cancelled)
+	if err != nil {
+		return EngineEvent{}, err
+	}
+	err = e.store.SetObjectiveWaitingFor(cancelled.Id(), swap.WaitingForNothing)
+	if err != nil {
+		return EngineEvent{}, err
+	}
 
-	return e.attemptProgress(o)
+	notifEvents, err := e.generateNotifications(cancelled)
+	if err != nil {
+		return EngineEvent{}, err
+	}
+	res := EngineEvent{CompletedObjectives: []protocols.Objective{cancelled}}
+	res.Merge(notifEvents)
+
+	return res, e.executeSideEffects(sideEffects)
 }
 
 func (e *Engine) handleRetryObjectiveTxRequest(request types.RetryObjectiveTxRequest) error {
@@ -909,30 +1243,42 @@ func (e *Engine) handleRetryObjectiveTxRequest(request types.RetryObjectiveTxReq
 		return &ErrGetObjective{wrappedError: err, objectiveId: protocols.ObjectiveId(request.ObjectiveId)}
 	}
 
-	// Based on objective type, send appropriate tx
+	var droppedEvent protocols.DroppedEventInfo
 	switch objective := obj.(type) {
 	case *directfund.Objective:
-		droppedEvent := objective.GetDroppedEvent()
-		if droppedEvent.ChannelId.IsZero() {
-			return errEmptyDroppedEvent
-		}
+		droppedEvent = objective.GetDroppedEvent()
+	case *directdefund.Objective:
+		droppedEvent = objective.GetDroppedEvent()
+	default:
+		return nil
+	}
 
-		objective.ResetTxSubmitted()
-		_, err = e.attemptProgress(objective)
+	if droppedEvent.ChannelId.IsZero() {
+		return errEmptyDroppedEvent
+	}
+
+	numOfRetries := e.numOfTxRetries[obj.Id()]
+	if !e.chain.GetRetryPolicy().CanRetry(numOfRetries) {
+		return fmt.Errorf("could not retry tx of objective %s: %w", obj.Id(), chainservice.ErrRetryLimitReached)
+	}
 
+	// Based on objective type, reset the submitted tx so that it is replaced when cranking the objective
+	switch objective := obj.(type) {
+	case *directfund.Objective:
+		objective.ResetTxSubmitted()
 	case *directdefund.Objective:
-		droppedEvent := objective.GetDroppedEvent()
-		if droppedEvent.ChannelId.IsZero() {
-			return errEmptyDroppedEvent
-		}
 		objective.ResetWithDrawAllTxSubmitted()
-		_, err = e.attemptProgress(objective)
 	}
 
+	e.txsToResend[droppedEvent.ChannelId] = txRetry{droppedTxHash: droppedEvent.TxHash, numOfRetries: numOfRetries}
+	defer delete(e.txsToResend, droppedEvent.ChannelId)
+
+	_, err = e.attemptProgress(obj)
 	if err != nil {
 		return err
 	}
 
+	e.numOfTxRetries[obj.Id()]++
 	return nil
 }
 
@@ -959,11 +1305,22 @@ func (e *Engine) executeSideEffects(sideEffects protocols.SideEffects) error {
 	go e.sendMessages(sideEffects.MessagesToSend)
 
 	for _, tx := range sideEffects.TransactionsToSubmit {
-		e.logger.Info("Sending chain transaction", "channel", tx.ChannelId().String())
-
-		_, err := e.chain.SendTransaction(tx)
+		var err error
+		if retry, ok := e.txsToResend[tx.ChannelId()]; ok {
+			e.logger.Info("Resending chain transaction", "channel", tx.ChannelId().String(), "droppedTxHash", retry.droppedTxHash.String())
+			delete(e.txsToResend, tx.ChannelId())
+			_, err = e.chain.ResendTransaction(tx, retry.droppedTxHash, retry.numOfRetries)
+		} else {
+			e.logger.Info("Sending chain transaction", "channel", tx.ChannelId().String())
+			_, err = e.chain.SendTransaction(tx)
+		}
 		if err != nil {
-			return err
+			// The transaction is recorded as dropped, so that it can be resubmitted with RetryObjectiveTx
+			dropErr := e.handleDroppedChainEvent(protocols.DroppedEventInfo{ChannelId: tx.ChannelId(), EventName: "SubmissionFailed"})
+			if dropErr != nil {
+				return dropErr
+			}
+			return fmt.Errorf("could not submit transaction for channel %s: %w", tx.ChannelId(), err)
 		}
 	}
 	for _, proposal := range sideEffects.ProposalsToProcess {
@@ -981,14 +1338,27 @@ func (e *Engine) executeSideEffects(sideEffects protocols.SideEffects) error {
 //  4. It executes any side effects that were declared during cranking
 //  5. It updates progress metadata in the store
 func (e *Engine) attemptProgress(objective protocols.Objective) (outgoing EngineEvent, err error) {
+	if reason, failed := e.store.GetObjectiveFailure(objective.Id()); failed {
+		e.logger.Info("Ignoring failed objective", logging.WithObjectiveIdAttribute(objective.Id()), "reason", reason)
+		return EngineEvent{}, nil
+	}
+
+	if so, ok := objective.(*swap.Objective); ok {
+		err = e.applySwapPolicy(so)
+		if err != nil {
+			return EngineEvent{}, newErrObjectiveFailed(objective.Id(), err)
+		}
+	}
+
 	secretKey := e.store.GetChannelSecretKey()
 	var crankedObjective protocols.Objective
 	var sideEffects protocols.SideEffects
 	var waitingFor protocols.WaitingFor
 
+	// Only protocol errors fail the objective. Other errors, such as a transaction which could not be submitted, leave it to be progressed again
 	crankedObjective, sideEffects, waitingFor, err = objective.Crank(secretKey)
 	if err != nil {
-		return
+		return EngineEvent{}, newErrObjectiveFailed(objective.Id(), err)
 	}
 
 	err = e.store.SetObjective(crankedObjective)
@@ -996,6 +1366,16 @@ func (e *Engine) attemptProgress(objective protocols.Objective) (outgoing Engine
 		return EngineEvent{}, err
 	}
 
+	err = e.store.SetObjectiveWaitingFor(crankedObjective.Id(), waitingFor)
+	if err != nil {
+		return EngineEvent{}, err
+	}
+
+	err = e.spawnSuccessorIfLedgerWithdrawObjective(crankedObjective)
+	if err != nil {
+		return EngineEvent{}, err
+	}
+
 	notifEvents, err := e.generateNotifications(crankedObjective)
 	if err != nil {
 		return EngineEvent{}, err
@@ -1007,9 +1387,12 @@ func (e *Engine) attemptProgress(objective protocols.Objective) (outgoing Engine
 	// If our protocol is waiting for nothing then we know the objective is complete
 	// TODO: If attemptProgress is called on a completed objective CompletedObjectives would include that objective id
 	// Probably should have a better check that only adds it to CompletedObjectives if it was completed in this crank
-	if waitingFor == "WaitingForNothing" {
+	if waitingFor == protocols.WaitingForNothing {
 		outgoing.CompletedObjectives = append(outgoing.CompletedObjectives, crankedObjective)
 
+		// The objective only completes once its transactions are mined, so their resubmissions no longer need counting
+		delete(e.numOfTxRetries, crankedObjective.Id())
+
 		// Only release the channel if the objective owns one
 		// Swap objective does not own a channel
 		channel := crankedObjective.OwnsChannel()
@@ -1035,6 +1418,15 @@ func (e *Engine) attemptProgress(objective protocols.Objective) (outgoing Engine
 		}
 	}
 	err = e.executeSideEffects(sideEffects)
+	if err != nil {
+		return
+	}
+
+	if lwo, ok := crankedObjective.(*ledgerwithdraw.Objective); ok && lwo.AwaitingLedgerRetirement() {
+		var retired EngineEvent
+		retired, err = e.retireLedger(lwo)
+		outgoing.Merge(retired)
+	}
 	return
 }
 
@@ -1068,6 +1460,10 @@ func (e *Engine) generateNotifications(o protocols.Objective) (EngineEvent, erro
 			slog.Debug("DEBUG: engine.go-generateNotifications generating notification for payment_channel_updated")
 			outgoing.PaymentChannelUpdates = append(outgoing.PaymentChannelUpdates, info)
 		case *channel.Channel:
+			if c.Type == types.Rebalance {
+				// Rebalance channels are never funded, so there is nothing to report
+				continue
+			}
 			l, err := query.ConstructLedgerInfoFromChannel(c, *e.store.GetAddress())
 			if err != nil {
 				return outgoing, err
@@ -1087,7 +1483,11 @@ func (e *Engine) generateNotifications(o protocols.Objective) (EngineEvent, erro
 
 			outgoing.SwapUpdates = append(outgoing.SwapUpdates, swapInfo)
 		case *channel.SwapChannel:
-			// TODO: Add notification for swap channel
+			info, err := query.ConstructSwapInfo(*c, *e.store.GetAddress())
+			if err != nil {
+				return outgoing, err
+			}
+			outgoing.SwapChannelUpdates = append(outgoing.SwapChannelUpdates, info)
 		default:
 			return outgoing, fmt.Errorf("handleNotifications: Unknown related type %T", c)
 		}
@@ -1130,6 +1530,159 @@ func (e Engine) spawnConsensusChannelIfDirectFundObjective(crankedObjective prot
 	return nil
 }
 
+// spawnSuccessorIfLedgerWithdrawObjective stores the successor ConsensusChannel of a ledger channel being withdrawn from,
+// as soon as the ledger channel is finalized. The successor is spawned before the withdrawal completes on chain, so that
+// the ledger channel can keep funding virtual channels in the meantime.
+func (e Engine) spawnSuccessorIfLedgerWithdrawObjective(crankedObjective protocols.Objective) error {
+	lwo, isLwo := crankedObjective.(*ledgerwithdraw.Objective)
+	if !isLwo || !lwo.SuccessorReady() {
+		return nil
+	}
+	if _, err := e.store.GetConsensusChannelById(lwo.SuccessorId()); err == nil {
+		return nil
+	}
+
+	c, err := lwo.CreateSuccessorConsensusChannel()
+	if err != nil {
+		return fmt.Errorf("could not create successor consensus channel for objective %s: %w", crankedObjective.Id(), err)
+	}
+	err = e.store.SetConsensusChannel(c)
+	if err != nil {
+		return fmt.Errorf("could not store successor consensus channel for objective %s: %w", crankedObjective.Id(), err)
+	}
+	return nil
+}
+
+// retireLedger destroys the ConsensusChannel of a ledger channel being withdrawn from, once the successor state is supported,
+// and attempts progress with the withdrawal, which can then sign the final state.
+func (e *Engine) retireLedger(lwo *ledgerwithdraw.Objective) (EngineEvent, error) {
+	ledger, err := e.store.GetConsensusChannelById(lwo.C.Id)
+	if err != nil {
+		return EngineEvent{}, newErrObjectiveFailed(lwo.Id(), fmt.Errorf("could not get ledger channel: %w", err))
+	}
+	retired, err := lwo.RetireLedger(ledger)
+	if err != nil {
+		return EngineEvent{}, newErrObjectiveFailed(lwo.Id(), err)
+	}
+
+	// A Channel governs the ledger channel from now on, until it is defunded
+	err = e.store.DestroyConsensusChannel(ledger.Id)
+	if err != nil {
+		return EngineEvent{}, err
+	}
+
+	return e.attemptProgress(retired)
+}
+
+// restoreLedgerIfWithdrawalAbandoned restores the ConsensusChannel of a ledger channel whose withdrawal was rejected or failed
+// after the ConsensusChannel was retired, but before we signed the final state.
+func (e *Engine) restoreLedgerIfWithdrawalAbandoned(objective protocols.Objective) error {
+	lwo, isLwo := objective.(*ledgerwithdraw.Objective)
+	if !isLwo || !lwo.LedgerRestorable() {
+		return nil
+	}
+	if _, err := e.store.GetConsensusChannelById(lwo.C.Id); err == nil {
+		return nil
+	}
+
+	ledger, err := lwo.RestoreLedger()
+	if err != nil {
+		return err
+	}
+	return e.store.SetConsensusChannel(ledger)
+}
+
+// fundSuccessorIfLedgerWithdrawn records the funds which the withdrawAll transaction of a ledger withdrawal transferred into
+// its successor, given the ledger channel as it was before the payout. The adjudicator does not emit a deposit event for
+// the successor, so its on chain funding is derived from the payout of the ledger channel.
+func (e *Engine) fundSuccessorIfLedgerWithdrawn(c *channel.Channel, event chainservice.AllocationUpdatedEvent) error {
+	objective, ok := e.store.GetObjectiveByChannelId(c.Id)
+	if !ok {
+		return nil
+	}
+	lwo, isLwo := objective.(*ledgerwithdraw.Objective)
+	if !isLwo || !lwo.SuccessorReady() {
+		return nil
+	}
+
+	funded, transferred, err := lwo.RecordSuccessorFunding(event.AssetAddress, c.OnChain.Holdings[event.AssetAddress])
+	if err != nil {
+		return newErrObjectiveFailed(lwo.Id(), err)
+	}
+	err = e.store.SetObjective(funded)
+	if err != nil {
+		return err
+	}
+
+	successor, err := e.store.GetConsensusChannelById(lwo.SuccessorId())
+	if err != nil {
+		// The successor is funded from the objective's record once it is spawned
+		return nil
+	}
+	successor.OnChainFunding = successor.OnChainFunding.Add(types.Funds{event.AssetAddress: transferred})
+	return e.store.SetConsensusChannel(successor)
+}
+
+// checkLedgerNotInUse returns an error if an objective in progress funds or defunds a channel through the given ledger channel,
+// or rebalances it. A withdrawal splices the ledger channel, which would leave such objectives stranded.
+func (e *Engine) checkLedgerNotInUse(ledgerId types.Destination) error {
+	allMetadata, err := e.store.GetAllObjectiveMetadata()
+	if err != nil {
+		return err
+	}
+
+	for id, metadata := range allMetadata {
+		if metadata.Failed() || metadata.WaitingFor == protocols.WaitingForNothing {
+			continue
+		}
+		if !virtualfund.IsVirtualFundObjective(id) && !swapfund.IsSwapFundObjective(id) &&
+			!virtualdefund.IsVirtualDefundObjective(id) && !swapdefund.IsSwapDefundObjective(id) && !rebalance.IsRebalanceObjective(id) {
+			continue
+		}
+
+		objective, err := e.store.GetObjectiveById(id)
+		if errors.Is(err, store.ErrNoSuchObjective) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if status := objective.GetStatus(); status != protocols.Unapproved && status != protocols.Approved {
+			continue
+		}
+
+		ledgers := []*consensus_channel.ConsensusChannel{}
+		switch o := objective.(type) {
+		case *virtualfund.Objective:
+			for _, connection := range []*virtualfund.Connection{o.ToMyLeft, o.ToMyRight} {
+				if connection != nil {
+					ledgers = append(ledgers, connection.Channel)
+				}
+			}
+		case *swapfund.Objective:
+			for _, connection := range []*swapfund.Connection{o.ToMyLeft, o.ToMyRight} {
+				if connection != nil {
+					ledgers = append(ledgers, connection.Channel)
+				}
+			}
+		case *virtualdefund.Objective:
+			ledgers = append(ledgers, o.ToMyLeft, o.ToMyRight)
+		case *swapdefund.Objective:
+			ledgers = append(ledgers, o.ToMyLeft, o.ToMyRight)
+		case *rebalance.Objective:
+			ledgers = append(ledgers, o.ToMyLeft, o.ToMyRight)
+		}
+
+		for _, ledger := range ledgers {
+			if ledger != nil && ledger.Id == ledgerId {
+				return fmt.Errorf("%w: %s is in progress", ledgerwithdraw.ErrLedgerInUse, objective.Id())
+			}
+		}
+	}
+
+	return nil
+}
+
 func (e Engine) spawnConsensusChannelIfBridgedFundObjective(crankedObjective protocols.Objective) error {
 	if bfo, isBfo := crankedObjective.(*bridgedfund.Objective); isBfo {
 		return e.spawnConsensusChannel(crankedObjective, bfo.CreateConsensusChannel)
@@ -1249,6 +1802,12 @@ func (e *Engine) constructObjectiveFromMessage(id protocols.ObjectiveId, p proto
 	case swap.IsSwapObjective(id):
 		so, err := swap.ConstructObjectiveFromPayload(p, false, e.store.GetChannelById, *e.store.GetAddress())
 		return &so, err
+	case multiswap.IsMultiSwapObjective(id):
+		mso, err := multiswap.ConstructObjectiveFromPayload(p, false, *e.store.GetAddress(), e.store.GetChannelById)
+		if err != nil {
+			return &multiswap.Objective{}, fromMsgErr(id, err)
+		}
+		return &mso, nil
 	case virtualdefund.IsVirtualDefundObjective(id):
 		vId, err := virtualdefund.GetVirtualChannelFromObjectiveId(id)
 		if err != nil {
@@ -1302,6 +1861,31 @@ func (e *Engine) constructObjectiveFromMessage(id protocols.ObjectiveId, p proto
 		}
 		return &mbdfo, nil
 
+	case ledgertopup.IsLedgerTopUpObjective(id):
+		lto, err := ledgertopup.ConstructObjectiveFromPayload(p, false, e.store.GetConsensusChannelById)
+		if err != nil {
+			return &ledgertopup.Objective{}, fromMsgErr(id, err)
+		}
+		return &lto, nil
+
+	case ledgerwithdraw.IsLedgerWithdrawObjective(id):
+		lwo, err := ledgerwithdraw.ConstructObjectiveFromPayload(p, false, e.store.GetConsensusChannelById)
+		if err != nil {
+			return &ledgerwithdraw.Objective{}, fromMsgErr(id, err)
+		}
+		err = e.checkLedgerNotInUse(lwo.C.Id)
+		if err != nil {
+			return &ledgerwithdraw.Objective{}, fromMsgErr(id, err)
+		}
+		return &lwo, nil
+
+	case rebalance.IsRebalanceObjective(id):
+		ro, err := rebalance.ConstructObjectiveFromPayload(p, false, *e.store.GetAddress(), e.chain.GetConsensusAppAddress(), e.store.GetConsensusChannel)
+		if err != nil {
+			return &rebalance.Objective{}, fromMsgErr(id, err)
+		}
+		return &ro, nil
+
 	default:
 		return &directfund.Objective{}, errors.New("cannot handle unimplemented objective type")
 	}
@@ -1320,9 +1904,12 @@ func getProposalObjectiveId(p consensus_channel.Proposal, channelType types.Chan
 		{
 			var prefix string
 
-			if channelType == types.Swap {
+			switch channelType {
+			case types.Swap:
 				prefix = swapfund.ObjectivePrefix
-			} else {
+			case types.Rebalance:
+				prefix = rebalance.ObjectivePrefix
+			default:
 				prefix = virtualfund.ObjectivePrefix
 			}
 
@@ -1334,9 +1921,12 @@ func getProposalObjectiveId(p consensus_channel.Proposal, channelType types.Chan
 		{
 			var prefix string
 
-			if channelType == types.Swap {
+			switch channelType {
+			case types.Swap:
 				prefix = swapdefund.ObjectivePrefix
-			} else {
+			case types.Rebalance:
+				prefix = rebalance.ObjectivePrefix
+			default:
 				prefix = virtualdefund.ObjectivePrefix
 			}
 
@@ -1344,6 +1934,8 @@ func getProposalObjectiveId(p consensus_channel.Proposal, channelType types.Chan
 			return protocols.ObjectiveId(prefix + channelId)
 
 		}
+	case consensus_channel.ResizeProposal:
+		return protocols.ObjectiveId(ledgertopup.ObjectivePrefix + p.ToResize.Target.String())
 	default:
 		{
 			panic("invalid proposal type")
@@ -1426,18 +2018,86 @@ func (e *Engine) processStoreChannels(latestblock chainservice.Block) error {
 	return nil
 }
 
-func (e *Engine) checkError(err error) {
+// handleError logs the error and marks the objective it is attributed to as failed, if any.
+// It panics on fatal errors.
+func (e *Engine) handleError(err error) EngineEvent {
+	if err == nil {
+		return EngineEvent{}
+	}
+
+	e.logger.Error("error in run loop", "err", err)
+
+	for _, fatalError := range fatalErrors {
+		if errors.Is(err, fatalError) {
+			panic(err)
+		}
+	}
+
+	var objectiveErr *ErrObjectiveFailed
+	if !errors.As(err, &objectiveErr) {
+		return EngineEvent{}
+	}
+
+	return e.failObjective(objectiveErr.objectiveId, objectiveErr.wrappedError.Error())
+}
+
+// failObjective marks the objective as failed so that it is no longer progressed, and releases the channel it owns
+func (e *Engine) failObjective(id protocols.ObjectiveId, reason string) EngineEvent {
+	failedEvent := EngineEvent{FailedObjectives: []query.FailedObjectiveInfo{{Id: id, Reason: reason}}}
+	delete(e.numOfTxRetries, id)
+
+	objective, err := e.store.GetObjectiveById(id)
 	if err != nil {
-		e.logger.Error("error in run loop", "err", err)
+		// The objective failed before it was stored, so there is nothing to clean up
+		return failedEvent
+	}
 
-		for _, nonFatalError := range nonFatalErrors {
-			if errors.Is(err, nonFatalError) {
-				return
-			}
+	err = e.store.SetObjectiveFailure(id, reason)
+	if err != nil {
+		e.logger.Error("could not mark objective as failed", logging.WithObjectiveIdAttribute(id), "err", err)
+		return failedEvent
+	}
+
+	err = store.RecordAbandonedSwaps(e.store, objective)
+	if err != nil {
+		e.logger.Error("could not record swaps of failed objective", logging.WithObjectiveIdAttribute(id), "err", err)
+	}
+
+	if channelId := objective.OwnsChannel(); !channelId.IsZero() {
+		err = e.store.ReleaseChannelFromOwnership(channelId)
+		if err != nil {
+			e.logger.Error("could not release channel of failed objective", logging.WithObjectiveIdAttribute(id), "err", err)
 		}
+	}
+
+	err = e.restoreLedgerIfWithdrawalAbandoned(objective)
+	if err != nil {
+		e.logger.Error("could not restore ledger channel of failed withdrawal", logging.WithObjectiveIdAttribute(id), "err", err)
+	}
+
+	return failedEvent
+}
 
-		panic(err)
+// unlockRebalance refunds the guarantees of a rejected rebalance to the payers, and stores the result.
+func (e *Engine) unlockRebalance(ro *rebalance.Objective) error {
+	unlocked, sideEffects, err := ro.Unlock(e.store.GetChannelSecretKey())
+	if err != nil {
+		return newErrObjectiveFailed(ro.Id(), err)
 	}
+
+	err = e.store.SetObjective(unlocked)
+	if err != nil {
+		return err
+	}
+
+	return e.executeSideEffects(sideEffects)
+}
+
+// TakeObjectiveRequestError returns the error from handling the objective request with the given objective id, if any.
+// It should be called once the objective request has been signalled as started.
+func (e *Engine) TakeObjectiveRequestError(id protocols.ObjectiveId) error {
+	err, _ := e.objectiveRequestErrors.LoadAndDelete(string(id))
+	return err
 }
 
 func (e *Engine) GetNodeInfo() types.NodeInfo {
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/routing"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// DEFAULT_CAPACITY_ANNOUNCEMENT_INTERVAL is how often the engine announces its capacity, unless configured in EngineOpts
+const DEFAULT_CAPACITY_ANNOUNCEMENT_INTERVAL = 30 * time.Second
+
+// capacityAnnouncementInterval returns how often the engine announces its capacity
+func (opts EngineOpts) capacityAnnouncementInterval() time.Duration {
+	if opts.CapacityAnnouncementInterval > 0 {
+		return opts.CapacityAnnouncementInterval
+	}
+
+	return DEFAULT_CAPACITY_ANNOUNCEMENT_INTERVAL
+}
+
+// FindRoute returns the cheapest route from us to the counterparty which can fund a virtual channel with the given amount of the asset,
+// using the capacity announcements we have made and received.
+func (e *Engine) FindRoute(counterparty types.Address, asset common.Address, amount *big.Int) (routing.Route, error) {
+	return e.graph.FindRoute(*e.store.GetAddress(), counterparty, asset, amount)
+}
+
+// announceCapacity announces our capacity in each of our ledger channels to the peers we have ledger channels with.
+// Only our own announcements are sent periodically; those of others are relayed once, when we first receive them.
+func (e *Engine) announceCapacity() error {
+	ledgers, err := e.store.GetAllConsensusChannels()
+	if err != nil {
+		return err
+	}
+
+	me := *e.store.GetAddress()
+	timestamp := uint64(time.Now().UnixMilli())
+	peers := make([]types.Address, 0, len(ledgers))
+	announcements := []routing.CapacityAnnouncement{}
+	for _, ledger := range ledgers {
+		peer := ledger.Participants()[0]
+		if peer == me {
+			peer = ledger.Participants()[1]
+		}
+		peers = append(peers, peer)
+
+		ledgerOutcome := ledger.ConsensusVars().AsState(ledger.FixedPart()).Outcome
+		for _, assetExit := range ledgerOutcome {
+			a := routing.CapacityAnnouncement{
+				From:      me,
+				To:        peer,
+				Asset:     assetExit.Asset,
+				Capacity:  assetExit.TotalAllocatedFor(types.AddressToDestination(me)),
+				FlatFee:   big.NewInt(0),
+				Timestamp: timestamp,
+			}
+			if rp, ok := e.policymaker.(*RulePolicy); ok {
+				schedule := rp.intermediaryFeeSchedule(assetExit.Asset)
+				a.FlatFee, a.FeeBasisPoints = schedule.Flat, schedule.BasisPoints
+			}
+			if err := a.Sign(*e.store.GetChannelSecretKey()); err != nil {
+				return err
+			}
+			e.graph.Update(a)
+			announcements = append(announcements, a)
+		}
+	}
+
+	if len(peers) == 0 {
+		return nil
+	}
+
+	messages := protocols.CreateCapacityAnnouncementMessage(announcements, peers...)
+	return e.executeSideEffects(protocols.SideEffects{MessagesToSend: messages})
+}
+
+// handleCapacityAnnouncements adds the announcements signed by their announcer to our graph, and relays the ones
+// which are new to us to our other peers. Announcements we already have are not relayed again, which ends the flood.
+func (e *Engine) handleCapacityAnnouncements(sender types.Address, announcements []routing.CapacityAnnouncement) error {
+	me := *e.store.GetAddress()
+	relayed := []routing.CapacityAnnouncement{}
+	for _, a := range announcements {
+		if a.From == me {
+			continue
+		}
+
+		signer, err := a.RecoverSigner()
+		if err != nil || signer != a.From {
+			e.logger.Warn("Ignoring capacity announcement with an invalid signature", "from", a.From, "to", a.To, "err", err)
+			continue
+		}
+
+		if e.graph.Update(a) {
+			relayed = append(relayed, a)
+		}
+	}
+	if len(relayed) == 0 {
+		return nil
+	}
+
+	ledgers, err := e.store.GetAllConsensusChannels()
+	if err != nil {
+		return err
+	}
+	peers := []types.Address{}
+	for _, ledger := range ledgers {
+		peer := ledger.Participants()[0]
+		if peer == me {
+			peer = ledger.Participants()[1]
+		}
+		if peer != sender {
+			peers = append(peers, peer)
+		}
+	}
+	if len(peers) == 0 {
+		return nil
+	}
+
+	messages := protocols.CreateCapacityAnnouncementMessage(relayed, peers...)
+	return e.executeSideEffects(protocols.SideEffects{MessagesToSend: messages})
+}
@@ -0,0 +1,486 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/internal/logging"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/bridgeddefund"
+	"github.com/statechannels/go-nitro/protocols/bridgedfund"
+	"github.com/statechannels/go-nitro/protocols/directdefund"
+	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/ledgertopup"
+	"github.com/statechannels/go-nitro/protocols/ledgerwithdraw"
+	"github.com/statechannels/go-nitro/protocols/mirrorbridgeddefund"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/rebalance"
+	"github.com/statechannels/go-nitro/protocols/swap"
+	"github.com/statechannels/go-nitro/protocols/swapdefund"
+	"github.com/statechannels/go-nitro/protocols/swapfund"
+	"github.com/statechannels/go-nitro/protocols/virtualdefund"
+	"github.com/statechannels/go-nitro/protocols/virtualfund"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// POLICY_RELOAD_INTERVAL is how often a watched policy file is checked for modifications
+const POLICY_RELOAD_INTERVAL = 5 * time.Second
+
+// AssetLimit caps an amount of a single asset
+type AssetLimit struct {
+	Asset  common.Address `toml:"asset"`
+	Amount *big.Int       `toml:"amount"`
+}
+
+// FeeSchedule is the fee we charge in an asset for intermediating a virtual channel:
+// a flat amount plus basis points of the channel's total
+type FeeSchedule struct {
+	Asset       common.Address `toml:"asset"`
+	Flat        *big.Int       `toml:"flat"`
+	BasisPoints uint64         `toml:"basisPoints"`
+}
+
+// Fee returns the fee for intermediating a virtual channel with the given total
+func (fs FeeSchedule) Fee(total *big.Int) *big.Int {
+	fee := big.NewInt(0).Mul(total, new(big.Int).SetUint64(fs.BasisPoints))
+	fee.Div(fee, big.NewInt(10_000))
+	if fs.Flat != nil {
+		fee.Add(fee, fs.Flat)
+	}
+
+	return fee
+}
+
+// PolicyRules are the rules a RulePolicy enforces on the objectives which fund channels or move funds between them.
+// Zero values do not restrict anything.
+type PolicyRules struct {
+	// AllowedCounterparties, if not empty, are the only participants we fund channels with
+	AllowedCounterparties []common.Address `toml:"allowedCounterparties"`
+	// DeniedCounterparties are participants we never fund channels with, even if they are allowed
+	DeniedCounterparties []common.Address `toml:"deniedCounterparties"`
+	// MaxDeposits caps how much of an asset we deposit into a single directly funded channel
+	MaxDeposits []AssetLimit `toml:"maxDeposits"`
+	// MaxExposures caps how much of an asset we hold in ledger channels and lock in virtual channels shared with any one counterparty
+	MaxExposures []AssetLimit `toml:"maxExposures"`
+	// MinChallengeDuration and MaxChallengeDuration bound the challenge duration of funded channels. A MaxChallengeDuration of 0 is unbounded.
+	MinChallengeDuration uint32 `toml:"minChallengeDuration"`
+	MaxChallengeDuration uint32 `toml:"maxChallengeDuration"`
+	// AllowedIntermediaries, if not empty, are the only intermediaries of the virtual channels we fund
+	AllowedIntermediaries []common.Address `toml:"allowedIntermediaries"`
+	// IntermediaryFees are the fees we require, per asset, to intermediate virtual channels.
+	// The fees are paid in our ledger channels as the guarantees for a virtual channel are added (see channel.IntermediaryFee).
+	IntermediaryFees []FeeSchedule `toml:"intermediaryFees"`
+}
+
+// Validate checks that the rules are consistent
+func (r PolicyRules) Validate() error {
+	if r.MaxChallengeDuration != 0 && r.MinChallengeDuration > r.MaxChallengeDuration {
+		return fmt.Errorf("minimum challenge duration %d exceeds maximum challenge duration %d", r.MinChallengeDuration, r.MaxChallengeDuration)
+	}
+
+	for _, limit := range slices.Concat(r.MaxDeposits, r.MaxExposures) {
+		if limit.Amount == nil || limit.Amount.Sign() < 0 {
+			return fmt.Errorf("limit for asset %s must be a non-negative amount", limit.Asset)
+		}
+	}
+
+	for _, schedule := range r.IntermediaryFees {
+		if schedule.Flat != nil && schedule.Flat.Sign() < 0 {
+			return fmt.Errorf("flat fee for asset %s must not be negative", schedule.Asset)
+		}
+		if schedule.BasisPoints > 10_000 {
+			return fmt.Errorf("fee for asset %s must not exceed 10000 basis points", schedule.Asset)
+		}
+	}
+
+	return nil
+}
+
+type policyFile struct {
+	Policy PolicyRules `toml:"policy"`
+}
+
+// LoadPolicyRules reads the rules from the [policy] table of the given TOML file.
+// Asset limits are written as arrays of inline tables, e.g. maxDeposits = [{ asset = "0x...", amount = "1000" }],
+// since the node's config loader does not support arrays of tables.
+func LoadPolicyRules(filePath string) (PolicyRules, error) {
+	var pf policyFile
+	if _, err := toml.DecodeFile(filePath, &pf); err != nil {
+		return PolicyRules{}, fmt.Errorf("could not load policy from %s: %w", filePath, err)
+	}
+
+	if err := pf.Policy.Validate(); err != nil {
+		return PolicyRules{}, fmt.Errorf("invalid policy in %s: %w", filePath, err)
+	}
+
+	return pf.Policy, nil
+}
+
+// RulePolicy is a policy maker that approves unapproved objectives which satisfy its rules.
+// Only objectives funding channels are subject to the rules, so that funds can always be recovered from existing channels.
+//
+// The engine supplies its store to a RulePolicy on construction, which is used to find the exposure to counterparties.
+type RulePolicy struct {
+	mu    sync.RWMutex
+	rules PolicyRules
+	store store.Store
+}
+
+// NewRulePolicy returns a RulePolicy enforcing the given rules
+func NewRulePolicy(rules PolicyRules) (*RulePolicy, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &RulePolicy{rules: rules}, nil
+}
+
+// Rules returns the rules currently enforced by the policy
+func (rp *RulePolicy) Rules() PolicyRules {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+	return rp.rules
+}
+
+// SetRules replaces the rules enforced by the policy. Objectives which were already approved are not affected.
+func (rp *RulePolicy) SetRules(rules PolicyRules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.rules = rules
+	return nil
+}
+
+// WatchFile reloads the rules from the [policy] table of the given TOML file whenever the file is modified, until the context is done.
+// Rules which cannot be loaded are logged and ignored, so that the policy keeps enforcing the last valid rules.
+func (rp *RulePolicy) WatchFile(ctx context.Context, filePath string) {
+	watchFile(ctx, filePath, func() {
+		rules, err := LoadPolicyRules(filePath)
+		if err != nil {
+			slog.Error("Could not reload policy, keeping the previous rules", "error", err)
+			return
+		}
+		_ = rp.SetRules(rules) // rules were validated on load
+		slog.Info("Reloaded policy", "file", filePath)
+	})
+}
+
+// watchFile calls reload whenever the given file is modified, until the context is done
+func watchFile(ctx context.Context, filePath string, reload func()) {
+	var lastModified time.Time
+	if info, err := os.Stat(filePath); err == nil {
+		lastModified = info.ModTime()
+	}
+
+	ticker := time.NewTicker(POLICY_RELOAD_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(filePath)
+			if err != nil || info.ModTime().Equal(lastModified) {
+				continue
+			}
+			lastModified = info.ModTime()
+			reload()
+		}
+	}
+}
+
+// bindStore supplies the store used to find the exposure to counterparties
+func (rp *RulePolicy) bindStore(s store.Store) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.store = s
+}
+
+// ShouldApprove decides to approve o if it is currently unapproved and satisfies the rules
+func (rp *RulePolicy) ShouldApprove(o protocols.Objective) bool {
+	if o.GetStatus() != protocols.Unapproved {
+		return false
+	}
+
+	if err := rp.check(o); err != nil {
+		slog.Info("Policy rejected objective", logging.WithObjectiveIdAttribute(o.Id()), "reason", err)
+		return false
+	}
+
+	return true
+}
+
+// check returns an error describing the first rule the objective breaks, if any
+func (rp *RulePolicy) check(o protocols.Objective) error {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+
+	var c *channel.Channel
+	var intermediaries []common.Address
+	switch obj := o.(type) {
+	case *directfund.Objective:
+		c = obj.C
+		if err := rp.checkDeposit(c); err != nil {
+			return err
+		}
+	case *virtualfund.Objective:
+		c = &obj.V.Channel
+		intermediaries = c.Participants[1 : len(c.Participants)-1]
+		if err := rp.checkIntermediaryFee(obj.V); err != nil {
+			return err
+		}
+	case *swapfund.Objective:
+		c = &obj.S.Channel
+	case *ledgertopup.Objective:
+		return rp.checkLedgerTopUp(obj)
+	case *rebalance.Objective:
+		return rp.checkRebalance(obj)
+	case *multiswap.Objective:
+		return rp.checkMultiSwap(obj)
+	case *directdefund.Objective, *virtualdefund.Objective, *swapdefund.Objective, *swap.Objective, *ledgerwithdraw.Objective,
+		*bridgedfund.Objective, *bridgeddefund.Objective, *mirrorbridgeddefund.Objective:
+		// These objectives release funds, or move them within channels we already fund
+		return nil
+	default:
+		return fmt.Errorf("objective type %T is not covered by the rules", o)
+	}
+
+	if err := rp.checkChallengeDuration(c.ChallengeDuration); err != nil {
+		return err
+	}
+
+	for _, intermediary := range intermediaries {
+		if len(rp.rules.AllowedIntermediaries) > 0 && intermediary != c.Participants[c.MyIndex] && !slices.Contains(rp.rules.AllowedIntermediaries, intermediary) {
+			return fmt.Errorf("intermediary %s is not allowed", intermediary)
+		}
+	}
+
+	committed := commitment(c)
+	for i, counterparty := range c.Participants {
+		if uint(i) == c.MyIndex {
+			continue
+		}
+		if err := rp.checkCounterparty(counterparty); err != nil {
+			return err
+		}
+		if err := rp.checkExposure(counterparty, c.Id, committed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkLedgerTopUp checks the counterparty of the ledger channel and, if we are the depositor, the deposit and the resulting exposure
+func (rp *RulePolicy) checkLedgerTopUp(o *ledgertopup.Objective) error {
+	participants := o.Ledger.Participants()
+	me := participants[o.Ledger.MyIndex]
+	counterparty := participants[1-o.Ledger.MyIndex]
+	if err := rp.checkCounterparty(counterparty); err != nil {
+		return err
+	}
+	if o.Depositor != me {
+		return nil
+	}
+
+	for _, limit := range rp.rules.MaxDeposits {
+		if limit.Asset == o.Asset && o.Amount.Cmp(limit.Amount) > 0 {
+			return fmt.Errorf("deposit of %s of asset %s exceeds the maximum of %s", o.Amount, limit.Asset, limit.Amount)
+		}
+	}
+
+	return rp.checkExposure(counterparty, o.Ledger.Id, types.Funds{o.Asset: o.Amount})
+}
+
+// checkRebalance checks the rebalance channel and our neighbours in the cycle. The rebalance moves the amount
+// from our ledger channel with the participant to our right into our ledger channel with the participant to our left.
+func (rp *RulePolicy) checkRebalance(o *rebalance.Objective) error {
+	if err := rp.checkChallengeDuration(o.R.ChallengeDuration); err != nil {
+		return err
+	}
+
+	payer := o.ToMyLeft.Participants()[1-o.ToMyLeft.MyIndex]
+	payee := o.ToMyRight.Participants()[1-o.ToMyRight.MyIndex]
+	for _, counterparty := range []common.Address{payer, payee} {
+		if err := rp.checkCounterparty(counterparty); err != nil {
+			return err
+		}
+	}
+
+	return rp.checkExposure(payer, o.R.Id, types.Funds{o.Asset(): o.Amount()})
+}
+
+// checkMultiSwap checks the counterparties of the swap channels in which we swap
+func (rp *RulePolicy) checkMultiSwap(o *multiswap.Objective) error {
+	for _, c := range o.C {
+		for i, counterparty := range c.Participants {
+			if uint(i) == c.MyIndex {
+				continue
+			}
+			if err := rp.checkCounterparty(counterparty); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (rp *RulePolicy) checkCounterparty(counterparty common.Address) error {
+	if slices.Contains(rp.rules.DeniedCounterparties, counterparty) {
+		return fmt.Errorf("counterparty %s is denied", counterparty)
+	}
+	if len(rp.rules.AllowedCounterparties) > 0 && !slices.Contains(rp.rules.AllowedCounterparties, counterparty) {
+		return fmt.Errorf("counterparty %s is not allowed", counterparty)
+	}
+
+	return nil
+}
+
+func (rp *RulePolicy) checkChallengeDuration(challengeDuration uint32) error {
+	if challengeDuration < rp.rules.MinChallengeDuration {
+		return fmt.Errorf("challenge duration %d is below the minimum of %d", challengeDuration, rp.rules.MinChallengeDuration)
+	}
+	if rp.rules.MaxChallengeDuration != 0 && challengeDuration > rp.rules.MaxChallengeDuration {
+		return fmt.Errorf("challenge duration %d exceeds the maximum of %d", challengeDuration, rp.rules.MaxChallengeDuration)
+	}
+
+	return nil
+}
+
+// intermediaryFeeSchedule returns the fee we charge to intermediate virtual channels in the asset
+func (rp *RulePolicy) intermediaryFeeSchedule(asset common.Address) FeeSchedule {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+
+	return rp.feeSchedule(asset)
+}
+
+// feeSchedule returns the fee schedule for the asset, which is free if the rules do not set one.
+// The caller must hold the lock.
+func (rp *RulePolicy) feeSchedule(asset common.Address) FeeSchedule {
+	for _, schedule := range rp.rules.IntermediaryFees {
+		if schedule.Asset == asset {
+			return schedule
+		}
+	}
+
+	return FeeSchedule{Asset: asset}
+}
+
+// checkIntermediaryFee checks that the virtual channel pays us at least our fee, if we are one of its intermediaries
+func (rp *RulePolicy) checkIntermediaryFee(v *channel.VirtualChannel) error {
+	if v.MyIndex == 0 || int(v.MyIndex) == len(v.Participants)-1 {
+		return nil
+	}
+
+	initialOutcome := v.PreFundState().Outcome[0]
+	required := rp.feeSchedule(initialOutcome.Asset).Fee(initialOutcome.TotalAllocated())
+	if required.Sign() == 0 {
+		return nil
+	}
+
+	fees, err := v.IntermediaryFees()
+	if err != nil {
+		return err
+	}
+	me := v.Participants[v.MyIndex]
+	offered := big.NewInt(0)
+	for _, fee := range fees {
+		if fee.Intermediary == me {
+			offered = fee.Amount
+		}
+	}
+
+	if offered.Cmp(required) < 0 {
+		return fmt.Errorf("intermediary fee of %s is less than the required fee of %s", offered, required)
+	}
+
+	return nil
+}
+
+func (rp *RulePolicy) checkDeposit(c *channel.Channel) error {
+	deposit := c.PreFundState().Outcome.TotalAllocatedFor(c.MyDestination())
+	for _, limit := range rp.rules.MaxDeposits {
+		if amount, ok := deposit[limit.Asset]; ok && amount.Cmp(limit.Amount) > 0 {
+			return fmt.Errorf("deposit of %s of asset %s exceeds the maximum of %s", amount, limit.Asset, limit.Amount)
+		}
+	}
+
+	return nil
+}
+
+// checkExposure checks that committing funds to the channel with the given id would not take the exposure to the counterparty beyond the limits
+func (rp *RulePolicy) checkExposure(counterparty common.Address, channelId types.Destination, committed types.Funds) error {
+	if len(rp.rules.MaxExposures) == 0 {
+		return nil
+	}
+	if rp.store == nil {
+		return errors.New("cannot work out the exposure to counterparties without a store")
+	}
+
+	exposure, err := rp.exposure(counterparty, channelId)
+	if err != nil {
+		return err
+	}
+	exposure = types.Sum(exposure, committed)
+
+	for _, limit := range rp.rules.MaxExposures {
+		if amount, ok := exposure[limit.Asset]; ok && amount.Cmp(limit.Amount) > 0 {
+			return fmt.Errorf("exposure of %s of asset %s to counterparty %s exceeds the maximum of %s", amount, limit.Asset, counterparty, limit.Amount)
+		}
+	}
+
+	return nil
+}
+
+// exposure returns the funds we hold in the ledger channel with the counterparty, plus the funds we locked in other open channels
+// shared with the counterparty. The channel with the given id is excluded.
+func (rp *RulePolicy) exposure(counterparty common.Address, excludedChannelId types.Destination) (types.Funds, error) {
+	exposure := types.Funds{}
+
+	if ledger, ok := rp.store.GetConsensusChannel(counterparty); ok {
+		ledgerOutcome := ledger.ConsensusVars().AsState(ledger.FixedPart()).Outcome
+		exposure = types.Sum(exposure, ledgerOutcome.TotalAllocatedFor(types.AddressToDestination(*rp.store.GetAddress())))
+	}
+
+	channels, err := rp.store.GetChannelsByParticipant(counterparty)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range channels {
+		// Ledger channels which are still being funded are held as channels, and are counted here instead
+		if c.Id == excludedChannelId || c.FinalCompleted() {
+			continue
+		}
+		exposure = types.Sum(exposure, commitment(c))
+	}
+
+	return exposure, nil
+}
+
+// commitment returns the funds we lock in the channel: our own allocation if we are an endpoint of the channel,
+// or every allocation if we are an intermediary.
+func commitment(c *channel.Channel) types.Funds {
+	prefundOutcome := c.PreFundState().Outcome
+	if c.MyIndex == 0 || int(c.MyIndex) == len(c.Participants)-1 {
+		return prefundOutcome.TotalAllocatedFor(c.MyDestination())
+	}
+
+	return prefundOutcome.TotalAllocated()
+}
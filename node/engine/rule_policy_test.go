@@ -0,0 +1,229 @@
+package engine
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/internal/testactors"
+	td "github.com/statechannels/go-nitro/internal/testdata"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/ledgertopup"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/rebalance"
+	"github.com/statechannels/go-nitro/protocols/virtualfund"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestLoadPolicyRules(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.toml")
+	config := `
+msgport = 3005
+
+[policy]
+deniedCounterparties = ["0x111122223333444455556666777788889999aAaa"]
+minChallengeDuration = 10
+maxChallengeDuration = 1000
+maxDeposits = [{ asset = "0x0000000000000000000000000000000000000000", amount = "1000000000000000000" }]
+intermediaryFees = [{ asset = "0x0000000000000000000000000000000000000000", flat = 10, basisPoints = 25 }]
+`
+	if err := os.WriteFile(filePath, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadPolicyRules(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules.DeniedCounterparties) != 1 || rules.DeniedCounterparties[0] != common.HexToAddress("0x111122223333444455556666777788889999aAaa") {
+		t.Fatalf("unexpected denied counterparties %v", rules.DeniedCounterparties)
+	}
+	if rules.MinChallengeDuration != 10 || rules.MaxChallengeDuration != 1000 {
+		t.Fatalf("unexpected challenge duration bounds %d, %d", rules.MinChallengeDuration, rules.MaxChallengeDuration)
+	}
+	if len(rules.MaxDeposits) != 1 || rules.MaxDeposits[0].Amount.Cmp(big.NewInt(1e18)) != 0 {
+		t.Fatalf("unexpected max deposits %v", rules.MaxDeposits)
+	}
+
+	if len(rules.IntermediaryFees) != 1 || rules.IntermediaryFees[0].Fee(big.NewInt(10_000)).Cmp(big.NewInt(35)) != 0 {
+		t.Fatalf("unexpected intermediary fees %v", rules.IntermediaryFees)
+	}
+
+	invalid := "[policy]\nminChallengeDuration = 100\nmaxChallengeDuration = 10\n"
+	if err := os.WriteFile(filePath, []byte(invalid), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicyRules(filePath); err == nil {
+		t.Fatal("expected inconsistent challenge duration bounds to be rejected")
+	}
+}
+
+// ledgerWith returns Alice's view of a funded ledger channel she leads with the counterparty
+func ledgerWith(t *testing.T, counterparty testactors.Actor) *consensus_channel.ConsensusChannel {
+	fp := state.FixedPart{
+		Participants:      []types.Address{testactors.Alice.Address(), counterparty.Address()},
+		ChannelNonce:      1,
+		ChallengeDuration: 60,
+	}
+	lo := *consensus_channel.NewLedgerOutcome(
+		types.Address{},
+		consensus_channel.NewBalance(testactors.Alice.Destination(), big.NewInt(10)),
+		consensus_channel.NewBalance(counterparty.Destination(), big.NewInt(10)),
+		[]consensus_channel.Guarantee{},
+	)
+	vars := consensus_channel.Vars{Outcome: lo, TurnNum: 1}
+
+	sigs := [2]state.Signature{}
+	for i, a := range []testactors.Actor{testactors.Alice, counterparty} {
+		sig, err := vars.AsState(fp).Sign(a.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs[i] = sig
+	}
+
+	cc, err := consensus_channel.NewLeaderChannel(fp, 1, lo, sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &cc
+}
+
+// unknownObjective is an objective the rules know nothing about
+type unknownObjective struct {
+	*directfund.Objective
+}
+
+func TestRulePolicy(t *testing.T) {
+	limit := func(amount int64) []AssetLimit {
+		return []AssetLimit{{Asset: common.Address{}, Amount: big.NewInt(amount)}}
+	}
+
+	// Alice deposits 6 into a channel with Bob, with a challenge duration of 60
+	dfo := td.Objectives.Directfund.GenericDFO()
+	// Alice funds a virtual channel with Bob through Irene
+	vfo := td.Objectives.Virtualfund.GenericVFO()
+	vfo.Status = protocols.Unapproved
+
+	// Irene intermediates the same virtual channel, for the given fees
+	ireneVFO := func(fees []channel.IntermediaryFee) *virtualfund.Objective {
+		s := vfo.V.PreFundState().Clone()
+		appData, err := channel.EncodeIntermediaryFees(fees)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.AppData = appData
+		v, err := channel.NewVirtualChannel(s, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &virtualfund.Objective{Status: protocols.Unapproved, V: v}
+	}
+	ireneFee := func(amount int64) []channel.IntermediaryFee {
+		return []channel.IntermediaryFee{{Intermediary: testactors.Irene.Address(), Amount: big.NewInt(amount)}}
+	}
+	feeSchedule := func(flat int64, basisPoints uint64) []FeeSchedule {
+		return []FeeSchedule{{Asset: common.Address{}, Flat: big.NewInt(flat), BasisPoints: basisPoints}}
+	}
+	vTotal := vfo.V.PreFundState().Outcome[0].TotalAllocated().Int64()
+
+	// Alice already has 6 in another channel with Bob
+	ms := store.NewMemStore(testactors.Alice.PrivateKey)
+	other := dfo.C.Clone()
+	other.Id = types.Destination{1}
+	if err := ms.SetChannel(other); err != nil {
+		t.Fatal(err)
+	}
+
+	// Alice or Bob tops up 5 into their ledger channel
+	topUp := func(depositor testactors.Actor) *ledgertopup.Objective {
+		return &ledgertopup.Objective{Status: protocols.Unapproved, Ledger: ledgerWith(t, testactors.Bob), Depositor: depositor.Address(), Asset: types.Address{}, Amount: big.NewInt(5)}
+	}
+
+	// Alice pays 5 to Bob, who pays Irene, who pays Alice
+	ledgers := map[types.Address]*consensus_channel.ConsensusChannel{
+		testactors.Bob.Address():   ledgerWith(t, testactors.Bob),
+		testactors.Irene.Address(): ledgerWith(t, testactors.Irene),
+	}
+	getLedger := func(counterparty types.Address) (*consensus_channel.ConsensusChannel, bool) {
+		ledger, ok := ledgers[counterparty]
+		return ledger, ok
+	}
+	rebalanceRequest := rebalance.NewObjectiveRequest([]types.Address{testactors.Bob.Address(), testactors.Irene.Address()}, types.Address{}, big.NewInt(5), common.HexToAddress("0x01"), 60, 1)
+	ro, err := rebalance.NewObjective(rebalanceRequest, false, testactors.Alice.Address(), getLedger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Alice swaps with Bob
+	mso := &multiswap.Objective{Status: protocols.Unapproved, C: map[uint]*channel.SwapChannel{0: {Channel: *dfo.C}}}
+
+	testCases := []struct {
+		name    string
+		rules   PolicyRules
+		o       protocols.Objective
+		approve bool
+	}{
+		{"no rules", PolicyRules{}, &dfo, true},
+		{"denied counterparty", PolicyRules{DeniedCounterparties: []common.Address{testactors.Bob.Address()}}, &dfo, false},
+		{"counterparty not allowed", PolicyRules{AllowedCounterparties: []common.Address{testactors.Irene.Address()}}, &dfo, false},
+		{"allowed counterparty", PolicyRules{AllowedCounterparties: []common.Address{testactors.Bob.Address()}}, &dfo, true},
+		{"deposit above maximum", PolicyRules{MaxDeposits: limit(5)}, &dfo, false},
+		{"deposit at maximum", PolicyRules{MaxDeposits: limit(6)}, &dfo, true},
+		{"challenge duration below minimum", PolicyRules{MinChallengeDuration: 61}, &dfo, false},
+		{"challenge duration above maximum", PolicyRules{MaxChallengeDuration: 59}, &dfo, false},
+		{"challenge duration within bounds", PolicyRules{MinChallengeDuration: 60, MaxChallengeDuration: 60}, &dfo, true},
+		{"exposure above maximum", PolicyRules{MaxExposures: limit(11)}, &dfo, false},
+		{"exposure at maximum", PolicyRules{MaxExposures: limit(12)}, &dfo, true},
+		{"intermediary not allowed", PolicyRules{AllowedIntermediaries: []common.Address{testactors.Bob.Address()}}, &vfo, false},
+		{"allowed intermediary", PolicyRules{AllowedIntermediaries: []common.Address{testactors.Irene.Address()}}, &vfo, true},
+		{"fees only charged by intermediaries", PolicyRules{IntermediaryFees: feeSchedule(1, 0)}, &vfo, true},
+		{"no intermediary fee", PolicyRules{IntermediaryFees: feeSchedule(1, 0)}, ireneVFO(nil), false},
+		{"flat fee paid", PolicyRules{IntermediaryFees: feeSchedule(1, 0)}, ireneVFO(ireneFee(1)), true},
+		{"basis point fee underpaid", PolicyRules{IntermediaryFees: feeSchedule(0, 10_000)}, ireneVFO(ireneFee(vTotal - 1)), false},
+		{"basis point fee paid", PolicyRules{IntermediaryFees: feeSchedule(0, 10_000)}, ireneVFO(ireneFee(vTotal)), true},
+		{"top up with denied counterparty", PolicyRules{DeniedCounterparties: []common.Address{testactors.Bob.Address()}}, topUp(testactors.Alice), false},
+		{"top up deposit above maximum", PolicyRules{MaxDeposits: limit(4)}, topUp(testactors.Alice), false},
+		{"counterparty top up ignores deposit limit", PolicyRules{MaxDeposits: limit(0)}, topUp(testactors.Bob), true},
+		{"top up exposure above maximum", PolicyRules{MaxExposures: limit(10)}, topUp(testactors.Alice), false},
+		{"top up exposure at maximum", PolicyRules{MaxExposures: limit(11)}, topUp(testactors.Alice), true},
+		{"rebalance within rules", PolicyRules{AllowedCounterparties: []common.Address{testactors.Bob.Address(), testactors.Irene.Address()}}, &ro, true},
+		{"rebalance with payee not allowed", PolicyRules{AllowedCounterparties: []common.Address{testactors.Irene.Address()}}, &ro, false},
+		{"rebalance with denied payer", PolicyRules{DeniedCounterparties: []common.Address{testactors.Irene.Address()}}, &ro, false},
+		{"rebalance challenge duration below minimum", PolicyRules{MinChallengeDuration: 61}, &ro, false},
+		{"rebalance exposure above maximum", PolicyRules{MaxExposures: limit(4)}, &ro, false},
+		{"rebalance exposure at maximum", PolicyRules{MaxExposures: limit(5)}, &ro, true},
+		{"multiswap within rules", PolicyRules{}, mso, true},
+		{"multiswap with denied counterparty", PolicyRules{DeniedCounterparties: []common.Address{testactors.Bob.Address()}}, mso, false},
+		{"unknown objective", PolicyRules{}, unknownObjective{&dfo}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rp, err := NewRulePolicy(tc.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rp.bindStore(ms)
+
+			if got := rp.ShouldApprove(tc.o); got != tc.approve {
+				t.Fatalf("expected ShouldApprove to return %t, got %t", tc.approve, got)
+			}
+		})
+	}
+
+	t.Run("exposure without a store", func(t *testing.T) {
+		rp, _ := NewRulePolicy(PolicyRules{MaxExposures: limit(100)})
+		if rp.ShouldApprove(&dfo) {
+			t.Fatal("expected the objective to be rejected when exposure cannot be worked out")
+		}
+	})
+}
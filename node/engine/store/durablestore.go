@@ -1,24 +1,31 @@
 package store
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/statechannels/go-nitro/channel"
 	"github.com/statechannels/go-nitro/channel/consensus_channel"
 	"github.com/statechannels/go-nitro/crypto"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
 	"github.com/statechannels/go-nitro/payments"
 	"github.com/statechannels/go-nitro/protocols"
 	"github.com/statechannels/go-nitro/protocols/bridgeddefund"
 	"github.com/statechannels/go-nitro/protocols/bridgedfund"
 	"github.com/statechannels/go-nitro/protocols/directdefund"
 	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/ledgertopup"
+	"github.com/statechannels/go-nitro/protocols/ledgerwithdraw"
 	"github.com/statechannels/go-nitro/protocols/mirrorbridgeddefund"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/rebalance"
 	"github.com/statechannels/go-nitro/protocols/swap"
 	"github.com/statechannels/go-nitro/protocols/swapdefund"
 	"github.com/statechannels/go-nitro/protocols/swapfund"
@@ -28,6 +35,37 @@ import (
 	"github.com/tidwall/buntdb"
 )
 
+const (
+	swapRecordsByTime    = "by_time"
+	swapRecordsByChannel = "by_channel"
+)
+
+// swapRecordKey is the part of a swap record which the swap records are indexed by
+type swapRecordKey struct {
+	Swap struct {
+		ChannelId types.Destination
+	}
+	RecordedAt time.Time
+}
+
+// decodeSwapRecordKey returns the key of the swap record. Records which cannot be decoded sort first, and are reported when they are read.
+func decodeSwapRecordKey(rJSON string) swapRecordKey {
+	var k swapRecordKey
+	_ = json.Unmarshal([]byte(rJSON), &k)
+	return k
+}
+
+// lessRecordedAt orders swap records by the time they were recorded
+func lessRecordedAt(a, b string) bool {
+	return decodeSwapRecordKey(a).RecordedAt.Before(decodeSwapRecordKey(b).RecordedAt)
+}
+
+// lessSwapChannelId orders swap records by the channel of their swap
+func lessSwapChannelId(a, b string) bool {
+	ka, kb := decodeSwapRecordKey(a), decodeSwapRecordKey(b)
+	return bytes.Compare(ka.Swap.ChannelId[:], kb.Swap.ChannelId[:]) < 0
+}
+
 type DurableStore struct {
 	objectives         *buntdb.DB
 	channels           *buntdb.DB
@@ -37,6 +75,9 @@ type DurableStore struct {
 	lastBlockNumSeen   *buntdb.DB
 	swaps              *buntdb.DB
 	channelToSwaps     *buntdb.DB
+	swapRecords        *buntdb.DB
+	failedObjectives   *buntdb.DB
+	objectiveMetadata  *buntdb.DB
 
 	key     string // the signing key of the store's engine
 	address string // the (Ethereum) address associated to the signing key
@@ -96,6 +137,29 @@ func NewDurableStore(key []byte, folder string, config buntdb.Config) (Store, er
 		return nil, err
 	}
 
+	ps.swapRecords, err = ps.openDB("swap_records", config)
+	if err != nil {
+		return nil, err
+	}
+	err = ps.swapRecords.CreateIndex(swapRecordsByTime, "*", lessRecordedAt)
+	if err != nil {
+		return nil, err
+	}
+	err = ps.swapRecords.CreateIndex(swapRecordsByChannel, "*", lessSwapChannelId, lessRecordedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	ps.failedObjectives, err = ps.openDB("failed_objectives", config)
+	if err != nil {
+		return nil, err
+	}
+
+	ps.objectiveMetadata, err = ps.openDB("objective_metadata", config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ps, nil
 }
 
@@ -128,6 +192,18 @@ func (ds *DurableStore) Close() error {
 	if err != nil {
 		return err
 	}
+	err = ds.swapRecords.Close()
+	if err != nil {
+		return err
+	}
+	err = ds.failedObjectives.Close()
+	if err != nil {
+		return err
+	}
+	err = ds.objectiveMetadata.Close()
+	if err != nil {
+		return err
+	}
 	return ds.vouchers.Close()
 }
 
@@ -191,7 +267,7 @@ func (ds *DurableStore) GetObjectiveById(id protocols.ObjectiveId) (protocols.Ob
 
 		obj, err = decodeObjective(id, []byte(objJSON))
 		if err != nil {
-			return fmt.Errorf("error decoding objective %s: %w", id, err)
+			return fmt.Errorf("%w: error decoding objective %s: %w", ErrCorruptedData, id, err)
 		}
 
 		err = ds.populateChannelData(obj)
@@ -204,10 +280,181 @@ func (ds *DurableStore) GetObjectiveById(id protocols.ObjectiveId) (protocols.Ob
 	if err != nil && errors.Is(err, buntdb.ErrNotFound) {
 		return nil, ErrNoSuchObjective
 	}
+	if errors.Is(err, ErrCorruptedData) {
+		return nil, err
+	}
 
 	return obj, nil
 }
 
+// GetAllObjectives returns every stored objective
+func (ds *DurableStore) GetAllObjectives() ([]protocols.Objective, error) {
+	ids := []protocols.ObjectiveId{}
+	err := ds.objectives.View(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys("*", func(key, _ string) bool {
+			ids = append(ids, protocols.ObjectiveId(key))
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn := make([]protocols.Objective, 0, len(ids))
+	for _, id := range ids {
+		obj, err := ds.GetObjectiveById(id)
+		if errors.Is(err, ErrNoSuchObjective) {
+			// The objective was destroyed in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		toReturn = append(toReturn, obj)
+	}
+
+	return toReturn, nil
+}
+
+// GetObjectiveMetadata returns what is recorded about the progress of the objective, if anything
+func (ds *DurableStore) GetObjectiveMetadata(id protocols.ObjectiveId) (ObjectiveMetadata, bool) {
+	var om ObjectiveMetadata
+	err := ds.objectiveMetadata.View(func(tx *buntdb.Tx) error {
+		omJSON, err := tx.Get(string(id))
+		if err != nil {
+			return err
+		}
+
+		return json.Unmarshal([]byte(omJSON), &om)
+	})
+
+	reason, failed := ds.GetObjectiveFailure(id)
+	om.FailureReason = reason
+
+	return om, err == nil || failed
+}
+
+// GetAllObjectiveMetadata returns what is recorded about the progress of every objective
+func (ds *DurableStore) GetAllObjectiveMetadata() (map[protocols.ObjectiveId]ObjectiveMetadata, error) {
+	toReturn := make(map[protocols.ObjectiveId]ObjectiveMetadata)
+	err := ds.objectiveMetadata.View(func(tx *buntdb.Tx) error {
+		var decodeErr error
+		err := tx.Ascend("", func(key, omJSON string) bool {
+			var om ObjectiveMetadata
+			if err := json.Unmarshal([]byte(omJSON), &om); err != nil {
+				decodeErr = fmt.Errorf("%w: error decoding metadata of objective %s: %w", ErrCorruptedData, key, err)
+				return false
+			}
+			toReturn[protocols.ObjectiveId(key)] = om
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		return decodeErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = ds.failedObjectives.View(func(tx *buntdb.Tx) error {
+		return tx.Ascend("", func(key, reason string) bool {
+			om := toReturn[protocols.ObjectiveId(key)]
+			om.FailureReason = reason
+			toReturn[protocols.ObjectiveId(key)] = om
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toReturn, nil
+}
+
+// createObjectiveMetadata records the creation of the objective, unless its metadata is already recorded
+func (ds *DurableStore) createObjectiveMetadata(id protocols.ObjectiveId) error {
+	return ds.objectiveMetadata.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Get(string(id))
+		if !errors.Is(err, buntdb.ErrNotFound) {
+			return err
+		}
+
+		omJSON, err := json.Marshal(newObjectiveMetadata())
+		if err != nil {
+			return err
+		}
+		_, _, err = tx.Set(string(id), string(omJSON), nil)
+		return err
+	})
+}
+
+// updateObjectiveMetadata applies the update to the metadata of the objective
+func (ds *DurableStore) updateObjectiveMetadata(id protocols.ObjectiveId, update func(*ObjectiveMetadata)) error {
+	return ds.objectiveMetadata.Update(func(tx *buntdb.Tx) error {
+		var om ObjectiveMetadata
+		omJSON, err := tx.Get(string(id))
+		if err == nil {
+			err = json.Unmarshal([]byte(omJSON), &om)
+			if err != nil {
+				return fmt.Errorf("%w: error decoding metadata of objective %s: %w", ErrCorruptedData, id, err)
+			}
+		} else if !errors.Is(err, buntdb.ErrNotFound) {
+			return err
+		}
+
+		update(&om)
+		om.touch()
+
+		updatedJSON, err := json.Marshal(om)
+		if err != nil {
+			return err
+		}
+		_, _, err = tx.Set(string(id), string(updatedJSON), nil)
+		return err
+	})
+}
+
+// SetObjectiveWaitingFor records the point the objective is paused at
+func (ds *DurableStore) SetObjectiveWaitingFor(id protocols.ObjectiveId, waitingFor protocols.WaitingFor) error {
+	return ds.updateObjectiveMetadata(id, func(om *ObjectiveMetadata) { om.WaitingFor = waitingFor })
+}
+
+// SetObjectiveFailure marks the objective as failed for the given reason
+func (ds *DurableStore) SetObjectiveFailure(id protocols.ObjectiveId, reason string) error {
+	err := ds.failedObjectives.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(string(id), reason, nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return ds.updateObjectiveMetadata(id, func(*ObjectiveMetadata) {})
+}
+
+// GetObjectiveFailure returns the reason the objective failed, if it has failed
+func (ds *DurableStore) GetObjectiveFailure(id protocols.ObjectiveId) (reason string, failed bool) {
+	err := ds.failedObjectives.View(func(tx *buntdb.Tx) error {
+		var err error
+		reason, err = tx.Get(string(id))
+		return err
+	})
+
+	return reason, err == nil
+}
+
+// ClearObjectiveFailure removes the failure of the objective, so that it can be progressed again
+func (ds *DurableStore) ClearObjectiveFailure(id protocols.ObjectiveId) error {
+	return ds.failedObjectives.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(string(id))
+		if errors.Is(err, buntdb.ErrNotFound) {
+			return nil
+		}
+		return err
+	})
+}
+
 func (ds *DurableStore) SetObjective(obj protocols.Objective) error {
 	// todo: locking
 	objJSON, err := obj.MarshalJSON()
@@ -222,6 +469,10 @@ func (ds *DurableStore) SetObjective(obj protocols.Objective) error {
 	if err != nil {
 		return err
 	}
+	err = ds.createObjectiveMetadata(obj.Id())
+	if err != nil {
+		return err
+	}
 	for _, rel := range obj.Related() {
 		switch related := rel.(type) {
 		case *channel.VirtualChannel:
@@ -246,6 +497,17 @@ func (ds *DurableStore) SetObjective(obj protocols.Objective) error {
 				return fmt.Errorf("expected swap objective")
 			}
 
+			if (so.GetStatus() == protocols.Completed && so.SwapStatus == types.Accepted) || so.GetStatus() == protocols.Rejected {
+				record, err := newSwapRecord(so)
+				if err != nil {
+					return fmt.Errorf("error recording swap %s from objective %s: %w", related.Id, obj.Id(), err)
+				}
+				err = ds.SetSwapRecord(record)
+				if err != nil {
+					return fmt.Errorf("error recording swap %s from objective %s: %w", related.Id, obj.Id(), err)
+				}
+			}
+
 			if so.GetStatus() == protocols.Completed && so.SwapStatus == types.Accepted {
 				// Add swap to channelToSwaps map if successful
 				removedSwap, err := ds.SetChannelToSwaps(*related)
@@ -285,6 +547,13 @@ func (ds *DurableStore) SetObjective(obj protocols.Objective) error {
 		}
 	}
 
+	if mso, ok := obj.(*multiswap.Objective); ok {
+		err := recordCommittedLegs(ds, mso)
+		if err != nil {
+			return fmt.Errorf("error recording swaps from objective %s: %w", obj.Id(), err)
+		}
+	}
+
 	// Objective ownership can only be transferred if the channel is not owned by another objective
 	var prevOwner protocols.ObjectiveId
 	var isOwned bool = false
@@ -346,6 +615,34 @@ func (ds *DurableStore) SetLastBlockNumSeen(blockNumber uint64) error {
 	})
 }
 
+// GetEventCursor retrieves the position of the last chain event handled by this node
+func (ds *DurableStore) GetEventCursor() (chainservice.EventCursor, error) {
+	var cursor chainservice.EventCursor
+	err := ds.lastBlockNumSeen.View(func(tx *buntdb.Tx) error {
+		val, err := tx.Get(eventCursorKey)
+		if err != nil {
+			if errors.Is(err, buntdb.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		return json.Unmarshal([]byte(val), &cursor)
+	})
+	return cursor, err
+}
+
+// SetEventCursor sets the position of the last chain event handled by this node
+func (ds *DurableStore) SetEventCursor(cursor chainservice.EventCursor) error {
+	cursorJSON, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	return ds.lastBlockNumSeen.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(eventCursorKey, string(cursorJSON), nil)
+		return err
+	})
+}
+
 // SetChannel sets the channel in the store.
 func (ds *DurableStore) SetChannel(ch *channel.Channel) error {
 	chJSON, err := ch.MarshalJSON()
@@ -711,6 +1008,10 @@ func (ds *DurableStore) populateChannelData(obj protocols.Objective) error {
 		}
 
 		swap, err := ds.GetSwapById(o.Swap.Id)
+		if errors.Is(err, ErrNoSuchSwap) && o.Status == protocols.Rejected {
+			// Rejected swaps are removed from the store, but their records are kept
+			swap, err = ds.getRecordedSwap(o.Swap.Id)
+		}
 		if err != nil {
 			return fmt.Errorf("error getting swap by Id: %w", err)
 		}
@@ -835,6 +1136,58 @@ func (ds *DurableStore) populateChannelData(obj protocols.Objective) error {
 
 		return nil
 
+	case *ledgertopup.Objective:
+		ledger, err := ds.GetConsensusChannelById(o.Ledger.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving ledger channel data for objective %s: %w", id, err)
+		}
+
+		o.Ledger = ledger
+
+		return nil
+
+	case *ledgerwithdraw.Objective:
+		ch, err := ds.getChannelById(o.C.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving channel data for objective %s: %w", id, err)
+		}
+
+		o.C = &ch
+
+		return nil
+
+	case *rebalance.Objective:
+		r, err := ds.getChannelById(o.R.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving rebalance channel data for objective %s: %w", id, err)
+		}
+		o.R = &r
+
+		left, err := ds.GetConsensusChannelById(o.ToMyLeft.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving left ledger channel data for objective %s: %w", id, err)
+		}
+		o.ToMyLeft = left
+
+		right, err := ds.GetConsensusChannelById(o.ToMyRight.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving right ledger channel data for objective %s: %w", id, err)
+		}
+		o.ToMyRight = right
+
+		return nil
+
+	case *multiswap.Objective:
+		for i, c := range o.C {
+			ch, err := ds.getChannelById(c.Id)
+			if err != nil {
+				return fmt.Errorf("error retrieving swap channel data for objective %s: %w", id, err)
+			}
+			o.C[i] = &channel.SwapChannel{Channel: ch}
+		}
+
+		return nil
+
 	default:
 		return fmt.Errorf("objective %s did not correctly represent a known Objective type", id)
 	}
@@ -882,10 +1235,28 @@ func (ds *DurableStore) RemoveVoucherInfo(channelId types.Destination) error {
 }
 
 func (ds *DurableStore) DestroyObjective(id protocols.ObjectiveId) error {
-	return ds.objectives.Update(func(tx *buntdb.Tx) error {
+	err := ds.objectives.Update(func(tx *buntdb.Tx) error {
 		_, err := tx.Delete(string(id))
 		return err
 	})
+	if err != nil {
+		return err
+	}
+
+	for _, db := range []*buntdb.DB{ds.objectiveMetadata, ds.failedObjectives} {
+		err := db.Update(func(tx *buntdb.Tx) error {
+			_, err := tx.Delete(string(id))
+			if errors.Is(err, buntdb.ErrNotFound) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (ds *DurableStore) GetPendingSwapByChannelId(id types.Destination) (*payments.Swap, error) {
@@ -903,7 +1274,7 @@ func (ds *DurableStore) GetPendingSwapByChannelId(id types.Destination) (*paymen
 				return true // objective not found, continue looking
 			}
 
-			if obj.C.Id == id && obj.SwapStatus == types.PendingConfirmation {
+			if obj.C.Id == id && (obj.SwapStatus == types.PendingConfirmation || obj.CancellationPending) {
 				pendingSwapId = obj.Swap.Id
 				return false // objective found, stop iteration
 			}
@@ -924,6 +1295,122 @@ func (ds *DurableStore) GetPendingSwapByChannelId(id types.Destination) (*paymen
 	return nil, nil
 }
 
+// SetSwapRecord records the outcome of a swap, unless it has already been recorded and the record does not supersede it
+func (ds *DurableStore) SetSwapRecord(record SwapRecord) error {
+	rJSON, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error marshalling swap record: %w", err)
+	}
+
+	return ds.swapRecords.Update(func(tx *buntdb.Tx) error {
+		existingJSON, err := tx.Get(record.Swap.Id.String())
+		if err == nil {
+			var existing SwapRecord
+			if err := json.Unmarshal([]byte(existingJSON), &existing); err != nil {
+				return fmt.Errorf("error unmarshalling swap record: %w", err)
+			}
+			if !record.supersedes(existing) {
+				return nil
+			}
+		} else if !errors.Is(err, buntdb.ErrNotFound) {
+			return err
+		}
+
+		_, _, err = tx.Set(record.Swap.Id.String(), string(rJSON), nil)
+		return err
+	})
+}
+
+// getRecordedSwap returns the swap of the swap record with the given id
+func (ds *DurableStore) getRecordedSwap(id types.Destination) (payments.Swap, error) {
+	var record SwapRecord
+	err := ds.swapRecords.View(func(tx *buntdb.Tx) error {
+		rJSON, err := tx.Get(id.String())
+		if errors.Is(err, buntdb.ErrNotFound) {
+			return ErrNoSuchSwap
+		}
+		if err != nil {
+			return err
+		}
+
+		return json.Unmarshal([]byte(rJSON), &record)
+	})
+	if err != nil {
+		return payments.Swap{}, err
+	}
+	return record.Swap, nil
+}
+
+// GetAllSwapRecords returns the record of every swap which has come to an end
+func (ds *DurableStore) GetAllSwapRecords() ([]SwapRecord, error) {
+	toReturn := []SwapRecord{}
+	err := ds.swapRecords.View(func(tx *buntdb.Tx) error {
+		var decodeErr error
+		err := tx.Ascend("", func(key, rJSON string) bool {
+			var record SwapRecord
+			if err := json.Unmarshal([]byte(rJSON), &record); err != nil {
+				decodeErr = fmt.Errorf("%w: error decoding record of swap %s: %w", ErrCorruptedData, key, err)
+				return false
+			}
+			toReturn = append(toReturn, record)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		return decodeErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toReturn, nil
+}
+
+// GetSwapRecords returns the records of the swaps of the channel, or of every channel if the id is zero, which were recorded
+// within the given times, ordered by the time they were recorded. The records are read from the indexes by channel and by time.
+func (ds *DurableStore) GetSwapRecords(channelId types.Destination, from, to time.Time) ([]SwapRecord, error) {
+	index := swapRecordsByTime
+	pivot := SwapRecord{RecordedAt: from}
+	if !channelId.IsZero() {
+		index = swapRecordsByChannel
+		pivot.Swap.ChannelId = channelId
+	}
+	pivotJSON, err := json.Marshal(pivot)
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn := []SwapRecord{}
+	err = ds.swapRecords.View(func(tx *buntdb.Tx) error {
+		var decodeErr error
+		err := tx.AscendGreaterOrEqual(index, string(pivotJSON), func(key, rJSON string) bool {
+			var record SwapRecord
+			if err := json.Unmarshal([]byte(rJSON), &record); err != nil {
+				decodeErr = fmt.Errorf("%w: error decoding record of swap %s: %w", ErrCorruptedData, key, err)
+				return false
+			}
+			if !channelId.IsZero() && record.Swap.ChannelId != channelId {
+				return false // past the records of the channel
+			}
+			if !to.IsZero() && !record.RecordedAt.Before(to) {
+				return false
+			}
+			toReturn = append(toReturn, record)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		return decodeErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toReturn, nil
+}
+
 func (ds *DurableStore) GetSwapsByChannelId(id types.Destination) ([]payments.Swap, error) {
 	swapQueue := payments.NewSwapsQueue()
 
@@ -2,21 +2,29 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/statechannels/go-nitro/channel"
 	"github.com/statechannels/go-nitro/channel/consensus_channel"
 	"github.com/statechannels/go-nitro/crypto"
 	"github.com/statechannels/go-nitro/internal/safesync"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
 	"github.com/statechannels/go-nitro/payments"
 	"github.com/statechannels/go-nitro/protocols"
 	"github.com/statechannels/go-nitro/protocols/bridgeddefund"
 	"github.com/statechannels/go-nitro/protocols/bridgedfund"
 	"github.com/statechannels/go-nitro/protocols/directdefund"
 	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/ledgertopup"
+	"github.com/statechannels/go-nitro/protocols/ledgerwithdraw"
 	"github.com/statechannels/go-nitro/protocols/mirrorbridgeddefund"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/rebalance"
 	"github.com/statechannels/go-nitro/protocols/swap"
 	"github.com/statechannels/go-nitro/protocols/swapdefund"
 	"github.com/statechannels/go-nitro/protocols/swapfund"
@@ -26,8 +34,9 @@ import (
 )
 
 type blockData struct {
-	blockNum uint64
-	mu       sync.Mutex
+	blockNum    uint64
+	eventCursor chainservice.EventCursor
+	mu          sync.Mutex
 }
 
 type MemStore struct {
@@ -38,6 +47,11 @@ type MemStore struct {
 	vouchers           safesync.Map[[]byte]
 	swaps              safesync.Map[[]byte]
 	channelToSwaps     safesync.Map[[]byte]
+	swapRecords        safesync.Map[[]byte]
+	failedObjectives   safesync.Map[string]
+	objectiveMetadata  safesync.Map[ObjectiveMetadata]
+	// objectiveMetadataLock serializes updates to objectiveMetadata
+	objectiveMetadataLock sync.Mutex
 
 	lastBlockSeen blockData
 
@@ -58,6 +72,9 @@ func NewMemStore(key []byte) Store {
 	ms.lastBlockSeen = blockData{}
 	ms.swaps = safesync.Map[[]byte]{}
 	ms.channelToSwaps = safesync.Map[[]byte]{}
+	ms.swapRecords = safesync.Map[[]byte]{}
+	ms.failedObjectives = safesync.Map[string]{}
+	ms.objectiveMetadata = safesync.Map[ObjectiveMetadata]{}
 	return &ms
 }
 
@@ -151,6 +168,86 @@ func (ms *MemStore) SetChannelToSwaps(swap payments.Swap) (payments.Swap, error)
 	return removedSwap, nil
 }
 
+// SetSwapRecord records the outcome of a swap, unless it has already been recorded and the record does not supersede it
+func (ms *MemStore) SetSwapRecord(record SwapRecord) error {
+	rJSON, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error marshalling swap record: %w", err)
+	}
+
+	if existingJSON, ok := ms.swapRecords.Load(record.Swap.Id.String()); ok {
+		var existing SwapRecord
+		if err := json.Unmarshal(existingJSON, &existing); err != nil {
+			return fmt.Errorf("error unmarshalling swap record: %w", err)
+		}
+		if !record.supersedes(existing) {
+			return nil
+		}
+	}
+
+	ms.swapRecords.Store(record.Swap.Id.String(), rJSON)
+	return nil
+}
+
+// getRecordedSwap returns the swap of the swap record with the given id
+func (ms *MemStore) getRecordedSwap(id types.Destination) (payments.Swap, error) {
+	rJSON, ok := ms.swapRecords.Load(id.String())
+	if !ok {
+		return payments.Swap{}, ErrNoSuchSwap
+	}
+
+	var record SwapRecord
+	if err := json.Unmarshal(rJSON, &record); err != nil {
+		return payments.Swap{}, fmt.Errorf("error unmarshalling swap record: %w", err)
+	}
+	return record.Swap, nil
+}
+
+// GetAllSwapRecords returns the record of every swap which has come to an end
+func (ms *MemStore) GetAllSwapRecords() ([]SwapRecord, error) {
+	toReturn := []SwapRecord{}
+	var decodeErr error
+	ms.swapRecords.Range(func(key string, rJSON []byte) bool {
+		var record SwapRecord
+		if err := json.Unmarshal(rJSON, &record); err != nil {
+			decodeErr = fmt.Errorf("%w: error decoding record of swap %s: %w", ErrCorruptedData, key, err)
+			return false
+		}
+		toReturn = append(toReturn, record)
+		return true
+	})
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return toReturn, nil
+}
+
+// GetSwapRecords returns the records of the swaps of the channel, or of every channel if the id is zero, which were recorded
+// within the given times, ordered by the time they were recorded
+func (ms *MemStore) GetSwapRecords(channelId types.Destination, from, to time.Time) ([]SwapRecord, error) {
+	records, err := ms.GetAllSwapRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn := []SwapRecord{}
+	for _, r := range records {
+		if (channelId.IsZero() || r.Swap.ChannelId == channelId) && recordedWithin(r, from, to) {
+			toReturn = append(toReturn, r)
+		}
+	}
+
+	sort.Slice(toReturn, func(i, j int) bool {
+		if toReturn[i].RecordedAt.Equal(toReturn[j].RecordedAt) {
+			return toReturn[i].Swap.Id.String() < toReturn[j].Swap.Id.String()
+		}
+		return toReturn[i].RecordedAt.Before(toReturn[j].RecordedAt)
+	})
+
+	return toReturn, nil
+}
+
 func (ms *MemStore) GetObjectiveById(id protocols.ObjectiveId) (protocols.Objective, error) {
 	// todo: locking
 	objJSON, ok := ms.objectives.Load(string(id))
@@ -162,7 +259,7 @@ func (ms *MemStore) GetObjectiveById(id protocols.ObjectiveId) (protocols.Object
 
 	obj, err := decodeObjective(id, objJSON)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding objective %s: %w", id, err)
+		return nil, fmt.Errorf("%w: error decoding objective %s: %w", ErrCorruptedData, id, err)
 	}
 
 	err = ms.populateChannelData(obj)
@@ -174,6 +271,101 @@ func (ms *MemStore) GetObjectiveById(id protocols.ObjectiveId) (protocols.Object
 	return obj, nil
 }
 
+// GetAllObjectives returns every stored objective
+func (ms *MemStore) GetAllObjectives() ([]protocols.Objective, error) {
+	ids := []protocols.ObjectiveId{}
+	ms.objectives.Range(func(key string, _ []byte) bool {
+		ids = append(ids, protocols.ObjectiveId(key))
+		return true
+	})
+
+	toReturn := make([]protocols.Objective, 0, len(ids))
+	for _, id := range ids {
+		obj, err := ms.GetObjectiveById(id)
+		if errors.Is(err, ErrNoSuchObjective) {
+			// The objective was destroyed in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		toReturn = append(toReturn, obj)
+	}
+
+	return toReturn, nil
+}
+
+// GetObjectiveMetadata returns what is recorded about the progress of the objective, if anything
+func (ms *MemStore) GetObjectiveMetadata(id protocols.ObjectiveId) (ObjectiveMetadata, bool) {
+	om, ok := ms.objectiveMetadata.Load(string(id))
+	reason, failed := ms.failedObjectives.Load(string(id))
+	om.FailureReason = reason
+
+	return om, ok || failed
+}
+
+// GetAllObjectiveMetadata returns what is recorded about the progress of every objective
+func (ms *MemStore) GetAllObjectiveMetadata() (map[protocols.ObjectiveId]ObjectiveMetadata, error) {
+	toReturn := make(map[protocols.ObjectiveId]ObjectiveMetadata)
+	ms.objectiveMetadata.Range(func(key string, om ObjectiveMetadata) bool {
+		toReturn[protocols.ObjectiveId(key)] = om
+		return true
+	})
+	ms.failedObjectives.Range(func(key string, reason string) bool {
+		om := toReturn[protocols.ObjectiveId(key)]
+		om.FailureReason = reason
+		toReturn[protocols.ObjectiveId(key)] = om
+		return true
+	})
+
+	return toReturn, nil
+}
+
+// createObjectiveMetadata records the creation of the objective, unless its metadata is already recorded
+func (ms *MemStore) createObjectiveMetadata(id protocols.ObjectiveId) {
+	ms.objectiveMetadataLock.Lock()
+	defer ms.objectiveMetadataLock.Unlock()
+
+	if _, ok := ms.objectiveMetadata.Load(string(id)); !ok {
+		ms.objectiveMetadata.Store(string(id), newObjectiveMetadata())
+	}
+}
+
+// updateObjectiveMetadata applies the update to the metadata of the objective
+func (ms *MemStore) updateObjectiveMetadata(id protocols.ObjectiveId, update func(*ObjectiveMetadata)) {
+	ms.objectiveMetadataLock.Lock()
+	defer ms.objectiveMetadataLock.Unlock()
+
+	om, _ := ms.objectiveMetadata.Load(string(id))
+	update(&om)
+	om.touch()
+	ms.objectiveMetadata.Store(string(id), om)
+}
+
+// SetObjectiveWaitingFor records the point the objective is paused at
+func (ms *MemStore) SetObjectiveWaitingFor(id protocols.ObjectiveId, waitingFor protocols.WaitingFor) error {
+	ms.updateObjectiveMetadata(id, func(om *ObjectiveMetadata) { om.WaitingFor = waitingFor })
+	return nil
+}
+
+// SetObjectiveFailure marks the objective as failed for the given reason
+func (ms *MemStore) SetObjectiveFailure(id protocols.ObjectiveId, reason string) error {
+	ms.failedObjectives.Store(string(id), reason)
+	ms.updateObjectiveMetadata(id, func(*ObjectiveMetadata) {})
+	return nil
+}
+
+// GetObjectiveFailure returns the reason the objective failed, if it has failed
+func (ms *MemStore) GetObjectiveFailure(id protocols.ObjectiveId) (string, bool) {
+	return ms.failedObjectives.Load(string(id))
+}
+
+// ClearObjectiveFailure removes the failure of the objective, so that it can be progressed again
+func (ms *MemStore) ClearObjectiveFailure(id protocols.ObjectiveId) error {
+	ms.failedObjectives.Delete(string(id))
+	return nil
+}
+
 func (ms *MemStore) SetObjective(obj protocols.Objective) error {
 	// todo: locking
 	objJSON, err := obj.MarshalJSON()
@@ -182,6 +374,7 @@ func (ms *MemStore) SetObjective(obj protocols.Objective) error {
 	}
 
 	ms.objectives.Store(string(obj.Id()), objJSON)
+	ms.createObjectiveMetadata(obj.Id())
 
 	for _, rel := range obj.Related() {
 		switch related := rel.(type) {
@@ -207,6 +400,17 @@ func (ms *MemStore) SetObjective(obj protocols.Objective) error {
 				return fmt.Errorf("expected swap objective")
 			}
 
+			if (so.GetStatus() == protocols.Completed && so.SwapStatus == types.Accepted) || so.GetStatus() == protocols.Rejected {
+				record, err := newSwapRecord(so)
+				if err != nil {
+					return fmt.Errorf("error recording swap %s from objective %s: %w", related.Id, obj.Id(), err)
+				}
+				err = ms.SetSwapRecord(record)
+				if err != nil {
+					return fmt.Errorf("error recording swap %s from objective %s: %w", related.Id, obj.Id(), err)
+				}
+			}
+
 			if so.GetStatus() == protocols.Completed && so.SwapStatus == types.Accepted {
 				// Add swap to channelToSwaps map if successful
 				removedSwap, err := ms.SetChannelToSwaps(*related)
@@ -246,6 +450,13 @@ func (ms *MemStore) SetObjective(obj protocols.Objective) error {
 		}
 	}
 
+	if mso, ok := obj.(*multiswap.Objective); ok {
+		err := recordCommittedLegs(ms, mso)
+		if err != nil {
+			return fmt.Errorf("error recording swaps from objective %s: %w", obj.Id(), err)
+		}
+	}
+
 	// Objective ownership can only be transferred if the channel is not owned by another objective
 	prevOwner, isOwned := ms.channelToObjective.Load(obj.OwnsChannel().String())
 	if status := obj.GetStatus(); status == protocols.Approved && !obj.OwnsChannel().IsZero() {
@@ -276,6 +487,22 @@ func (ms *MemStore) GetLastBlockNumSeen() (uint64, error) {
 	return lastBlockNumSeen, nil
 }
 
+// SetEventCursor records the position of the last chain event handled by the engine
+func (ms *MemStore) SetEventCursor(cursor chainservice.EventCursor) error {
+	ms.lastBlockSeen.mu.Lock()
+	ms.lastBlockSeen.eventCursor = cursor
+	ms.lastBlockSeen.mu.Unlock()
+	return nil
+}
+
+// GetEventCursor returns the position of the last chain event handled by the engine
+func (ms *MemStore) GetEventCursor() (chainservice.EventCursor, error) {
+	ms.lastBlockSeen.mu.Lock()
+	cursor := ms.lastBlockSeen.eventCursor
+	ms.lastBlockSeen.mu.Unlock()
+	return cursor, nil
+}
+
 // SetChannel sets the channel in the store.
 func (ms *MemStore) SetChannel(ch *channel.Channel) error {
 	chJSON, err := ch.MarshalJSON()
@@ -295,6 +522,8 @@ func (ms *MemStore) DestroyChannel(id types.Destination) error {
 
 func (ms *MemStore) DestroyObjective(id protocols.ObjectiveId) error {
 	ms.objectives.Delete(string(id))
+	ms.objectiveMetadata.Delete(string(id))
+	ms.failedObjectives.Delete(string(id))
 	return nil
 }
 
@@ -415,7 +644,7 @@ func (ms *MemStore) GetPendingSwapByChannelId(id types.Destination) (*payments.S
 			return true // objective not found, continue looking
 		}
 
-		if obj.C.Id == id && obj.SwapStatus == types.PendingConfirmation {
+		if obj.C.Id == id && (obj.SwapStatus == types.PendingConfirmation || obj.CancellationPending) {
 			pendingSwapId = obj.Swap.Id
 			return false // objective found, stop iteration
 		}
@@ -648,6 +877,10 @@ func (ms *MemStore) populateChannelData(obj protocols.Objective) error {
 		}
 
 		swap, err := ms.GetSwapById(o.Swap.Id)
+		if errors.Is(err, ErrNoSuchSwap) && o.Status == protocols.Rejected {
+			// Rejected swaps are removed from the store, but their records are kept
+			swap, err = ms.getRecordedSwap(o.Swap.Id)
+		}
 		if err != nil {
 			return fmt.Errorf("error getting swap by id: %w", err)
 		}
@@ -745,6 +978,58 @@ func (ms *MemStore) populateChannelData(obj protocols.Objective) error {
 
 		return nil
 
+	case *ledgertopup.Objective:
+		ledger, err := ms.GetConsensusChannelById(o.Ledger.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving ledger channel data for objective %s: %w", id, err)
+		}
+
+		o.Ledger = ledger
+
+		return nil
+
+	case *ledgerwithdraw.Objective:
+		ch, err := ms.getChannelById(o.C.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving channel data for objective %s: %w", id, err)
+		}
+
+		o.C = &ch
+
+		return nil
+
+	case *rebalance.Objective:
+		r, err := ms.getChannelById(o.R.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving rebalance channel data for objective %s: %w", id, err)
+		}
+		o.R = &r
+
+		left, err := ms.GetConsensusChannelById(o.ToMyLeft.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving left ledger channel data for objective %s: %w", id, err)
+		}
+		o.ToMyLeft = left
+
+		right, err := ms.GetConsensusChannelById(o.ToMyRight.Id)
+		if err != nil {
+			return fmt.Errorf("error retrieving right ledger channel data for objective %s: %w", id, err)
+		}
+		o.ToMyRight = right
+
+		return nil
+
+	case *multiswap.Objective:
+		for i, c := range o.C {
+			ch, err := ms.getChannelById(c.Id)
+			if err != nil {
+				return fmt.Errorf("error retrieving swap channel data for objective %s: %w", id, err)
+			}
+			o.C[i] = &channel.SwapChannel{Channel: ch}
+		}
+
+		return nil
+
 	default:
 		return fmt.Errorf("objective %s did not correctly represent a known Objective type", id)
 	}
@@ -783,6 +1068,18 @@ func decodeObjective(id protocols.ObjectiveId, data []byte) (protocols.Objective
 		mbdfo := mirrorbridgeddefund.Objective{}
 		err := mbdfo.UnmarshalJSON(data)
 		return &mbdfo, err
+	case ledgertopup.IsLedgerTopUpObjective(id):
+		lto := ledgertopup.Objective{}
+		err := lto.UnmarshalJSON(data)
+		return &lto, err
+	case ledgerwithdraw.IsLedgerWithdrawObjective(id):
+		lwo := ledgerwithdraw.Objective{}
+		err := lwo.UnmarshalJSON(data)
+		return &lwo, err
+	case rebalance.IsRebalanceObjective(id):
+		ro := rebalance.Objective{}
+		err := ro.UnmarshalJSON(data)
+		return &ro, err
 	case swapfund.IsSwapFundObjective(id):
 		sfo := swapfund.Objective{}
 		err := sfo.UnmarshalJSON(data)
@@ -791,6 +1088,10 @@ func decodeObjective(id protocols.ObjectiveId, data []byte) (protocols.Objective
 		sdfo := swapdefund.Objective{}
 		err := sdfo.UnmarshalJSON(data)
 		return &sdfo, err
+	case multiswap.IsMultiSwapObjective(id):
+		mso := multiswap.Objective{}
+		err := mso.UnmarshalJSON(data)
+		return &mso, err
 	case swap.IsSwapObjective(id):
 		so := swap.Objective{}
 		err := so.UnmarshalJSON(data)
@@ -2,15 +2,20 @@
 package store // import "github.com/statechannels/go-nitro/node/engine/store"
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"path/filepath"
+	"time"
 
 	"github.com/statechannels/go-nitro/channel"
 	"github.com/statechannels/go-nitro/channel/consensus_channel"
 	"github.com/statechannels/go-nitro/crypto"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
 	"github.com/statechannels/go-nitro/payments"
 	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/swap"
 	"github.com/statechannels/go-nitro/types"
 	"github.com/tidwall/buntdb"
 )
@@ -20,18 +25,27 @@ const (
 	ErrNoSuchChannel    = types.ConstError("store: failed to find required channel data")
 	ErrNoSuchSwap       = types.ConstError("store: failed to find required swap data")
 	ErrLoadVouchers     = types.ConstError("store: could not load vouchers")
+	ErrCorruptedData    = types.ConstError("store: corrupted data")
 	lastBlockNumSeenKey = "lastBlockNumSeen"
+	eventCursorKey      = "eventCursor"
 )
 
 // Store is responsible for persisting objectives, objective metadata, states, signatures, private keys and blockchain data
 type Store interface {
-	GetChannelSecretKey() *[]byte                                                 // Get a pointer to a secret key for signing channel updates
-	GetAddress() *types.Address                                                   // Get the (Ethereum) address associated with the ChannelSecretKey
-	GetObjectiveById(protocols.ObjectiveId) (protocols.Objective, error)          // Read an existing objective
-	GetObjectiveByChannelId(types.Destination) (obj protocols.Objective, ok bool) // Get the objective that currently owns the channel with the supplied ChannelId
-	SetObjective(protocols.Objective) error                                       // Write an objective
-	DestroyObjective(id protocols.ObjectiveId) error                              // Deletes an objective identified by the given ObjectiveId
-	GetChannelsByIds(ids []types.Destination) ([]*channel.Channel, error)         // Returns a collection of channels with the given ids
+	GetChannelSecretKey() *[]byte                                                  // Get a pointer to a secret key for signing channel updates
+	GetAddress() *types.Address                                                    // Get the (Ethereum) address associated with the ChannelSecretKey
+	GetObjectiveById(protocols.ObjectiveId) (protocols.Objective, error)           // Read an existing objective
+	GetObjectiveByChannelId(types.Destination) (obj protocols.Objective, ok bool)  // Get the objective that currently owns the channel with the supplied ChannelId
+	SetObjective(protocols.Objective) error                                        // Write an objective
+	DestroyObjective(id protocols.ObjectiveId) error                               // Deletes an objective identified by the given ObjectiveId, and what is recorded about its progress
+	GetAllObjectives() ([]protocols.Objective, error)                              // Returns every stored objective
+	GetObjectiveMetadata(id protocols.ObjectiveId) (ObjectiveMetadata, bool)       // Returns what is recorded about the progress of the objective, if anything
+	GetAllObjectiveMetadata() (map[protocols.ObjectiveId]ObjectiveMetadata, error) // Returns what is recorded about the progress of every objective
+	SetObjectiveWaitingFor(id protocols.ObjectiveId, w protocols.WaitingFor) error // Records the point the objective is paused at
+	SetObjectiveFailure(id protocols.ObjectiveId, reason string) error             // Marks the objective as failed for the given reason
+	GetObjectiveFailure(id protocols.ObjectiveId) (reason string, failed bool)     // Returns the reason the objective failed, if it has failed
+	ClearObjectiveFailure(id protocols.ObjectiveId) error                          // Removes the failure of the objective, so that it can be progressed again
+	GetChannelsByIds(ids []types.Destination) ([]*channel.Channel, error)          // Returns a collection of channels with the given ids
 	GetChannelById(id types.Destination) (c *channel.Channel, ok bool)
 	GetChannelsByParticipant(participant types.Address) ([]*channel.Channel, error) // Returns any channels that includes the given participant
 	GetAllChannels() ([]*channel.Channel, error)
@@ -41,15 +55,187 @@ type Store interface {
 	ReleaseChannelFromOwnership(types.Destination) error                         // Release channel from being owned by any objective
 	GetLastBlockNumSeen() (uint64, error)
 	SetLastBlockNumSeen(uint64) error
-	GetPendingSwapByChannelId(id types.Destination) (*payments.Swap, error)
+	GetEventCursor() (chainservice.EventCursor, error)                      // Returns the position of the last chain event handled by the engine
+	SetEventCursor(chainservice.EventCursor) error                          // Records the position of the last chain event handled by the engine
+	GetPendingSwapByChannelId(id types.Destination) (*payments.Swap, error) // Returns the swap which may still be applied to the channel, including a cancelled swap the counterparty has not acknowledged
 	GetSwapById(id types.Destination) (payments.Swap, error)
 	GetSwapsByChannelId(id types.Destination) ([]payments.Swap, error)
 	SetChannelToSwaps(swap payments.Swap) (payments.Swap, error)
+	SetSwapRecord(record SwapRecord) error    // Records the outcome of a swap, unless it has already been recorded and the record does not supersede it
+	GetAllSwapRecords() ([]SwapRecord, error) // Returns the record of every swap which has come to an end
+	// GetSwapRecords returns the records of the swaps of the channel, or of every channel if the id is zero, which were recorded
+	// from (inclusive) to (exclusive) the given times, ordered by the time they were recorded. Zero times do not bound the records.
+	GetSwapRecords(channelId types.Destination, from, to time.Time) ([]SwapRecord, error)
 	ConsensusChannelStore
 	payments.VoucherStore
 	io.Closer
 }
 
+// ObjectiveMetadata is what the store records about the progress of an objective, in addition to the objective itself
+type ObjectiveMetadata struct {
+	WaitingFor protocols.WaitingFor
+	// FailureReason is recorded with the failed objectives, rather than with the rest of the metadata
+	FailureReason string `json:"-"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// newObjectiveMetadata returns the metadata of an objective which is created now
+func newObjectiveMetadata() ObjectiveMetadata {
+	now := time.Now()
+	return ObjectiveMetadata{CreatedAt: now, UpdatedAt: now}
+}
+
+// Failed returns true if the objective has failed
+func (om ObjectiveMetadata) Failed() bool {
+	return om.FailureReason != ""
+}
+
+// touch sets the update time of the metadata. The creation time is recorded when the objective is first stored,
+// and is only set here for objectives which have not been stored.
+func (om *ObjectiveMetadata) touch() {
+	now := time.Now()
+	if om.CreatedAt.IsZero() {
+		om.CreatedAt = now
+	}
+	om.UpdatedAt = now
+}
+
+// SwapEnd is how a swap which was not accepted came to an end
+type SwapEnd string
+
+const (
+	SwapEndRejected  SwapEnd = ""          // rejected by us or by the counterparty
+	SwapEndCancelled SwapEnd = "Cancelled" // cancelled by us, or expired, before the counterparty accepted it
+	SwapEndAbandoned SwapEnd = "Abandoned" // abandoned because the swap, or the route it is a leg of, failed or timed out
+)
+
+// SwapRecord is what the store records about a swap once it has come to an end.
+// Unlike the recent swaps kept for each channel, swap records are never evicted.
+type SwapRecord struct {
+	Swap         payments.Swap
+	Status       types.SwapStatus
+	Counterparty types.Address
+	// IsSender is true if the swap was initiated by us
+	IsSender bool
+	// End is how the swap came to an end, if it was not accepted
+	End        SwapEnd `json:",omitempty"`
+	RecordedAt time.Time
+}
+
+// supersedes returns true if the record replaces the existing record of the same swap.
+// A swap which was cancelled, or expired, is still accepted if the counterparty accepted it before it learned of the cancellation.
+// A swap which is abandoned is rejected first, so that its rejection is recorded as abandonment.
+func (r SwapRecord) supersedes(existing SwapRecord) bool {
+	if existing.Status != types.Rejected {
+		return false
+	}
+	return r.Status == types.Accepted || (r.End == SwapEndAbandoned && existing.End == SwapEndRejected)
+}
+
+// recordedWithin returns true if the swap was recorded from (inclusive) to (exclusive) the given times, which are ignored if they are zero
+func recordedWithin(r SwapRecord, from, to time.Time) bool {
+	return (from.IsZero() || !r.RecordedAt.Before(from)) && (to.IsZero() || r.RecordedAt.Before(to))
+}
+
+// newSwapRecord returns the record of the swap of the given objective
+func newSwapRecord(so *swap.Objective) (SwapRecord, error) {
+	myIndex, err := swap.MyIndexInAllocations(so.C)
+	if err != nil {
+		return SwapRecord{}, err
+	}
+
+	record := SwapRecord{
+		Swap:         so.Swap.Clone(),
+		Status:       so.SwapStatus,
+		Counterparty: so.CounterParty(),
+		IsSender:     so.SwapSenderIndex == myIndex,
+		RecordedAt:   time.Now(),
+	}
+	if so.GetStatus() == protocols.Rejected && so.CancellationPending {
+		record.End = SwapEndCancelled
+	}
+	return record, nil
+}
+
+// RecordAbandonedSwaps records the swaps of a failed objective which were not completed as abandoned:
+// the swap of a swap objective, or the legs we are a party to of a route of swaps.
+func RecordAbandonedSwaps(s Store, objective protocols.Objective) error {
+	var abandoned []*swap.Objective
+	switch o := objective.(type) {
+	case *swap.Objective:
+		abandoned = append(abandoned, o)
+	case *multiswap.Objective:
+		abandoned = o.MyLegs()
+	}
+
+	for _, so := range abandoned {
+		if so.GetStatus() == protocols.Completed {
+			continue
+		}
+
+		record, err := newSwapRecord(so)
+		if err != nil {
+			return fmt.Errorf("error recording swap %s: %w", so.Swap.Id, err)
+		}
+		record.Status = types.Rejected
+		record.End = SwapEndAbandoned
+		err = s.SetSwapRecord(record)
+		if err != nil {
+			return fmt.Errorf("error recording swap %s: %w", so.Swap.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// swapStore is implemented by the stores which keep the swaps of each channel
+type swapStore interface {
+	Store
+	SetSwap(swap payments.Swap) error
+	DestroySwapById(id types.Destination) error
+}
+
+// recordCommittedLegs records the committed legs of a route of swaps which we are a party to as swaps of their own,
+// so that they are found among the recent swaps of their channels and in the swap history.
+func recordCommittedLegs(s swapStore, mso *multiswap.Objective) error {
+	for _, leg := range mso.MyLegs() {
+		if leg.GetStatus() != protocols.Completed {
+			continue
+		}
+		if _, err := s.GetSwapById(leg.Swap.Id); err == nil {
+			continue // already recorded
+		}
+
+		err := s.SetSwap(leg.Swap)
+		if err != nil {
+			return fmt.Errorf("error setting swap %s: %w", leg.Swap.Id, err)
+		}
+
+		record, err := newSwapRecord(leg)
+		if err != nil {
+			return fmt.Errorf("error recording swap %s: %w", leg.Swap.Id, err)
+		}
+		err = s.SetSwapRecord(record)
+		if err != nil {
+			return fmt.Errorf("error recording swap %s: %w", leg.Swap.Id, err)
+		}
+
+		removedSwap, err := s.SetChannelToSwaps(leg.Swap)
+		if err != nil {
+			return fmt.Errorf("error setting channel to swaps %s: %w", leg.Swap.Id, err)
+		}
+		if !removedSwap.Id.IsZero() {
+			err = s.DestroySwapById(removedSwap.Id)
+			if err != nil {
+				return fmt.Errorf("error in destroying old swap %s: %w", removedSwap.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 type ConsensusChannelStore interface {
 	GetAllConsensusChannels() ([]*consensus_channel.ConsensusChannel, error)
 	GetConsensusChannel(counterparty types.Address) (channel *consensus_channel.ConsensusChannel, ok bool)
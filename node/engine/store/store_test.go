@@ -3,7 +3,9 @@ package store_test
 import (
 	"math"
 	"math/big"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/go-cmp/cmp"
@@ -15,7 +17,9 @@ import (
 	ta "github.com/statechannels/go-nitro/internal/testactors"
 	td "github.com/statechannels/go-nitro/internal/testdata"
 	"github.com/statechannels/go-nitro/internal/testhelpers"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
 	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/payments"
 	"github.com/statechannels/go-nitro/protocols"
 	"github.com/statechannels/go-nitro/protocols/directfund"
 	"github.com/statechannels/go-nitro/protocols/virtualfund"
@@ -255,6 +259,40 @@ func TestGetLastBlockNumSeenDurableStore(t *testing.T) {
 	}
 }
 
+func TestGetEventCursorDurableStore(t *testing.T) {
+	pk := common.Hex2Bytes(`2af069c584758f9ec47c4224a8becc1983f28acfbe837bd7710b70f9fc6d5e44`)
+
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	durableStore, err := store.NewDurableStore(pk, dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := durableStore.GetEventCursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsZero() {
+		t.Fatalf("expected no event cursor, got %+v", got)
+	}
+
+	want := chainservice.EventCursor{BlockNum: 15, BlockHash: common.HexToHash("0xabc"), LogIndex: 2}
+	err = durableStore.SetEventCursor(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = durableStore.GetEventCursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("fetched result different than expected %s", diff)
+	}
+}
+
 func TestBigNumberStorage(t *testing.T) {
 	pk := common.Hex2Bytes(`2af069c584758f9ec47c4224a8becc1983f28acfbe837bd7710b70f9fc6d5e44`)
 
@@ -303,3 +341,271 @@ func TestBigNumberStorage(t *testing.T) {
 		}
 	}
 }
+
+func TestObjectiveMetadata(t *testing.T) {
+	pk := common.Hex2Bytes(`2af069c584758f9ec47c4224a8becc1983f28acfbe837bd7710b70f9fc6d5e44`)
+
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	durableStore, err := store.NewDurableStore(pk, dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer durableStore.Close()
+
+	stores := map[string]store.Store{"MemStore": store.NewMemStore(pk), "DurableStore": durableStore}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			id := protocols.ObjectiveId("DirectFunding-0x1234")
+
+			if _, ok := s.GetObjectiveMetadata(id); ok {
+				t.Fatal("expected no metadata for the objective")
+			}
+
+			if err := s.SetObjectiveWaitingFor(id, directfund.WaitingForCompletePrefund); err != nil {
+				t.Fatal(err)
+			}
+			created, ok := s.GetObjectiveMetadata(id)
+			if !ok || created.WaitingFor != directfund.WaitingForCompletePrefund || created.CreatedAt.IsZero() || !created.UpdatedAt.Equal(created.CreatedAt) {
+				t.Fatalf("unexpected metadata after first update: %+v", created)
+			}
+			if _, failed := s.GetObjectiveFailure(id); failed {
+				t.Fatal("expected objective not to have failed")
+			}
+
+			if err := s.SetObjectiveFailure(id, "insufficient funds"); err != nil {
+				t.Fatal(err)
+			}
+			reason, failed := s.GetObjectiveFailure(id)
+			if !failed || reason != "insufficient funds" {
+				t.Fatalf("expected objective to have failed with the stored reason, got %q (failed: %t)", reason, failed)
+			}
+			updated, _ := s.GetObjectiveMetadata(id)
+			if updated.WaitingFor != directfund.WaitingForCompletePrefund || !updated.CreatedAt.Equal(created.CreatedAt) || updated.UpdatedAt.Before(created.UpdatedAt) {
+				t.Fatalf("expected the failure to keep the other metadata, got %+v", updated)
+			}
+
+			if err := s.ClearObjectiveFailure(id); err != nil {
+				t.Fatal(err)
+			}
+			if _, failed := s.GetObjectiveFailure(id); failed {
+				t.Fatal("expected objective failure to be cleared")
+			}
+			if err := s.ClearObjectiveFailure(protocols.ObjectiveId("DirectFunding-0x5678")); err != nil {
+				t.Fatalf("expected clearing a missing failure to succeed, got %v", err)
+			}
+
+			all, err := s.GetAllObjectiveMetadata()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cleared, _ := s.GetObjectiveMetadata(id)
+			if len(all) != 1 || !cmp.Equal(all[id], cleared) {
+				t.Fatalf("expected the metadata of exactly one objective, got %+v", all)
+			}
+		})
+	}
+}
+
+func TestObjectiveMetadataLifecycle(t *testing.T) {
+	pk := common.Hex2Bytes(`2af069c584758f9ec47c4224a8becc1983f28acfbe837bd7710b70f9fc6d5e44`)
+
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	durableStore, err := store.NewDurableStore(pk, dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer durableStore.Close()
+
+	stores := map[string]store.Store{"MemStore": store.NewMemStore(pk), "DurableStore": durableStore}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			dfo := td.Objectives.Directfund.GenericDFO()
+			id := dfo.Id()
+
+			if err := s.SetObjective(&dfo); err != nil {
+				t.Fatal(err)
+			}
+			created, ok := s.GetObjectiveMetadata(id)
+			if !ok || created.CreatedAt.IsZero() || created.WaitingFor != "" {
+				t.Fatalf("expected storing the objective to record its creation, got %+v", created)
+			}
+
+			if err := s.SetObjective(&dfo); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.SetObjectiveWaitingFor(id, directfund.WaitingForCompletePrefund); err != nil {
+				t.Fatal(err)
+			}
+			updated, _ := s.GetObjectiveMetadata(id)
+			if !updated.CreatedAt.Equal(created.CreatedAt) {
+				t.Fatalf("expected the creation time %v to be kept, got %v", created.CreatedAt, updated.CreatedAt)
+			}
+
+			if err := s.SetObjectiveFailure(id, "insufficient funds"); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.DestroyObjective(id); err != nil {
+				t.Fatal(err)
+			}
+			if om, ok := s.GetObjectiveMetadata(id); ok {
+				t.Fatalf("expected the metadata to be destroyed with the objective, got %+v", om)
+			}
+			if _, failed := s.GetObjectiveFailure(id); failed {
+				t.Fatal("expected the failure to be destroyed with the objective")
+			}
+			all, err := s.GetAllObjectiveMetadata()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := all[id]; ok {
+				t.Fatal("expected the destroyed objective to have no metadata")
+			}
+		})
+	}
+}
+
+func TestGetAllObjectives(t *testing.T) {
+	pk := common.Hex2Bytes(`2af069c584758f9ec47c4224a8becc1983f28acfbe837bd7710b70f9fc6d5e44`)
+
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	durableStore, err := store.NewDurableStore(pk, dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer durableStore.Close()
+
+	stores := map[string]store.Store{"MemStore": store.NewMemStore(pk), "DurableStore": durableStore}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			dfo := td.Objectives.Directfund.GenericDFO()
+			vfo := td.Objectives.Virtualfund.GenericVFO()
+			wants := map[protocols.ObjectiveId]protocols.Objective{dfo.Id(): &dfo, vfo.Id(): &vfo}
+			for _, want := range wants {
+				if err := s.SetObjective(want); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := s.GetAllObjectives()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(wants) {
+				t.Fatalf("expected %d objectives, got %d", len(wants), len(got))
+			}
+			for _, obj := range got {
+				if diff := compareObjectives(obj, wants[obj.Id()]); diff != "" {
+					t.Errorf("expected no diff between set and retrieved objective, but found:\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSwapRecords(t *testing.T) {
+	pk := common.Hex2Bytes(`2af069c584758f9ec47c4224a8becc1983f28acfbe837bd7710b70f9fc6d5e44`)
+
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	durableStore, err := store.NewDurableStore(pk, dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer durableStore.Close()
+
+	channelId := types.Destination{1}
+	accepted := store.SwapRecord{
+		Swap:         payments.NewSwap(channelId, common.Address{}, common.Address{2}, big.NewInt(10), big.NewInt(20), 1, 0),
+		Status:       types.Accepted,
+		Counterparty: ta.Bob.Address(),
+		IsSender:     true,
+		RecordedAt:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	rejected := store.SwapRecord{
+		Swap:         payments.NewSwap(channelId, common.Address{2}, common.Address{}, big.NewInt(5), big.NewInt(1), 2, 0),
+		Status:       types.Rejected,
+		Counterparty: ta.Bob.Address(),
+		RecordedAt:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	abandoned := store.SwapRecord{
+		Swap:         payments.NewSwap(types.Destination{2}, common.Address{}, common.Address{2}, big.NewInt(10), big.NewInt(20), 3, 0),
+		Status:       types.Rejected,
+		Counterparty: ta.Irene.Address(),
+		End:          store.SwapEndAbandoned,
+		RecordedAt:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	stores := map[string]store.Store{"MemStore": store.NewMemStore(pk), "DurableStore": durableStore}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			for _, r := range []store.SwapRecord{accepted, rejected, abandoned} {
+				if err := s.SetSwapRecord(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Recording a swap again keeps the original record
+			again := accepted
+			again.RecordedAt = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+			if err := s.SetSwapRecord(again); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.GetAllSwapRecords()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i].Swap.Nonce < got[j].Swap.Nonce })
+
+			want := []store.SwapRecord{accepted, rejected, abandoned}
+			if diff := cmp.Diff(want, got, cmp.AllowUnexported(big.Int{})); diff != "" {
+				t.Fatalf("unexpected swap records (-want +got):\n%s", diff)
+			}
+
+			testCases := []struct {
+				name      string
+				channelId types.Destination
+				from, to  time.Time
+				want      []store.SwapRecord
+			}{
+				{"every channel", types.Destination{}, time.Time{}, time.Time{}, []store.SwapRecord{accepted, abandoned, rejected}},
+				{"one channel", channelId, time.Time{}, time.Time{}, []store.SwapRecord{accepted, rejected}},
+				{"a channel without swaps", types.Destination{3}, time.Time{}, time.Time{}, []store.SwapRecord{}},
+				{"from a time", types.Destination{}, abandoned.RecordedAt, time.Time{}, []store.SwapRecord{abandoned, rejected}},
+				{"to a time", types.Destination{}, time.Time{}, abandoned.RecordedAt, []store.SwapRecord{accepted}},
+				{"one channel within times", channelId, accepted.RecordedAt, rejected.RecordedAt, []store.SwapRecord{accepted}},
+			}
+			for _, tc := range testCases {
+				got, err := s.GetSwapRecords(tc.channelId, tc.from, tc.to)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(big.Int{})); diff != "" {
+					t.Fatalf("%s: unexpected swap records (-want +got):\n%s", tc.name, diff)
+				}
+			}
+
+			// The abandonment of a rejected swap is recorded, but an accepted swap keeps its record
+			rejectedAgain := rejected
+			rejectedAgain.End = store.SwapEndAbandoned
+			acceptedAgain := accepted
+			acceptedAgain.Status = types.Rejected
+			acceptedAgain.End = store.SwapEndAbandoned
+			for _, r := range []store.SwapRecord{rejectedAgain, acceptedAgain} {
+				if err := s.SetSwapRecord(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err = s.GetSwapRecords(channelId, time.Time{}, time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]store.SwapRecord{accepted, rejectedAgain}, got, cmp.AllowUnexported(big.Int{})); diff != "" {
+				t.Fatalf("unexpected swap records after abandonment (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
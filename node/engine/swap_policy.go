@@ -0,0 +1,357 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/internal/logging"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/payments"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/swap"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// SWAP_LIMIT_WINDOW is the period over which the daily limits of a QuotePolicy are enforced
+const SWAP_LIMIT_WINDOW = 24 * time.Hour
+
+// SwapPolicy decides whether to accept the swaps we receive, without waiting for them to be confirmed through the API
+type SwapPolicy interface {
+	// DecideSwap returns Accepted or Rejected to settle the swap proposed by the counterparty,
+	// or PendingConfirmation to leave the swap to be confirmed through the API
+	DecideSwap(s payments.Swap, counterparty common.Address) types.SwapStatus
+}
+
+// SwapQuote is the rate at which we take in TokenIn from swap senders in exchange for TokenOut.
+// Nil amounts do not restrict anything.
+type SwapQuote struct {
+	TokenIn  common.Address `toml:"tokenIn"`
+	TokenOut common.Address `toml:"tokenOut"`
+	// Rate is the mid-market price of TokenIn in units of TokenOut
+	Rate *big.Rat `toml:"rate"`
+	// SpreadBasisPoints is deducted from the rate when pricing a swap
+	SpreadBasisPoints uint64   `toml:"spreadBasisPoints"`
+	MinAmountIn       *big.Int `toml:"minAmountIn"`
+	MaxAmountIn       *big.Int `toml:"maxAmountIn"`
+}
+
+// MaxAmountOut returns the most TokenOut we pay for the given amount of TokenIn
+func (q SwapQuote) MaxAmountOut(amountIn *big.Int) *big.Rat {
+	price := new(big.Rat).Mul(q.Rate, big.NewRat(int64(10_000-q.SpreadBasisPoints), 10_000))
+	return price.Mul(price, new(big.Rat).SetInt(amountIn))
+}
+
+// SwapPolicyRules are the rules a QuotePolicy accepts swaps by
+type SwapPolicyRules struct {
+	// Quotes are the token pairs we accept swaps for. Swaps of other pairs are left to be confirmed through the API.
+	Quotes []SwapQuote `toml:"quotes"`
+	// DailyLimits cap how much of an asset we pay out to any one counterparty in the swaps we accepted over the last 24 hours,
+	// together with the swaps we received from it which may still be accepted
+	DailyLimits []AssetLimit `toml:"dailyLimits"`
+}
+
+// Validate checks that the rules are consistent
+func (r SwapPolicyRules) Validate() error {
+	for _, q := range r.Quotes {
+		if q.Rate == nil || q.Rate.Sign() <= 0 {
+			return fmt.Errorf("rate for swapping %s to %s must be positive", q.TokenIn, q.TokenOut)
+		}
+		if q.SpreadBasisPoints > 10_000 {
+			return fmt.Errorf("spread for swapping %s to %s must not exceed 10000 basis points", q.TokenIn, q.TokenOut)
+		}
+		if q.MinAmountIn != nil && q.MaxAmountIn != nil && q.MinAmountIn.Cmp(q.MaxAmountIn) > 0 {
+			return fmt.Errorf("minimum amount %s for swapping %s to %s exceeds the maximum amount %s", q.MinAmountIn, q.TokenIn, q.TokenOut, q.MaxAmountIn)
+		}
+	}
+
+	for _, limit := range r.DailyLimits {
+		if limit.Amount == nil || limit.Amount.Sign() < 0 {
+			return fmt.Errorf("limit for asset %s must be a non-negative amount", limit.Asset)
+		}
+	}
+
+	return nil
+}
+
+type swapPolicyFile struct {
+	SwapPolicy SwapPolicyRules `toml:"swapPolicy"`
+}
+
+// LoadSwapPolicyRules reads the rules from the [swapPolicy] table of the given TOML file.
+// Quotes and limits are written as arrays of inline tables, e.g.
+// quotes = [{ tokenIn = "0x...", tokenOut = "0x...", rate = "1.5", spreadBasisPoints = 30 }]
+func LoadSwapPolicyRules(filePath string) (SwapPolicyRules, error) {
+	var spf swapPolicyFile
+	if _, err := toml.DecodeFile(filePath, &spf); err != nil {
+		return SwapPolicyRules{}, fmt.Errorf("could not load swap policy from %s: %w", filePath, err)
+	}
+
+	if err := spf.SwapPolicy.Validate(); err != nil {
+		return SwapPolicyRules{}, fmt.Errorf("invalid swap policy in %s: %w", filePath, err)
+	}
+
+	return spf.SwapPolicy, nil
+}
+
+// payout is an amount of an asset we pay a counterparty in a swap we received
+type payout struct {
+	counterparty common.Address
+	asset        common.Address
+	amount       *big.Int
+	at           time.Time
+}
+
+// QuotePolicy is a swap policy that accepts the swaps which are priced no better for the sender than its quotes, within its limits.
+// Every other swap is left to be confirmed through the API, so that a QuotePolicy never rejects a swap.
+//
+// The daily limits count the swaps we accepted over the last day, and the swaps we received which may still be accepted.
+// The engine supplies its store to a QuotePolicy on construction, from which the accepted swaps are loaded once.
+// From then on the engine reports the outcome of the swaps we receive (see settleSwap).
+type QuotePolicy struct {
+	mu    sync.RWMutex
+	rules SwapPolicyRules
+	store store.Store
+
+	loaded   bool
+	accepted map[types.Destination]payout // swaps we accepted within the window
+	pending  map[types.Destination]payout // swaps we received which have not been accepted or rejected yet
+}
+
+// NewQuotePolicy returns a QuotePolicy accepting swaps by the given rules
+func NewQuotePolicy(rules SwapPolicyRules) (*QuotePolicy, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &QuotePolicy{
+		rules:    rules,
+		accepted: make(map[types.Destination]payout),
+		pending:  make(map[types.Destination]payout),
+	}, nil
+}
+
+// Rules returns the rules the policy currently accepts swaps by
+func (qp *QuotePolicy) Rules() SwapPolicyRules {
+	qp.mu.RLock()
+	defer qp.mu.RUnlock()
+	return qp.rules
+}
+
+// SetRules replaces the rules the policy accepts swaps by
+func (qp *QuotePolicy) SetRules(rules SwapPolicyRules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	qp.mu.Lock()
+	defer qp.mu.Unlock()
+	qp.rules = rules
+	return nil
+}
+
+// WatchFile reloads the rules from the [swapPolicy] table of the given TOML file whenever the file is modified, until the context is done.
+// Rules which cannot be loaded are logged and ignored, so that the policy keeps the last valid rules.
+func (qp *QuotePolicy) WatchFile(ctx context.Context, filePath string) {
+	watchFile(ctx, filePath, func() {
+		rules, err := LoadSwapPolicyRules(filePath)
+		if err != nil {
+			slog.Error("Could not reload swap policy, keeping the previous rules", "error", err)
+			return
+		}
+		_ = qp.SetRules(rules) // rules were validated on load
+		slog.Info("Reloaded swap policy", "file", filePath)
+	})
+}
+
+// bindStore supplies the store used to find the swaps accepted within the daily limits
+func (qp *QuotePolicy) bindStore(s store.Store) {
+	qp.mu.Lock()
+	defer qp.mu.Unlock()
+	qp.store = s
+}
+
+// DecideSwap accepts the swap if it satisfies the rules, and otherwise leaves it to be confirmed through the API.
+// Either way the swap counts towards the daily limits until it is settled.
+func (qp *QuotePolicy) DecideSwap(s payments.Swap, counterparty common.Address) types.SwapStatus {
+	qp.mu.Lock()
+	defer qp.mu.Unlock()
+
+	err := qp.check(s, counterparty)
+	qp.pending[s.Id] = payout{counterparty: counterparty, asset: s.Exchange.TokenOut, amount: new(big.Int).Set(s.Exchange.AmountOut), at: time.Now()}
+	if err != nil {
+		slog.Info("Swap policy did not accept swap", "swap-id", s.Id, "reason", err)
+		return types.PendingConfirmation
+	}
+
+	return types.Accepted
+}
+
+// settleSwap records the outcome of a swap we received, so that it counts towards the daily limits only if it was accepted
+func (qp *QuotePolicy) settleSwap(s payments.Swap, counterparty common.Address, status types.SwapStatus) {
+	qp.mu.Lock()
+	defer qp.mu.Unlock()
+
+	delete(qp.pending, s.Id)
+	if status == types.Accepted {
+		qp.accepted[s.Id] = payout{counterparty: counterparty, asset: s.Exchange.TokenOut, amount: new(big.Int).Set(s.Exchange.AmountOut), at: time.Now()}
+	}
+}
+
+// check returns an error describing why the swap is not accepted, if it is not. The caller must hold the lock.
+func (qp *QuotePolicy) check(s payments.Swap, counterparty common.Address) error {
+	quote, ok := qp.quote(s.Exchange.TokenIn, s.Exchange.TokenOut)
+	if !ok {
+		return fmt.Errorf("no quote for swapping %s to %s", s.Exchange.TokenIn, s.Exchange.TokenOut)
+	}
+
+	amountIn, amountOut := s.Exchange.AmountIn, s.Exchange.AmountOut
+	if quote.MinAmountIn != nil && amountIn.Cmp(quote.MinAmountIn) < 0 {
+		return fmt.Errorf("amount %s is below the minimum of %s", amountIn, quote.MinAmountIn)
+	}
+	if quote.MaxAmountIn != nil && amountIn.Cmp(quote.MaxAmountIn) > 0 {
+		return fmt.Errorf("amount %s exceeds the maximum of %s", amountIn, quote.MaxAmountIn)
+	}
+	if maxAmountOut := quote.MaxAmountOut(amountIn); new(big.Rat).SetInt(amountOut).Cmp(maxAmountOut) > 0 {
+		return fmt.Errorf("amount out %s exceeds the quoted %s", amountOut, maxAmountOut.FloatString(0))
+	}
+
+	return qp.checkDailyLimit(s.Id, counterparty, s.Exchange.TokenOut, amountOut)
+}
+
+// quote returns the quote for the token pair, if there is one. The caller must hold the lock.
+func (qp *QuotePolicy) quote(tokenIn, tokenOut common.Address) (SwapQuote, bool) {
+	for _, q := range qp.rules.Quotes {
+		if q.TokenIn == tokenIn && q.TokenOut == tokenOut {
+			return q, true
+		}
+	}
+
+	return SwapQuote{}, false
+}
+
+// checkDailyLimit checks that paying out the amount of the asset in the swap would not take what we pay the counterparty
+// in the swaps accepted over the last day, and in the other swaps which may still be accepted, beyond the limit.
+// The caller must hold the lock.
+func (qp *QuotePolicy) checkDailyLimit(swapId types.Destination, counterparty, asset common.Address, amount *big.Int) error {
+	var limit *big.Int
+	for _, l := range qp.rules.DailyLimits {
+		if l.Asset == asset {
+			limit = l.Amount
+		}
+	}
+	if limit == nil {
+		return nil
+	}
+	if err := qp.loadAccepted(); err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-SWAP_LIMIT_WINDOW)
+	for id, p := range qp.accepted {
+		if p.at.Before(since) {
+			delete(qp.accepted, id)
+		}
+	}
+
+	paid := new(big.Int).Set(amount)
+	for _, payouts := range []map[types.Destination]payout{qp.accepted, qp.pending} {
+		for id, p := range payouts {
+			if id != swapId && p.counterparty == counterparty && p.asset == asset {
+				paid.Add(paid, p.amount)
+			}
+		}
+	}
+
+	if paid.Cmp(limit) > 0 {
+		return fmt.Errorf("paying out %s of asset %s to counterparty %s over the last day exceeds the limit of %s", paid, asset, counterparty, limit)
+	}
+
+	return nil
+}
+
+// loadAccepted loads the swaps we accepted within the window from the store, the first time the daily limits are checked.
+// The caller must hold the lock.
+func (qp *QuotePolicy) loadAccepted() error {
+	if qp.loaded {
+		return nil
+	}
+	if qp.store == nil {
+		return errors.New("cannot work out the swaps accepted within the daily limits without a store")
+	}
+
+	records, err := qp.store.GetSwapRecords(types.Destination{}, time.Now().Add(-SWAP_LIMIT_WINDOW), time.Time{})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		if r.Status != types.Accepted || r.IsSender {
+			continue
+		}
+		if _, ok := qp.accepted[r.Swap.Id]; !ok {
+			qp.accepted[r.Swap.Id] = payout{counterparty: r.Counterparty, asset: r.Swap.Exchange.TokenOut, amount: r.Swap.Exchange.AmountOut, at: r.RecordedAt}
+		}
+	}
+
+	qp.loaded = true
+	return nil
+}
+
+// applySwapPolicy settles the swap of the objective by the decision of the swap policy, if we received the swap and have not confirmed it yet
+func (e *Engine) applySwapPolicy(so *swap.Objective) error {
+	if e.opts.SwapPolicy == nil || so.GetStatus() != protocols.Approved || so.SwapStatus != types.PendingConfirmation {
+		return nil
+	}
+
+	myIndex, err := swap.MyIndexInAllocations(so.C)
+	if err != nil {
+		return err
+	}
+	if so.SwapSenderIndex == myIndex {
+		return nil
+	}
+
+	decision := e.opts.SwapPolicy.DecideSwap(so.Swap, so.CounterParty())
+	if decision == types.PendingConfirmation {
+		return nil
+	}
+
+	e.logger.Info("Swap policy settled swap", logging.WithObjectiveIdAttribute(so.Id()), "accepted", decision == types.Accepted)
+	so.SwapStatus = decision
+	return nil
+}
+
+// settleSwapPolicy reports the outcome of the swaps we received in a finished objective to the swap policy
+func (e *Engine) settleSwapPolicy(objective protocols.Objective) {
+	qp, ok := e.opts.SwapPolicy.(*QuotePolicy)
+	if !ok {
+		return
+	}
+
+	var received []*swap.Objective
+	switch o := objective.(type) {
+	case *swap.Objective:
+		received = append(received, o)
+	case *multiswap.Objective:
+		received = o.MyLegs()
+	}
+
+	for _, so := range received {
+		if so.IsSender() {
+			continue
+		}
+		status := so.SwapStatus
+		if so.GetStatus() != protocols.Completed {
+			status = types.Rejected
+		}
+		qp.settleSwap(so.Swap, so.CounterParty(), status)
+	}
+}
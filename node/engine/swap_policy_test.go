@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/payments"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestLoadSwapPolicyRules(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.toml")
+	config := `
+msgport = 3005
+
+[swapPolicy]
+quotes = [{ tokenIn = "0x0000000000000000000000000000000000000000", tokenOut = "0x1111111111111111111111111111111111111111", rate = "2.5", spreadBasisPoints = 100, minAmountIn = "10", maxAmountIn = "1000" }]
+dailyLimits = [{ asset = "0x1111111111111111111111111111111111111111", amount = "5000" }]
+`
+	if err := os.WriteFile(filePath, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadSwapPolicyRules(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules.Quotes) != 1 || rules.Quotes[0].TokenOut != common.HexToAddress("0x1111111111111111111111111111111111111111") {
+		t.Fatalf("unexpected quotes %v", rules.Quotes)
+	}
+	// 100 in at 2.5, less 1%
+	if got := rules.Quotes[0].MaxAmountOut(big.NewInt(100)); got.Cmp(big.NewRat(2475, 10)) != 0 {
+		t.Fatalf("expected a maximum amount out of 247.5, got %s", got.FloatString(1))
+	}
+	if len(rules.DailyLimits) != 1 || rules.DailyLimits[0].Amount.Cmp(big.NewInt(5000)) != 0 {
+		t.Fatalf("unexpected daily limits %v", rules.DailyLimits)
+	}
+
+	invalid := "[swapPolicy]\nquotes = [{ tokenIn = \"0x0000000000000000000000000000000000000000\", tokenOut = \"0x1111111111111111111111111111111111111111\", rate = \"0\" }]\n"
+	if err := os.WriteFile(filePath, []byte(invalid), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSwapPolicyRules(filePath); err == nil {
+		t.Fatal("expected a zero rate to be rejected")
+	}
+}
+
+func TestQuotePolicy(t *testing.T) {
+	eth, token := common.Address{}, common.Address{1}
+	channelId := types.Destination{1}
+	bob := testactors.Bob.Address()
+
+	quote := SwapQuote{TokenIn: eth, TokenOut: token, Rate: big.NewRat(2, 1), SpreadBasisPoints: 500, MinAmountIn: big.NewInt(10), MaxAmountIn: big.NewInt(1000)}
+	limit := []AssetLimit{{Asset: token, Amount: big.NewInt(300)}}
+	swapOf := func(amountIn, amountOut int64) payments.Swap {
+		return payments.NewSwap(channelId, eth, token, big.NewInt(amountIn), big.NewInt(amountOut), 1, 0)
+	}
+
+	// Bob was paid 200 of the token in an earlier swap today, and 1000 in a swap two days ago
+	ms := store.NewMemStore(testactors.Alice.PrivateKey)
+	for i, r := range []store.SwapRecord{
+		{Swap: payments.NewSwap(channelId, eth, token, big.NewInt(100), big.NewInt(200), 2, 0), Status: types.Accepted, Counterparty: bob, RecordedAt: time.Now().Add(-time.Hour)},
+		{Swap: payments.NewSwap(channelId, eth, token, big.NewInt(500), big.NewInt(1000), 3, 0), Status: types.Accepted, Counterparty: bob, RecordedAt: time.Now().Add(-48 * time.Hour)},
+		{Swap: payments.NewSwap(channelId, eth, token, big.NewInt(500), big.NewInt(1000), 4, 0), Status: types.Rejected, Counterparty: bob, RecordedAt: time.Now()},
+	} {
+		if err := ms.SetSwapRecord(r); err != nil {
+			t.Fatalf("could not set swap record %d: %v", i, err)
+		}
+	}
+
+	testCases := []struct {
+		name  string
+		rules SwapPolicyRules
+		swap  payments.Swap
+		want  types.SwapStatus
+	}{
+		{"no quotes", SwapPolicyRules{}, swapOf(100, 190), types.PendingConfirmation},
+		{"no quote for the pair", SwapPolicyRules{Quotes: []SwapQuote{quote}}, payments.NewSwap(channelId, token, eth, big.NewInt(100), big.NewInt(1), 1, 0), types.PendingConfirmation},
+		{"at the quoted rate", SwapPolicyRules{Quotes: []SwapQuote{quote}}, swapOf(100, 190), types.Accepted},
+		{"better than the quoted rate", SwapPolicyRules{Quotes: []SwapQuote{quote}}, swapOf(100, 150), types.Accepted},
+		{"worse than the quoted rate", SwapPolicyRules{Quotes: []SwapQuote{quote}}, swapOf(100, 191), types.PendingConfirmation},
+		{"below the minimum amount", SwapPolicyRules{Quotes: []SwapQuote{quote}}, swapOf(9, 1), types.PendingConfirmation},
+		{"above the maximum amount", SwapPolicyRules{Quotes: []SwapQuote{quote}}, swapOf(1001, 1), types.PendingConfirmation},
+		{"within the daily limit", SwapPolicyRules{Quotes: []SwapQuote{quote}, DailyLimits: limit}, swapOf(100, 100), types.Accepted},
+		{"beyond the daily limit", SwapPolicyRules{Quotes: []SwapQuote{quote}, DailyLimits: limit}, swapOf(100, 101), types.PendingConfirmation},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qp, err := NewQuotePolicy(tc.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			qp.bindStore(ms)
+
+			if got := qp.DecideSwap(tc.swap, bob); got != tc.want {
+				t.Fatalf("expected decision %d, got %d", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("swaps which may still be accepted count towards the daily limit", func(t *testing.T) {
+		qp, err := NewQuotePolicy(SwapPolicyRules{Quotes: []SwapQuote{quote}, DailyLimits: limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		qp.bindStore(ms)
+
+		// 200 were paid earlier today, so only 100 are left
+		first := payments.NewSwap(channelId, eth, token, big.NewInt(100), big.NewInt(60), 5, 0)
+		second := payments.NewSwap(channelId, eth, token, big.NewInt(100), big.NewInt(60), 6, 0)
+		if got := qp.DecideSwap(first, bob); got != types.Accepted {
+			t.Fatalf("expected the first swap to be accepted, got %d", got)
+		}
+		if got := qp.DecideSwap(second, bob); got != types.PendingConfirmation {
+			t.Fatalf("expected the second swap to be left for confirmation while the first is pending, got %d", got)
+		}
+
+		// Once the first swap is rejected, the second swap fits within the limit
+		qp.settleSwap(first, bob, types.Rejected)
+		if got := qp.DecideSwap(second, bob); got != types.Accepted {
+			t.Fatalf("expected the second swap to be accepted, got %d", got)
+		}
+
+		// Once it is accepted, it counts towards the limit like the swaps accepted earlier
+		qp.settleSwap(second, bob, types.Accepted)
+		if got := qp.DecideSwap(first, bob); got != types.PendingConfirmation {
+			t.Fatalf("expected the first swap to be left for confirmation after the second was accepted, got %d", got)
+		}
+		if got := qp.DecideSwap(swapOf(100, 40), testactors.Irene.Address()); got != types.Accepted {
+			t.Fatalf("expected a swap with another counterparty to be accepted, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,348 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/internal/logging"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/node/query"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/ledgerwithdraw"
+	"github.com/statechannels/go-nitro/protocols/rebalance"
+	"github.com/statechannels/go-nitro/protocols/swap"
+	"github.com/statechannels/go-nitro/protocols/swapfund"
+	"github.com/statechannels/go-nitro/protocols/virtualfund"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// OBJECTIVE_DEADLINE_CHECK_INTERVAL is how often the engine looks for objectives which have exceeded their deadline, and for swaps which have expired
+const OBJECTIVE_DEADLINE_CHECK_INTERVAL = 10 * time.Second
+
+// SWAP_EXPIRY_GRACE_PERIOD is how long the sender of a swap waits after its expiry before cancelling it,
+// so that an acceptance sent just before the expiry is likely to arrive first
+const SWAP_EXPIRY_GRACE_PERIOD = 30 * time.Second
+
+// LEDGER_WITHDRAW_GRACE_PERIOD is how long the counterparty of a ledger withdrawal waits for the withdrawer to submit the withdrawAll transaction,
+// after which it submits the transaction itself, so that the successor ledger channel is funded
+const LEDGER_WITHDRAW_GRACE_PERIOD = 2 * time.Minute
+
+// DefaultObjectiveDeadlines are the deadlines of the objective types which are not configured in EngineOpts.
+//
+// Deadlines are opt-in: no objective is abandoned unless a deadline is configured for its type. Deadlines are intended for objectives
+// which lock funds in ledger channels, or wait on several counterparties to swap, since they depend on counterparties which may never respond.
+// Objectives which interact with the chain or defund a channel should run to completion.
+var DefaultObjectiveDeadlines = map[query.ObjectiveType]time.Duration{}
+
+// EngineOpts configures the optional behaviour of the engine
+type EngineOpts struct {
+	// ObjectiveDeadlines overrides DefaultObjectiveDeadlines for the given objective types.
+	// A zero duration disables the deadline of that type. Objectives whose guarantees we have signed are never abandoned.
+	ObjectiveDeadlines map[query.ObjectiveType]time.Duration
+	// CapacityAnnouncementInterval is how often capacity is announced to peers to find routes for virtual channels.
+	// It defaults to DEFAULT_CAPACITY_ANNOUNCEMENT_INTERVAL.
+	CapacityAnnouncementInterval time.Duration
+	// SwapPolicy, if set, decides whether to accept the swaps we receive before they are left to be confirmed through the API
+	SwapPolicy SwapPolicy
+	// AutoCounterChallenge disables, for the channel types set to false, the automatic response to challenges registered by a counterparty
+	// with a stale state. Such challenges are countered automatically in ledger channels unless configured otherwise.
+	// Challenges in virtual and swap channels are never countered automatically.
+	AutoCounterChallenge map[types.ChannelType]bool
+}
+
+// objectiveDeadline returns how long an objective of the given type may take to complete, or 0 if it may take forever
+func (opts EngineOpts) objectiveDeadline(objectiveType query.ObjectiveType) time.Duration {
+	if deadline, ok := opts.ObjectiveDeadlines[objectiveType]; ok {
+		return deadline
+	}
+
+	return DefaultObjectiveDeadlines[objectiveType]
+}
+
+// shortestObjectiveDeadline returns the shortest enabled deadline of any objective type, or 0 if no deadline is enabled
+func (opts EngineOpts) shortestObjectiveDeadline() time.Duration {
+	shortest := time.Duration(0)
+	for _, objectiveType := range objectiveTypes {
+		deadline := opts.objectiveDeadline(objectiveType)
+		if deadline > 0 && (shortest == 0 || deadline < shortest) {
+			shortest = deadline
+		}
+	}
+
+	return shortest
+}
+
+var objectiveTypes = []query.ObjectiveType{
+	query.DirectFund, query.DirectDefund,
+	query.VirtualFund, query.VirtualDefund,
+	query.SwapFund, query.SwapDefund, query.Swap,
+	query.BridgedFund, query.BridgedDefund, query.MirrorBridgedDefund,
+	query.LedgerTopUp, query.LedgerWithdraw, query.Rebalance, query.MultiSwap,
+}
+
+// ParseObjectiveDeadlines parses a comma-delimited list of objective deadlines, such as "virtualfund=5m,swapfund=0".
+// Durations are formatted as accepted by time.ParseDuration.
+func ParseObjectiveDeadlines(s string) (map[query.ObjectiveType]time.Duration, error) {
+	deadlines := make(map[query.ObjectiveType]time.Duration)
+	if strings.TrimSpace(s) == "" {
+		return deadlines, nil
+	}
+
+	for _, entry := range strings.Split(s, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid objective deadline %q: expected <objective type>=<duration>", entry)
+		}
+
+		objectiveType := query.ObjectiveType(strings.TrimSpace(name))
+		known := false
+		for _, t := range objectiveTypes {
+			known = known || t == objectiveType
+		}
+		if !known {
+			return nil, fmt.Errorf("invalid objective deadline %q: unknown objective type %s", entry, objectiveType)
+		}
+
+		deadline, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid objective deadline %q: %w", entry, err)
+		}
+		if deadline < 0 {
+			return nil, fmt.Errorf("invalid objective deadline %q: duration must not be negative", entry)
+		}
+
+		deadlines[objectiveType] = deadline
+	}
+
+	return deadlines, nil
+}
+
+// abandonExpiredObjectives abandons the objectives which have been in progress for longer than the deadline of their type,
+// unless they can no longer be safely abandoned.
+func (e *Engine) abandonExpiredObjectives() (EngineEvent, error) {
+	shortestDeadline := e.opts.shortestObjectiveDeadline()
+	if shortestDeadline == 0 {
+		return EngineEvent{}, nil
+	}
+
+	allMetadata, err := e.store.GetAllObjectiveMetadata()
+	if err != nil {
+		return EngineEvent{}, err
+	}
+
+	now := time.Now()
+	res := EngineEvent{}
+	for id, metadata := range allMetadata {
+		if metadata.Failed() || metadata.WaitingFor == protocols.WaitingForNothing || now.Sub(metadata.CreatedAt) < shortestDeadline {
+			continue
+		}
+
+		objective, err := e.store.GetObjectiveById(id)
+		if errors.Is(err, store.ErrNoSuchObjective) {
+			continue
+		}
+		if err != nil {
+			return res, err
+		}
+		if status := objective.GetStatus(); status != protocols.Unapproved && status != protocols.Approved {
+			continue
+		}
+		if ro, ok := objective.(*rebalance.Objective); ok && ro.Committed() {
+			// the settlement may be supported by the other participants, so the rebalance can no longer be abandoned
+			continue
+		}
+		if lwo, ok := objective.(*ledgerwithdraw.Objective); ok && lwo.C.FinalSignedByMe() {
+			// the counterparty may finalize the ledger channel, so the withdrawal can no longer be abandoned
+			continue
+		}
+		if hasSignedGuarantees(objective) {
+			// the guarantees can only be removed by defunding the channel, and the other participants may be relying on them
+			continue
+		}
+
+		objectiveType, err := query.GetObjectiveType(objective)
+		if err != nil {
+			return res, err
+		}
+		deadline := e.opts.objectiveDeadline(objectiveType)
+		if deadline == 0 || now.Sub(metadata.CreatedAt) < deadline {
+			continue
+		}
+
+		event, err := e.abandonObjective(objective, fmt.Sprintf("timed out: not completed within %s", deadline))
+		res.Merge(event)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// rejectExpiredSwaps rejects the pending swaps which have expired, which frees their swap channels for new swaps.
+// The receiver of a swap rejects it as soon as it expires, while the sender cancels it after SWAP_EXPIRY_GRACE_PERIOD.
+func (e *Engine) rejectExpiredSwaps() (EngineEvent, error) {
+	allMetadata, err := e.store.GetAllObjectiveMetadata()
+	if err != nil {
+		return EngineEvent{}, err
+	}
+
+	now := time.Now()
+	res := EngineEvent{}
+	for id, metadata := range allMetadata {
+		if !swap.IsSwapObjective(id) || metadata.Failed() || metadata.WaitingFor == swap.WaitingForNothing {
+			continue
+		}
+
+		objective, err := e.store.GetObjectiveById(id)
+		if errors.Is(err, store.ErrNoSuchObjective) {
+			continue
+		}
+		if err != nil {
+			return res, err
+		}
+		so, ok := objective.(*swap.Objective)
+		if !ok || so.GetStatus() != protocols.Approved || so.SwapStatus != types.PendingConfirmation {
+			continue
+		}
+
+		var event EngineEvent
+		if so.IsSender() {
+			if !so.Swap.IsExpired(now.Add(-SWAP_EXPIRY_GRACE_PERIOD)) {
+				continue
+			}
+			e.logger.Info("Cancelling expired swap", logging.WithObjectiveIdAttribute(id))
+			event, err = e.cancelSwap(so)
+		} else {
+			if !so.Swap.IsExpired(now) {
+				continue
+			}
+			// The swap is rejected when it is cranked
+			e.logger.Info("Rejecting expired swap", logging.WithObjectiveIdAttribute(id))
+			event, err = e.attemptProgress(so)
+		}
+		res.Merge(event)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// submitOverdueWithdrawals submits the withdrawAll transactions of the ledger withdrawals which the withdrawer has not submitted
+// within LEDGER_WITHDRAW_GRACE_PERIOD of the final state being supported.
+func (e *Engine) submitOverdueWithdrawals() (EngineEvent, error) {
+	allMetadata, err := e.store.GetAllObjectiveMetadata()
+	if err != nil {
+		return EngineEvent{}, err
+	}
+
+	now := time.Now()
+	res := EngineEvent{}
+	for id, metadata := range allMetadata {
+		if !ledgerwithdraw.IsLedgerWithdrawObjective(id) || metadata.Failed() || metadata.WaitingFor != ledgerwithdraw.WaitingForWithdraw {
+			continue
+		}
+		if now.Sub(metadata.UpdatedAt) < LEDGER_WITHDRAW_GRACE_PERIOD {
+			continue
+		}
+
+		objective, err := e.store.GetObjectiveById(id)
+		if errors.Is(err, store.ErrNoSuchObjective) {
+			continue
+		}
+		if err != nil {
+			return res, err
+		}
+		lwo, ok := objective.(*ledgerwithdraw.Objective)
+		if !ok || !lwo.AwaitingWithdrawal() {
+			continue
+		}
+
+		e.logger.Info("Submitting overdue ledger withdrawal", logging.WithObjectiveIdAttribute(id))
+		event, err := e.attemptProgress(lwo.MarkWithdrawalOverdue())
+		res.Merge(event)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// abandonObjective rejects the objective, notifying the other participants, retracts the guarantees it proposed where possible,
+// and marks it as failed for the given reason. An abandoned rebalance refunds its guarantees instead.
+func (e *Engine) abandonObjective(objective protocols.Objective, reason string) (EngineEvent, error) {
+	e.logger.Warn("Abandoning objective", logging.WithObjectiveIdAttribute(objective.Id()), "reason", reason)
+
+	rejected, sideEffects := objective.Reject()
+	retractGuarantees(rejected)
+	err := e.store.SetObjective(rejected)
+	if err != nil {
+		return EngineEvent{}, err
+	}
+
+	res := e.failObjective(rejected.Id(), reason)
+
+	if ro, ok := rejected.(*rebalance.Objective); ok {
+		err = e.unlockRebalance(ro)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return res, e.executeSideEffects(sideEffects)
+}
+
+// hasSignedGuarantees returns whether we have signed a ledger state guaranteeing the objective's channel in any of its ledger channels
+// (see ConsensusChannel.HasSignedGuaranteeFor).
+func hasSignedGuarantees(objective protocols.Objective) bool {
+	target, ledgers := fundingLedgers(objective)
+	for _, ledger := range ledgers {
+		if ledger.HasSignedGuaranteeFor(target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retractGuarantees retracts the proposals to fund the objective's channel from its ledger channels, if they are still at the end of the proposal queue
+// and we never signed them (see ConsensusChannel.RetractProposals). Guarantees which we proposed or countersigned can only be removed by defunding the channel.
+//
+// The ledger channels are mutated in place, and persisted by storing the objective.
+func retractGuarantees(objective protocols.Objective) {
+	target, ledgers := fundingLedgers(objective)
+	for _, ledger := range ledgers {
+		ledger.RetractProposals(target)
+	}
+}
+
+// fundingLedgers returns the channel funded by a virtualfund or swapfund objective, and the ledger channels which guarantee it
+func fundingLedgers(objective protocols.Objective) (types.Destination, []*consensus_channel.ConsensusChannel) {
+	var target types.Destination
+	ledgers := []*consensus_channel.ConsensusChannel{}
+
+	switch o := objective.(type) {
+	case *virtualfund.Objective:
+		target = o.V.Id
+		for _, connection := range []*virtualfund.Connection{o.ToMyLeft, o.ToMyRight} {
+			if connection != nil {
+				ledgers = append(ledgers, connection.Channel)
+			}
+		}
+	case *swapfund.Objective:
+		target = o.S.Id
+		for _, connection := range []*swapfund.Connection{o.ToMyLeft, o.ToMyRight} {
+			if connection != nil {
+				ledgers = append(ledgers, connection.Channel)
+			}
+		}
+	}
+
+	return target, ledgers
+}
@@ -2,11 +2,13 @@
 package node // import "github.com/statechannels/go-nitro/node"
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math/big"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -26,7 +28,11 @@ import (
 	"github.com/statechannels/go-nitro/protocols/bridgedfund"
 	"github.com/statechannels/go-nitro/protocols/directdefund"
 	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/ledgertopup"
+	"github.com/statechannels/go-nitro/protocols/ledgerwithdraw"
 	"github.com/statechannels/go-nitro/protocols/mirrorbridgeddefund"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/rebalance"
 	"github.com/statechannels/go-nitro/protocols/swap"
 	"github.com/statechannels/go-nitro/protocols/swapdefund"
 	"github.com/statechannels/go-nitro/protocols/swapfund"
@@ -44,7 +50,8 @@ type Node struct {
 	completedObjectivesNotifier *notifier.CompletedObjetivesNotifier
 
 	completedObjectives *safesync.Map[chan struct{}]
-	failedObjectives    chan protocols.ObjectiveId
+	failedObjectives    *notifier.EventBus[query.FailedObjectiveInfo]
+	allFailedObjectives func() <-chan query.FailedObjectiveInfo
 	receivedVouchers    chan payments.Voucher
 	chainId             *big.Int
 	store               store.Store
@@ -52,29 +59,35 @@ type Node struct {
 }
 
 // New is the constructor for a Node. It accepts a messaging service, a chain service, and a store as injected dependencies.
-func New(messageService messageservice.MessageService, chainservice chainservice.ChainService, store store.Store, policymaker engine.PolicyMaker) Node {
+func New(messageService messageservice.MessageService, chainservice chainservice.ChainService, store store.Store, policymaker engine.PolicyMaker, engineOpts engine.EngineOpts) (Node, error) {
 	n := Node{}
 	n.Address = store.GetAddress()
 
 	chainId, err := chainservice.GetChainId()
 	if err != nil {
-		panic(err)
+		return Node{}, fmt.Errorf("could not get chain id from chain service: %w", err)
 	}
 	n.chainId = chainId
 	n.store = store
 	n.vm = payments.NewVoucherManager(*store.GetAddress(), store)
 
-	n.engine = engine.New(n.vm, messageService, chainservice, store, policymaker, n.handleEngineEvent)
+	n.engine, err = engine.New(n.vm, messageService, chainservice, store, policymaker, engineOpts, n.handleEngineEvent)
+	if err != nil {
+		return Node{}, err
+	}
 	n.completedObjectives = &safesync.Map[chan struct{}]{}
 
-	n.failedObjectives = make(chan protocols.ObjectiveId, 100)
+	n.failedObjectives = notifier.NewEventBus[query.FailedObjectiveInfo]("failed-objectives")
+	n.allFailedObjectives = sync.OnceValue(func() <-chan query.FailedObjectiveInfo {
+		return n.SubscribeFailedObjectives(context.Background(), notifier.SubscriptionOpts{BufferSize: 100})
+	})
 	// Using a larger buffer since payments can be sent frequently.
 	n.receivedVouchers = make(chan payments.Voucher, 1000)
 
 	n.channelNotifier = notifier.NewChannelNotifier(store, n.vm)
 	n.completedObjectivesNotifier = notifier.NewCompletedObjectivesNotifier()
 
-	return n
+	return n, nil
 }
 
 // handleEngineEvents dispatches events to the necessary node chan.
@@ -89,7 +102,7 @@ func (n *Node) handleEngineEvent(update engine.EngineEvent) {
 	}
 
 	for _, erred := range update.FailedObjectives {
-		n.failedObjectives <- erred
+		n.failedObjectives.Publish(erred)
 	}
 
 	for _, payment := range update.ReceivedVouchers {
@@ -117,6 +130,11 @@ func (n *Node) handleEngineEvent(update engine.EngineEvent) {
 		err := n.channelNotifier.NotifySwapUpdated(updated)
 		n.handleError(err)
 	}
+
+	for _, updated := range update.SwapChannelUpdates {
+		err := n.channelNotifier.NotifySwapChannelUpdated(updated)
+		n.handleError(err)
+	}
 }
 
 // Begin API
@@ -144,21 +162,54 @@ func (n *Node) Version() string {
 	return version
 }
 
-// LedgerUpdates returns a chan that receives ledger channel info whenever that ledger channel is updated. Not suitable for multiple subscribers.
+// LedgerUpdates returns a chan that receives ledger channel info whenever that ledger channel is updated.
+// Every call returns a new chan, which receives the updates made after the call until the node is closed: use SubscribeLedgerUpdates to unsubscribe earlier.
 func (n *Node) LedgerUpdates() <-chan query.LedgerChannelInfo {
 	return n.channelNotifier.RegisterForAllLedgerUpdates()
 }
 
-// PaymentUpdates returns a chan that receives payment channel info whenever that payment channel is updated. Not suitable fo multiple subscribers.
+// PaymentUpdates returns a chan that receives payment channel info whenever that payment channel is updated.
+// Every call returns a new chan, which receives the updates made after the call until the node is closed: use SubscribePaymentUpdates to unsubscribe earlier.
 func (n *Node) PaymentUpdates() <-chan query.PaymentChannelInfo {
 	return n.channelNotifier.RegisterForAllPaymentUpdates()
 }
 
-// SwapUpdates returns a chan that receives swap info whenever a swap objective is updated. Not suitable fo multiple subscribers.
+// SwapUpdates returns a chan that receives swap info whenever a swap objective is updated.
+// Every call returns a new chan, which receives the updates made after the call until the node is closed: use SubscribeSwapUpdates to unsubscribe earlier.
 func (n *Node) SwapUpdates() <-chan query.SwapInfo {
 	return n.channelNotifier.RegisterForAllSwapUpdates()
 }
 
+// SwapChannelUpdates returns a chan that receives swap channel info whenever a swap channel is updated.
+// Every call returns a new chan, which receives the updates made after the call until the node is closed: use SubscribeSwapChannelUpdates to unsubscribe earlier.
+func (n *Node) SwapChannelUpdates() <-chan query.SwapChannelInfo {
+	return n.channelNotifier.RegisterForAllSwapChannelUpdates()
+}
+
+// SubscribeLedgerUpdates returns a new chan that receives ledger channel info whenever a ledger channel is updated.
+// The chan is closed once ctx is done.
+func (n *Node) SubscribeLedgerUpdates(ctx context.Context, opts notifier.SubscriptionOpts) <-chan query.LedgerChannelInfo {
+	return n.channelNotifier.SubscribeLedgerUpdates(ctx, opts)
+}
+
+// SubscribePaymentUpdates returns a new chan that receives payment channel info whenever a payment channel is updated.
+// The chan is closed once ctx is done.
+func (n *Node) SubscribePaymentUpdates(ctx context.Context, opts notifier.SubscriptionOpts) <-chan query.PaymentChannelInfo {
+	return n.channelNotifier.SubscribePaymentUpdates(ctx, opts)
+}
+
+// SubscribeSwapUpdates returns a new chan that receives swap info whenever a swap objective is updated.
+// The chan is closed once ctx is done.
+func (n *Node) SubscribeSwapUpdates(ctx context.Context, opts notifier.SubscriptionOpts) <-chan query.SwapInfo {
+	return n.channelNotifier.SubscribeSwapUpdates(ctx, opts)
+}
+
+// SubscribeSwapChannelUpdates returns a new chan that receives swap channel info whenever a swap channel is updated.
+// The chan is closed once ctx is done.
+func (n *Node) SubscribeSwapChannelUpdates(ctx context.Context, opts notifier.SubscriptionOpts) <-chan query.SwapChannelInfo {
+	return n.channelNotifier.SubscribeSwapChannelUpdates(ctx, opts)
+}
+
 // CompletedObjectives returns a chan that receives a objective ID whenever that objective is completed
 func (n *Node) CompletedObjectives() <-chan protocols.ObjectiveId {
 	return n.completedObjectivesNotifier.RegisterForAllCompletedObjectives()
@@ -180,9 +231,21 @@ func (n *Node) PaymentChannelUpdatedChan(ledgerId types.Destination) <-chan quer
 	return n.channelNotifier.RegisterForPaymentChannelUpdates(ledgerId)
 }
 
-// FailedObjectives returns a chan that receives an objective id whenever that objective has failed
-func (n *Node) FailedObjectives() <-chan protocols.ObjectiveId {
-	return n.failedObjectives
+// SwapChannelUpdatedChan returns a chan that receives a swap channel info whenever the swap channel with given id is updated
+func (n *Node) SwapChannelUpdatedChan(swapChannelId types.Destination) <-chan query.SwapChannelInfo {
+	return n.channelNotifier.RegisterForSwapChannelUpdates(swapChannelId)
+}
+
+// FailedObjectives returns a chan that receives the id of an objective and the reason it failed whenever an objective has failed.
+// The same chan is returned on every call, so it is not suitable for multiple subscribers: use SubscribeFailedObjectives instead.
+func (n *Node) FailedObjectives() <-chan query.FailedObjectiveInfo {
+	return n.allFailedObjectives()
+}
+
+// SubscribeFailedObjectives returns a new chan that receives the id of an objective and the reason it failed whenever an objective has failed.
+// The chan is closed once ctx is done.
+func (n *Node) SubscribeFailedObjectives(ctx context.Context, opts notifier.SubscriptionOpts) <-chan query.FailedObjectiveInfo {
+	return n.failedObjectives.Subscribe(ctx, opts)
 }
 
 // ReceivedVouchers returns a chan that receives a voucher every time we receive a payment voucher
@@ -240,15 +303,22 @@ func (n *Node) CreateSwapChannel(Intermediaries []types.Address, CounterParty ty
 	}
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
-
-	objectiveRequest.WaitForObjectiveToStart()
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return swapfund.ObjectiveResponse{}, err
+	}
 	return objectiveRequest.Response(*n.Address), nil
 }
 
 // CreatePaymentChannel creates a virtual channel with the counterParty using ledger channels
 // with the supplied intermediaries.
 func (n *Node) CreatePaymentChannel(Intermediaries []types.Address, CounterParty types.Address, ChallengeDuration uint32, Outcome outcome.Exit) (virtualfund.ObjectiveResponse, error) {
+	return n.CreatePaymentChannelWithFees(Intermediaries, CounterParty, ChallengeDuration, Outcome, nil)
+}
+
+// CreatePaymentChannelWithFees creates a virtual channel with the counterParty using ledger channels
+// with the supplied intermediaries, paying them the supplied fees from our ledger channel as the channel is funded
+// (see channel.IntermediaryFee).
+func (n *Node) CreatePaymentChannelWithFees(Intermediaries []types.Address, CounterParty types.Address, ChallengeDuration uint32, Outcome outcome.Exit, IntermediaryFees []channel.IntermediaryFee) (virtualfund.ObjectiveResponse, error) {
 	objectiveRequest := virtualfund.NewObjectiveRequest(
 		Intermediaries,
 		CounterParty,
@@ -257,15 +327,53 @@ func (n *Node) CreatePaymentChannel(Intermediaries []types.Address, CounterParty
 		rand.Uint64(),
 		n.engine.GetVirtualPaymentAppAddress(),
 	)
+	objectiveRequest.IntermediaryFees = IntermediaryFees
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
-
-	objectiveRequest.WaitForObjectiveToStart()
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return virtualfund.ObjectiveResponse{}, err
+	}
 	return objectiveRequest.Response(*n.Address), nil
 }
 
+// CreatePaymentChannelAuto creates a virtual channel with the counterParty, in which we deposit the amount of the asset.
+// The intermediaries are chosen by finding the cheapest route with enough capacity, and are paid the fees they announced.
+func (n *Node) CreatePaymentChannelAuto(CounterParty types.Address, Asset common.Address, Amount *big.Int, ChallengeDuration uint32) (virtualfund.ObjectiveResponse, error) {
+	route, err := n.engine.FindRoute(CounterParty, Asset, Amount)
+	if err != nil {
+		return virtualfund.ObjectiveResponse{}, fmt.Errorf("could not find a route to %s: %w", CounterParty, err)
+	}
+
+	fees := []channel.IntermediaryFee{}
+	for _, intermediary := range route.Intermediaries {
+		if fee, ok := route.Fees[intermediary]; ok {
+			fees = append(fees, channel.IntermediaryFee{Intermediary: intermediary, Amount: fee})
+		}
+	}
+
+	Outcome := outcome.Exit{outcome.SingleAssetExit{
+		Asset: Asset,
+		Allocations: outcome.Allocations{
+			{Destination: types.AddressToDestination(*n.Address), Amount: Amount},
+			{Destination: types.AddressToDestination(CounterParty), Amount: big.NewInt(0)},
+		},
+	}}
+
+	return n.CreatePaymentChannelWithFees(route.Intermediaries, CounterParty, ChallengeDuration, Outcome, fees)
+}
+
+// SwapAssets proposes a swap to the counterparty of the swap channel, which expires after swap.DEFAULT_SWAP_EXPIRY.
 func (n *Node) SwapAssets(channelId types.Destination, tokenIn common.Address, tokenOut common.Address, amountIn *big.Int, amountOut *big.Int) (swap.ObjectiveResponse, error) {
+	return n.SwapAssetsWithExpiry(channelId, tokenIn, tokenOut, amountIn, amountOut, time.Now().Add(swap.DEFAULT_SWAP_EXPIRY))
+}
+
+// SwapAssetsWithExpiry proposes a swap to the counterparty of the swap channel, which the counterparty may not accept after the expiry.
+// The swap channel is freed for new swaps once the swap expires, unless it was accepted.
+func (n *Node) SwapAssetsWithExpiry(channelId types.Destination, tokenIn common.Address, tokenOut common.Address, amountIn *big.Int, amountOut *big.Int, expiry time.Time) (swap.ObjectiveResponse, error) {
+	if !expiry.After(time.Now()) {
+		return swap.ObjectiveResponse{}, swap.ErrSwapExpired
+	}
+
 	ch, ok := n.store.GetChannelById(channelId)
 	if !ok {
 		return swap.ObjectiveResponse{}, fmt.Errorf("no swap channel found for channel ID %v", channelId)
@@ -289,19 +397,46 @@ func (n *Node) SwapAssets(channelId types.Destination, tokenIn common.Address, t
 	}
 
 	nonce := rand.Uint64()
-	newSwap := payments.NewSwap(channelId, tokenIn, tokenOut, amountIn, amountOut, nonce)
+	newSwap := payments.NewSwap(channelId, tokenIn, tokenOut, amountIn, amountOut, nonce, uint64(expiry.Unix()))
 
 	isSwapValid := swap.IsValidSwap(swapState, newSwap, myIndex)
 	if !isSwapValid {
 		return swap.ObjectiveResponse{}, swap.ErrInvalidSwap
 	}
 
-	objectiveRequest := swap.NewObjectiveRequest(channelId, tokenIn, tokenOut, amountIn, amountOut, swapChannel.FixedPart, nonce)
+	objectiveRequest := swap.NewObjectiveRequest(channelId, tokenIn, tokenOut, amountIn, amountOut, swapChannel.FixedPart, nonce, uint64(expiry.Unix()))
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return swap.ObjectiveResponse{}, err
+	}
+	return objectiveRequest.Response(), nil
+}
 
-	objectiveRequest.WaitForObjectiveToStart()
+// SwapAlongRoute executes the swaps of the legs. We must send the first leg.
+// The legs are committed from the end of the route backwards, so that no leg commits unless the legs after it have
+// (see package multiswap). The other legs may be swaps between other nodes, which take part in the route.
+func (n *Node) SwapAlongRoute(legs []multiswap.Leg) (multiswap.ObjectiveResponse, error) {
+	for _, leg := range legs {
+		if leg.Sender != *n.Address && leg.Receiver != *n.Address {
+			continue
+		}
+
+		s, err := n.store.GetPendingSwapByChannelId(leg.Swap.ChannelId)
+		if err != nil {
+			return multiswap.ObjectiveResponse{}, err
+		}
+		if s != nil && !s.Id.IsZero() {
+			return multiswap.ObjectiveResponse{}, fmt.Errorf("%w: channel Id %+v", swap.ErrSwapExists, leg.Swap.ChannelId)
+		}
+	}
+
+	objectiveRequest := multiswap.NewObjectiveRequest(legs)
+
+	// Send the event to the engine
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return multiswap.ObjectiveResponse{}, err
+	}
 	return objectiveRequest.Response(), nil
 }
 
@@ -310,8 +445,9 @@ func (n *Node) ClosePaymentChannel(channelId types.Destination) (protocols.Objec
 	objectiveRequest := virtualdefund.NewObjectiveRequest(channelId)
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
-	objectiveRequest.WaitForObjectiveToStart()
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return "", err
+	}
 	return objectiveRequest.Id(*n.Address, n.chainId), nil
 }
 
@@ -319,8 +455,9 @@ func (n *Node) CloseSwapChannel(channelId types.Destination) (protocols.Objectiv
 	objectiveRequest := swapdefund.NewObjectiveRequest(channelId)
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
-	objectiveRequest.WaitForObjectiveToStart()
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return "", err
+	}
 	return objectiveRequest.Id(*n.Address, n.chainId), nil
 }
 
@@ -349,8 +486,9 @@ func (n *Node) CreateLedgerChannel(Counterparty types.Address, ChallengeDuration
 	}
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
-	objectiveRequest.WaitForObjectiveToStart()
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return directfund.ObjectiveResponse{}, err
+	}
 	return objectiveRequest.Response(*n.Address, n.chainId), nil
 }
 
@@ -379,8 +517,9 @@ func (n *Node) CreateBridgeChannel(Counterparty types.Address, ChallengeDuration
 	}
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
-	objectiveRequest.WaitForObjectiveToStart()
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return bridgedfund.ObjectiveResponse{}, err
+	}
 	return objectiveRequest.Response(*n.Address, n.chainId), nil
 }
 
@@ -389,17 +528,73 @@ func (n *Node) CloseLedgerChannel(channelId types.Destination, isChallenge bool)
 	objectiveRequest := directdefund.NewObjectiveRequest(channelId, isChallenge)
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
-	objectiveRequest.WaitForObjectiveToStart()
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return "", err
+	}
 	return objectiveRequest.Id(*n.Address, n.chainId), nil
 }
 
+// TopUpLedgerChannel deposits the given amount of the asset into an open ledger channel, and credits it to our balance
+// in the ledger channel once the deposit is confirmed. The ledger channel remains open (and usable) throughout.
+func (n *Node) TopUpLedgerChannel(channelId types.Destination, asset common.Address, amount *big.Int) (ledgertopup.ObjectiveResponse, error) {
+	objectiveRequest := ledgertopup.NewObjectiveRequest(channelId, asset, amount, rand.Uint64())
+
+	// Send the event to the engine
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return ledgertopup.ObjectiveResponse{}, err
+	}
+	return objectiveRequest.Response(*n.Address, n.chainId), nil
+}
+
+// WithdrawFromLedgerChannel withdraws the given amount of the asset from our balance in an open ledger channel, without closing it.
+// The remaining funds move to a successor ledger channel (identified in the response), which replaces the ledger channel
+// as soon as both participants have agreed the withdrawal.
+func (n *Node) WithdrawFromLedgerChannel(channelId types.Destination, asset common.Address, amount *big.Int) (ledgerwithdraw.ObjectiveResponse, error) {
+	ledger, err := n.store.GetConsensusChannelById(channelId)
+	if err != nil {
+		return ledgerwithdraw.ObjectiveResponse{}, fmt.Errorf("could not find ledger channel %s: %w", channelId, err)
+	}
+	objectiveRequest := ledgerwithdraw.NewObjectiveRequest(channelId, asset, amount, rand.Uint64())
+
+	// Send the event to the engine
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return ledgerwithdraw.ObjectiveResponse{}, err
+	}
+	return objectiveRequest.Response(*n.Address, n.chainId, ledger.FixedPart()), nil
+}
+
+// RebalanceLedgerChannels moves the given amount of the asset from our balance in one ledger channel to our balance in another,
+// without any on chain transactions. The counterparty of the first ledger channel pays the amount on to the counterparty
+// of the second ledger channel, via the given intermediaries (if any), and the counterparty of the second ledger channel pays it back to us.
+func (n *Node) RebalanceLedgerChannels(fromChannelId, toChannelId types.Destination, intermediaries []types.Address, asset common.Address, amount *big.Int) (rebalance.ObjectiveResponse, error) {
+	from, err := n.store.GetConsensusChannelById(fromChannelId)
+	if err != nil {
+		return rebalance.ObjectiveResponse{}, fmt.Errorf("could not find ledger channel %s: %w", fromChannelId, err)
+	}
+	to, err := n.store.GetConsensusChannelById(toChannelId)
+	if err != nil {
+		return rebalance.ObjectiveResponse{}, fmt.Errorf("could not find ledger channel %s: %w", toChannelId, err)
+	}
+
+	peers := []types.Address{from.Participants()[1-from.MyIndex]}
+	peers = append(peers, intermediaries...)
+	peers = append(peers, to.Participants()[1-to.MyIndex])
+	objectiveRequest := rebalance.NewObjectiveRequest(peers, asset, amount, n.engine.GetConsensusAppAddress(), from.FixedPart().ChallengeDuration, rand.Uint64())
+
+	// Send the event to the engine
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return rebalance.ObjectiveResponse{}, err
+	}
+	return objectiveRequest.Response(*n.Address), nil
+}
+
 func (n *Node) CloseBridgeChannel(channelId types.Destination) (protocols.ObjectiveId, error) {
 	objectiveRequest := bridgeddefund.NewObjectiveRequest(channelId)
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
-	objectiveRequest.WaitForObjectiveToStart()
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return "", err
+	}
 	return objectiveRequest.Id(*n.Address, n.chainId), nil
 }
 
@@ -407,8 +602,9 @@ func (n *Node) MirrorBridgedDefund(l1ChannelId types.Destination, l2SignedState
 	objectiveRequest := mirrorbridgeddefund.NewObjectiveRequest(l1ChannelId, l2SignedState, isChallenge)
 
 	// Send the event to the engine
-	n.engine.ObjectiveRequestsFromAPI <- objectiveRequest
-	objectiveRequest.WaitForObjectiveToStart()
+	if err := n.startObjective(objectiveRequest); err != nil {
+		return "", err
+	}
 	return objectiveRequest.Id(*n.Address, n.chainId), nil
 }
 
@@ -424,8 +620,9 @@ func (n *Node) Pay(channelId types.Destination, amount *big.Int) error {
 	}
 
 	// Send the event to the engine
-	n.engine.PaymentRequestsFromAPI <- engine.PaymentRequest{ChannelId: channelId, Amount: amount}
-	return nil
+	errChan := make(chan error, 1)
+	n.engine.PaymentRequestsFromAPI <- engine.PaymentRequest{ChannelId: channelId, Amount: amount, ErrChan: errChan}
+	return <-errChan
 }
 
 // GetPaymentChannel returns the payment channel with the given id.
@@ -434,7 +631,9 @@ func (n *Node) GetPaymentChannel(id types.Destination) (query.PaymentChannelInfo
 	return query.GetPaymentChannelInfo(id, n.store, n.vm)
 }
 
-func (n *Node) GetSwapChannel(id types.Destination) (string, error) {
+// GetSwapChannel returns the swap channel with the given id.
+// If no swap channel exists with the given id an error is returned.
+func (n *Node) GetSwapChannel(id types.Destination) (query.SwapChannelInfo, error) {
 	return query.GetSwapChannelInfo(id, n.store)
 }
 
@@ -451,6 +650,12 @@ func (n *Node) GetRecentSwapsByChannelId(swapChannelId types.Destination) ([]pay
 	return n.store.GetSwapsByChannelId(swapChannelId)
 }
 
+// ListSwaps returns the swaps which have come to an end and match the filter, ordered by the time they were recorded.
+// The first offset swaps are skipped and at most limit swaps are returned, or every remaining swap if the limit is zero.
+func (n *Node) ListSwaps(filter query.SwapFilter, offset, limit uint) (query.SwapHistory, error) {
+	return query.GetSwapHistory(filter, offset, limit, n.store)
+}
+
 func (n *Node) GetVoucher(id types.Destination) payments.Voucher {
 	var voucher payments.Voucher
 	voucherInfo, voucherFound := n.vm.GetVoucherIfAmountPresent(id)
@@ -467,6 +672,11 @@ func (n *Node) GetAllLedgerChannels() ([]query.LedgerChannelInfo, error) {
 	return query.GetAllLedgerChannels(n.store, n.engine.GetConsensusAppAddress())
 }
 
+// GetAllSwapChannels returns all swap channels.
+func (n *Node) GetAllSwapChannels() ([]query.SwapChannelInfo, error) {
+	return query.GetAllSwapChannels(n.store)
+}
+
 // GetLastBlockNum returns last confirmed blockNum read from store
 func (n *Node) GetLastBlockNum() (uint64, error) {
 	return n.store.GetLastBlockNumSeen()
@@ -487,6 +697,16 @@ func (n *Node) GetSignedState(id types.Destination) (state.SignedState, error) {
 	return consensusChannel.SupportedSignedState(), nil
 }
 
+// GetObjectiveInfo returns the status of the objective with the given id, and what it is waiting for
+func (n *Node) GetObjectiveInfo(objectiveId protocols.ObjectiveId) (query.ObjectiveInfo, error) {
+	return query.GetObjectiveInfo(objectiveId, n.store)
+}
+
+// ListObjectives returns the status of every objective with the given status, or of every objective if the status is empty
+func (n *Node) ListObjectives(status query.ObjectiveStatus) ([]query.ObjectiveInfo, error) {
+	return query.GetAllObjectiveInfo(status, n.store)
+}
+
 func (n *Node) GetObjectiveById(objectiveId protocols.ObjectiveId) (protocols.Objective, error) {
 	return n.store.GetObjectiveById(objectiveId)
 }
@@ -504,26 +724,34 @@ func (n *Node) Close() error {
 		return err
 	}
 
+	if err := n.failedObjectives.Close(); err != nil {
+		return err
+	}
+
 	return n.store.Close()
 }
 
-// handleError logs the error and panics
-// Eventually it should return the error to the caller
+// handleError logs the error.
+// Errors from API requests are returned to the caller, so the errors handled here only affect notifications.
 func (n *Node) handleError(err error) {
 	if err != nil {
-
 		slog.Error("Error in nitro node", "error", err)
+	}
+}
 
-		<-time.After(1000 * time.Millisecond) // We wait for a bit so the previous log line has time to complete
-
-		// TODO instead of a panic, errors should be returned to the caller.
-		panic(err)
+// startObjective sends the objective request to the engine and waits for the objective to start.
+// It returns the error encountered by the engine while handling the request, if any.
+func (n *Node) startObjective(or protocols.ObjectiveRequest) error {
+	n.engine.ObjectiveRequestsFromAPI <- or
+	or.WaitForObjectiveToStart()
 
-	}
+	return n.engine.TakeObjectiveRequestError(or.Id(*n.Address, n.chainId))
 }
 
-func (n *Node) CounterChallenge(id types.Destination, action types.CounterChallengeAction, payload state.SignedState) {
-	n.engine.CounterChallengeRequestsFromAPI <- engine.CounterChallengeRequest{ChannelId: id, Action: action, Payload: payload}
+func (n *Node) CounterChallenge(id types.Destination, action types.CounterChallengeAction, payload state.SignedState) error {
+	errChan := make(chan error, 1)
+	n.engine.CounterChallengeRequestsFromAPI <- engine.CounterChallengeRequest{ChannelId: id, Action: action, Payload: payload, ErrChan: errChan}
+	return <-errChan
 }
 
 func (n *Node) ConfirmSwap(swapId types.Destination, action types.SwapStatus) error {
@@ -551,13 +779,31 @@ func (n *Node) ConfirmSwap(swapId types.Destination, action types.SwapStatus) er
 		return fmt.Errorf("swap cannot be confirmed by swap sender")
 	}
 
-	n.engine.ConfirmSwapRequestFromAPI <- types.ConfirmSwapRequest{SwapId: swapId, Action: action}
+	errChan := make(chan error, 1)
+	n.engine.ConfirmSwapRequestFromAPI <- engine.ConfirmSwapRequest{
+		ConfirmSwapRequest: types.ConfirmSwapRequest{SwapId: swapId, Action: action},
+		ErrChan:            errChan,
+	}
+
+	return <-errChan
+}
 
-	return nil
+// CancelSwap cancels a swap we initiated which the counterparty has not accepted yet, freeing the swap channel for new swaps.
+// If the counterparty accepts the swap before it learns of the cancellation, the swap still goes ahead.
+func (n *Node) CancelSwap(swapId types.Destination) error {
+	errChan := make(chan error, 1)
+	n.engine.CancelSwapRequestFromAPI <- engine.CancelSwapRequest{SwapId: swapId, ErrChan: errChan}
+	return <-errChan
 }
 
-func (n *Node) RetryObjectiveTx(objectiveId protocols.ObjectiveId) {
-	n.engine.RetryObjectiveTxRequestFromAPI <- types.RetryObjectiveTxRequest{ObjectiveId: string(objectiveId)}
+func (n *Node) RetryObjectiveTx(objectiveId protocols.ObjectiveId) error {
+	errChan := make(chan error, 1)
+	n.engine.RetryObjectiveTxRequestFromAPI <- engine.RetryObjectiveTxRequest{
+		RetryObjectiveTxRequest: types.RetryObjectiveTxRequest{ObjectiveId: string(objectiveId)},
+		ErrChan:                 errChan,
+	}
+
+	return <-errChan
 }
 
 func (n *Node) GetNodeInfo() types.NodeInfo {
@@ -1,6 +1,8 @@
 package notifier
 
 import (
+	"context"
+
 	"github.com/statechannels/go-nitro/internal/safesync"
 	"github.com/statechannels/go-nitro/node/engine/store"
 	"github.com/statechannels/go-nitro/node/query"
@@ -12,28 +14,59 @@ const ALL_NOTIFICATIONS = "all"
 
 // ChannelNotifier is used to notify multiple listeners of a channel update.
 type ChannelNotifier struct {
-	ledgerListeners  *safesync.Map[*ledgerChannelListeners]
-	paymentListeners *safesync.Map[*paymentChannelListeners]
-	swapListeners    *safesync.Map[*swapListeners]
-	store            store.Store
-	vm               *payments.VoucherManager
+	ledgerListeners      *safesync.Map[*ledgerChannelListeners]
+	paymentListeners     *safesync.Map[*paymentChannelListeners]
+	swapListeners        *safesync.Map[*swapListeners]
+	swapChannelListeners *safesync.Map[*swapChannelListeners]
+	store                store.Store
+	vm                   *payments.VoucherManager
+
+	ledgerUpdates      *EventBus[query.LedgerChannelInfo]
+	paymentUpdates     *EventBus[query.PaymentChannelInfo]
+	swapUpdates        *EventBus[query.SwapInfo]
+	swapChannelUpdates *EventBus[query.SwapChannelInfo]
 }
 
 // NewChannelNotifier constructs a channel notifier using the provided store.
 func NewChannelNotifier(store store.Store, vm *payments.VoucherManager) *ChannelNotifier {
 	return &ChannelNotifier{
-		ledgerListeners:  &safesync.Map[*ledgerChannelListeners]{},
-		paymentListeners: &safesync.Map[*paymentChannelListeners]{},
-		swapListeners:    &safesync.Map[*swapListeners]{},
-		store:            store,
-		vm:               vm,
+		ledgerListeners:      &safesync.Map[*ledgerChannelListeners]{},
+		paymentListeners:     &safesync.Map[*paymentChannelListeners]{},
+		swapListeners:        &safesync.Map[*swapListeners]{},
+		swapChannelListeners: &safesync.Map[*swapChannelListeners]{},
+		store:                store,
+		vm:                   vm,
+		ledgerUpdates:        NewEventBus[query.LedgerChannelInfo]("ledger-updates"),
+		paymentUpdates:       NewEventBus[query.PaymentChannelInfo]("payment-updates"),
+		swapUpdates:          NewEventBus[query.SwapInfo]("swap-updates"),
+		swapChannelUpdates:   NewEventBus[query.SwapChannelInfo]("swap-channel-updates"),
 	}
 }
 
-// RegisterForAllLedgerUpdates returns a buffered channel that will receive updates for all ledger channels.
+// SubscribeLedgerUpdates returns a chan that will receive updates for all ledger channels until ctx is done.
+func (cn *ChannelNotifier) SubscribeLedgerUpdates(ctx context.Context, opts SubscriptionOpts) <-chan query.LedgerChannelInfo {
+	return cn.ledgerUpdates.Subscribe(ctx, opts)
+}
+
+// SubscribePaymentUpdates returns a chan that will receive updates for all payment channels until ctx is done.
+func (cn *ChannelNotifier) SubscribePaymentUpdates(ctx context.Context, opts SubscriptionOpts) <-chan query.PaymentChannelInfo {
+	return cn.paymentUpdates.Subscribe(ctx, opts)
+}
+
+// SubscribeSwapUpdates returns a chan that will receive updates for all swaps until ctx is done.
+func (cn *ChannelNotifier) SubscribeSwapUpdates(ctx context.Context, opts SubscriptionOpts) <-chan query.SwapInfo {
+	return cn.swapUpdates.Subscribe(ctx, opts)
+}
+
+// SubscribeSwapChannelUpdates returns a chan that will receive updates for all swap channels until ctx is done.
+func (cn *ChannelNotifier) SubscribeSwapChannelUpdates(ctx context.Context, opts SubscriptionOpts) <-chan query.SwapChannelInfo {
+	return cn.swapChannelUpdates.Subscribe(ctx, opts)
+}
+
+// RegisterForAllLedgerUpdates returns a new buffered channel that will receive updates for all ledger channels, until the notifier is closed.
+// Every call creates an independent subscription, use SubscribeLedgerUpdates to end the subscription earlier.
 func (cn *ChannelNotifier) RegisterForAllLedgerUpdates() <-chan query.LedgerChannelInfo {
-	li, _ := cn.ledgerListeners.LoadOrStore(ALL_NOTIFICATIONS, newLedgerChannelListeners())
-	return li.getOrCreateListener()
+	return cn.SubscribeLedgerUpdates(context.Background(), SubscriptionOpts{})
 }
 
 // RegisterForLedgerUpdates returns a buffered channel that will receive updates for a specific ledger channel.
@@ -42,10 +75,10 @@ func (cn *ChannelNotifier) RegisterForLedgerUpdates(cId types.Destination) <-cha
 	return li.createNewListener()
 }
 
-// RegisterForAllPaymentUpdates returns a buffered channel that will receive updates for all payment channels.
+// RegisterForAllPaymentUpdates returns a new buffered channel that will receive updates for all payment channels, until the notifier is closed.
+// Every call creates an independent subscription, use SubscribePaymentUpdates to end the subscription earlier.
 func (cn *ChannelNotifier) RegisterForAllPaymentUpdates() <-chan query.PaymentChannelInfo {
-	li, _ := cn.paymentListeners.LoadOrStore(ALL_NOTIFICATIONS, newPaymentChannelListeners())
-	return li.getOrCreateListener()
+	return cn.SubscribePaymentUpdates(context.Background(), SubscriptionOpts{})
 }
 
 // RegisterForLedgerUpdates returns a buffered channel that will receive updates or a specific payment channel.
@@ -54,19 +87,31 @@ func (cn *ChannelNotifier) RegisterForPaymentChannelUpdates(cId types.Destinatio
 	return li.createNewListener()
 }
 
-// RegisterForAllSwapUpdates returns a buffered channel that will receive updates for all swaps.
+// RegisterForAllSwapUpdates returns a new buffered channel that will receive updates for all swaps, until the notifier is closed.
+// Every call creates an independent subscription, use SubscribeSwapUpdates to end the subscription earlier.
 func (cn *ChannelNotifier) RegisterForAllSwapUpdates() <-chan query.SwapInfo {
-	li, _ := cn.swapListeners.LoadOrStore(ALL_NOTIFICATIONS, newSwapListeners())
-	return li.getOrCreateListener()
+	return cn.SubscribeSwapUpdates(context.Background(), SubscriptionOpts{})
+}
+
+// RegisterForAllSwapChannelUpdates returns a new buffered channel that will receive updates for all swap channels, until the notifier is closed.
+// Every call creates an independent subscription, use SubscribeSwapChannelUpdates to end the subscription earlier.
+func (cn *ChannelNotifier) RegisterForAllSwapChannelUpdates() <-chan query.SwapChannelInfo {
+	return cn.SubscribeSwapChannelUpdates(context.Background(), SubscriptionOpts{})
+}
+
+// RegisterForSwapChannelUpdates returns a buffered channel that will receive updates for a specific swap channel.
+func (cn *ChannelNotifier) RegisterForSwapChannelUpdates(cId types.Destination) <-chan query.SwapChannelInfo {
+	li, _ := cn.swapChannelListeners.LoadOrStore(cId.String(), newSwapChannelListeners())
+	return li.createNewListener()
 }
 
 // NotifyLedgerUpdated notifies all listeners of a ledger channel update.
 // It should be called whenever a ledger channel is updated.
 func (cn *ChannelNotifier) NotifyLedgerUpdated(info query.LedgerChannelInfo) error {
 	li, _ := cn.ledgerListeners.LoadOrStore(info.ID.String(), newLedgerChannelListeners())
-	li.Notify(info)
-	allLi, _ := cn.ledgerListeners.LoadOrStore(ALL_NOTIFICATIONS, newLedgerChannelListeners())
-	allLi.Notify(info)
+	if li.Notify(info) {
+		cn.ledgerUpdates.Publish(info)
+	}
 
 	return nil
 }
@@ -75,20 +120,29 @@ func (cn *ChannelNotifier) NotifyLedgerUpdated(info query.LedgerChannelInfo) err
 // It should be called whenever a payment channel is updated.
 func (cn *ChannelNotifier) NotifyPaymentUpdated(info query.PaymentChannelInfo) error {
 	li, _ := cn.paymentListeners.LoadOrStore(info.ID.String(), newPaymentChannelListeners())
-	li.Notify(info)
-
-	allLi, _ := cn.paymentListeners.LoadOrStore(ALL_NOTIFICATIONS, newPaymentChannelListeners())
-	allLi.Notify(info)
+	if li.Notify(info) {
+		cn.paymentUpdates.Publish(info)
+	}
 
 	return nil
 }
 
 func (cn *ChannelNotifier) NotifySwapUpdated(info query.SwapInfo) error {
 	li, _ := cn.swapListeners.LoadOrStore(info.Id.String(), newSwapListeners())
-	li.Notify(info)
+	if li.Notify(info) {
+		cn.swapUpdates.Publish(info)
+	}
+
+	return nil
+}
 
-	allLi, _ := cn.swapListeners.LoadOrStore(ALL_NOTIFICATIONS, newSwapListeners())
-	allLi.Notify(info)
+// NotifySwapChannelUpdated notifies all listeners of a swap channel update.
+// It should be called whenever a swap channel is updated.
+func (cn *ChannelNotifier) NotifySwapChannelUpdated(info query.SwapChannelInfo) error {
+	li, _ := cn.swapChannelListeners.LoadOrStore(info.ID.String(), newSwapChannelListeners())
+	if li.Notify(info) {
+		cn.swapChannelUpdates.Publish(info)
+	}
 
 	return nil
 }
@@ -108,5 +162,22 @@ func (cn *ChannelNotifier) Close() error {
 		err = v.Close()
 		return err == nil
 	})
-	return err
+	cn.swapChannelListeners.Range(func(k string, v *swapChannelListeners) bool {
+		err = v.Close()
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := cn.ledgerUpdates.Close(); err != nil {
+		return err
+	}
+	if err := cn.paymentUpdates.Close(); err != nil {
+		return err
+	}
+	if err := cn.swapUpdates.Close(); err != nil {
+		return err
+	}
+	return cn.swapChannelUpdates.Close()
 }
@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/statechannels/go-nitro/node/query"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestRegisterForAllSwapUpdates(t *testing.T) {
+	cn := NewChannelNotifier(nil, nil)
+	a := cn.RegisterForAllSwapUpdates()
+	b := cn.RegisterForAllSwapUpdates()
+	if a == b {
+		t.Fatal("expected every caller to receive its own subscription")
+	}
+
+	info := query.SwapInfo{Id: types.Destination{1}, ChannelId: types.Destination{2}}
+	if err := cn.NotifySwapUpdated(info); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sub := range []<-chan query.SwapInfo{a, b} {
+		select {
+		case received := <-sub:
+			if received.Id != info.Id {
+				t.Fatalf("expected to receive swap %s, got %s", info.Id, received.Id)
+			}
+		default:
+			t.Fatal("expected every subscription to receive the update")
+		}
+	}
+
+	if err := cn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, sub := range []<-chan query.SwapInfo{a, b} {
+		if _, ok := <-sub; ok {
+			t.Fatal("expected the subscriptions to be closed with the notifier")
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// SlowConsumerPolicy determines what happens when an event is published to a subscriber whose buffer is full
+type SlowConsumerPolicy uint8
+
+const (
+	// DropOldest discards the oldest buffered event to make room for the new one, and logs a warning for every dropped event
+	DropOldest SlowConsumerPolicy = iota
+	// Disconnect closes the subscription of the subscriber
+	Disconnect
+)
+
+const DEFAULT_SUBSCRIPTION_BUFFER_SIZE = 1000
+
+// SubscriptionOpts configures a subscription to an EventBus
+type SubscriptionOpts struct {
+	// BufferSize is the number of events buffered for the subscriber, DEFAULT_SUBSCRIPTION_BUFFER_SIZE is used if it is not set
+	BufferSize         int
+	SlowConsumerPolicy SlowConsumerPolicy
+}
+
+type subscriber[T any] struct {
+	events chan T
+	policy SlowConsumerPolicy
+}
+
+// EventBus broadcasts published events to any number of independent subscribers.
+// Publishing never blocks: each subscriber has its own buffer, and a subscriber which does not keep up
+// is dealt with according to the SlowConsumerPolicy of its subscription.
+type EventBus[T any] struct {
+	name        string
+	subscribers map[uint64]*subscriber[T]
+	nextId      uint64
+	closed      bool
+	// lock is used to protect against concurrent access to sibling struct members
+	lock sync.Mutex
+}
+
+// NewEventBus constructs an EventBus, the name is used when logging slow subscribers.
+func NewEventBus[T any](name string) *EventBus[T] {
+	return &EventBus[T]{name: name, subscribers: make(map[uint64]*subscriber[T])}
+}
+
+// Subscribe returns a chan that receives every event published after the call.
+// The chan is closed once ctx is done, when the subscriber is disconnected for being too slow, or when the bus is closed.
+func (eb *EventBus[T]) Subscribe(ctx context.Context, opts SubscriptionOpts) <-chan T {
+	bufferSize := opts.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = DEFAULT_SUBSCRIPTION_BUFFER_SIZE
+	}
+	events := make(chan T, bufferSize)
+
+	eb.lock.Lock()
+	defer eb.lock.Unlock()
+	if eb.closed {
+		close(events)
+		return events
+	}
+
+	id := eb.nextId
+	eb.nextId++
+	eb.subscribers[id] = &subscriber[T]{events: events, policy: opts.SlowConsumerPolicy}
+
+	context.AfterFunc(ctx, func() {
+		eb.lock.Lock()
+		defer eb.lock.Unlock()
+		eb.unsubscribe(id)
+	})
+
+	return events
+}
+
+// Publish sends the event to every subscriber
+func (eb *EventBus[T]) Publish(event T) {
+	eb.lock.Lock()
+	defer eb.lock.Unlock()
+
+	for id, s := range eb.subscribers {
+		select {
+		case s.events <- event:
+			continue
+		default:
+		}
+
+		switch s.policy {
+		case DropOldest:
+			// The subscriber may have consumed events in the meantime, so neither operation is allowed to block
+			select {
+			case <-s.events:
+			default:
+			}
+			select {
+			case s.events <- event:
+			default:
+			}
+			slog.Warn("Subscriber is too slow, dropped oldest event", "bus", eb.name, "subscriber", id)
+		case Disconnect:
+			slog.Warn("Subscriber is too slow, disconnecting it", "bus", eb.name, "subscriber", id)
+			eb.unsubscribe(id)
+		}
+	}
+}
+
+// NumSubscribers returns the number of active subscriptions
+func (eb *EventBus[T]) NumSubscribers() int {
+	eb.lock.Lock()
+	defer eb.lock.Unlock()
+	return len(eb.subscribers)
+}
+
+// Close closes every subscription, subsequent subscriptions are closed immediately
+func (eb *EventBus[T]) Close() error {
+	eb.lock.Lock()
+	defer eb.lock.Unlock()
+
+	for id := range eb.subscribers {
+		eb.unsubscribe(id)
+	}
+	eb.closed = true
+
+	return nil
+}
+
+// unsubscribe removes the subscriber and closes its chan, it is a no-op if the subscriber has already been removed.
+// The caller must hold the lock.
+func (eb *EventBus[T]) unsubscribe(id uint64) {
+	s, ok := eb.subscribers[id]
+	if !ok {
+		return
+	}
+
+	close(s.events)
+	delete(eb.subscribers, id)
+}
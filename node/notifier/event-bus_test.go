@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// drain returns the events buffered in the chan, and whether the chan is closed
+func drain(events <-chan int) (received []int, closed bool) {
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return received, true
+			}
+			received = append(received, e)
+		default:
+			return received, false
+		}
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	t.Run("subscribers receive every event independently", func(t *testing.T) {
+		eb := NewEventBus[int]("test")
+		a := eb.Subscribe(context.Background(), SubscriptionOpts{})
+		b := eb.Subscribe(context.Background(), SubscriptionOpts{})
+
+		eb.Publish(1)
+		eb.Publish(2)
+
+		for _, sub := range []<-chan int{a, b} {
+			received, closed := drain(sub)
+			if closed || len(received) != 2 || received[0] != 1 || received[1] != 2 {
+				t.Fatalf("expected to receive [1 2] on an open subscription, got %v (closed: %t)", received, closed)
+			}
+		}
+	})
+
+	t.Run("slow subscribers lose their oldest events", func(t *testing.T) {
+		eb := NewEventBus[int]("test")
+		slow := eb.Subscribe(context.Background(), SubscriptionOpts{BufferSize: 2, SlowConsumerPolicy: DropOldest})
+		fast := eb.Subscribe(context.Background(), SubscriptionOpts{BufferSize: 10})
+
+		for i := 1; i <= 4; i++ {
+			eb.Publish(i)
+		}
+
+		received, closed := drain(slow)
+		if closed || len(received) != 2 || received[0] != 3 || received[1] != 4 {
+			t.Fatalf("expected slow subscriber to receive [3 4], got %v (closed: %t)", received, closed)
+		}
+		if received, _ := drain(fast); len(received) != 4 {
+			t.Fatalf("expected fast subscriber to receive every event, got %v", received)
+		}
+	})
+
+	t.Run("slow subscribers are disconnected", func(t *testing.T) {
+		eb := NewEventBus[int]("test")
+		slow := eb.Subscribe(context.Background(), SubscriptionOpts{BufferSize: 1, SlowConsumerPolicy: Disconnect})
+
+		eb.Publish(1)
+		eb.Publish(2)
+
+		received, closed := drain(slow)
+		if !closed || len(received) != 1 || received[0] != 1 {
+			t.Fatalf("expected slow subscriber to receive [1] and be disconnected, got %v (closed: %t)", received, closed)
+		}
+		if eb.NumSubscribers() != 0 {
+			t.Fatalf("expected no subscribers, got %d", eb.NumSubscribers())
+		}
+	})
+
+	t.Run("subscriptions end with their context", func(t *testing.T) {
+		eb := NewEventBus[int]("test")
+		ctx, cancel := context.WithCancel(context.Background())
+		sub := eb.Subscribe(ctx, SubscriptionOpts{})
+
+		cancel()
+		select {
+		case _, ok := <-sub:
+			if ok {
+				t.Fatal("expected the subscription to be closed")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the subscription to close")
+		}
+
+		// Publishing after a subscription is cancelled must not panic
+		eb.Publish(1)
+	})
+
+	t.Run("closing the bus closes every subscription", func(t *testing.T) {
+		eb := NewEventBus[int]("test")
+		before := eb.Subscribe(context.Background(), SubscriptionOpts{})
+
+		if err := eb.Close(); err != nil {
+			t.Fatal(err)
+		}
+		after := eb.Subscribe(context.Background(), SubscriptionOpts{})
+
+		for _, sub := range []<-chan int{before, after} {
+			if _, closed := drain(sub); !closed {
+				t.Fatal("expected the subscription to be closed")
+			}
+		}
+	})
+}
@@ -25,18 +25,19 @@ func newSwapListeners() *swapListeners {
 }
 
 // Notify notifies all listeners of a swap update.
-// It only notifies listeners if the new info is different from the previous info.
-func (li *swapListeners) Notify(info query.SwapInfo) {
+// It only notifies listeners if the new info is different from the previous info, and returns whether it did.
+func (li *swapListeners) Notify(info query.SwapInfo) bool {
 	li.listenersLock.Lock()
 	defer li.listenersLock.Unlock()
 	if li.prev.Id == info.Id && li.prev.ChannelId == info.ChannelId {
-		return
+		return false
 	}
 
 	for _, list := range li.listeners {
 		list <- info
 	}
 	li.prev = info
+	return true
 }
 
 // createNewListener creates a new listener and adds it to the list of listeners.
@@ -49,18 +50,6 @@ func (li *swapListeners) createNewListener() <-chan query.SwapInfo {
 	return listener
 }
 
-// getOrCreateListener returns the first listener, creating one if none exist.
-func (li *swapListeners) getOrCreateListener() <-chan query.SwapInfo {
-	li.listenersLock.Lock()
-	if len(li.listeners) != 0 {
-		l := li.listeners[0]
-		li.listenersLock.Unlock()
-		return l
-	}
-	li.listenersLock.Unlock()
-	return li.createNewListener()
-}
-
 // Close closes any active listeners.
 func (li *swapListeners) Close() error {
 	li.listenersLock.Lock()
@@ -88,12 +77,12 @@ func newPaymentChannelListeners() *paymentChannelListeners {
 }
 
 // Notify notifies all listeners of a payment channel update.
-// It only notifies listeners if the new info is different from the previous info.
-func (li *paymentChannelListeners) Notify(info query.PaymentChannelInfo) {
+// It only notifies listeners if the new info is different from the previous info, and returns whether it did.
+func (li *paymentChannelListeners) Notify(info query.PaymentChannelInfo) bool {
 	li.listenersLock.Lock()
 	defer li.listenersLock.Unlock()
 	if li.prev.Equal(info) {
-		return
+		return false
 	}
 	for i, list := range li.listeners {
 		list <- info
@@ -107,6 +96,7 @@ func (li *paymentChannelListeners) Notify(info query.PaymentChannelInfo) {
 
 	}
 	li.prev = info
+	return true
 }
 
 // createNewListener creates a new listener and adds it to the list of listeners.
@@ -119,18 +109,6 @@ func (li *paymentChannelListeners) createNewListener() <-chan query.PaymentChann
 	return listener
 }
 
-// getOrCreateListener returns the first listener, creating one if none exist.
-func (li *paymentChannelListeners) getOrCreateListener() <-chan query.PaymentChannelInfo {
-	li.listenersLock.Lock()
-	if len(li.listeners) != 0 {
-		l := li.listeners[0]
-		li.listenersLock.Unlock()
-		return l
-	}
-	li.listenersLock.Unlock()
-	return li.createNewListener()
-}
-
 // Close closes any active listeners.
 func (li *paymentChannelListeners) Close() error {
 	li.listenersLock.Lock()
@@ -158,18 +136,19 @@ func newLedgerChannelListeners() *ledgerChannelListeners {
 }
 
 // Notify notifies all listeners of a ledger channel update.
-// It only notifies listeners if the new info is different from the previous info.
-func (li *ledgerChannelListeners) Notify(info query.LedgerChannelInfo) {
+// It only notifies listeners if the new info is different from the previous info, and returns whether it did.
+func (li *ledgerChannelListeners) Notify(info query.LedgerChannelInfo) bool {
 	li.listenersLock.Lock()
 	defer li.listenersLock.Unlock()
 	if li.prev.Equal(info) {
-		return
+		return false
 	}
 
 	for _, list := range li.listeners {
 		list <- info
 	}
 	li.prev = info
+	return true
 }
 
 // createNewListener creates a new listener and adds it to the list of listeners.
@@ -182,20 +161,60 @@ func (li *ledgerChannelListeners) createNewListener() <-chan query.LedgerChannel
 	return listener
 }
 
-// getOrCreateListener returns the first listener, creating one if none exist.
-func (li *ledgerChannelListeners) getOrCreateListener() <-chan query.LedgerChannelInfo {
+// Close closes all listeners.
+func (li *ledgerChannelListeners) Close() error {
+	li.listenersLock.Lock()
+	defer li.listenersLock.Unlock()
+	for _, c := range li.listeners {
+		close(c)
+	}
+
+	return nil
+}
+
+// swapChannelListeners is a struct that holds a list of listeners for swap channel info.
+type swapChannelListeners struct {
+	// listeners is a list of listeners for swap channel info that we need to notify.
+	listeners []chan query.SwapChannelInfo
+	// prev is the previous swap channel info that was sent to the listeners.
+	prev query.SwapChannelInfo
+	// listenersLock is used to protect against concurrent access to sibling struct members.
+	listenersLock sync.Mutex
+}
+
+// newSwapChannelListeners constructs a new swap channel listeners struct.
+func newSwapChannelListeners() *swapChannelListeners {
+	return &swapChannelListeners{listeners: []chan query.SwapChannelInfo{}, listenersLock: sync.Mutex{}}
+}
+
+// Notify notifies all listeners of a swap channel update.
+// It only notifies listeners if the new info is different from the previous info, and returns whether it did.
+func (li *swapChannelListeners) Notify(info query.SwapChannelInfo) bool {
 	li.listenersLock.Lock()
-	if len(li.listeners) != 0 {
-		l := li.listeners[0]
-		li.listenersLock.Unlock()
-		return l
+	defer li.listenersLock.Unlock()
+	if li.prev.Equal(info) {
+		return false
+	}
+
+	for _, list := range li.listeners {
+		list <- info
 	}
-	li.listenersLock.Unlock()
-	return li.createNewListener()
+	li.prev = info
+	return true
+}
+
+// createNewListener creates a new listener and adds it to the list of listeners.
+func (li *swapChannelListeners) createNewListener() <-chan query.SwapChannelInfo {
+	li.listenersLock.Lock()
+	defer li.listenersLock.Unlock()
+	// Use a buffered channel to avoid blocking the notifier.
+	listener := make(chan query.SwapChannelInfo, 1000)
+	li.listeners = append(li.listeners, listener)
+	return listener
 }
 
 // Close closes all listeners.
-func (li *ledgerChannelListeners) Close() error {
+func (li *swapChannelListeners) Close() error {
 	li.listenersLock.Lock()
 	defer li.listenersLock.Unlock()
 	for _, c := range li.listeners {
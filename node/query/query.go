@@ -1,10 +1,10 @@
 package query
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -15,6 +15,19 @@ import (
 	"github.com/statechannels/go-nitro/node/engine/store"
 	"github.com/statechannels/go-nitro/payments"
 	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/bridgeddefund"
+	"github.com/statechannels/go-nitro/protocols/bridgedfund"
+	"github.com/statechannels/go-nitro/protocols/directdefund"
+	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/ledgertopup"
+	"github.com/statechannels/go-nitro/protocols/ledgerwithdraw"
+	"github.com/statechannels/go-nitro/protocols/mirrorbridgeddefund"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/rebalance"
+	"github.com/statechannels/go-nitro/protocols/swap"
+	"github.com/statechannels/go-nitro/protocols/swapdefund"
+	"github.com/statechannels/go-nitro/protocols/swapfund"
+	"github.com/statechannels/go-nitro/protocols/virtualdefund"
 	"github.com/statechannels/go-nitro/protocols/virtualfund"
 	"github.com/statechannels/go-nitro/types"
 )
@@ -186,28 +199,17 @@ func GetPaymentChannelInfo(id types.Destination, store store.Store, vm *payments
 	return PaymentChannelInfo{}, fmt.Errorf("could not find channel with id %v", id)
 }
 
-func GetSwapChannelInfo(id types.Destination, store store.Store) (string, error) {
+func GetSwapChannelInfo(id types.Destination, store store.Store) (SwapChannelInfo, error) {
 	if (id == types.Destination{}) {
-		return "", errors.New("a valid channel id must be provided")
+		return SwapChannelInfo{}, errors.New("a valid channel id must be provided")
 	}
 
 	c, channelFound := store.GetChannelById(id)
-	swapChannnel := channel.SwapChannel{Channel: *c}
 	if channelFound {
-		SwapChannelInfo, err := ConstructSwapInfo(swapChannnel, *store.GetAddress())
-		if err != nil {
-			return "", err
-		}
-
-		marshalledSwapChannelInfo, err := json.Marshal(SwapChannelInfo)
-		if err != nil {
-			return "", err
-		}
-
-		return string(marshalledSwapChannelInfo), nil
+		return ConstructSwapInfo(channel.SwapChannel{Channel: *c}, *store.GetAddress())
 	}
 
-	return "", fmt.Errorf("could not find channel with id %v", id)
+	return SwapChannelInfo{}, fmt.Errorf("could not find channel with id %v", id)
 }
 
 // GetAllLedgerChannels returns a `LedgerChannelInfo` for each ledger channel in the store.
@@ -253,6 +255,30 @@ func GetAllLedgerChannels(store store.Store, consensusAppDefinition types.Addres
 	return toReturn, err
 }
 
+// GetAllSwapChannels returns a `SwapChannelInfo` for each swap channel in the store.
+func GetAllSwapChannels(store store.Store) ([]SwapChannelInfo, error) {
+	toReturn := []SwapChannelInfo{}
+	myAddress := *store.GetAddress()
+
+	allChannels, err := store.GetAllChannels()
+	if err != nil {
+		return []SwapChannelInfo{}, err
+	}
+	for _, c := range allChannels {
+		if c.Type != types.Swap {
+			continue
+		}
+
+		s, err := ConstructSwapInfo(channel.SwapChannel{Channel: *c}, myAddress)
+		if err != nil {
+			return []SwapChannelInfo{}, err
+		}
+		toReturn = append(toReturn, s)
+	}
+
+	return toReturn, nil
+}
+
 // GetPaymentChannelsByLedger returns a `PaymentChannelInfo` for each active payment channel funded by the given ledger channel.
 func GetPaymentChannelsByLedger(ledgerId types.Destination, s store.Store, vm *payments.VoucherManager) ([]PaymentChannelInfo, error) {
 	// If a ledger channel is actively funding payment channels it must be in the form of a consensus channel
@@ -380,3 +406,173 @@ func ConstructSwapInfo(c channel.SwapChannel, myAddress common.Address) (SwapCha
 		Balances: balances,
 	}, nil
 }
+
+// GetObjectiveType returns the type of the objective
+func GetObjectiveType(o protocols.Objective) (ObjectiveType, error) {
+	switch o.(type) {
+	case *directfund.Objective:
+		return DirectFund, nil
+	case *directdefund.Objective:
+		return DirectDefund, nil
+	case *virtualfund.Objective:
+		return VirtualFund, nil
+	case *virtualdefund.Objective:
+		return VirtualDefund, nil
+	case *swapfund.Objective:
+		return SwapFund, nil
+	case *swapdefund.Objective:
+		return SwapDefund, nil
+	case *swap.Objective:
+		return Swap, nil
+	case *bridgedfund.Objective:
+		return BridgedFund, nil
+	case *bridgeddefund.Objective:
+		return BridgedDefund, nil
+	case *mirrorbridgeddefund.Objective:
+		return MirrorBridgedDefund, nil
+	case *ledgertopup.Objective:
+		return LedgerTopUp, nil
+	case *ledgerwithdraw.Objective:
+		return LedgerWithdraw, nil
+	case *rebalance.Objective:
+		return Rebalance, nil
+	case *multiswap.Objective:
+		return MultiSwap, nil
+	default:
+		return "", fmt.Errorf("unknown objective type %T", o)
+	}
+}
+
+// getObjectiveStatus returns the status of the objective, which is failed if the store recorded a failure
+func getObjectiveStatus(o protocols.Objective, metadata store.ObjectiveMetadata) ObjectiveStatus {
+	if metadata.Failed() {
+		return ObjectiveFailed
+	}
+
+	switch o.GetStatus() {
+	case protocols.Approved:
+		return ObjectiveApproved
+	case protocols.Rejected:
+		return ObjectiveRejected
+	case protocols.Completed:
+		return ObjectiveCompleted
+	default:
+		return ObjectiveUnapproved
+	}
+}
+
+// ConstructObjectiveInfo constructs the ObjectiveInfo of the objective from the metadata the store recorded about it
+func ConstructObjectiveInfo(o protocols.Objective, metadata store.ObjectiveMetadata) (ObjectiveInfo, error) {
+	objectiveType, err := GetObjectiveType(o)
+	if err != nil {
+		return ObjectiveInfo{}, err
+	}
+
+	return ObjectiveInfo{
+		Id:            o.Id(),
+		Type:          objectiveType,
+		Status:        getObjectiveStatus(o, metadata),
+		WaitingFor:    metadata.WaitingFor,
+		OwnedChannel:  o.OwnsChannel(),
+		FailureReason: metadata.FailureReason,
+		CreatedAt:     metadata.CreatedAt,
+		UpdatedAt:     metadata.UpdatedAt,
+	}, nil
+}
+
+// GetObjectiveInfo returns the ObjectiveInfo for the objective with the given id
+func GetObjectiveInfo(id protocols.ObjectiveId, store store.Store) (ObjectiveInfo, error) {
+	o, err := store.GetObjectiveById(id)
+	if err != nil {
+		return ObjectiveInfo{}, err
+	}
+
+	metadata, _ := store.GetObjectiveMetadata(id)
+	return ConstructObjectiveInfo(o, metadata)
+}
+
+// GetAllObjectiveInfo returns the ObjectiveInfo of every objective with the given status, ordered by creation time.
+// Objectives of any status are returned if the status is empty.
+func GetAllObjectiveInfo(status ObjectiveStatus, store store.Store) ([]ObjectiveInfo, error) {
+	objectives, err := store.GetAllObjectives()
+	if err != nil {
+		return []ObjectiveInfo{}, err
+	}
+
+	toReturn := []ObjectiveInfo{}
+	for _, o := range objectives {
+		metadata, _ := store.GetObjectiveMetadata(o.Id())
+		info, err := ConstructObjectiveInfo(o, metadata)
+		if err != nil {
+			return []ObjectiveInfo{}, err
+		}
+
+		if status != "" && info.Status != status {
+			continue
+		}
+		toReturn = append(toReturn, info)
+	}
+
+	sort.SliceStable(toReturn, func(i, j int) bool {
+		if toReturn[i].CreatedAt.Equal(toReturn[j].CreatedAt) {
+			return toReturn[i].Id < toReturn[j].Id
+		}
+		return toReturn[i].CreatedAt.Before(toReturn[j].CreatedAt)
+	})
+
+	return toReturn, nil
+}
+
+// ConstructSwapRecordInfo returns the SwapRecordInfo of the swap record
+func ConstructSwapRecordInfo(r store.SwapRecord) SwapRecordInfo {
+	status := SwapRejected
+	switch {
+	case r.Status == types.Accepted:
+		status = SwapAccepted
+	case r.End == store.SwapEndCancelled:
+		status = SwapCancelled
+	case r.End == store.SwapEndAbandoned:
+		status = SwapAbandoned
+	}
+
+	return SwapRecordInfo{
+		Id:           r.Swap.Id,
+		ChannelId:    r.Swap.ChannelId,
+		Status:       status,
+		Counterparty: r.Counterparty,
+		IsSender:     r.IsSender,
+		TokenIn:      r.Swap.Exchange.TokenIn,
+		TokenOut:     r.Swap.Exchange.TokenOut,
+		AmountIn:     (*hexutil.Big)(r.Swap.Exchange.AmountIn),
+		AmountOut:    (*hexutil.Big)(r.Swap.Exchange.AmountOut),
+		Nonce:        r.Swap.Nonce,
+		RecordedAt:   r.RecordedAt,
+	}
+}
+
+// GetSwapHistory returns the swaps matching the filter ordered by the time they were recorded, skipping the first offset swaps
+// and returning at most limit swaps. Every remaining swap is returned if the limit is zero.
+func GetSwapHistory(filter SwapFilter, offset, limit uint, store store.Store) (SwapHistory, error) {
+	// The store selects the records of the channel and times, in the order they were recorded
+	records, err := store.GetSwapRecords(filter.ChannelId, filter.From, filter.To)
+	if err != nil {
+		return SwapHistory{}, err
+	}
+
+	matching := []SwapRecordInfo{}
+	for _, r := range records {
+		info := ConstructSwapRecordInfo(r)
+		if filter.matches(info) {
+			matching = append(matching, info)
+		}
+	}
+
+	total := uint(len(matching))
+	start := min(offset, total)
+	end := total
+	if limit != 0 {
+		end = min(start+limit, total)
+	}
+
+	return SwapHistory{Swaps: matching[start:end], Total: total}, nil
+}
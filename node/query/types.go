@@ -1,8 +1,12 @@
 package query
 
 import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/protocols"
 	"github.com/statechannels/go-nitro/types"
 )
 
@@ -59,6 +63,114 @@ type SwapInfo struct {
 	ChannelId types.Destination
 }
 
+type SwapRecordStatus string
+
+const (
+	SwapAccepted  SwapRecordStatus = "Accepted"
+	SwapRejected  SwapRecordStatus = "Rejected"
+	SwapCancelled SwapRecordStatus = "Cancelled" // cancelled by us, or expired, before the counterparty accepted it
+	SwapAbandoned SwapRecordStatus = "Abandoned" // abandoned because the swap, or the route it is a leg of, failed or timed out
+)
+
+// SwapRecordInfo contains the details of a swap which has come to an end
+type SwapRecordInfo struct {
+	Id           types.Destination
+	ChannelId    types.Destination
+	Status       SwapRecordStatus
+	Counterparty types.Address
+	// IsSender is true if the swap was initiated by us
+	IsSender   bool
+	TokenIn    common.Address
+	TokenOut   common.Address
+	AmountIn   *hexutil.Big
+	AmountOut  *hexutil.Big
+	Nonce      uint64
+	RecordedAt time.Time
+}
+
+// SwapFilter selects swap records. Fields which are not set match every swap.
+type SwapFilter struct {
+	ChannelId types.Destination
+	// Token matches swaps which exchange the token in either direction
+	Token *common.Address
+	// From (inclusive) and To (exclusive) bound the time at which the swap was recorded
+	From time.Time
+	To   time.Time
+}
+
+// matches returns true if the swap record is selected by the filter
+func (f SwapFilter) matches(r SwapRecordInfo) bool {
+	if !f.ChannelId.IsZero() && r.ChannelId != f.ChannelId {
+		return false
+	}
+	if f.Token != nil && r.TokenIn != *f.Token && r.TokenOut != *f.Token {
+		return false
+	}
+	if !f.From.IsZero() && r.RecordedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !r.RecordedAt.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// SwapHistory is a page of the swaps matching a SwapFilter
+type SwapHistory struct {
+	Swaps []SwapRecordInfo
+	// Total is the number of swaps matching the filter across all pages
+	Total uint
+}
+
+// FailedObjectiveInfo identifies an objective which failed, and why it failed
+type FailedObjectiveInfo struct {
+	Id     protocols.ObjectiveId
+	Reason string
+}
+
+type ObjectiveType string
+
+const (
+	DirectFund          ObjectiveType = "directfund"
+	DirectDefund        ObjectiveType = "directdefund"
+	VirtualFund         ObjectiveType = "virtualfund"
+	VirtualDefund       ObjectiveType = "virtualdefund"
+	SwapFund            ObjectiveType = "swapfund"
+	SwapDefund          ObjectiveType = "swapdefund"
+	Swap                ObjectiveType = "swap"
+	BridgedFund         ObjectiveType = "bridgedfund"
+	BridgedDefund       ObjectiveType = "bridgeddefund"
+	MirrorBridgedDefund ObjectiveType = "mirrorbridgeddefund"
+	LedgerTopUp         ObjectiveType = "ledgertopup"
+	LedgerWithdraw      ObjectiveType = "ledgerwithdraw"
+	Rebalance           ObjectiveType = "rebalance"
+	MultiSwap           ObjectiveType = "multiswap"
+)
+
+type ObjectiveStatus string
+
+const (
+	ObjectiveUnapproved ObjectiveStatus = "Unapproved"
+	ObjectiveApproved   ObjectiveStatus = "Approved"
+	ObjectiveRejected   ObjectiveStatus = "Rejected"
+	ObjectiveCompleted  ObjectiveStatus = "Completed"
+	ObjectiveFailed     ObjectiveStatus = "Failed"
+)
+
+// ObjectiveInfo contains the status of an objective, and what it is waiting for if it has not completed
+type ObjectiveInfo struct {
+	Id     protocols.ObjectiveId
+	Type   ObjectiveType
+	Status ObjectiveStatus
+	// WaitingFor is the point the objective paused at after it was last cranked
+	WaitingFor   protocols.WaitingFor
+	OwnedChannel types.Destination
+	// FailureReason is set if the objective has failed
+	FailureReason string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
 // LedgerChannelBalance contains the balance of a ledger channel
 type LedgerChannelBalance struct {
 	AssetAddress types.Address
@@ -108,15 +108,22 @@ func TestBridgeFlow(t *testing.T) {
 	bridgeConfig := bridge.BridgeConfig{
 		L1ChainUrl:        infraL1.anvilChain.ChainUrl,
 		L1ChainStartBlock: 0,
+		L2ChainUrl:        infraL2.laconicdChain.ChainUrl,
+		L2ChainStartBlock: 0,
 		ChainPK:           infraL1.anvilChain.ChainPks[tcL1.Participants[1].ChainAccountIndex],
 		StateChannelPK:    common.Bytes2Hex(tcL1.Participants[1].PrivateKey),
 		NaAddress:         infraL1.anvilChain.ContractAddresses.NaAddress.String(),
 		VpaAddress:        infraL1.anvilChain.ContractAddresses.VpaAddress.String(),
 		CaAddress:         infraL1.anvilChain.ContractAddresses.CaAddress.String(),
+		L2ChainPK:         infraL2.laconicdChain.ChainPks[tcL2.Participants[1].ChainAccountIndex],
+		L2NaAddress:       infraL2.laconicdChain.ContractAddresses.NaAddress.String(),
+		L2VpaAddress:      infraL2.laconicdChain.ContractAddresses.VpaAddress.String(),
+		L2CaAddress:       infraL2.laconicdChain.ContractAddresses.CaAddress.String(),
 		DurableStoreDir:   dataFolder,
 		BridgePublicIp:    DEFAULT_PUBLIC_IP,
 		NodeL1MsgPort:     int(tcL1.Participants[1].Port),
 		NodeL2MsgPort:     int(tcL2.Participants[1].Port),
+		Assets:            bridgeAssetMap(infraL1, infraL2),
 	}
 
 	nodeAChainservice, err := chainservice.NewEthChainService(chainservice.ChainOpts{
@@ -133,8 +140,13 @@ func TestBridgeFlow(t *testing.T) {
 	}
 
 	nodeAPrimeChainservice, err := chainservice.NewLaconicdChainService(chainservice.LaconicdChainOpts{
-		VpaAddress: infraL1.anvilChain.ContractAddresses.VpaAddress,
-		CaAddress:  infraL1.anvilChain.ContractAddresses.CaAddress,
+		ChainUrl:           infraL2.laconicdChain.ChainUrl,
+		ChainStartBlockNum: 0,
+		ChainAuthToken:     infraL2.laconicdChain.ChainAuthToken,
+		ChainPk:            infraL2.laconicdChain.ChainPks[tcL2.Participants[0].ChainAccountIndex],
+		NaAddress:          infraL2.laconicdChain.ContractAddresses.NaAddress,
+		VpaAddress:         infraL2.laconicdChain.ContractAddresses.VpaAddress,
+		CaAddress:          infraL2.laconicdChain.ContractAddresses.CaAddress,
 	})
 
 	bridgeClient, nodeL1MultiAddress, nodeL2MultiAddress, cleanUp := setupBridgeWithRPCClient(t, bridgeConfig)
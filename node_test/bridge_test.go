@@ -875,15 +875,22 @@ func initializeUtilsWithBridge(t *testing.T, closeBridge bool) (UtilsWithBridge,
 	bridgeConfig := bridge.BridgeConfig{
 		L1ChainUrl:        infraL1.anvilChain.ChainUrl,
 		L1ChainStartBlock: 0,
+		L2ChainUrl:        infraL2.laconicdChain.ChainUrl,
+		L2ChainStartBlock: 0,
 		ChainPK:           infraL1.anvilChain.ChainPks[tcL1.Participants[1].ChainAccountIndex],
 		StateChannelPK:    common.Bytes2Hex(tcL1.Participants[1].PrivateKey),
 		NaAddress:         infraL1.anvilChain.ContractAddresses.NaAddress.String(),
 		VpaAddress:        infraL1.anvilChain.ContractAddresses.VpaAddress.String(),
 		CaAddress:         infraL1.anvilChain.ContractAddresses.CaAddress.String(),
+		L2ChainPK:         infraL2.laconicdChain.ChainPks[tcL2.Participants[0].ChainAccountIndex],
+		L2NaAddress:       infraL2.laconicdChain.ContractAddresses.NaAddress.String(),
+		L2VpaAddress:      infraL2.laconicdChain.ContractAddresses.VpaAddress.String(),
+		L2CaAddress:       infraL2.laconicdChain.ContractAddresses.CaAddress.String(),
 		DurableStoreDir:   dataFolder,
 		BridgePublicIp:    DEFAULT_PUBLIC_IP,
 		NodeL1MsgPort:     int(tcL1.Participants[1].Port),
 		NodeL2MsgPort:     int(tcL2.Participants[0].Port),
+		Assets:            bridgeAssetMap(infraL1, infraL2),
 	}
 
 	bridge := bridge.New()
@@ -932,6 +939,19 @@ func initializeUtilsWithBridge(t *testing.T, closeBridge bool) (UtilsWithBridge,
 	return utils, cleanupUtilsWithBridge
 }
 
+// bridgeAssetMap maps the tokens deployed on L1 to the tokens deployed on L2
+func bridgeAssetMap(infraL1, infraL2 sharedTestInfrastructure) []bridge.AssetMapEntry {
+	assets := []bridge.AssetMapEntry{}
+	for i, l1TokenAddress := range infraL1.anvilChain.ContractAddresses.TokenAddresses {
+		assets = append(assets, bridge.AssetMapEntry{
+			L1AssetAddress: l1TokenAddress,
+			L2AssetAddress: infraL2.laconicdChain.ContractAddresses.TokenAddresses[i],
+		})
+	}
+
+	return assets
+}
+
 func createMirrorChannel(t *testing.T, node node.Node, bridge *bridge.Bridge, challengeDuration uint, l1AssetAddress common.Address) (types.Destination, types.Destination) {
 	outcome := CreateLedgerOutcome(*node.Address, bridge.GetBridgeAddress(), ledgerChannelDeposit, 0, l1AssetAddress)
 	l1LedgerChannelResponse, err := node.CreateLedgerChannel(bridge.GetBridgeAddress(), uint32(challengeDuration), outcome)
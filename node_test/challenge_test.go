@@ -14,6 +14,7 @@ import (
 	"github.com/statechannels/go-nitro/internal/testactors"
 	"github.com/statechannels/go-nitro/internal/testhelpers"
 	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
 	"github.com/statechannels/go-nitro/node/engine/chainservice"
 	NitroAdjudicator "github.com/statechannels/go-nitro/node/engine/chainservice/adjudicator"
 	Token "github.com/statechannels/go-nitro/node/engine/chainservice/erc20"
@@ -107,6 +108,8 @@ func TestCheckpoint(t *testing.T) {
 		ChallengeDuration: 10,
 		MessageDelay:      0,
 		LogName:           "Checkpoint_test",
+		// Bob counters the challenge through the API rather than automatically
+		EngineOpts: engine.EngineOpts{AutoCounterChallenge: map[types.ChannelType]bool{types.Ledger: false}},
 		Participants: []TestParticipant{
 			{StoreType: MemStore, Actor: testactors.Alice},
 			{StoreType: MemStore, Actor: testactors.Bob},
@@ -226,6 +229,8 @@ func TestCounterChallenge(t *testing.T) {
 		ChallengeDuration: 10,
 		MessageDelay:      0,
 		LogName:           "Counter_challenge_test",
+		// Bob counters the challenge through the API rather than automatically
+		EngineOpts: engine.EngineOpts{AutoCounterChallenge: map[types.ChannelType]bool{types.Ledger: false}},
 		Participants: []TestParticipant{
 			{StoreType: MemStore, Actor: testactors.Alice},
 			{StoreType: MemStore, Actor: testactors.Bob},
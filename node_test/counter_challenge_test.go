@@ -0,0 +1,104 @@
+package node_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	ta "github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/node/engine/messageservice"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestAutomaticCounterChallenge(t *testing.T) {
+	t.Run("a stale challenge is countered automatically", func(t *testing.T) {
+		testStaleChallenge(t, false)
+	})
+	t.Run("a stale challenge is left to be countered manually", func(t *testing.T) {
+		testStaleChallenge(t, true)
+	})
+}
+
+// testStaleChallenge has Alice challenge her ledger channel with Bob using an old state, and checks that Bob's node
+// clears the challenge with a checkpoint, either automatically or when asked to through the API
+func testStaleChallenge(t *testing.T, counterManually bool) {
+	sim, bindings, ethAccounts, err := chainservice.SetupSimulatedBackend(2)
+	defer closeSimulatedChain(t, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broker := messageservice.NewBroker()
+	setupSimulatedChainNode := func(actor ta.Actor, accountIndex int, opts engine.EngineOpts) (node.Node, store.Store) {
+		cs, err := chainservice.NewSimulatedBackendChainService(sim, bindings, ethAccounts[accountIndex])
+		if err != nil {
+			t.Fatal(err)
+		}
+		ms := messageservice.NewTestMessageService(actor.Address(), broker, 0)
+		s := store.NewMemStore(actor.PrivateKey)
+		n, err := node.New(ms, cs, s, &engine.PermissivePolicy{}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n, s
+	}
+
+	alice, aliceStore := setupSimulatedChainNode(ta.Alice, 0, engine.EngineOpts{})
+	defer closeNode(t, &alice)
+	bobOpts := engine.EngineOpts{}
+	if counterManually {
+		bobOpts.AutoCounterChallenge = map[types.ChannelType]bool{types.Ledger: false}
+	}
+	bob, _ := setupSimulatedChainNode(ta.Bob, 1, bobOpts)
+	defer closeNode(t, &bob)
+
+	asset := types.Address{}
+	ledgerId := openLedgerChannel(t, alice, bob, asset, 1000)
+
+	staleLedger, err := aliceStore.GetConsensusChannelById(ledgerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The ledger channel moves on from the state which Alice challenges with
+	topUpResponse, err := alice.TopUpLedgerChannel(ledgerId, asset, big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForObjectives(t, alice, bob, []node.Node{}, []protocols.ObjectiveId{topUpResponse.Id})
+
+	// Alice performs a direct defund with a challenge using the old state
+	err = aliceStore.SetConsensusChannel(staleLedger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledgerUpdates := bob.LedgerUpdatedChan(ledgerId)
+	defundId, err := alice.CloseLedgerChannel(ledgerId, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if counterManually {
+		listenForLedgerUpdates(ledgerUpdates, channel.Challenge)
+		err = bob.CounterChallenge(ledgerId, types.Checkpoint, state.SignedState{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitForObjectives(t, alice, bob, []node.Node{}, []protocols.ObjectiveId{defundId})
+
+	ledgerInfo, err := bob.GetLedgerChannel(ledgerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ledgerInfo.ChannelMode != channel.Open {
+		t.Fatalf("expected the ledger channel to be open again, got mode %v", ledgerInfo.ChannelMode)
+	}
+}
@@ -49,7 +49,10 @@ func TestCrashTolerance(t *testing.T) {
 		t.Fatal(err)
 	}
 	messageserviceA := messageservice.NewTestMessageService(ta.Alice.Address(), broker, 0)
-	nodeA := node.New(messageserviceA, chainA, storeA, &engine.PermissivePolicy{})
+	nodeA, err := node.New(messageserviceA, chainA, storeA, &engine.PermissivePolicy{}, engine.EngineOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	nodeB, _ := setupNode(ta.Bob.PrivateKey, chainB, broker, 0, dataFolder)
 	defer closeNode(t, &nodeB)
@@ -72,10 +75,13 @@ func TestCrashTolerance(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		anotherClientA := node.New(
+		anotherClientA, err := node.New(
 			anotherMessageserviceA,
 			anotherChainA,
-			anotherStoreA, &engine.PermissivePolicy{})
+			anotherStoreA, &engine.PermissivePolicy{}, engine.EngineOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
 		defer closeNode(t, &anotherClientA)
 
 		closeLedgerChannel(t, anotherClientA, nodeB, channelId)
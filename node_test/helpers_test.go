@@ -1,7 +1,6 @@
 package node_test
 
 import (
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math/big"
@@ -16,7 +15,6 @@ import (
 	"github.com/statechannels/go-nitro/channel/state/outcome"
 	"github.com/statechannels/go-nitro/crypto"
 	"github.com/statechannels/go-nitro/internal/logging"
-	"github.com/statechannels/go-nitro/internal/testactors"
 	"github.com/statechannels/go-nitro/internal/testdata"
 	"github.com/statechannels/go-nitro/node"
 	"github.com/statechannels/go-nitro/node/engine"
@@ -41,7 +39,11 @@ func setupNode(pk []byte, chain chainservice.ChainService, msgBroker messageserv
 	if err != nil {
 		panic(err)
 	}
-	return node.New(messageservice, chain, store, &engine.PermissivePolicy{}), store
+	n, err := node.New(messageservice, chain, store, &engine.PermissivePolicy{}, engine.EngineOpts{})
+	if err != nil {
+		panic(err)
+	}
+	return n, store
 }
 
 func closeNode(t *testing.T, node *node.Node) {
@@ -116,8 +118,13 @@ func setupChainService(tc TestCase, tp TestParticipant, si sharedTestInfrastruct
 	case LaconicdChain:
 		cs, err := chainservice.NewLaconicdChainService(
 			chainservice.LaconicdChainOpts{
-				VpaAddress: si.laconicdChain.ContractAddresses.VpaAddress,
-				CaAddress:  si.laconicdChain.ContractAddresses.CaAddress,
+				ChainUrl:           si.laconicdChain.ChainUrl,
+				ChainStartBlockNum: 0,
+				ChainAuthToken:     si.laconicdChain.ChainAuthToken,
+				ChainPk:            si.laconicdChain.ChainPks[tp.ChainAccountIndex],
+				NaAddress:          si.laconicdChain.ContractAddresses.NaAddress,
+				VpaAddress:         si.laconicdChain.ContractAddresses.VpaAddress,
+				CaAddress:          si.laconicdChain.ContractAddresses.CaAddress,
 			})
 		if err != nil {
 			panic(err)
@@ -149,7 +156,10 @@ func setupIntegrationNode(tc TestCase, tp TestParticipant, si sharedTestInfrastr
 	messageService, multiAddr := setupMessageService(tc, tp, si, bootPeers)
 	cs := setupChainService(tc, tp, si)
 	store := setupStore(tc, tp, si, dataFolder)
-	n := node.New(messageService, cs, store, &engine.PermissivePolicy{})
+	n, err := node.New(messageService, cs, store, &engine.PermissivePolicy{}, tc.EngineOpts)
+	if err != nil {
+		panic(err)
+	}
 	return n, messageService, multiAddr, store, cs
 }
 
@@ -157,19 +167,11 @@ func CreateLedgerOutcome(alpha, beta types.Address, ledgerDepositAlpha, ledgerDe
 	return testdata.Outcomes.Create(alpha, beta, ledgerDepositAlpha, ledgerDepositBeta, asset)
 }
 
-func finalAliceLedger(intermediary, asset types.Address, numPayments, paymentAmount, numChannels uint) outcome.Exit {
-	return testdata.Outcomes.Create(
-		testactors.Alice.Address(),
-		intermediary,
-		uint64(ledgerChannelDeposit-(numPayments*paymentAmount*numChannels)),
-		uint64(ledgerChannelDeposit+(numPayments*paymentAmount*numChannels)),
-		asset)
-}
-
-func finalBobLedger(intermediary, asset types.Address, numPayments, paymentAmount, numChannels uint) outcome.Exit {
+// finalLedger returns the outcome of a ledger channel along the path from Alice to Bob, once the payments through it have been settled
+func finalLedger(left, right, asset types.Address, numPayments, paymentAmount, numChannels uint) outcome.Exit {
 	return testdata.Outcomes.Create(
-		intermediary,
-		testactors.Bob.Address(),
+		left,
+		right,
 		uint64(ledgerChannelDeposit-(numPayments*paymentAmount*numChannels)),
 		uint64(ledgerChannelDeposit+(numPayments*paymentAmount*numChannels)),
 		asset)
@@ -286,7 +288,19 @@ func setupSharedInfra(tc TestCase) sharedTestInfrastructure {
 		}
 		infra.anvilChain = chain
 	case LaconicdChain:
-		infra.laconicdChain = chainutils.LaconicdChain{}
+		// Laconicd exposes an Ethereum compatible json-rpc endpoint, so an anvil chain stands in for it
+		ethAccountIndex := tc.Participants[tc.deployerIndex].ChainAccountIndex
+		chain, err := chainservice.NewAnvilChain(tc.ChainPort, false, ethAccountIndex)
+		if err != nil {
+			panic(err)
+		}
+		infra.anvilChain = chain
+		infra.laconicdChain = chainutils.LaconicdChain{
+			ChainUrl:          chain.ChainUrl,
+			ChainAuthToken:    chain.ChainAuthToken,
+			ChainPks:          chain.ChainPks,
+			ContractAddresses: chain.ContractAddresses,
+		}
 	default:
 		panic("Unknown chain service")
 	}
@@ -481,16 +495,12 @@ func createSwapChInfo(id types.Destination, outcome outcome.Exit, status query.C
 func checkSwapChannel(t *testing.T, swapChannelId types.Destination, o outcome.Exit, status query.ChannelStatus, clients ...node.Node) {
 	for _, c := range clients {
 		expected := createSwapChInfo(swapChannelId, o, status, *c.Address)
-		marshalledExpected, err := json.Marshal(expected)
-		if err != nil {
-			t.Fatal(err)
-		}
 
 		swap, err := c.GetSwapChannel(swapChannelId)
 		if err != nil {
 			t.Fatal(err)
 		}
-		if diff := cmp.Diff(string(marshalledExpected), swap, cmp.AllowUnexported(big.Int{})); diff != "" {
+		if diff := cmp.Diff(expected, swap, cmp.AllowUnexported(big.Int{})); diff != "" {
 			t.Errorf("swap diff mismatch (-want +got):\n%s", diff)
 		}
 	}
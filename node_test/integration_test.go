@@ -60,6 +60,27 @@ func TestComplexIntegrationScenario(t *testing.T) {
 	RunIntegrationTestCase(complexCase, t)
 }
 
+func TestMultiHopIntegrationScenario(t *testing.T) {
+	multiHopCase := TestCase{
+		Description:    "Multi-hop test",
+		Chain:          MockChain,
+		MessageService: TestMessageService,
+		NumOfChannels:  3,
+		MessageDelay:   20 * time.Millisecond,
+		LogName:        "multi_hop_integration",
+		NumOfHops:      3,
+		NumOfPayments:  2,
+		Participants: []TestParticipant{
+			{StoreType: MemStore, Actor: testactors.Alice},
+			{StoreType: MemStore, Actor: testactors.Bob},
+			{StoreType: MemStore, Actor: testactors.Irene},
+			{StoreType: MemStore, Actor: testactors.Ivan},
+			{StoreType: MemStore, Actor: testactors.Brian},
+		},
+	}
+	RunIntegrationTestCase(multiHopCase, t)
+}
+
 // RunIntegrationTestCase runs the integration test case.
 func RunIntegrationTestCase(tc TestCase, t *testing.T) {
 	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
@@ -114,26 +135,15 @@ func RunIntegrationTestCase(tc TestCase, t *testing.T) {
 		}
 
 		asset := common.Address{}
-		// Setup ledger channels between Alice/Bob and intermediaries
-		aliceLedgers := make([]types.Destination, tc.NumOfHops)
-		bobLedgers := make([]types.Destination, tc.NumOfHops)
-		for i, clientI := range intermediaries {
-			t.Log("DEBUG: Setting up ledger channel between Alice/Bob and intermediaries, intermediary number", i)
-			// Setup and check the ledger channel between Alice and the intermediary
-			aliceLedgers[i] = openLedgerChannel(t, clientA, clientI, asset, 0)
-			checkLedgerChannel(t, aliceLedgers[i], CreateLedgerOutcome(*clientA.Address, *clientI.Address, ledgerChannelDeposit, ledgerChannelDeposit, asset), query.Open, channel.Open, clientA)
-			// Setup and check the ledger channel between Bob and the intermediary
-			bobLedgers[i] = openLedgerChannel(t, clientI, clientB, asset, 0)
-			checkLedgerChannel(t, bobLedgers[i], CreateLedgerOutcome(*clientI.Address, *clientB.Address, ledgerChannelDeposit, ledgerChannelDeposit, asset), query.Open, channel.Open, clientB)
-
+		// Setup a ledger channel between each adjacent pair of participants on the path from Alice to Bob
+		path := append(append([]node.Node{clientA}, intermediaries...), clientB)
+		ledgers := make([]types.Destination, len(path)-1)
+		for i := range ledgers {
+			ledgers[i] = openLedgerChannel(t, path[i], path[i+1], asset, 0)
+			checkLedgerChannel(t, ledgers[i], CreateLedgerOutcome(*path[i].Address, *path[i+1].Address, ledgerChannelDeposit, ledgerChannelDeposit, asset), query.Open, channel.Open, path[i], path[i+1])
 		}
 
-		t.Log("DEBUG: After setting up ledger channels between Alice/Bob and intermediaries")
-
-		if tc.NumOfHops == 2 {
-			openLedgerChannel(t, intermediaries[0], intermediaries[1], asset, 0)
-			t.Log("DEBUG: After setting up ledger channels when NumOfHops is 2")
-		}
+		t.Log("DEBUG: After setting up ledger channels along the path")
 		// Setup virtual channels
 		objectiveIds := make([]protocols.ObjectiveId, tc.NumOfChannels)
 		virtualIds := make([]types.Destination, tc.NumOfChannels)
@@ -224,20 +234,10 @@ func RunIntegrationTestCase(tc TestCase, t *testing.T) {
 			channelMode = channel.Finalized
 		}
 
-		closeLedgerChannel(t, clientA, intermediaries[0], aliceLedgers[0])
-		checkLedgerChannel(t, aliceLedgers[0], finalAliceLedger(*intermediaries[0].Address, asset, tc.NumOfPayments, 1, tc.NumOfChannels), query.Complete, channelMode, clientA)
-		t.Log("DEBUG: After closing first alice ledger Channel")
-
-		// TODO: This is brittle, we should generalize this to n number of intermediaries
-		if tc.NumOfHops == 1 {
-			closeLedgerChannel(t, intermediaries[0], clientB, bobLedgers[0])
-			checkLedgerChannel(t, bobLedgers[0], finalBobLedger(*intermediaries[0].Address, asset, tc.NumOfPayments, 1, tc.NumOfChannels), query.Complete, channelMode, clientB)
-			t.Log("DEBUG: After closing ledger channel when NumOfHops is 1")
-		}
-		if tc.NumOfHops == 2 {
-			closeLedgerChannel(t, intermediaries[1], clientB, bobLedgers[1])
-			checkLedgerChannel(t, bobLedgers[1], finalBobLedger(*intermediaries[1].Address, asset, tc.NumOfPayments, 1, tc.NumOfChannels), query.Complete, channelMode, clientB)
-			t.Log("DEBUG: After closing ledger channel when NumOfHops is 2")
+		// Every ledger along the path has passed the payments on to its right participant
+		for i, ledger := range ledgers {
+			closeLedgerChannel(t, path[i], path[i+1], ledger)
+			checkLedgerChannel(t, ledger, finalLedger(*path[i].Address, *path[i+1].Address, asset, tc.NumOfPayments, 1, tc.NumOfChannels), query.Complete, channelMode, path[i])
 		}
 
 		t.Log("DEBUG: After closing all ledger channels")
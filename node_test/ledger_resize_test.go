@@ -0,0 +1,138 @@
+package node_test // import "github.com/statechannels/go-nitro/node_test"
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	ta "github.com/statechannels/go-nitro/internal/testactors"
+	td "github.com/statechannels/go-nitro/internal/testdata"
+	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/node/engine/messageservice"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/node/query"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestTopUpAndWithdrawFromLedgerChannel(t *testing.T) {
+	chain := chainservice.NewMockChain()
+	broker := messageservice.NewBroker()
+	asset := common.Address{}
+
+	setupMockChainNode := func(actor ta.Actor) node.Node {
+		ms := messageservice.NewTestMessageService(actor.Address(), broker, 0)
+		cs := chainservice.NewMockChainService(chain, actor.Address())
+		n, err := node.New(ms, cs, store.NewMemStore(actor.PrivateKey), &engine.PermissivePolicy{}, engine.EngineOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	alice := setupMockChainNode(ta.Alice)
+	irene := setupMockChainNode(ta.Irene)
+	bob := setupMockChainNode(ta.Bob)
+	for _, n := range []*node.Node{&alice, &irene, &bob} {
+		defer closeNode(t, n)
+	}
+
+	aliceIrene := openLedgerChannel(t, alice, irene, asset, 0)
+	ireneBob := openLedgerChannel(t, irene, bob, asset, 0)
+
+	const topUp, withdrawal, payment = 50, 30, 5
+
+	// Irene tops up her ledger channel with Alice while Alice funds a virtual channel through it
+	topUpResponse, err := irene.TopUpLedgerChannel(aliceIrene, asset, big.NewInt(topUp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	virtualResponse, err := alice.CreatePaymentChannel([]types.Address{*irene.Address}, *bob.Address, 0, td.Outcomes.Create(*alice.Address, *bob.Address, virtualChannelDeposit, 0, asset))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Subscribe to both objectives up front, since either may complete while we wait for the other
+	completions := []<-chan struct{}{
+		alice.ObjectiveCompleteChan(topUpResponse.Id),
+		irene.ObjectiveCompleteChan(topUpResponse.Id),
+		alice.ObjectiveCompleteChan(virtualResponse.Id),
+		irene.ObjectiveCompleteChan(virtualResponse.Id),
+		bob.ObjectiveCompleteChan(virtualResponse.Id),
+	}
+	for _, completed := range completions {
+		<-completed
+	}
+
+	// Bob withdraws from his ledger channel with Irene, which keeps funding the virtual channel
+	withdrawResponse, err := bob.WithdrawFromLedgerChannel(ireneBob, asset, big.NewInt(withdrawal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForObjectives(t, irene, bob, []node.Node{}, []protocols.ObjectiveId{withdrawResponse.Id})
+
+	if _, err := bob.WithdrawFromLedgerChannel(withdrawResponse.SuccessorId, asset, big.NewInt(10*ledgerChannelDeposit)); err == nil {
+		t.Fatal("expected an error when withdrawing more than the balance")
+	}
+
+	err = alice.Pay(virtualResponse.ChannelId, big.NewInt(payment))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeId, err := alice.ClosePaymentChannel(virtualResponse.ChannelId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForObjectives(t, alice, bob, []node.Node{irene}, []protocols.ObjectiveId{closeId})
+
+	checkLedgerChannel(t, aliceIrene, CreateLedgerOutcome(*alice.Address, *irene.Address, ledgerChannelDeposit-payment, ledgerChannelDeposit+topUp+payment, asset), query.Open, channel.Open, alice, irene)
+	checkLedgerChannel(t, withdrawResponse.SuccessorId, CreateLedgerOutcome(*irene.Address, *bob.Address, ledgerChannelDeposit-payment, ledgerChannelDeposit-withdrawal+payment, asset), query.Open, channel.Open, irene, bob)
+}
+
+func TestDuplicateCloseLedgerChannel(t *testing.T) {
+	chain := chainservice.NewMockChain()
+	broker := messageservice.NewBroker()
+	asset := common.Address{}
+
+	setupMockChainNode := func(actor ta.Actor) node.Node {
+		ms := messageservice.NewTestMessageService(actor.Address(), broker, 0)
+		cs := chainservice.NewMockChainService(chain, actor.Address())
+		n, err := node.New(ms, cs, store.NewMemStore(actor.PrivateKey), &engine.PermissivePolicy{}, engine.EngineOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	alice := setupMockChainNode(ta.Alice)
+	bob := setupMockChainNode(ta.Bob)
+	for _, n := range []*node.Node{&alice, &bob} {
+		defer closeNode(t, n)
+	}
+
+	ledgerId := openLedgerChannel(t, alice, bob, asset, 0)
+
+	closeId, err := alice.CloseLedgerChannel(ledgerId, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completions := []<-chan struct{}{alice.ObjectiveCompleteChan(closeId), bob.ObjectiveCompleteChan(closeId)}
+
+	// The second request has the same objective id, and is rejected without failing the first
+	if _, err := alice.CloseLedgerChannel(ledgerId, false); err == nil {
+		t.Fatal("expected an error when closing a ledger channel twice")
+	}
+	info, err := alice.GetObjectiveInfo(closeId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.FailureReason != "" {
+		t.Fatalf("expected the first close to be unaffected, but it failed with: %s", info.FailureReason)
+	}
+
+	for _, completed := range completions {
+		<-completed
+	}
+}
@@ -0,0 +1,164 @@
+package node_test // import "github.com/statechannels/go-nitro/node_test"
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	ta "github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/node/engine/messageservice"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/node/query"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// refuseMultiSwapPolicy approves every objective other than a route of swaps
+type refuseMultiSwapPolicy struct{}
+
+func (p *refuseMultiSwapPolicy) ShouldApprove(o protocols.Objective) bool {
+	_, isMultiSwap := o.(*multiswap.Objective)
+	return o.GetStatus() == protocols.Unapproved && !isMultiSwap
+}
+
+// openMockSwapChannel funds a swap channel between alpha and beta in which each of them holds the amount of every asset
+func openMockSwapChannel(t *testing.T, alpha, beta node.Node, assets []common.Address, amount int64) (types.Destination, outcome.Exit) {
+	createMultiAssetLedgerChannel(t, alpha, beta, assets, 0)
+
+	o := outcome.Exit{}
+	for _, asset := range assets {
+		o = append(o, outcome.SingleAssetExit{
+			Asset: asset,
+			Allocations: outcome.Allocations{
+				{Destination: types.AddressToDestination(*alpha.Address), Amount: big.NewInt(amount)},
+				{Destination: types.AddressToDestination(*beta.Address), Amount: big.NewInt(amount)},
+			},
+		})
+	}
+
+	response, err := alpha.CreateSwapChannel(nil, *beta.Address, 0, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chB := beta.ObjectiveCompleteChan(response.Id)
+	<-alpha.ObjectiveCompleteChan(response.Id)
+	<-chB
+
+	return response.ChannelId, o
+}
+
+func TestSwapAlongRoute(t *testing.T) {
+	eth, token := common.Address{}, common.Address{1}
+	assets := []common.Address{eth, token}
+
+	setup := func(t *testing.T, bobPolicy engine.PolicyMaker) (alice, irene, bob node.Node) {
+		chain := chainservice.NewMockChain()
+		broker := messageservice.NewBroker()
+		setupMockChainNode := func(actor ta.Actor, policy engine.PolicyMaker) node.Node {
+			ms := messageservice.NewTestMessageService(actor.Address(), broker, 0)
+			cs := chainservice.NewMockChainService(chain, actor.Address())
+			n, err := node.New(ms, cs, store.NewMemStore(actor.PrivateKey), policy, engine.EngineOpts{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return n
+		}
+
+		alice = setupMockChainNode(ta.Alice, &engine.PermissivePolicy{})
+		irene = setupMockChainNode(ta.Irene, &engine.PermissivePolicy{})
+		bob = setupMockChainNode(ta.Bob, bobPolicy)
+		for _, n := range []*node.Node{&alice, &irene, &bob} {
+			t.Cleanup(func() { closeNode(t, n) })
+		}
+		return alice, irene, bob
+	}
+
+	// Alice is not connected to Bob, so Irene takes Alice's ether for Bob's tokens: Alice pays Irene 100 wei for 50 tokens,
+	// and Irene pays Bob 100 wei for 50 tokens.
+	route := func(aliceIrene, ireneBob types.Destination, alice, irene, bob node.Node) []multiswap.Leg {
+		return []multiswap.Leg{
+			multiswap.NewLeg(aliceIrene, *alice.Address, *irene.Address, eth, token, big.NewInt(100), big.NewInt(50), 1, 0),
+			multiswap.NewLeg(ireneBob, *irene.Address, *bob.Address, eth, token, big.NewInt(100), big.NewInt(50), 1, 0),
+		}
+	}
+
+	t.Run("every leg commits", func(t *testing.T) {
+		alice, irene, bob := setup(t, &engine.PermissivePolicy{})
+		aliceIrene, aliceIreneOutcome := openMockSwapChannel(t, alice, irene, assets, 1000)
+		ireneBob, ireneBobOutcome := openMockSwapChannel(t, irene, bob, assets, 1000)
+
+		legs := route(aliceIrene, ireneBob, alice, irene, bob)
+		response, err := alice.SwapAlongRoute(legs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(response.SwapIds) != 2 || response.SwapIds[1] != legs[1].Swap.Id {
+			t.Fatalf("unexpected swap ids %v", response.SwapIds)
+		}
+		waitForObjectives(t, alice, bob, []node.Node{irene}, []protocols.ObjectiveId{response.Id})
+
+		checkSwapChannel(t, aliceIrene, modifyOutcomeWithSwap(aliceIreneOutcome, &legs[0].Swap, 0), query.Open, alice, irene)
+		checkSwapChannel(t, ireneBob, modifyOutcomeWithSwap(ireneBobOutcome, &legs[1].Swap, 0), query.Open, irene, bob)
+
+		// Irene took part in both legs, and records them like any other swap
+		history, err := irene.ListSwaps(query.SwapFilter{}, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if history.Total != 2 {
+			t.Fatalf("expected Irene to record 2 swaps, got %d", history.Total)
+		}
+		for _, s := range history.Swaps {
+			if s.Status != query.SwapAccepted || s.IsSender != (s.Id == legs[1].Swap.Id) {
+				t.Fatalf("unexpected swap record %+v", s)
+			}
+		}
+	})
+
+	t.Run("no leg commits if a party refuses", func(t *testing.T) {
+		alice, irene, bob := setup(t, &refuseMultiSwapPolicy{})
+		aliceIrene, aliceIreneOutcome := openMockSwapChannel(t, alice, irene, assets, 1000)
+		ireneBob, ireneBobOutcome := openMockSwapChannel(t, irene, bob, assets, 1000)
+
+		response, err := alice.SwapAlongRoute(route(aliceIrene, ireneBob, alice, irene, bob))
+		if err != nil {
+			t.Fatal(err)
+		}
+		waitForObjectives(t, alice, bob, []node.Node{irene}, []protocols.ObjectiveId{response.Id})
+
+		for _, n := range []node.Node{alice, irene, bob} {
+			info, err := n.GetObjectiveInfo(response.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Status != query.ObjectiveRejected {
+				t.Fatalf("expected the route to be rejected, got %s", info.Status)
+			}
+		}
+		checkSwapChannel(t, aliceIrene, aliceIreneOutcome, query.Open, alice, irene)
+		checkSwapChannel(t, ireneBob, ireneBobOutcome, query.Open, irene, bob)
+	})
+
+	t.Run("invalid routes", func(t *testing.T) {
+		alice, irene, bob := setup(t, &engine.PermissivePolicy{})
+		aliceIrene, _ := openMockSwapChannel(t, alice, irene, assets, 1000)
+		ireneBob, _ := openMockSwapChannel(t, irene, bob, assets, 1000)
+		legs := route(aliceIrene, ireneBob, alice, irene, bob)
+
+		if _, err := irene.SwapAlongRoute(legs); err == nil {
+			t.Fatal("expected an error when the first leg is not sent by us")
+		}
+		if _, err := alice.SwapAlongRoute(legs[:1]); err == nil {
+			t.Fatal("expected an error for a route with a single leg")
+		}
+		oversized := append([]multiswap.Leg{multiswap.NewLeg(aliceIrene, *alice.Address, *irene.Address, eth, token, big.NewInt(100), big.NewInt(5000), 1, 0)}, legs[1])
+		if _, err := alice.SwapAlongRoute(oversized); err == nil {
+			t.Fatal("expected an error when a leg exceeds the balance of its channel")
+		}
+	})
+}
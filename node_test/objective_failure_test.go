@@ -0,0 +1,121 @@
+package node_test // import "github.com/statechannels/go-nitro/node_test"
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	ta "github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/node/engine/messageservice"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/directfund"
+)
+
+// unreliableChainService fails to submit the first transactions it is given, as an RPC endpoint which is briefly unavailable would
+type unreliableChainService struct {
+	*chainservice.MockChainService
+	failures int
+}
+
+func (ucs *unreliableChainService) SendTransaction(tx protocols.ChainTransaction) (*ethTypes.Transaction, error) {
+	if ucs.failures > 0 {
+		ucs.failures--
+		return nil, errors.New("connection refused")
+	}
+	return ucs.MockChainService.SendTransaction(tx)
+}
+
+func waitForObjective(t *testing.T, completed <-chan struct{}) {
+	select {
+	case <-completed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the objective to complete")
+	}
+}
+
+func TestRetryTransactionWhichCouldNotBeSubmitted(t *testing.T) {
+	chain := chainservice.NewMockChain()
+	broker := messageservice.NewBroker()
+
+	setupMockChainNode := func(actor ta.Actor, cs chainservice.ChainService) node.Node {
+		ms := messageservice.NewTestMessageService(actor.Address(), broker, 0)
+		n, err := node.New(ms, cs, store.NewMemStore(actor.PrivateKey), &engine.PermissivePolicy{}, engine.EngineOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	alice := setupMockChainNode(ta.Alice, &unreliableChainService{MockChainService: chainservice.NewMockChainService(chain, ta.Alice.Address()), failures: 1})
+	bob := setupMockChainNode(ta.Bob, chainservice.NewMockChainService(chain, ta.Bob.Address()))
+	for _, n := range []*node.Node{&alice, &bob} {
+		defer closeNode(t, n)
+	}
+
+	outcome := CreateLedgerOutcome(*alice.Address, *bob.Address, ledgerChannelDeposit, ledgerChannelDeposit, common.Address{})
+	response, err := alice.CreateLedgerChannel(*bob.Address, 0, outcome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completions := []<-chan struct{}{alice.ObjectiveCompleteChan(response.Id), bob.ObjectiveCompleteChan(response.Id)}
+
+	// The deposit can be retried once its submission has failed
+	deadline := time.Now().Add(5 * time.Second)
+	for err = alice.RetryObjectiveTx(response.Id); err != nil; err = alice.RetryObjectiveTx(response.Id) {
+		if time.Now().After(deadline) {
+			t.Fatalf("could not retry the deposit: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, completed := range completions {
+		waitForObjective(t, completed)
+	}
+}
+
+func TestInvalidPayloadDoesNotFailObjective(t *testing.T) {
+	chain := chainservice.NewMockChain()
+	broker := messageservice.NewBroker()
+
+	setupMockChainNode := func(actor ta.Actor) node.Node {
+		ms := messageservice.NewTestMessageService(actor.Address(), broker, 0)
+		cs := chainservice.NewMockChainService(chain, actor.Address())
+		n, err := node.New(ms, cs, store.NewMemStore(actor.PrivateKey), &engine.PermissivePolicy{}, engine.EngineOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	alice := setupMockChainNode(ta.Alice)
+	bob := setupMockChainNode(ta.Bob)
+	for _, n := range []*node.Node{&alice, &bob} {
+		defer closeNode(t, n)
+	}
+	brian := messageservice.NewTestMessageService(ta.Brian.Address(), broker, 0)
+
+	outcome := CreateLedgerOutcome(*alice.Address, *bob.Address, ledgerChannelDeposit, ledgerChannelDeposit, common.Address{})
+	response, err := alice.CreateLedgerChannel(*bob.Address, 0, outcome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completions := []<-chan struct{}{alice.ObjectiveCompleteChan(response.Id), bob.ObjectiveCompleteChan(response.Id)}
+
+	// A third party sends Alice a payload for the objective which cannot be decoded
+	invalidPayload := protocols.ObjectivePayload{ObjectiveId: response.Id, PayloadData: []byte("not a signed state"), Type: directfund.SignedStatePayload}
+	err = brian.Send(protocols.Message{To: *alice.Address, From: ta.Brian.Address(), ObjectivePayloads: []protocols.ObjectivePayload{invalidPayload}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed objective would never complete
+	for _, completed := range completions {
+		waitForObjective(t, completed)
+	}
+}
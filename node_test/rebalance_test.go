@@ -0,0 +1,66 @@
+package node_test // import "github.com/statechannels/go-nitro/node_test"
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	ta "github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/node/engine/messageservice"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/node/query"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+func TestRebalanceLedgerChannels(t *testing.T) {
+	chain := chainservice.NewMockChain()
+	broker := messageservice.NewBroker()
+	asset := common.Address{}
+
+	setupMockChainNode := func(actor ta.Actor) node.Node {
+		ms := messageservice.NewTestMessageService(actor.Address(), broker, 0)
+		cs := chainservice.NewMockChainService(chain, actor.Address())
+		n, err := node.New(ms, cs, store.NewMemStore(actor.PrivateKey), &engine.PermissivePolicy{}, engine.EngineOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	alice := setupMockChainNode(ta.Alice)
+	irene := setupMockChainNode(ta.Irene)
+	bob := setupMockChainNode(ta.Bob)
+	ivan := setupMockChainNode(ta.Ivan)
+	for _, n := range []*node.Node{&alice, &irene, &bob, &ivan} {
+		defer closeNode(t, n)
+	}
+
+	// Irene and Ivan are both hubs for Alice and Bob
+	aliceIrene := openLedgerChannel(t, alice, irene, asset, 0)
+	ireneBob := openLedgerChannel(t, irene, bob, asset, 0)
+	ivanBob := openLedgerChannel(t, ivan, bob, asset, 0)
+	aliceIvan := openLedgerChannel(t, alice, ivan, asset, 0)
+
+	const amount = 40
+
+	if _, err := irene.RebalanceLedgerChannels(ireneBob, aliceIrene, []types.Address{*ivan.Address}, asset, big.NewInt(10*ledgerChannelDeposit)); err == nil {
+		t.Fatal("expected an error when rebalancing more than the balance")
+	}
+
+	// Irene moves funds from her ledger channel with Bob to her ledger channel with Alice: Irene pays Bob, who pays Ivan, who pays Alice, who pays Irene
+	response, err := irene.RebalanceLedgerChannels(ireneBob, aliceIrene, []types.Address{*ivan.Address}, asset, big.NewInt(amount))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForObjectives(t, irene, bob, []node.Node{ivan, alice}, []protocols.ObjectiveId{response.Id})
+
+	checkLedgerChannel(t, ireneBob, CreateLedgerOutcome(*irene.Address, *bob.Address, ledgerChannelDeposit-amount, ledgerChannelDeposit+amount, asset), query.Open, channel.Open, irene, bob)
+	checkLedgerChannel(t, ivanBob, CreateLedgerOutcome(*ivan.Address, *bob.Address, ledgerChannelDeposit+amount, ledgerChannelDeposit-amount, asset), query.Open, channel.Open, ivan, bob)
+	checkLedgerChannel(t, aliceIvan, CreateLedgerOutcome(*alice.Address, *ivan.Address, ledgerChannelDeposit+amount, ledgerChannelDeposit-amount, asset), query.Open, channel.Open, alice, ivan)
+	checkLedgerChannel(t, aliceIrene, CreateLedgerOutcome(*alice.Address, *irene.Address, ledgerChannelDeposit-amount, ledgerChannelDeposit+amount, asset), query.Open, channel.Open, alice, irene)
+}
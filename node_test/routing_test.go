@@ -0,0 +1,82 @@
+package node_test // import "github.com/statechannels/go-nitro/node_test"
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	ta "github.com/statechannels/go-nitro/internal/testactors"
+	td "github.com/statechannels/go-nitro/internal/testdata"
+	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/node/engine/messageservice"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/node/query"
+	"github.com/statechannels/go-nitro/protocols"
+)
+
+func TestCreatePaymentChannelAuto(t *testing.T) {
+	chain := chainservice.NewMockChain()
+	broker := messageservice.NewBroker()
+	opts := engine.EngineOpts{CapacityAnnouncementInterval: 50 * time.Millisecond}
+	asset := common.Address{}
+
+	setupRoutingNode := func(actor ta.Actor, flatFee int64) node.Node {
+		policy, err := engine.NewRulePolicy(engine.PolicyRules{IntermediaryFees: []engine.FeeSchedule{{Asset: asset, Flat: big.NewInt(flatFee)}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ms := messageservice.NewTestMessageService(actor.Address(), broker, 0)
+		cs := chainservice.NewMockChainService(chain, actor.Address())
+		n, err := node.New(ms, cs, store.NewMemStore(actor.PrivateKey), policy, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// Irene charges more than Ivan and Brian together
+	alice := setupRoutingNode(ta.Alice, 0)
+	bob := setupRoutingNode(ta.Bob, 0)
+	irene := setupRoutingNode(ta.Irene, 10)
+	ivan := setupRoutingNode(ta.Ivan, 1)
+	brian := setupRoutingNode(ta.Brian, 1)
+	for _, n := range []*node.Node{&alice, &bob, &irene, &ivan, &brian} {
+		defer closeNode(t, n)
+	}
+
+	openLedgerChannel(t, alice, irene, asset, 0)
+	openLedgerChannel(t, irene, bob, asset, 0)
+	aliceIvan := openLedgerChannel(t, alice, ivan, asset, 0)
+	ivanBrian := openLedgerChannel(t, ivan, brian, asset, 0)
+	brianBob := openLedgerChannel(t, brian, bob, asset, 0)
+
+	// Give the capacity announcements time to be gossiped from Brian to Alice
+	time.Sleep(time.Second)
+
+	response, err := alice.CreatePaymentChannelAuto(*bob.Address, asset, big.NewInt(virtualChannelDeposit), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForObjectives(t, alice, bob, []node.Node{ivan, brian}, []protocols.ObjectiveId{response.Id})
+	// The fees Alice agreed to pay Ivan and Brian were paid from her ledger channel, so her whole deposit is available for payments
+	checkPaymentChannel(t, response.ChannelId, td.Outcomes.Create(*alice.Address, *bob.Address, virtualChannelDeposit, 0, asset), query.Open, alice, bob)
+
+	closeId, err := alice.ClosePaymentChannel(response.ChannelId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForObjectives(t, alice, bob, []node.Node{ivan, brian}, []protocols.ObjectiveId{closeId})
+
+	// The virtual channel was routed through Ivan and Brian, who were each paid their fee
+	checkLedgerChannel(t, aliceIvan, CreateLedgerOutcome(*alice.Address, *ivan.Address, ledgerChannelDeposit-2, ledgerChannelDeposit+2, asset), query.Open, channel.Open, alice, ivan)
+	checkLedgerChannel(t, ivanBrian, CreateLedgerOutcome(*ivan.Address, *brian.Address, ledgerChannelDeposit-1, ledgerChannelDeposit+1, asset), query.Open, channel.Open, ivan, brian)
+	checkLedgerChannel(t, brianBob, CreateLedgerOutcome(*brian.Address, *bob.Address, ledgerChannelDeposit, ledgerChannelDeposit, asset), query.Open, channel.Open, brian, bob)
+
+	if _, err := alice.CreatePaymentChannelAuto(*bob.Address, asset, big.NewInt(10*ledgerChannelDeposit), 0); err == nil {
+		t.Fatal("expected no route to have enough capacity")
+	}
+}
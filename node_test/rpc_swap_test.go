@@ -0,0 +1,158 @@
+package node_test // import "github.com/statechannels/go-nitro/node_test"
+
+import (
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/go-cmp/cmp"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/statechannels/go-nitro/internal/logging"
+	ta "github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/node/query"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/swap"
+	"github.com/statechannels/go-nitro/rpc"
+	"github.com/statechannels/go-nitro/rpc/transport"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// waitForSwap waits for the next swap proposed in the swap channel to be notified
+func waitForSwap(t *testing.T, swapUpdates <-chan query.SwapInfo) query.SwapInfo {
+	select {
+	case info := <-swapUpdates:
+		return info
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a swap")
+		return query.SwapInfo{}
+	}
+}
+
+func checkRpcSwapChannel(t *testing.T, client rpc.RpcClientApi, channelId types.Destination, o outcome.Exit, status query.ChannelStatus, me types.Address) {
+	expected := createSwapChInfo(channelId, o, status, me)
+	info, err := client.GetSwapChannel(channelId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expected, info, cmp.AllowUnexported(big.Int{})); diff != "" {
+		t.Fatalf("swap channel diff mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRpcSwaps(t *testing.T) {
+	logging.SetupDefaultFileLogger("test_rpc_swaps.log", slog.LevelDebug)
+
+	eth, token := common.Address{}, common.Address{1}
+	alice, bob := ta.Alice.Address(), ta.Bob.Address()
+
+	chain := chainservice.NewMockChain()
+	defer chain.Close()
+
+	aliceClient, msgAlice, aliceCleanup := setupNitroNodeWithRPCClient(t, ta.Alice.PrivateKey, 3305, 6305, 4305, chainservice.NewMockChainService(chain, alice), transport.Http, []string{})
+	defer aliceCleanup()
+	bobClient, msgBob, bobCleanup := setupNitroNodeWithRPCClient(t, ta.Bob.PrivateKey, 3306, 6306, 4306, chainservice.NewMockChainService(chain, bob), transport.Http, []string{msgAlice.MultiAddr})
+	defer bobCleanup()
+	waitForPeerInfoExchange(msgAlice, msgBob)
+
+	var ledgerOutcome, swapOutcome outcome.Exit
+	for _, asset := range []common.Address{eth, token} {
+		ledgerOutcome = append(ledgerOutcome, CreateLedgerOutcome(alice, bob, ledgerChannelDeposit, ledgerChannelDeposit, asset)...)
+		swapOutcome = append(swapOutcome, CreateLedgerOutcome(alice, bob, 1000, 1000, asset)...)
+	}
+	ledgerRes, err := aliceClient.CreateLedgerChannel(bob, 100, ledgerOutcome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-aliceClient.ObjectiveCompleteChan(ledgerRes.Id)
+	<-bobClient.ObjectiveCompleteChan(ledgerRes.Id)
+
+	swapChannelRes, err := aliceClient.CreateSwapChannel(nil, bob, 100, swapOutcome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-aliceClient.ObjectiveCompleteChan(swapChannelRes.Id)
+	<-bobClient.ObjectiveCompleteChan(swapChannelRes.Id)
+	channelId := swapChannelRes.ChannelId
+	checkRpcSwapChannel(t, aliceClient, channelId, swapOutcome, query.Open, alice)
+	checkRpcSwapChannel(t, bobClient, channelId, swapOutcome, query.Open, bob)
+
+	// Alice proposes a swap, which Bob accepts
+	bobSwaps := bobClient.SwapUpdatesChan(channelId)
+	aliceChannelUpdates := aliceClient.SwapChannelUpdatesChan(channelId)
+	initiated, err := aliceClient.SwapAssets(channelId, eth, token, 10, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposed := waitForSwap(t, bobSwaps)
+	if proposed.ChannelId != channelId {
+		t.Fatalf("expected a swap in channel %s, got %s", channelId, proposed.ChannelId)
+	}
+	if proposed.Id != initiated.SwapId {
+		t.Fatalf("expected the proposed swap to be %s, got %s", initiated.SwapId, proposed.Id)
+	}
+
+	pending, err := bobClient.GetPendingSwap(channelId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending == nil || pending.Id != proposed.Id {
+		t.Fatalf("expected swap %s to be pending, got %+v", proposed.Id, pending)
+	}
+	if err := bobClient.ConfirmSwap(pending.Id, types.Accepted); err != nil {
+		t.Fatal(err)
+	}
+	swapObjectiveId := protocols.ObjectiveId(swap.ObjectivePrefix + pending.Id.String())
+	<-aliceClient.ObjectiveCompleteChan(swapObjectiveId)
+	<-bobClient.ObjectiveCompleteChan(swapObjectiveId)
+
+	swapOutcome = modifyOutcomeWithSwap(swapOutcome, pending, 0)
+	expected := createSwapChInfo(channelId, swapOutcome, query.Open, alice)
+	for deadline := time.After(5 * time.Second); ; {
+		select {
+		case info := <-aliceChannelUpdates:
+			if !cmp.Equal(expected, info, cmp.AllowUnexported(big.Int{})) {
+				continue
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for the swap channel update")
+		}
+		break
+	}
+	checkRpcSwapChannel(t, aliceClient, channelId, swapOutcome, query.Open, alice)
+	checkRpcSwapChannel(t, bobClient, channelId, swapOutcome, query.Open, bob)
+
+	for _, client := range []rpc.RpcClientApi{aliceClient, bobClient} {
+		if pending, err := client.GetPendingSwap(channelId); err != nil || pending != nil {
+			t.Fatalf("expected no pending swap, got %+v, %v", pending, err)
+		}
+		recent, err := client.GetRecentSwaps(channelId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(recent) != 1 || recent[0].Id != proposed.Id || !recent[0].Exchange.Equal(pending.Exchange) {
+			t.Fatalf("expected swap %s to be the only recent swap, got %+v", proposed.Id, recent)
+		}
+	}
+
+	// Bob rejects the next swap proposed by Alice, which leaves the channel untouched
+	if _, err := aliceClient.SwapAssets(channelId, token, eth, 5, 5); err != nil {
+		t.Fatal(err)
+	}
+	rejected := waitForSwap(t, bobSwaps)
+	if err := bobClient.ConfirmSwap(rejected.Id, types.Rejected); err != nil {
+		t.Fatal(err)
+	}
+	<-aliceClient.ObjectiveCompleteChan(protocols.ObjectiveId(swap.ObjectivePrefix + rejected.Id.String()))
+	checkRpcSwapChannel(t, aliceClient, channelId, swapOutcome, query.Open, alice)
+
+	closeId, err := aliceClient.CloseSwapChannel(channelId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-aliceClient.ObjectiveCompleteChan(closeId)
+	<-bobClient.ObjectiveCompleteChan(closeId)
+	checkRpcSwapChannel(t, aliceClient, channelId, swapOutcome, query.Complete, alice)
+}
@@ -420,11 +420,15 @@ func setupNitroNodeWithRPCClient(
 		SCAddr:    *ourStore.GetAddress(),
 	})
 
-	node := node.New(
+	node, err := node.New(
 		messageService,
 		chain,
 		ourStore,
-		&engine.PermissivePolicy{})
+		&engine.PermissivePolicy{},
+		engine.EngineOpts{})
+	if err != nil {
+		panic(err)
+	}
 
 	var useNats bool
 	switch connectionType {
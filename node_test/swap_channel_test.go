@@ -0,0 +1,83 @@
+package node_test // import "github.com/statechannels/go-nitro/node_test"
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/go-cmp/cmp"
+	ta "github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/node/engine/messageservice"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/node/query"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// waitForSwapChannelInfo waits until the chan receives the expected swap channel info, ignoring any earlier updates
+func waitForSwapChannelInfo(t *testing.T, updates <-chan query.SwapChannelInfo, expected query.SwapChannelInfo) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case info := <-updates:
+			if cmp.Equal(expected, info, cmp.AllowUnexported(big.Int{})) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for swap channel update %+v", expected)
+		}
+	}
+}
+
+func TestSwapChannelUpdates(t *testing.T) {
+	eth, token := common.Address{}, common.Address{1}
+	assets := []common.Address{eth, token}
+
+	chain := chainservice.NewMockChain()
+	broker := messageservice.NewBroker()
+	setupMockChainNode := func(actor ta.Actor) node.Node {
+		ms := messageservice.NewTestMessageService(actor.Address(), broker, 0)
+		cs := chainservice.NewMockChainService(chain, actor.Address())
+		n, err := node.New(ms, cs, store.NewMemStore(actor.PrivateKey), &engine.PermissivePolicy{}, engine.EngineOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	alice, bob := setupMockChainNode(ta.Alice), setupMockChainNode(ta.Bob)
+	for _, n := range []*node.Node{&alice, &bob} {
+		t.Cleanup(func() { closeNode(t, n) })
+	}
+
+	channelId, o := openMockSwapChannel(t, alice, bob, assets, 1000)
+
+	for _, n := range []node.Node{alice, bob} {
+		all, err := n.GetAllSwapChannels()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []query.SwapChannelInfo{createSwapChInfo(channelId, o, query.Open, *n.Address)}
+		if diff := cmp.Diff(expected, all, cmp.AllowUnexported(big.Int{})); diff != "" {
+			t.Fatalf("swap channels diff mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	aliceUpdates := alice.SwapChannelUpdatedChan(channelId)
+	bobUpdates := bob.SwapChannelUpdatedChan(channelId)
+
+	if _, err := alice.SwapAssets(channelId, eth, token, big.NewInt(10), big.NewInt(20)); err != nil {
+		t.Fatal(err)
+	}
+	pending := waitForPendingSwap(t, bob, channelId)
+	if err := bob.ConfirmSwap(pending.Id, types.Accepted); err != nil {
+		t.Fatal(err)
+	}
+
+	o = modifyOutcomeWithSwap(o, pending, 0)
+	waitForSwapChannelInfo(t, aliceUpdates, createSwapChInfo(channelId, o, query.Open, *alice.Address))
+	waitForSwapChannelInfo(t, bobUpdates, createSwapChInfo(channelId, o, query.Open, *bob.Address))
+	checkSwapChannel(t, channelId, o, query.Open, alice, bob)
+}
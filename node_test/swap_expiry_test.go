@@ -0,0 +1,211 @@
+package node_test // import "github.com/statechannels/go-nitro/node_test"
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ta "github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/engine"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/node/engine/messageservice"
+	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/node/query"
+	"github.com/statechannels/go-nitro/payments"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/swap"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// waitForPendingSwap waits until the node has received the swap in the swap channel, and returns it
+func waitForPendingSwap(t *testing.T, n node.Node, channelId types.Destination) *payments.Swap {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		s, err := n.GetPendingSwapByChannelId(channelId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != nil {
+			return s
+		}
+	}
+
+	t.Fatalf("no pending swap in channel %s", channelId)
+	return nil
+}
+
+// waitForNoPendingSwap waits until the node has no pending swap in the swap channel, e.g. until a cancellation is acknowledged
+func waitForNoPendingSwap(t *testing.T, n node.Node, channelId types.Destination) {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		s, err := n.GetPendingSwapByChannelId(channelId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s == nil {
+			return
+		}
+	}
+
+	t.Fatalf("swap still pending in channel %s", channelId)
+}
+
+func checkObjectiveStatus(t *testing.T, id protocols.ObjectiveId, want query.ObjectiveStatus, nodes ...node.Node) {
+	for _, n := range nodes {
+		info, err := n.GetObjectiveInfo(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Status != want {
+			t.Fatalf("expected objective %s of %s to be %s, got %s", id, n.Address, want, info.Status)
+		}
+	}
+}
+
+func TestSwapExpiryAndCancellation(t *testing.T) {
+	eth, token := common.Address{}, common.Address{1}
+	assets := []common.Address{eth, token}
+
+	setup := func(t *testing.T, maxMessageDelay time.Duration) (alice, bob node.Node) {
+		chain := chainservice.NewMockChain()
+		broker := messageservice.NewBroker()
+		setupMockChainNode := func(actor ta.Actor) node.Node {
+			ms := messageservice.NewTestMessageService(actor.Address(), broker, maxMessageDelay)
+			cs := chainservice.NewMockChainService(chain, actor.Address())
+			n, err := node.New(ms, cs, store.NewMemStore(actor.PrivateKey), &engine.PermissivePolicy{}, engine.EngineOpts{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return n
+		}
+
+		alice = setupMockChainNode(ta.Alice)
+		bob = setupMockChainNode(ta.Bob)
+		for _, n := range []*node.Node{&alice, &bob} {
+			t.Cleanup(func() { closeNode(t, n) })
+		}
+		return alice, bob
+	}
+
+	t.Run("an expired swap cannot be accepted", func(t *testing.T) {
+		alice, bob := setup(t, 0)
+		channelId, o := openMockSwapChannel(t, alice, bob, assets, 1000)
+
+		if _, err := alice.SwapAssetsWithExpiry(channelId, eth, token, big.NewInt(10), big.NewInt(20), time.Now().Add(-time.Second)); !errors.Is(err, swap.ErrSwapExpired) {
+			t.Fatalf("expected a swap which has already expired to be refused, got %v", err)
+		}
+
+		response, err := alice.SwapAssetsWithExpiry(channelId, eth, token, big.NewInt(10), big.NewInt(20), time.Now().Add(2*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pending := waitForPendingSwap(t, bob, channelId)
+
+		time.Sleep(time.Until(time.Unix(int64(pending.Expiry), 0)))
+		aliceDone := alice.ObjectiveCompleteChan(response.Id)
+		if err := bob.ConfirmSwap(pending.Id, types.Accepted); !errors.Is(err, swap.ErrSwapExpired) {
+			t.Fatalf("expected the acceptance of an expired swap to fail, got %v", err)
+		}
+		<-aliceDone
+
+		checkObjectiveStatus(t, response.Id, query.ObjectiveRejected, alice, bob)
+		checkSwapChannel(t, channelId, o, query.Open, alice, bob)
+
+		// The channel is free for new swaps
+		if _, err := alice.SwapAssets(channelId, eth, token, big.NewInt(10), big.NewInt(20)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("the sender cancels a pending swap", func(t *testing.T) {
+		alice, bob := setup(t, 0)
+		channelId, o := openMockSwapChannel(t, alice, bob, assets, 1000)
+
+		response, err := alice.SwapAssets(channelId, eth, token, big.NewInt(10), big.NewInt(20))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pending := waitForPendingSwap(t, bob, channelId)
+
+		if err := bob.CancelSwap(pending.Id); !errors.Is(err, swap.ErrNotCancelable) {
+			t.Fatalf("expected the receiver not to be able to cancel the swap, got %v", err)
+		}
+
+		bobDone := bob.ObjectiveCompleteChan(response.Id)
+		if err := alice.CancelSwap(pending.Id); err != nil {
+			t.Fatal(err)
+		}
+		<-bobDone
+
+		checkObjectiveStatus(t, response.Id, query.ObjectiveRejected, alice, bob)
+		checkSwapChannel(t, channelId, o, query.Open, alice, bob)
+		if err := alice.CancelSwap(pending.Id); !errors.Is(err, swap.ErrNotCancelable) {
+			t.Fatalf("expected a cancelled swap not to be cancelled again, got %v", err)
+		}
+
+		// The channel is free for new swaps once Bob acknowledges the cancellation
+		waitForNoPendingSwap(t, alice, channelId)
+		for _, tc := range []struct {
+			n    node.Node
+			want query.SwapRecordStatus
+		}{{alice, query.SwapCancelled}, {bob, query.SwapRejected}} {
+			history, err := tc.n.ListSwaps(query.SwapFilter{ChannelId: channelId}, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if history.Total != 1 || history.Swaps[0].Status != tc.want {
+				t.Fatalf("expected the swap to be recorded as %s by %s, got %+v", tc.want, tc.n.Address, history.Swaps)
+			}
+		}
+		if _, err := alice.SwapAssets(channelId, eth, token, big.NewInt(10), big.NewInt(20)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("a cancellation which crosses the acceptance leaves both parties agreeing", func(t *testing.T) {
+		alice, bob := setup(t, 50*time.Millisecond)
+		channelId, o := openMockSwapChannel(t, alice, bob, assets, 1000)
+
+		for i := 0; i < 5; i++ {
+			response, err := alice.SwapAssets(channelId, eth, token, big.NewInt(10), big.NewInt(20))
+			if err != nil {
+				t.Fatal(err)
+			}
+			pending := waitForPendingSwap(t, bob, channelId)
+
+			// Either request may lose the race, so their errors are ignored
+			bobDone := bob.ObjectiveCompleteChan(response.Id)
+			done := make(chan struct{})
+			go func() {
+				_ = alice.CancelSwap(pending.Id)
+				close(done)
+			}()
+			_ = bob.ConfirmSwap(pending.Id, types.Accepted)
+			<-done
+			<-bobDone
+
+			info, err := bob.GetObjectiveInfo(response.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Status == query.ObjectiveCompleted {
+				o = modifyOutcomeWithSwap(o, pending, 0)
+			}
+			for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+				aliceInfo, err := alice.GetObjectiveInfo(response.Id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if aliceInfo.Status == info.Status {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("expected Alice's swap to be %s like Bob's, got %s", info.Status, aliceInfo.Status)
+				}
+			}
+			waitForNoPendingSwap(t, alice, channelId)
+			checkSwapChannel(t, channelId, o, query.Open, alice, bob)
+		}
+	})
+}
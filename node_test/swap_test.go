@@ -1,7 +1,7 @@
 package node_test
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"math/big"
 	"testing"
@@ -17,6 +17,7 @@ import (
 	"github.com/statechannels/go-nitro/node/engine/messageservice"
 	p2pms "github.com/statechannels/go-nitro/node/engine/messageservice/p2p-message-service"
 	"github.com/statechannels/go-nitro/node/engine/store"
+	"github.com/statechannels/go-nitro/node/notifier"
 	"github.com/statechannels/go-nitro/node/query"
 	"github.com/statechannels/go-nitro/payments"
 	"github.com/statechannels/go-nitro/protocols"
@@ -227,6 +228,10 @@ func createSwapChannel(t *testing.T, nodeA, nodeB node.Node, utils TestUtils) (s
 }
 
 func performSwap(t *testing.T, sender *node.Node, receiver *node.Node, swapSenderIndex int, swapExchange payments.Exchange, swapChannelId types.Destination, expectedInitialOutcome outcome.Exit, action types.SwapStatus) (outcome.Exit, types.Destination, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	swapUpdates := receiver.SubscribeSwapUpdates(ctx, notifier.SubscriptionOpts{})
+
 	swapAssetResponse, err := sender.SwapAssets(swapChannelId, swapExchange.TokenIn, swapExchange.TokenOut, swapExchange.AmountIn, swapExchange.AmountOut)
 	if err != nil {
 		return outcome.Exit{}, types.Destination{}, err
@@ -234,7 +239,7 @@ func performSwap(t *testing.T, sender *node.Node, receiver *node.Node, swapSende
 
 	// Wait for objective to wait for confirmation
 	for {
-		swapDetails := <-receiver.SwapUpdates()
+		swapDetails := <-swapUpdates
 		if protocols.ObjectiveId(swap.ObjectivePrefix+swapDetails.Id.String()) == swapAssetResponse.Id {
 			break
 		}
@@ -318,6 +323,27 @@ func TestStorageOfLastNSwap(t *testing.T) {
 				testhelpers.Assert(t, err == store.ErrNoSuchSwap, "expected swap to be removed from store")
 			}
 		}
+
+		for _, n := range []node.Node{utils.nodeA, utils.nodeB} {
+			history, err := n.ListSwaps(query.SwapFilter{ChannelId: swapChannelResponse.ChannelId}, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testhelpers.Assert(t, history.Total == uint(swapIterations) && len(history.Swaps) == swapIterations, "expected every swap to be kept in the swap history")
+			for i, record := range history.Swaps {
+				testhelpers.Assert(t, record.Id == swapsIds[i], "expected swap history to be ordered by time")
+				testhelpers.Assert(t, record.Status == query.SwapAccepted, "expected swap to be recorded as accepted")
+				testhelpers.Assert(t, record.IsSender == (*n.Address == *utils.nodeA.Address), "expected only node A to be recorded as the swap sender")
+			}
+
+			token := utils.infra.anvilChain.ContractAddresses.TokenAddresses[0]
+			page, err := n.ListSwaps(query.SwapFilter{ChannelId: swapChannelResponse.ChannelId, Token: &token}, 2, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testhelpers.Assert(t, page.Total == uint(swapIterations) && len(page.Swaps) == 3 && page.Swaps[0].Id == swapsIds[2], "unexpected page of swap history")
+		}
 	})
 
 	t.Run("Check ledger channel after swapdefund", func(t *testing.T) {
@@ -445,13 +471,7 @@ func TestSwapTillEmptyBalance(t *testing.T) {
 			out, _, err := performSwap(t, &utils.nodeB, &utils.nodeA, 1, exchange, swapChannelResponse.ChannelId, expectedInitialOutcome, types.Accepted)
 			if err != nil {
 				// Check that balance of node A is zero now that swap has failed
-				var swapInfo query.SwapChannelInfo
-				marshalledSwapInfo, er := utils.nodeA.GetSwapChannel(swapChannelResponse.ChannelId)
-				if er != nil {
-					t.Fatal(err)
-				}
-
-				er = json.Unmarshal([]byte(marshalledSwapInfo), &swapInfo)
+				swapInfo, er := utils.nodeA.GetSwapChannel(swapChannelResponse.ChannelId)
 				if er != nil {
 					t.Fatal(er)
 				}
@@ -485,13 +505,7 @@ func TestSwapTillEmptyBalance(t *testing.T) {
 			out, _, err := performSwap(t, &utils.nodeA, &utils.nodeB, 0, exchange, swapChannelResponse.ChannelId, expectedInitialOutcome, types.Accepted)
 			if err != nil {
 				// Check that balance of node B is zero now that swap has failed
-				var swapInfo query.SwapChannelInfo
-				marshalledSwapInfo, er := utils.nodeB.GetSwapChannel(swapChannelResponse.ChannelId)
-				if er != nil {
-					t.Fatal(err)
-				}
-
-				er = json.Unmarshal([]byte(marshalledSwapInfo), &swapInfo)
+				swapInfo, er := utils.nodeB.GetSwapChannel(swapChannelResponse.ChannelId)
 				if er != nil {
 					t.Fatal(er)
 				}
@@ -605,6 +619,8 @@ func TestSwapsWithIntermediary(t *testing.T) {
 	for i := 1; i <= swapIterations; i++ {
 
 		// Initiate swap from Bob
+		ctx, cancel := context.WithCancel(context.Background())
+		swapUpdates := utils.nodeC.SubscribeSwapUpdates(ctx, notifier.SubscriptionOpts{})
 		swapAssetResponse, err := utils.nodeB.SwapAssets(swapChannelResponse.ChannelId, utils.infra.anvilChain.ContractAddresses.TokenAddresses[0], utils.infra.anvilChain.ContractAddresses.TokenAddresses[1], big.NewInt(100), big.NewInt(200))
 		if err != nil {
 			t.Fatal(err)
@@ -612,11 +628,12 @@ func TestSwapsWithIntermediary(t *testing.T) {
 
 		// Wait for objective to wait for confirmation
 		for {
-			swapDetails := <-utils.nodeC.SwapUpdates()
+			swapDetails := <-swapUpdates
 			if protocols.ObjectiveId(swap.ObjectivePrefix+swapDetails.Id.String()) == swapAssetResponse.Id {
 				break
 			}
 		}
+		cancel()
 
 		pendingSwap, err := utils.nodeC.GetPendingSwapByChannelId(swapAssetResponse.ChannelId)
 		if err != nil {
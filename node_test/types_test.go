@@ -8,13 +8,14 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/statechannels/go-nitro/cmd/utils"
 	"github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/node/engine"
 	"github.com/statechannels/go-nitro/node/engine/chainservice"
 	chainutils "github.com/statechannels/go-nitro/node/engine/chainservice/utils"
 	"github.com/statechannels/go-nitro/node/engine/messageservice"
 )
 
 const (
-	MAX_PARTICIPANTS      = 4
+	MAX_PARTICIPANTS      = 5
 	DEFAULT_PUBLIC_IP     = "127.0.0.1"
 	DURABLE_STORE_FOLDER  = "../data/node_test"
 	ledgerChannelDeposit  = 5_000_000
@@ -65,15 +66,15 @@ type TestCase struct {
 	Participants      []TestParticipant
 	deployerIndex     uint
 	ChainPort         string
+	EngineOpts        engine.EngineOpts
 }
 
 // Validate validates the test case and makes sure that the current test supports the test case.
 func (tc *TestCase) Validate() error {
-	if tc.NumOfHops < 1 || tc.NumOfHops > 2 {
-		return fmt.Errorf("NumOfHops must be 1 or 2")
+	if tc.NumOfHops < 1 || tc.NumOfHops > MAX_PARTICIPANTS-2 {
+		return fmt.Errorf("NumOfHops must be between 1 and %d", MAX_PARTICIPANTS-2)
 	}
-	if tc.NumOfHops == 1 && len(tc.Participants) != 3 ||
-		tc.NumOfHops == 2 && len(tc.Participants) != 4 {
+	if len(tc.Participants) != int(tc.NumOfHops)+2 {
 		return fmt.Errorf("NumOfHops is %d, but there are %d participants", tc.NumOfHops, len(tc.Participants))
 	}
 	if tc.NumOfChannels < 1 || tc.NumOfChannels > 9 {
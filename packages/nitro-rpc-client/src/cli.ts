@@ -16,7 +16,12 @@ import {
   parseAssetData as parseAssetsData,
   parseSwapAssetsData,
 } from "./utils";
-import { AssetData, ConfirmSwapAction, CounterChallengeAction } from "./types";
+import {
+  AssetData,
+  ConfirmSwapAction,
+  CounterChallengeAction,
+  ObjectiveStatus,
+} from "./types";
 import { ZERO_ETHEREUM_ADDRESS } from "./constants";
 
 yargs(hideBin(process.argv))
@@ -138,6 +143,25 @@ yargs(hideBin(process.argv))
       process.exit(0);
     }
   )
+  .command(
+    "get-all-swap-channels",
+    "Get all swap channels",
+    async () => {},
+    async (yargs) => {
+      const rpcPort = yargs.p;
+      const rpcHost = yargs.h;
+      const isSecure = yargs.s;
+
+      const rpcClient = await NitroRpcClient.CreateHttpNitroClient(
+        getRPCUrl(rpcHost, rpcPort),
+        isSecure
+      );
+      const swapChannels = await rpcClient.GetAllSwapChannels();
+      console.log(prettyJson(swapChannels));
+      await rpcClient.Close();
+      process.exit(0);
+    }
+  )
   .command(
     "get-signed-state <channelId> <jsonFilePath>",
     "Get latest signed state",
@@ -240,6 +264,117 @@ yargs(hideBin(process.argv))
       process.exit(0);
     }
   )
+  .command(
+    "get-objective-info <objectiveId>",
+    "Get the status of the objective with given objective ID, what it is waiting for and why it failed",
+    (yargsBuilder) => {
+      return yargsBuilder.positional("objectiveId", {
+        describe: "ID of the objective",
+        type: "string",
+        demandOption: true,
+      });
+    },
+    async (yargs) => {
+      const rpcPort = yargs.p;
+      const rpcHost = yargs.h;
+      const isSecure = yargs.s;
+
+      const rpcClient = await NitroRpcClient.CreateHttpNitroClient(
+        getRPCUrl(rpcHost, rpcPort),
+        isSecure
+      );
+      const objectiveInfo = await rpcClient.GetObjectiveInfo(yargs.objectiveId);
+      console.log(prettyJson(objectiveInfo));
+
+      await rpcClient.Close();
+      process.exit(0);
+    }
+  )
+  .command(
+    "list-objectives",
+    "Get the status of every objective",
+    (yargsBuilder) => {
+      return yargsBuilder.option("status", {
+        describe: "Only list objectives with this status",
+        type: "string",
+        choices: ["Unapproved", "Approved", "Rejected", "Completed", "Failed"],
+      });
+    },
+    async (yargs) => {
+      const rpcPort = yargs.p;
+      const rpcHost = yargs.h;
+      const isSecure = yargs.s;
+
+      const rpcClient = await NitroRpcClient.CreateHttpNitroClient(
+        getRPCUrl(rpcHost, rpcPort),
+        isSecure
+      );
+      const objectives = await rpcClient.ListObjectives(
+        (yargs.status ?? "") as ObjectiveStatus | ""
+      );
+      console.log(prettyJson(objectives));
+
+      await rpcClient.Close();
+      process.exit(0);
+    }
+  )
+  .command(
+    "list-swaps",
+    "Get the swaps which have come to an end",
+    (yargsBuilder) => {
+      return yargsBuilder
+        .option("channel", {
+          describe: "Only list swaps in this swap channel",
+          type: "string",
+        })
+        .option("token", {
+          describe: "Only list swaps exchanging this token",
+          type: "string",
+        })
+        .option("from", {
+          describe: "Only list swaps recorded at or after this RFC 3339 time",
+          type: "string",
+        })
+        .option("to", {
+          describe: "Only list swaps recorded before this RFC 3339 time",
+          type: "string",
+        })
+        .option("offset", {
+          describe: "Number of matching swaps to skip",
+          type: "number",
+          default: 0,
+        })
+        .option("limit", {
+          describe: "Maximum number of swaps to list",
+          type: "number",
+          default: 0,
+        });
+    },
+    async (yargs) => {
+      const rpcPort = yargs.p;
+      const rpcHost = yargs.h;
+      const isSecure = yargs.s;
+
+      const rpcClient = await NitroRpcClient.CreateHttpNitroClient(
+        getRPCUrl(rpcHost, rpcPort),
+        isSecure
+      );
+      const swaps = await rpcClient.ListSwaps(
+        {
+          ChannelId: yargs.channel,
+          Token: yargs.token,
+          From: yargs.from,
+          To: yargs.to,
+        },
+        yargs.offset,
+        yargs.limit
+      );
+      console.log(prettyJson(swaps));
+
+      await rpcClient.Close();
+      process.exit(0);
+    }
+  )
   .command(
     "get-l2-objective-from-l1 <l1ObjectiveId>",
     "Get current status of objective with given objective ID",
@@ -818,9 +953,8 @@ yargs(hideBin(process.argv))
         isSecure
       );
 
-      // TODO: Generate JSON schema for get current swap
       const pendingSwap = await rpcClient.GetPendingSwap(yargs.channelId);
-      console.log(JSON.parse(pendingSwap));
+      console.log(pendingSwap);
       await rpcClient.Close();
       process.exit(0);
     }
@@ -845,9 +979,8 @@ yargs(hideBin(process.argv))
         isSecure
       );
 
-      // TODO: Generate JSON schema for get current swap
       const recentSwaps = await rpcClient.GetRecentSwaps(yargs.channelId);
-      console.log(JSON.parse(recentSwaps));
+      console.log(recentSwaps);
       await rpcClient.Close();
       process.exit(0);
     }
@@ -965,6 +1098,33 @@ yargs(hideBin(process.argv))
       process.exit(0);
     }
   )
+  .command(
+    "swap-cancel <SwapId>",
+    "Cancel a swap which the counterparty has not accepted yet",
+    (yargsBuilder) => {
+      return yargsBuilder.positional("SwapId", {
+        describe: "The Id of swap",
+        type: "string",
+        demandOption: true,
+      });
+    },
+    async (yargs) => {
+      const rpcPort = yargs.p;
+      const rpcHost = yargs.h;
+      const isSecure = yargs.s;
+
+      const rpcClient = await NitroRpcClient.CreateHttpNitroClient(
+        getRPCUrl(rpcHost, rpcPort),
+        isSecure
+      );
+
+      const response = await rpcClient.CancelSwap(yargs.SwapId);
+      console.log(`Cancelled swap ${response.SwapId}`);
+
+      await rpcClient.Close();
+      process.exit(0);
+    }
+  )
   .command(
     "retry-objective-tx <objectiveId>",
     "Retries transaction for given objective",
@@ -4,13 +4,20 @@ import {
   CounterChallengeAction,
   CounterChallengeResult,
   LedgerChannelInfo,
+  ObjectiveInfo,
   ObjectiveResponse,
+  ObjectiveStatus,
   PaymentChannelInfo,
   PaymentPayload,
   ReceiveVoucherResult,
+  Swap,
   SwapAssetsData,
   SwapChannelInfo,
-  SwapInitiatePayload,
+  SwapFilter,
+  SwapHistory,
+  SwapInitiateResult,
+  SwapRouteLeg,
+  SwapAlongRouteResult,
   Voucher,
 } from "./types";
 
@@ -59,6 +66,17 @@ interface paymentChannelApi {
     intermediaries: string[],
     amount: number
   ): Promise<ObjectiveResponse>;
+  /**
+   * CreatePaymentChannelAuto creates a virtually funded payment channel with the counterparty, through intermediaries chosen by the node.
+   *
+   * @param counterParty - The counterparty to create the channel with
+   * @param amount - The amount to deposit in the channel
+   * @returns A promise that resolves to an objective response, containing the ID of the objective and the channel id.
+   */
+  CreatePaymentChannelAuto(
+    counterParty: string,
+    amount: number
+  ): Promise<ObjectiveResponse>;
   /**
    * ClosePaymentChannel defunds a virtually funded payment channel.
    *
@@ -166,9 +184,28 @@ interface swapAPI {
    * GetSwapChannel gets swap channel info.
    *
    * @param channelId - The ID of the channel to query for
-   * @returns A JSON object containing the channel's information
+   * @returns A `SwapChannelInfo` object containing the channel's information
    */
   GetSwapChannel(channelId: string): Promise<SwapChannelInfo>;
+  /**
+   * GetAllSwapChannels queries the RPC server for all swap channels.
+   * @returns A `SwapChannelInfo` object containing the channel's information for each swap channel
+   */
+  GetAllSwapChannels(): Promise<SwapChannelInfo[]>;
+  /**
+   * GetPendingSwap gets the swap awaiting confirmation in a swap channel.
+   *
+   * @param channelId - The ID of the swap channel
+   * @returns The pending swap, or null if there is none
+   */
+  GetPendingSwap(channelId: string): Promise<Swap | null>;
+  /**
+   * GetRecentSwaps gets the most recent swaps of a swap channel.
+   *
+   * @param channelId - The ID of the swap channel
+   * @returns The recent swaps of the channel
+   */
+  GetRecentSwaps(channelId: string): Promise<Swap[]>;
   /**
    * CloseSwapChannel defunds a swap channel.
    *
@@ -180,12 +217,23 @@ interface swapAPI {
    * SwapAssets exchanges the asset.
    *
    * @param channelId - The ID of the swap channel
-   * @returns The data used for swapping the assets
+   * @returns The data used for swapping the assets, and the id of the proposed swap
    */
   SwapAssets(
     channelId: string,
     swapAssetsData: SwapAssetsData
-  ): Promise<SwapInitiatePayload>;
+  ): Promise<SwapInitiateResult>;
+  /**
+   * SwapAlongRoute executes the swaps of the legs.
+   *
+   * The legs are committed from the end of the route backwards: no participant signs the update of
+   * a leg before the legs after it have been committed, so a dishonest intermediary cannot collect
+   * the leg it gains from while withholding its signature on the leg it pays into.
+   *
+   * @param legs - The swaps of the route, the first of which must be sent by this node
+   * @returns The id of the objective and the ids of the swaps of the legs
+   */
+  SwapAlongRoute(legs: SwapRouteLeg[]): Promise<SwapAlongRouteResult>;
 }
 
 interface syncAPI {
@@ -219,6 +267,16 @@ interface syncAPI {
     channelId: string,
     callback: (info: PaymentChannelInfo) => void
   ): () => void;
+  /**
+   * onSwapChannelUpdated attaches a callback which is triggered when the swap channel with supplied ID is updated.
+   * Returns a cleanup function which can be used to remove the subscription.
+   *
+   * @param channelId - The id of the swap channel to watch
+   */
+  onSwapChannelUpdated(
+    channelId: string,
+    callback: (info: SwapChannelInfo) => void
+  ): () => void;
 
   /**
    * WaitForObjectiveToComplete blocks until the objective with the given ID is complete.
@@ -257,6 +315,33 @@ export interface RpcClientApi
    * @returns objectiveId of the objective for which tx was retried
    */
   RetryObjectiveTx(objectiveId: string): Promise<string>;
+  /**
+   * GetObjectiveInfo queries the RPC server for the status of an objective.
+   *
+   * @param objectiveId - The ID of the objective to query for
+   * @returns An `ObjectiveInfo` object containing the status of the objective, what it is waiting for and why it failed if it did
+   */
+  GetObjectiveInfo(objectiveId: string): Promise<ObjectiveInfo>;
+  /**
+   * ListObjectives queries the RPC server for the status of every objective.
+   *
+   * @param status - Only objectives with this status are returned, if it is set
+   * @returns An `ObjectiveInfo` object for each objective
+   */
+  ListObjectives(status?: ObjectiveStatus | ""): Promise<ObjectiveInfo[]>;
+  /**
+   * ListSwaps queries the RPC server for the swaps which have come to an end, ordered by the time they were recorded.
+   *
+   * @param filter - Only swaps matching the filter are returned
+   * @param offset - The number of matching swaps to skip
+   * @param limit - The maximum number of swaps to return, or 0 for the largest page the server allows
+   * @returns A `SwapHistory` object containing a page of swaps and the total number of matching swaps
+   */
+  ListSwaps(
+    filter?: SwapFilter,
+    offset?: number,
+    limit?: number
+  ): Promise<SwapHistory>;
   /**
    * RetryTx retries tx with given tx hash if it is failed.
    *
@@ -5,6 +5,7 @@ import {
   PaymentChannelInfo,
   PaymentPayload,
   VirtualFundPayload,
+  CreatePaymentChannelAutoPayload,
   RequestMethod,
   RPCRequestAndResponses,
   ObjectiveResponse,
@@ -22,11 +23,19 @@ import {
   AssetData,
   SwapFundPayload,
   SwapAssetsData,
-  SwapInitiatePayload,
+  SwapInitiateResult,
+  SwapRouteLeg,
+  SwapAlongRouteResult,
   GetPendingSwap,
   ConfirmSwapAction,
   ConfirmSwapResult,
+  CancelSwapPayload,
+  Swap,
   SwapChannelInfo,
+  ObjectiveInfo,
+  ObjectiveStatus,
+  SwapFilter,
+  SwapHistory,
 } from "./types";
 import { Transport } from "./transport";
 import { createOutcome, generateRequest } from "./utils";
@@ -150,6 +159,21 @@ export class NitroRpcClient implements RpcClientApi {
     };
   }
 
+  public onSwapChannelUpdated(
+    channelId: string,
+    callback: (info: SwapChannelInfo) => void
+  ): () => void {
+    const wrapperFn = (info: SwapChannelInfo) => {
+      if (info.ID.toLowerCase() == channelId.toLowerCase()) {
+        callback(info);
+      }
+    };
+    this.transport.Notifications.on("swap_channel_updated", wrapperFn);
+    return () => {
+      this.transport.Notifications.off("swap_channel_updated", wrapperFn);
+    };
+  }
+
   public async CreateLedgerChannel(
     counterParty: string,
     assetsData: AssetData[],
@@ -194,7 +218,7 @@ export class NitroRpcClient implements RpcClientApi {
   public async SwapAssets(
     channelId: string,
     swapAssetsData: SwapAssetsData
-  ): Promise<SwapInitiatePayload> {
+  ): Promise<SwapInitiateResult> {
     const payload = {
       SwapAssetsData: swapAssetsData,
       Channel: channelId,
@@ -208,14 +232,14 @@ export class NitroRpcClient implements RpcClientApi {
     return getAndValidateResult(res, "swap_initiate");
   }
 
-  public async GetPendingSwap(channelId: string): Promise<string> {
+  public async GetPendingSwap(channelId: string): Promise<Swap | null> {
     const payload: GetPendingSwap = {
       Id: channelId,
     };
     return this.sendRequest("get_pending_swap", payload);
   }
 
-  public async GetRecentSwaps(channelId: string): Promise<string> {
+  public async GetRecentSwaps(channelId: string): Promise<Swap[]> {
     const payload = {
       Id: channelId,
     };
@@ -247,6 +271,20 @@ export class NitroRpcClient implements RpcClientApi {
     return this.sendRequest("create_payment_channel", payload);
   }
 
+  public async CreatePaymentChannelAuto(
+    counterParty: string,
+    amount: number
+  ): Promise<ObjectiveResponse> {
+    const payload: CreatePaymentChannelAutoPayload = {
+      Counterparty: counterParty,
+      Asset: `0x${"00".repeat(20)}`,
+      Amount: amount,
+      ChallengeDuration: 0,
+    };
+
+    return this.sendRequest("create_payment_channel_auto", payload);
+  }
+
   public async Pay(channelId: string, amount: number): Promise<PaymentPayload> {
     const payload = {
       Amount: amount,
@@ -320,6 +358,10 @@ export class NitroRpcClient implements RpcClientApi {
     return this.sendRequest("confirm_swap", payload);
   }
 
+  public async CancelSwap(swapId: string): Promise<CancelSwapPayload> {
+    return this.sendRequest("cancel_swap", { SwapId: swapId });
+  }
+
   public async ClosePaymentChannel(channelId: string): Promise<string> {
     const payload: DefundObjectiveRequest = { ChannelId: channelId };
     return this.sendRequest("close_payment_channel", payload);
@@ -373,6 +415,10 @@ export class NitroRpcClient implements RpcClientApi {
     return this.sendRequest("get_swap_channel", { Id: channelId });
   }
 
+  public async GetAllSwapChannels(): Promise<SwapChannelInfo[]> {
+    return this.sendRequest("get_all_swap_channels", {});
+  }
+
   public async GetPaymentChannelsByLedger(
     ledgerId: string
   ): Promise<PaymentChannelInfo[]> {
@@ -388,6 +434,34 @@ export class NitroRpcClient implements RpcClientApi {
     });
   }
 
+  public async SwapAlongRoute(
+    legs: SwapRouteLeg[]
+  ): Promise<SwapAlongRouteResult> {
+    return this.sendRequest("swap_along_route", { Legs: legs });
+  }
+
+  public async GetObjectiveInfo(objectiveId: string): Promise<ObjectiveInfo> {
+    return this.sendRequest("get_objective_info", { ObjectiveId: objectiveId });
+  }
+
+  public async ListObjectives(
+    status: ObjectiveStatus | "" = ""
+  ): Promise<ObjectiveInfo[]> {
+    return this.sendRequest("list_objectives", { Status: status });
+  }
+
+  public async ListSwaps(
+    filter: SwapFilter = {},
+    offset = 0,
+    limit = 0
+  ): Promise<SwapHistory> {
+    return this.sendRequest("list_swaps", {
+      Filter: filter,
+      Offset: offset,
+      Limit: limit,
+    });
+  }
+
   public async GetL2ObjectiveFromL1(l1ObjectiveId: string): Promise<string> {
     return this.sendRequest("get_l2_objective_from_l1", {
       L1ObjectiveId: l1ObjectiveId,
@@ -1,7 +1,6 @@
 import Ajv, { JTDDataType } from "ajv/dist/jtd";
 
 import {
-  Balance,
   ChannelMode,
   ChannelStatus,
   ConfirmSwapAction,
@@ -9,11 +8,16 @@ import {
   CounterChallengeAction,
   CounterChallengeResult,
   LedgerChannelInfo,
+  ObjectiveInfo,
+  ObjectiveStatus,
   PaymentChannelInfo,
   RPCNotification,
   RPCRequestAndResponses,
   RequestMethod,
+  Swap,
   SwapChannelInfo,
+  SwapHistory,
+  SwapRecordStatus,
 } from "./types";
 
 const ajv = new Ajv();
@@ -67,6 +71,13 @@ const confirmSwapSchema = {
 } as const;
 type ConfirmSwapSchemaType = JTDDataType<typeof confirmSwapSchema>;
 
+const cancelSwapSchema = {
+  properties: {
+    SwapId: { type: "string" },
+  },
+} as const;
+type CancelSwapSchemaType = JTDDataType<typeof cancelSwapSchema>;
+
 const ledgerChannelSchema = {
   properties: {
     ID: { type: "string" },
@@ -87,6 +98,51 @@ const ledgerChannelSchema = {
 } as const;
 type LedgerChannelSchemaType = JTDDataType<typeof ledgerChannelSchema>;
 
+const objectiveInfoSchema = {
+  properties: {
+    Id: { type: "string" },
+    Type: { type: "string" },
+    Status: { type: "string" },
+    WaitingFor: { type: "string" },
+    OwnedChannel: { type: "string" },
+    FailureReason: { type: "string" },
+    CreatedAt: { type: "string" },
+    UpdatedAt: { type: "string" },
+  },
+} as const;
+type ObjectiveInfoSchemaType = JTDDataType<typeof objectiveInfoSchema>;
+
+const objectiveInfosSchema = {
+  elements: {
+    ...objectiveInfoSchema,
+  },
+} as const;
+type ObjectiveInfosSchemaType = JTDDataType<typeof objectiveInfosSchema>;
+
+const swapHistorySchema = {
+  properties: {
+    Swaps: {
+      elements: {
+        properties: {
+          Id: { type: "string" },
+          ChannelId: { type: "string" },
+          Status: { type: "string" },
+          Counterparty: { type: "string" },
+          IsSender: { type: "boolean" },
+          TokenIn: { type: "string" },
+          TokenOut: { type: "string" },
+          AmountIn: { type: "string" },
+          AmountOut: { type: "string" },
+          Nonce: { type: "float64" },
+          RecordedAt: { type: "string" },
+        },
+      },
+    },
+    Total: { type: "uint32" },
+  },
+} as const;
+type SwapHistorySchemaType = JTDDataType<typeof swapHistorySchema>;
+
 const paymentChannelSchema = {
   properties: {
     ID: { type: "string" },
@@ -109,14 +165,13 @@ const swapChannelSchema = {
     ID: { type: "string" },
     Status: { type: "string" },
     Balances: {
-      // TODO: Check array inside properties
       elements: {
         properties: {
           AssetAddress: { type: "string" },
-          NodeA: { type: "string" },
-          NodeB: { type: "string" },
-          AmountNodeA: { type: "string" },
-          AmountNodeB: { type: "string" },
+          Me: { type: "string" },
+          Them: { type: "string" },
+          MyBalance: { type: "string" },
+          TheirBalance: { type: "string" },
         },
       },
     },
@@ -124,6 +179,43 @@ const swapChannelSchema = {
 } as const;
 type SwapChannelSchemaType = JTDDataType<typeof swapChannelSchema>;
 
+const swapChannelsSchema = {
+  elements: {
+    ...swapChannelSchema,
+  },
+} as const;
+type SwapChannelsSchemaType = JTDDataType<typeof swapChannelsSchema>;
+
+// Swap amounts and nonces are encoded as JSON numbers, which may exceed the uint32 range
+const pendingSwapSchema = {
+  properties: {
+    Id: { type: "string" },
+    ChannelId: { type: "string" },
+    Exchange: {
+      properties: {
+        TokenIn: { type: "string" },
+        TokenOut: { type: "string" },
+        AmountIn: { type: "float64" },
+        AmountOut: { type: "float64" },
+      },
+    },
+    Sigs: { values: { type: "string" }, nullable: true },
+    Nonce: { type: "float64" },
+    Expiry: { type: "float64" },
+  },
+  nullable: true,
+} as const;
+type PendingSwapSchemaType = JTDDataType<typeof pendingSwapSchema>;
+
+const recentSwapsSchema = {
+  elements: {
+    ...pendingSwapSchema,
+    nullable: false,
+  },
+  nullable: true,
+} as const;
+type RecentSwapsSchemaType = JTDDataType<typeof recentSwapsSchema>;
+
 const ledgerChannelsSchema = {
   elements: {
     ...ledgerChannelSchema,
@@ -157,10 +249,19 @@ const swapSchema = {
       },
     },
     Channel: { type: "string" },
+    SwapId: { type: "string" },
   },
 } as const;
 type swapSchemaType = JTDDataType<typeof swapSchema>;
 
+const swapAlongRouteSchema = {
+  properties: {
+    Id: { type: "string" },
+    SwapIds: { elements: { type: "string" } },
+  },
+} as const;
+type SwapAlongRouteSchemaType = JTDDataType<typeof swapAlongRouteSchema>;
+
 const voucherSchema = {
   properties: {
     ChannelId: { type: "string" },
@@ -195,15 +296,22 @@ type ResponseSchema =
   | typeof stringSchema
   | typeof ledgerChannelSchema
   | typeof ledgerChannelsSchema
+  | typeof objectiveInfoSchema
+  | typeof objectiveInfosSchema
+  | typeof swapHistorySchema
   | typeof paymentChannelSchema
   | typeof paymentChannelsSchema
   | typeof swapChannelSchema
+  | typeof swapChannelsSchema
+  | typeof pendingSwapSchema
+  | typeof recentSwapsSchema
   | typeof paymentSchema
   | typeof voucherSchema
   | typeof swapSchema
   | typeof receiveVoucherSchema
   | typeof counterChallengeSchema
   | typeof confirmSwapSchema
+  | typeof cancelSwapSchema
   | typeof getNodeInfoSchema;
 
 type ResponseSchemaType =
@@ -211,8 +319,14 @@ type ResponseSchemaType =
   | StringSchemaType
   | LedgerChannelSchemaType
   | LedgerChannelsSchemaType
+  | ObjectiveInfoSchemaType
+  | ObjectiveInfosSchemaType
+  | SwapHistorySchemaType
   | PaymentChannelSchemaType
   | SwapChannelSchemaType
+  | SwapChannelsSchemaType
+  | PendingSwapSchemaType
+  | RecentSwapsSchemaType
   | PaymentChannelsSchemaType
   | PaymentSchemaType
   | VoucherSchemaType
@@ -220,6 +334,7 @@ type ResponseSchemaType =
   | ReceiveVoucherSchemaType
   | CounterChallengeSchemaType
   | ConfirmSwapSchemaType
+  | CancelSwapSchemaType
   | GetNodeInfoSchemaType;
 
 /**
@@ -239,6 +354,7 @@ export function getAndValidateResult<T extends RequestMethod>(
   switch (method) {
     case "create_ledger_channel":
     case "create_payment_channel":
+    case "create_payment_channel_auto":
     case "create_swap_channel":
       return validateAndConvertResult(
         objectiveSchema,
@@ -259,18 +375,34 @@ export function getAndValidateResult<T extends RequestMethod>(
     case "get_signed_state":
     case "close_payment_channel":
     case "close_swap_channel":
-    case "get_pending_swap":
-    case "get_recent_swaps":
       return validateAndConvertResult(
         stringSchema,
         result,
         (result: StringSchemaType) => result
       );
+    case "get_pending_swap":
+      return validateAndConvertResult(
+        pendingSwapSchema,
+        result,
+        convertToInternalPendingSwapType
+      );
+    case "get_recent_swaps":
+      return validateAndConvertResult(
+        recentSwapsSchema,
+        result,
+        convertToInternalRecentSwapsType
+      );
     case "get_swap_channel":
       return validateAndConvertResult(
-        stringSchema,
+        swapChannelSchema,
         result,
-        convertToSwapChannelInfoType
+        convertToInternalSwapChannelType
+      );
+    case "get_all_swap_channels":
+      return validateAndConvertResult(
+        swapChannelsSchema,
+        result,
+        convertToInternalSwapChannelsType
       );
     case "get_ledger_channel":
       return validateAndConvertResult(
@@ -290,6 +422,12 @@ export function getAndValidateResult<T extends RequestMethod>(
         result,
         convertToConfirmSwapResultType
       );
+    case "cancel_swap":
+      return validateAndConvertResult(
+        cancelSwapSchema,
+        result,
+        (result: CancelSwapSchemaType) => result
+      );
     case "get_all_ledger_channels":
       return validateAndConvertResult(
         ledgerChannelsSchema,
@@ -302,6 +440,24 @@ export function getAndValidateResult<T extends RequestMethod>(
         result,
         convertToInternalLedgerChannelsType
       );
+    case "get_objective_info":
+      return validateAndConvertResult(
+        objectiveInfoSchema,
+        result,
+        convertToInternalObjectiveInfoType
+      );
+    case "list_objectives":
+      return validateAndConvertResult(
+        objectiveInfosSchema,
+        result,
+        convertToInternalObjectiveInfosType
+      );
+    case "list_swaps":
+      return validateAndConvertResult(
+        swapHistorySchema,
+        result,
+        convertToInternalSwapHistoryType
+      );
     case "get_payment_channel":
       return validateAndConvertResult(
         paymentChannelSchema,
@@ -326,6 +482,12 @@ export function getAndValidateResult<T extends RequestMethod>(
         result,
         (result: swapSchemaType) => result
       );
+    case "swap_along_route":
+      return validateAndConvertResult(
+        swapAlongRouteSchema,
+        result,
+        (result: SwapAlongRouteSchemaType) => result
+      );
     case "receive_voucher":
       return validateAndConvertResult(
         receiveVoucherSchema,
@@ -371,6 +533,8 @@ export function getAndValidateNotification<T extends RPCNotification["method"]>(
       return convertToInternalLedgerChannelType(
         data as LedgerChannelSchemaType
       );
+    case "swap_channel_updated":
+      return convertToInternalSwapChannelType(data as SwapChannelSchemaType);
     case "objective_completed":
       return data as string;
     default:
@@ -438,20 +602,51 @@ function convertToInternalLedgerChannelType(
   };
 }
 
-export const convertToSwapChannelInfoType = (
-  result: string
-): SwapChannelInfo => {
-  const swapChannelInfo = JSON.parse(result);
-  const modifiedSwapChannelInfo: SwapChannelInfo = {
-    ...swapChannelInfo,
-    Balances: swapChannelInfo.Balances.map((balance: Balance) => ({
+function convertToInternalSwapChannelType(
+  result: SwapChannelSchemaType
+): SwapChannelInfo {
+  return {
+    ...result,
+    Status: result.Status as ChannelStatus,
+    Balances: (result.Balances ?? []).map((balance) => ({
       ...balance,
-      MyBalance: BigInt(balance.MyBalance),
-      TheirBalance: BigInt(balance.TheirBalance),
+      MyBalance: BigInt(balance.MyBalance ?? 0),
+      TheirBalance: BigInt(balance.TheirBalance ?? 0),
     })),
   };
-  return modifiedSwapChannelInfo;
-};
+}
+
+function convertToInternalSwapChannelsType(
+  result: SwapChannelsSchemaType
+): SwapChannelInfo[] {
+  return result.map((sc) => convertToInternalSwapChannelType(sc));
+}
+
+function convertToInternalSwapType(
+  result: NonNullable<PendingSwapSchemaType>
+): Swap {
+  return {
+    ...result,
+    Exchange: {
+      ...result.Exchange,
+      AmountIn: BigInt(result.Exchange.AmountIn),
+      AmountOut: BigInt(result.Exchange.AmountOut),
+    },
+    Sigs: result.Sigs ?? {},
+  };
+}
+
+function convertToInternalPendingSwapType(
+  result: PendingSwapSchemaType
+): Swap | null {
+  return result === null ? null : convertToInternalSwapType(result);
+}
+
+function convertToInternalRecentSwapsType(
+  result: RecentSwapsSchemaType
+): Swap[] {
+  return (result ?? []).map((s) => convertToInternalSwapType(s));
+}
 
 function convertToCounterChallengeResultType(
   result: CounterChallengeSchemaType
@@ -479,6 +674,35 @@ function convertToInternalLedgerChannelsType(
   return result.map((lc) => convertToInternalLedgerChannelType(lc));
 }
 
+function convertToInternalObjectiveInfoType(
+  result: ObjectiveInfoSchemaType
+): ObjectiveInfo {
+  return {
+    ...result,
+    Status: result.Status as ObjectiveStatus,
+  };
+}
+
+function convertToInternalObjectiveInfosType(
+  result: ObjectiveInfosSchemaType
+): ObjectiveInfo[] {
+  return result.map((oi) => convertToInternalObjectiveInfoType(oi));
+}
+
+function convertToInternalSwapHistoryType(
+  result: SwapHistorySchemaType
+): SwapHistory {
+  return {
+    ...result,
+    Swaps: result.Swaps.map((swap) => ({
+      ...swap,
+      Status: swap.Status as SwapRecordStatus,
+      AmountIn: BigInt(swap.AmountIn ?? 0),
+      AmountOut: BigInt(swap.AmountOut ?? 0),
+    })),
+  };
+}
+
 function convertToInternalPaymentChannelType(
   result: PaymentChannelSchemaType
 ): PaymentChannelInfo {
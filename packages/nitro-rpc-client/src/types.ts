@@ -45,6 +45,21 @@ export type VirtualFundPayload = {
   Outcome: Outcome;
   Nonce: number;
   AppDefinition: string;
+  /**
+   * Fees paid to the intermediaries from the ledger channels as the channel is funded.
+   * They are part of the ledger channels' outcomes, so they are settled on chain as well.
+   */
+  IntermediaryFees?: IntermediaryFee[];
+};
+export type CreatePaymentChannelAutoPayload = {
+  Counterparty: string;
+  Asset: string;
+  Amount: number;
+  ChallengeDuration: number;
+};
+export type IntermediaryFee = {
+  Intermediary: string;
+  Amount: number;
 };
 export type SwapFundPayload = {
   Intermediaries: string[];
@@ -65,6 +80,26 @@ export type SwapInitiatePayload = {
   Channel: string;
 };
 
+export type SwapInitiateResult = SwapInitiatePayload & {
+  SwapId: string;
+};
+
+export type SwapRouteLeg = {
+  SwapAssetsData: SwapAssetsData;
+  Channel: string;
+  Sender: string;
+  Receiver: string;
+};
+
+export type SwapAlongRoutePayload = {
+  Legs: SwapRouteLeg[];
+};
+
+export type SwapAlongRouteResult = {
+  Id: string;
+  SwapIds: string[];
+};
+
 export enum ConfirmSwapAction {
   accepted = 1,
   rejected,
@@ -80,6 +115,10 @@ export type ConfirmSwapPayload = {
   Action: ConfirmSwapAction;
 };
 
+export type CancelSwapPayload = {
+  SwapId: string;
+};
+
 export enum CounterChallengeAction {
   checkpoint,
   challenge,
@@ -96,20 +135,27 @@ export type CounterChallengeResult = {
   Action: keyof typeof CounterChallengeAction;
 };
 
-export interface Balance {
-  AssetAddress: string;
-  Me: string;
-  Them: string;
-  MyBalance: string | bigint;
-  TheirBalance: string | bigint;
-}
-
 export interface SwapChannelInfo {
   ID: string;
-  Status: string;
-  Balances: Balance[];
+  Status: ChannelStatus;
+  Balances: SwapChannelBalance[];
 }
 
+export type Swap = {
+  Id: string;
+  ChannelId: string;
+  Exchange: {
+    TokenIn: string;
+    TokenOut: string;
+    AmountIn: bigint;
+    AmountOut: bigint;
+  };
+  // keyed by participant index in the swap channel
+  Sigs: Record<string, string>;
+  Nonce: number;
+  Expiry: number;
+};
+
 export type Voucher = {
   ChannelId: string;
   // todo: this should be a bigint
@@ -177,11 +223,21 @@ export type PaymentRequest = JsonRpcRequest<"pay", PaymentPayload>;
 
 export type SwapRequest = JsonRpcRequest<"swap_initiate", SwapInitiatePayload>;
 
+export type SwapAlongRouteRequest = JsonRpcRequest<
+  "swap_along_route",
+  SwapAlongRoutePayload
+>;
+
 export type ConfirmSwapRequest = JsonRpcRequest<
   "confirm_swap",
   ConfirmSwapPayload
 >;
 
+export type CancelSwapRequest = JsonRpcRequest<
+  "cancel_swap",
+  CancelSwapPayload
+>;
+
 export type CounterChallengeRequest = JsonRpcRequest<
   "counter_challenge",
   CounterChallengePayload
@@ -191,6 +247,10 @@ export type VirtualFundRequest = JsonRpcRequest<
   "create_payment_channel",
   VirtualFundPayload
 >;
+export type CreatePaymentChannelAutoRequest = JsonRpcRequest<
+  "create_payment_channel_auto",
+  CreatePaymentChannelAutoPayload
+>;
 export type SwapFundRequest = JsonRpcRequest<
   "create_swap_channel",
   SwapFundPayload
@@ -203,6 +263,10 @@ export type GetAllLedgerChannelsRequest = JsonRpcRequest<
   "get_all_ledger_channels",
   Record<string, never>
 >;
+export type GetAllSwapChannelsRequest = JsonRpcRequest<
+  "get_all_swap_channels",
+  Record<string, never>
+>;
 export type GetSignedStateRequest = JsonRpcRequest<
   "get_signed_state",
   GetChannelRequest
@@ -242,6 +306,29 @@ export type GetObjectiveRequest = JsonRpcRequest<
   }
 >;
 
+export type GetObjectiveInfoRequest = JsonRpcRequest<
+  "get_objective_info",
+  {
+    ObjectiveId: string;
+  }
+>;
+
+export type ListObjectivesRequest = JsonRpcRequest<
+  "list_objectives",
+  {
+    Status: ObjectiveStatus | "";
+  }
+>;
+
+export type ListSwapsRequest = JsonRpcRequest<
+  "list_swaps",
+  {
+    Filter: SwapFilter;
+    Offset: number;
+    Limit: number;
+  }
+>;
+
 export type GetPendingBridgeTxsRequest = JsonRpcRequest<
   "get_pending_bridge_txs",
   {
@@ -298,12 +385,14 @@ export type GetAuthTokenResponse = JsonRpcResponse<string>;
 export type GetPaymentChannelResponse = JsonRpcResponse<PaymentChannelInfo>;
 export type GetSwapChannelResponse = JsonRpcResponse<SwapChannelInfo>;
 export type GetVoucherResponse = JsonRpcResponse<Voucher>;
-export type GetPendingSwapResponse = JsonRpcResponse<string>;
-export type GetRecentSwapsResponse = JsonRpcResponse<string>;
+export type GetPendingSwapResponse = JsonRpcResponse<Swap | null>;
+export type GetRecentSwapsResponse = JsonRpcResponse<Swap[]>;
 export type PaymentResponse = JsonRpcResponse<PaymentPayload>;
-export type SwapResponse = JsonRpcResponse<SwapInitiatePayload>;
+export type SwapResponse = JsonRpcResponse<SwapInitiateResult>;
+export type SwapAlongRouteResponse = JsonRpcResponse<SwapAlongRouteResult>;
 export type CounterChallengeResponse = JsonRpcResponse<CounterChallengeResult>;
 export type ConfirmSwapResponse = JsonRpcResponse<ConfirmSwapResult>;
+export type CancelSwapResponse = JsonRpcResponse<CancelSwapPayload>;
 export type GetLedgerChannelResponse = JsonRpcResponse<LedgerChannelInfo>;
 export type VirtualFundResponse = JsonRpcResponse<ObjectiveResponse>;
 export type SwapFundResponse = JsonRpcResponse<ObjectiveResponse>;
@@ -319,11 +408,15 @@ export type BridgedDefundResponse = JsonRpcResponse<string>;
 export type VirtualDefundResponse = JsonRpcResponse<string>;
 export type SwapDefundResponse = JsonRpcResponse<string>;
 export type GetAllLedgerChannelsResponse = JsonRpcResponse<LedgerChannelInfo[]>;
+export type GetAllSwapChannelsResponse = JsonRpcResponse<SwapChannelInfo[]>;
 export type GetSignedStateResponse = JsonRpcResponse<string>;
 export type GetPaymentChannelsByLedgerResponse = JsonRpcResponse<
   PaymentChannelInfo[]
 >;
 export type GetObjectiveResponse = JsonRpcResponse<string>;
+export type GetObjectiveInfoResponse = JsonRpcResponse<ObjectiveInfo>;
+export type ListObjectivesResponse = JsonRpcResponse<ObjectiveInfo[]>;
+export type ListSwapsResponse = JsonRpcResponse<SwapHistory>;
 export type GetL2ObjectiveFromL1Response = JsonRpcResponse<string>;
 export type GetPendingBridgeTxsResponse = JsonRpcResponse<string>;
 export type CreateVoucherResponse = JsonRpcResponse<Voucher>;
@@ -346,6 +439,10 @@ export type RPCRequestAndResponses = {
   version: [VersionRequest, VersionResponse];
   get_node_info: [GetNodeInfoRequest, GetNodeInfoResponse];
   create_payment_channel: [VirtualFundRequest, VirtualFundResponse];
+  create_payment_channel_auto: [
+    CreatePaymentChannelAutoRequest,
+    VirtualFundResponse
+  ];
   create_swap_channel: [SwapFundRequest, SwapFundResponse];
   get_address: [GetAddressRequest, GetAddressResponse];
   get_ledger_channel: [GetLedgerChannelRequest, GetLedgerChannelResponse];
@@ -356,7 +453,9 @@ export type RPCRequestAndResponses = {
   get_recent_swaps: [GetRecentSwapsRequest, GetRecentSwapsResponse];
   pay: [PaymentRequest, PaymentResponse];
   swap_initiate: [SwapRequest, SwapResponse];
+  swap_along_route: [SwapAlongRouteRequest, SwapAlongRouteResponse];
   confirm_swap: [ConfirmSwapRequest, ConfirmSwapResponse];
+  cancel_swap: [CancelSwapRequest, CancelSwapResponse];
   counter_challenge: [CounterChallengeRequest, CounterChallengeResponse];
   close_payment_channel: [VirtualDefundRequest, VirtualDefundResponse];
   close_swap_channel: [SwapDefundRequest, SwapDefundResponse];
@@ -368,12 +467,19 @@ export type RPCRequestAndResponses = {
     GetAllLedgerChannelsRequest,
     GetAllLedgerChannelsResponse
   ];
+  get_all_swap_channels: [
+    GetAllSwapChannelsRequest,
+    GetAllSwapChannelsResponse
+  ];
   get_signed_state: [GetSignedStateRequest, GetSignedStateResponse];
   get_payment_channels_by_ledger: [
     GetPaymentChannelsByLedgerRequest,
     GetPaymentChannelsByLedgerResponse
   ];
   get_objective: [GetObjectiveRequest, GetObjectiveResponse];
+  get_objective_info: [GetObjectiveInfoRequest, GetObjectiveInfoResponse];
+  list_objectives: [ListObjectivesRequest, ListObjectivesResponse];
+  list_swaps: [ListSwapsRequest, ListSwapsResponse];
   get_l2_objective_from_l1: [
     GetL2ObjectiveFromL1Request,
     GetL2ObjectiveFromL1Response
@@ -399,7 +505,8 @@ export type RPCResponse =
 export type RPCNotification =
   | ObjectiveCompleteNotification
   | PaymentChannelUpdatedNotification
-  | LedgerChannelUpdatedNotification;
+  | LedgerChannelUpdatedNotification
+  | SwapChannelUpdatedNotification;
 export type NotificationMethod = RPCNotification["method"];
 export type NotificationParams = RPCNotification["params"];
 export type PaymentChannelUpdatedNotification = JsonRpcNotification<
@@ -412,6 +519,11 @@ export type LedgerChannelUpdatedNotification = JsonRpcNotification<
   LedgerChannelInfo
 >;
 
+export type SwapChannelUpdatedNotification = JsonRpcNotification<
+  "swap_channel_updated",
+  SwapChannelInfo
+>;
+
 export type ObjectiveCompleteNotification = JsonRpcNotification<
   "objective_completed",
   string
@@ -500,6 +612,60 @@ export type AssetMetadata = {
 
 export type ChannelStatus = "Proposed" | "Open" | "Closing" | "Complete";
 
+export type ObjectiveStatus =
+  | "Unapproved"
+  | "Approved"
+  | "Rejected"
+  | "Completed"
+  | "Failed";
+
+export type ObjectiveInfo = {
+  Id: string;
+  Type: string;
+  Status: ObjectiveStatus;
+  WaitingFor: string;
+  OwnedChannel: string;
+  FailureReason: string;
+  CreatedAt: string;
+  UpdatedAt: string;
+};
+
+export type SwapRecordStatus =
+  | "Accepted"
+  | "Rejected"
+  | "Cancelled"
+  | "Abandoned";
+
+export type SwapRecordInfo = {
+  Id: string;
+  ChannelId: string;
+  Status: SwapRecordStatus;
+  Counterparty: string;
+  IsSender: boolean;
+  TokenIn: string;
+  TokenOut: string;
+  AmountIn: bigint;
+  AmountOut: bigint;
+  Nonce: number;
+  RecordedAt: string;
+};
+
+export type SwapHistory = {
+  Swaps: SwapRecordInfo[];
+  Total: number;
+};
+
+/**
+ * SwapFilter selects swaps. Fields which are not set match every swap.
+ * From (inclusive) and To (exclusive) are RFC 3339 timestamps.
+ */
+export type SwapFilter = {
+  ChannelId?: string;
+  Token?: string;
+  From?: string;
+  To?: string;
+};
+
 export enum SwapStatus {
   PendingConfirmation,
   Accepted,
@@ -9,6 +9,7 @@ import {
   RequestMethod,
   RPCRequestAndResponses,
   SwapAssetsData,
+  SwapChannelInfo,
 } from "./types";
 
 export const RPC_PATH = "api/v1";
@@ -114,6 +115,12 @@ export async function logOutChannelUpdates(rpcClient: NitroRpcClient) {
       );
     }
   );
+  rpcClient.Notifications.on(
+    "swap_channel_updated",
+    (info: SwapChannelInfo) => {
+      console.log(`${shortAddress}: Swap channel update\n${prettyJson(info)}`);
+    }
+  );
 }
 
 export function prettyJson(obj: unknown): string {
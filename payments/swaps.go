@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -24,9 +25,11 @@ type Swap struct {
 	Exchange  Exchange
 	Sigs      map[uint]state.Signature // keyed by participant index in swap channel
 	Nonce     uint64
+	// Expiry is the unix time, in seconds, after which the swap may no longer be accepted. A zero expiry never expires.
+	Expiry uint64
 }
 
-func NewSwap(channelId types.Destination, tokenIn, tokenOut common.Address, amountIn, amountOut *big.Int, nonce uint64) Swap {
+func NewSwap(channelId types.Destination, tokenIn, tokenOut common.Address, amountIn, amountOut *big.Int, nonce uint64, expiry uint64) Swap {
 	swap := Swap{
 		ChannelId: channelId,
 		Exchange: Exchange{
@@ -35,8 +38,9 @@ func NewSwap(channelId types.Destination, tokenIn, tokenOut common.Address, amou
 			amountIn,
 			amountOut,
 		},
-		Sigs:  make(map[uint]state.Signature, 2),
-		Nonce: nonce,
+		Sigs:   make(map[uint]state.Signature, 2),
+		Nonce:  nonce,
+		Expiry: expiry,
 	}
 	swap.Id = swap.SwapId()
 
@@ -52,6 +56,7 @@ func (s Swap) encode() (types.Bytes, error) {
 		{Type: abi.Uint256},     // amountIn
 		{Type: abi.Uint256},     // amountOut
 		{Type: abi.Uint256},     // nonce
+		{Type: abi.Uint256},     // expiry
 	}.Pack(
 		s.ChannelId,
 		s.Exchange.TokenIn,
@@ -59,11 +64,17 @@ func (s Swap) encode() (types.Bytes, error) {
 		s.Exchange.AmountIn,
 		s.Exchange.AmountOut,
 		new(big.Int).SetUint64(s.Nonce),
+		new(big.Int).SetUint64(s.Expiry),
 	)
 }
 
 func (s Swap) Equal(target Swap) bool {
-	return s.ChannelId == target.ChannelId && s.Exchange.Equal(target.Exchange) && s.Nonce == target.Nonce
+	return s.ChannelId == target.ChannelId && s.Exchange.Equal(target.Exchange) && s.Nonce == target.Nonce && s.Expiry == target.Expiry
+}
+
+// IsExpired returns true if the swap may no longer be accepted at the given time
+func (s Swap) IsExpired(now time.Time) bool {
+	return s.Expiry != 0 && now.Unix() >= int64(s.Expiry)
 }
 
 func (s Swap) Clone() Swap {
@@ -80,9 +91,10 @@ func (s Swap) Clone() Swap {
 			AmountIn:  s.Exchange.AmountIn,
 			AmountOut: s.Exchange.AmountOut,
 		},
-		Sigs:  clonedSigs,
-		Nonce: s.Nonce,
-		Id:    s.Id,
+		Sigs:   clonedSigs,
+		Nonce:  s.Nonce,
+		Expiry: s.Expiry,
+		Id:     s.Id,
 	}
 }
 
@@ -135,6 +147,7 @@ type jsonSwap struct {
 	Exchange  Exchange
 	Sigs      map[uint]state.Signature // keyed by participant index in swap channel
 	Nonce     uint64
+	Expiry    uint64
 }
 
 func (s *Swap) MarshalJSON() ([]byte, error) {
@@ -144,6 +157,7 @@ func (s *Swap) MarshalJSON() ([]byte, error) {
 		Exchange:  s.Exchange,
 		Sigs:      s.Sigs,
 		Nonce:     s.Nonce,
+		Expiry:    s.Expiry,
 	}
 
 	return json.Marshal(jsonSwap)
@@ -161,6 +175,7 @@ func (s *Swap) UnmarshalJSON(data []byte) error {
 	s.Exchange = jsonSwap.Exchange
 	s.Sigs = jsonSwap.Sigs
 	s.Nonce = jsonSwap.Nonce
+	s.Expiry = jsonSwap.Expiry
 
 	return nil
 }
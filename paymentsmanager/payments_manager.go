@@ -1,6 +1,7 @@
 package paymentsmanager
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"math/big"
@@ -10,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/hashicorp/golang-lru/v2/expirable"
 	"github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/notifier"
 	"github.com/statechannels/go-nitro/node/query"
 	"github.com/statechannels/go-nitro/payments"
 	"github.com/statechannels/go-nitro/types"
@@ -84,10 +86,15 @@ func NewPaymentsManager(nitro *node.Node) (PaymentsManager, error) {
 func (pm *PaymentsManager) Start(wg *sync.WaitGroup) {
 	slog.Info("starting payments manager...")
 
+	// Subscribe synchronously so that no payment channel update is missed
+	ctx, cancel := context.WithCancel(context.Background())
+	paymentUpdates := pm.nitro.SubscribePaymentUpdates(ctx, notifier.SubscriptionOpts{})
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		pm.run()
+		defer cancel()
+		pm.run(paymentUpdates)
 	}()
 }
 
@@ -140,7 +147,7 @@ func (pm *PaymentsManager) checkVoucherInCache(voucherHash common.Hash, signerAd
 	return true, true
 }
 
-func (pm *PaymentsManager) run() {
+func (pm *PaymentsManager) run(paymentUpdates <-chan query.PaymentChannelInfo) {
 	slog.Info("starting voucher subscription...")
 	for {
 		select {
@@ -178,6 +185,14 @@ func (pm *PaymentsManager) run() {
 			}
 
 			vouchersMap.Add(voucherHash.Hex(), InFlightVoucher{voucher: voucher, amount: paymentAmount})
+		case paymentChannel, ok := <-paymentUpdates:
+			if !ok {
+				// Stop selecting on the closed subscription
+				paymentUpdates = nil
+				continue
+			}
+
+			pm.handlePaymentChannelUpdate(paymentChannel)
 		case <-pm.quitChan:
 			slog.Info("stopping voucher subscription loop...")
 			return
@@ -185,6 +200,17 @@ func (pm *PaymentsManager) run() {
 	}
 }
 
+// handlePaymentChannelUpdate stops tracking the amount paid on a payment channel once it is closed.
+//
+// No more vouchers can be received on a closed channel, so its entry in paidSoFarOnChannel is never read again, and loadPaymentChannels
+// only tracks open channels on startup. Since the cache is bounded by DEFAULT_LRU_CACHE_MAX_PAYMENT_CHANNELS, keeping the entry could
+// evict the amount paid on an open channel, and the next voucher on that channel would then be credited with the whole amount paid so far.
+func (pm *PaymentsManager) handlePaymentChannelUpdate(paymentChannel query.PaymentChannelInfo) {
+	if paymentChannel.Status == query.Complete {
+		pm.paidSoFarOnChannel.Remove(paymentChannel.ID.String())
+	}
+}
+
 func (pm *PaymentsManager) getChannelCounterparty(channelId types.Destination) (common.Address, error) {
 	paymentChannel, err := pm.nitro.GetPaymentChannel(channelId)
 	if err != nil {
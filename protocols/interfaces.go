@@ -147,6 +147,9 @@ type SideEffects struct {
 // WaitingFor is an enumerable "pause-point" computed from an Objective. It describes how the objective is blocked on actions by third parties (i.e. co-participants or the blockchain).
 type WaitingFor string
 
+// WaitingForNothing is the pause-point of every objective which has finished
+const WaitingForNothing WaitingFor = "WaitingForNothing"
+
 // AdjudicationStatus mirrors the on chain adjudication status of a particular channel.
 // Everything that is stored on chain, other than holdings.
 type AdjudicationStatus struct {
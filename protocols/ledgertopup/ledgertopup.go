@@ -0,0 +1,428 @@
+// Package ledgertopup implements a protocol to deposit more collateral into an open ledger channel.
+//
+// The depositor deposits on chain, and once the deposit is observed the participants agree a new consensus
+// state (via a resize proposal) which credits the deposit to the depositor. The ledger channel stays open
+// throughout, so guarantees for virtual channels can keep being added and removed while it is topped up.
+package ledgertopup // import "github.com/statechannels/go-nitro/protocols/ledgertopup"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	nitroAbi "github.com/statechannels/go-nitro/abi"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+const (
+	WaitingForApproval protocols.WaitingFor = "WaitingForApproval"
+	WaitingForDeposit  protocols.WaitingFor = "WaitingForDeposit"
+	WaitingForResize   protocols.WaitingFor = "WaitingForResize"
+	WaitingForNothing  protocols.WaitingFor = "WaitingForNothing" // Finished
+)
+
+const (
+	RequestPayload  protocols.PayloadType = "RequestPayload"
+	ApprovalPayload protocols.PayloadType = "ApprovalPayload"
+)
+
+const ObjectivePrefix = "LedgerTopUp-"
+
+const (
+	ErrLedgerNotFound = types.ConstError("could not find ledger channel")
+	ErrInvalidAmount  = types.ConstError("top up amount must be positive")
+	ErrUnknownAsset   = types.ConstError("ledger channel does not hold the asset")
+	ErrNotParticipant = types.ConstError("depositor is not a participant of the ledger channel")
+)
+
+// GetConsensusChannel describes functions which return a ConsensusChannel ledger channel for a channel id.
+type GetConsensusChannel func(channelId types.Destination) (ledger *consensus_channel.ConsensusChannel, err error)
+
+// Objective is a cache of data computed by reading from the store. It stores (potentially) infinite data
+type Objective struct {
+	Status    protocols.ObjectiveStatus
+	Ledger    *consensus_channel.ConsensusChannel
+	Depositor types.Address
+	Asset     common.Address
+	Amount    *big.Int
+	Nonce     uint64
+
+	// FundingTarget is the amount of the asset the ledger channel holds on chain once the deposit has been made
+	FundingTarget *big.Int
+
+	requestSent          bool // whether the depositor has asked the counterparty to approve the top up
+	approvalSent         bool // whether the counterparty has told the depositor it approved the top up
+	counterpartyApproved bool // whether the depositor has learned that the counterparty approved the top up
+	depositSubmitted     bool // whether the depositor has declared the deposit transaction as a side effect
+	resizeProposed       bool // whether the leader has proposed the resize
+	resizeAccepted       bool // whether the follower has signed the resize
+}
+
+// NewObjective creates a new ledger top up objective from a given request.
+func NewObjective(request ObjectiveRequest, preApprove bool, myAddress types.Address, getConsensusChannel GetConsensusChannel) (Objective, error) {
+	return newObjective(request.ChannelId, myAddress, request.Asset, request.Amount, request.Nonce, preApprove, getConsensusChannel)
+}
+
+// ConstructObjectiveFromPayload takes in a request payload from the depositor and constructs an objective from it.
+func ConstructObjectiveFromPayload(p protocols.ObjectivePayload, preApprove bool, getConsensusChannel GetConsensusChannel) (Objective, error) {
+	if p.Type != RequestPayload {
+		return Objective{}, fmt.Errorf("expected a %s, got a %s", RequestPayload, p.Type)
+	}
+
+	r := requestPayload{}
+	if err := json.Unmarshal(p.PayloadData, &r); err != nil {
+		return Objective{}, fmt.Errorf("could not unmarshal top up request: %w", err)
+	}
+
+	o, err := newObjective(r.ChannelId, r.Depositor, r.Asset, r.Amount, r.Nonce, preApprove, getConsensusChannel)
+	if err != nil {
+		return Objective{}, err
+	}
+	if o.isDepositor() {
+		return Objective{}, errors.New("received a top up request for a deposit of our own")
+	}
+	if o.Id() != p.ObjectiveId {
+		return Objective{}, fmt.Errorf("payload objective id %s does not match the top up request %s", p.ObjectiveId, o.Id())
+	}
+
+	return o, nil
+}
+
+func newObjective(ledgerId types.Destination, depositor types.Address, asset common.Address, amount *big.Int, nonce uint64, preApprove bool, getConsensusChannel GetConsensusChannel) (Objective, error) {
+	ledger, err := getConsensusChannel(ledgerId)
+	if err != nil {
+		return Objective{}, fmt.Errorf("%w %s: %w", ErrLedgerNotFound, ledgerId, err)
+	}
+	if amount == nil || amount.Sign() <= 0 {
+		return Objective{}, ErrInvalidAmount
+	}
+	if _, ok := ledger.ConsensusVars().AsState(ledger.FixedPart()).Outcome.TotalAllocated()[asset]; !ok {
+		return Objective{}, fmt.Errorf("%w %s", ErrUnknownAsset, asset)
+	}
+
+	isParticipant := false
+	for _, p := range ledger.Participants() {
+		isParticipant = isParticipant || p == depositor
+	}
+	if !isParticipant {
+		return Objective{}, ErrNotParticipant
+	}
+
+	held := big.NewInt(0)
+	if h, ok := ledger.OnChainFunding[asset]; ok && h != nil {
+		held.Set(h)
+	}
+
+	o := Objective{
+		Ledger:        ledger,
+		Depositor:     depositor,
+		Asset:         asset,
+		Amount:        new(big.Int).Set(amount),
+		Nonce:         nonce,
+		FundingTarget: held.Add(held, amount),
+	}
+	if preApprove {
+		o.Status = protocols.Approved
+	} else {
+		o.Status = protocols.Unapproved
+	}
+
+	return o, nil
+}
+
+// Id returns the unique id of the objective.
+func (o *Objective) Id() protocols.ObjectiveId {
+	return protocols.ObjectiveId(ObjectivePrefix + o.target().String())
+}
+
+// target identifies the resize proposal which credits the deposit.
+func (o *Objective) target() types.Destination {
+	return resizeTarget(o.Ledger.Id, o.Depositor, o.Asset, o.Amount, o.Nonce)
+}
+
+// Approve returns an approved copy of the objective.
+func (o *Objective) Approve() protocols.Objective {
+	updated := o.clone()
+	// todo: consider case of o.Status == Rejected
+	updated.Status = protocols.Approved
+
+	return &updated
+}
+
+// Reject returns a rejected copy of the objective, and a message informing the counterparty.
+func (o *Objective) Reject() (protocols.Objective, protocols.SideEffects) {
+	updated := o.clone()
+	updated.Status = protocols.Rejected
+
+	messages := protocols.CreateRejectionNoticeMessage(o.Id(), o.counterparty())
+	sideEffects := protocols.SideEffects{MessagesToSend: messages}
+	return &updated, sideEffects
+}
+
+// OwnsChannel returns the ledger channel being topped up.
+func (o *Objective) OwnsChannel() types.Destination {
+	return o.Ledger.Id
+}
+
+// GetStatus returns the status of the objective.
+func (o *Objective) GetStatus() protocols.ObjectiveStatus {
+	return o.Status
+}
+
+// Related returns the ledger channel, which needs to be stored along with the objective.
+func (o *Objective) Related() []protocols.Storable {
+	return []protocols.Storable{o.Ledger}
+}
+
+// Update receives a protocols.ObjectivePayload and applies it to a copy of the objective.
+func (o *Objective) Update(p protocols.ObjectivePayload) (protocols.Objective, error) {
+	if o.Id() != p.ObjectiveId {
+		return o, fmt.Errorf("event and objective Ids do not match: %s and %s respectively", string(p.ObjectiveId), string(o.Id()))
+	}
+
+	updated := o.clone()
+
+	switch p.Type {
+	case ApprovalPayload:
+		if updated.isDepositor() {
+			updated.counterpartyApproved = true
+		}
+	case RequestPayload:
+		// The counterparty constructs the objective from the request, so there is nothing to update
+	default:
+		return o, fmt.Errorf("unexpected payload type %s", p.Type)
+	}
+
+	return &updated, nil
+}
+
+// ReceiveProposal receives a signed resize proposal and applies it to a copy of the objective.
+func (o *Objective) ReceiveProposal(sp consensus_channel.SignedProposal) (protocols.ProposalReceiver, error) {
+	pId, err := protocols.GetProposalObjectiveId(sp.Proposal, types.Ledger)
+	if err != nil {
+		return o, err
+	}
+	if o.Id() != pId {
+		return o, fmt.Errorf("sp and objective Ids do not match: %s and %s respectively", string(pId), string(o.Id()))
+	}
+	if sp.Proposal.LedgerID != o.Ledger.Id {
+		return o, consensus_channel.ErrIncorrectChannelID
+	}
+
+	updated := o.clone()
+
+	err = updated.Ledger.Receive(sp)
+	// Ignore stale or future proposals
+	if err != nil && !errors.Is(err, consensus_channel.ErrInvalidTurnNum) {
+		return o, fmt.Errorf("error incorporating signed proposal %+v into objective: %w", sp, err)
+	}
+
+	return &updated, nil
+}
+
+// Crank inspects the extended state and declares a list of Effects to be executed.
+func (o *Objective) Crank(secretKey *[]byte) (protocols.Objective, protocols.SideEffects, protocols.WaitingFor, error) {
+	updated := o.clone()
+	sideEffects := protocols.SideEffects{}
+
+	if updated.Status != protocols.Approved {
+		return &updated, sideEffects, WaitingForNothing, protocols.ErrNotApproved
+	}
+
+	// The depositor only deposits once the counterparty has agreed to credit the deposit
+	if updated.isDepositor() && !updated.requestSent {
+		messages, err := protocols.CreateObjectivePayloadMessage(updated.Id(), updated.request(), RequestPayload, updated.counterparty())
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not create request payload message: %w", err)
+		}
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, messages...)
+		updated.requestSent = true
+	}
+	if !updated.isDepositor() && !updated.approvalSent {
+		messages, err := protocols.CreateObjectivePayloadMessage(updated.Id(), updated.request(), ApprovalPayload, updated.counterparty())
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not create approval payload message: %w", err)
+		}
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, messages...)
+		updated.approvalSent = true
+	}
+	if updated.isDepositor() && !updated.counterpartyApproved {
+		return &updated, sideEffects, WaitingForApproval, nil
+	}
+
+	if !updated.fundingComplete() {
+		if updated.isDepositor() && !updated.depositSubmitted {
+			deposit := types.Funds{updated.Asset: updated.Amount}
+			sideEffects.TransactionsToSubmit = append(sideEffects.TransactionsToSubmit, protocols.NewDepositTransaction(updated.Ledger.Id, deposit))
+			updated.depositSubmitted = true
+		}
+		return &updated, sideEffects, WaitingForDeposit, nil
+	}
+
+	if updated.Ledger.IsLeader() {
+		if !updated.resizeProposed {
+			_, err := updated.Ledger.Propose(updated.resizeProposal(), *secretKey)
+			if err != nil {
+				return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("error proposing ledger resize: %w", err)
+			}
+			// Since the proposal queue is constructed with consecutive turn numbers, we can pass it straight in
+			// to create a valid message with ordered proposals:
+			message := protocols.CreateSignedProposalMessage(updated.Ledger.Follower(), updated.Ledger.ProposalQueue()...)
+			sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, message)
+			updated.resizeProposed = true
+		}
+		if updated.Ledger.HasResizeBeenProposed(updated.target()) {
+			return &updated, sideEffects, WaitingForResize, nil
+		}
+	} else if !updated.resizeAccepted {
+		if !updated.Ledger.HasResizeBeenProposedNext(updated.target()) {
+			return &updated, sideEffects, WaitingForResize, nil
+		}
+		sp, err := updated.Ledger.SignNextProposal(updated.resizeProposal(), *secretKey)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("error accepting ledger resize: %w", err)
+		}
+		if proposals := updated.Ledger.ProposalQueue(); len(proposals) != 0 {
+			sideEffects.ProposalsToProcess = append(sideEffects.ProposalsToProcess, proposals[0].Proposal)
+		}
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, protocols.CreateSignedProposalMessage(updated.Ledger.Leader(), sp))
+		updated.resizeAccepted = true
+	}
+
+	updated.Status = protocols.Completed
+	return &updated, sideEffects, WaitingForNothing, nil
+}
+
+// fundingComplete returns true if the ledger channel holds the deposit on chain
+func (o *Objective) fundingComplete() bool {
+	held, ok := o.Ledger.OnChainFunding[o.Asset]
+	return ok && held != nil && held.Cmp(o.FundingTarget) >= 0
+}
+
+func (o *Objective) resizeProposal() consensus_channel.Proposal {
+	return consensus_channel.NewResizeProposal(o.Ledger.Id, o.target(), types.AddressToDestination(o.Depositor), o.Amount, o.Asset)
+}
+
+func (o *Objective) isDepositor() bool {
+	return o.Ledger.Participants()[o.Ledger.MyIndex] == o.Depositor
+}
+
+func (o *Objective) counterparty() types.Address {
+	return o.Ledger.Participants()[1-o.Ledger.MyIndex]
+}
+
+func (o *Objective) request() requestPayload {
+	return requestPayload{
+		ChannelId: o.Ledger.Id,
+		Depositor: o.Depositor,
+		Asset:     o.Asset,
+		Amount:    o.Amount,
+		Nonce:     o.Nonce,
+	}
+}
+
+// clone returns a deep copy of the receiver.
+func (o *Objective) clone() Objective {
+	clone := Objective{}
+	clone.Status = o.Status
+	clone.Ledger = o.Ledger.Clone()
+	clone.Depositor = o.Depositor
+	clone.Asset = o.Asset
+	clone.Amount = new(big.Int).Set(o.Amount)
+	clone.Nonce = o.Nonce
+	clone.FundingTarget = new(big.Int).Set(o.FundingTarget)
+
+	clone.requestSent = o.requestSent
+	clone.approvalSent = o.approvalSent
+	clone.counterpartyApproved = o.counterpartyApproved
+	clone.depositSubmitted = o.depositSubmitted
+	clone.resizeProposed = o.resizeProposed
+	clone.resizeAccepted = o.resizeAccepted
+
+	return clone
+}
+
+// IsLedgerTopUpObjective inspects an objective id and returns true if the objective id is for a ledger top up objective.
+func IsLedgerTopUpObjective(id protocols.ObjectiveId) bool {
+	return strings.HasPrefix(string(id), ObjectivePrefix)
+}
+
+// requestPayload is sent by the depositor to the counterparty, which constructs the objective from it
+type requestPayload struct {
+	ChannelId types.Destination
+	Depositor types.Address
+	Asset     common.Address
+	Amount    *big.Int
+	Nonce     uint64
+}
+
+// resizeTarget derives a unique target for the resize proposal of a top up
+func resizeTarget(ledgerId types.Destination, depositor types.Address, asset common.Address, amount *big.Int, nonce uint64) types.Destination {
+	encoded, err := abi.Arguments{
+		{Type: nitroAbi.Bytes32},
+		{Type: nitroAbi.Address},
+		{Type: nitroAbi.Address},
+		{Type: nitroAbi.Uint256},
+		{Type: nitroAbi.Uint256},
+	}.Pack(ledgerId, depositor, asset, amount, new(big.Int).SetUint64(nonce))
+	if err != nil {
+		panic(fmt.Errorf("failed to encode top up: %w", err))
+	}
+	return types.Destination(crypto.Keccak256Hash(encoded))
+}
+
+// ObjectiveRequest represents a request to create a new ledger top up objective.
+type ObjectiveRequest struct {
+	ChannelId        types.Destination
+	Asset            common.Address
+	Amount           *big.Int
+	Nonce            uint64
+	objectiveStarted chan struct{}
+}
+
+// NewObjectiveRequest creates a new ObjectiveRequest.
+func NewObjectiveRequest(channelId types.Destination, asset common.Address, amount *big.Int, nonce uint64) ObjectiveRequest {
+	return ObjectiveRequest{
+		ChannelId:        channelId,
+		Asset:            asset,
+		Amount:           amount,
+		Nonce:            nonce,
+		objectiveStarted: make(chan struct{}),
+	}
+}
+
+// Id returns the objective id for the request.
+func (r ObjectiveRequest) Id(myAddress types.Address, chainId *big.Int) protocols.ObjectiveId {
+	return protocols.ObjectiveId(ObjectivePrefix + resizeTarget(r.ChannelId, myAddress, r.Asset, r.Amount, r.Nonce).String())
+}
+
+// SignalObjectiveStarted is used by the engine to signal the objective has been started.
+func (r ObjectiveRequest) SignalObjectiveStarted() {
+	close(r.objectiveStarted)
+}
+
+// WaitForObjectiveToStart blocks until the objective starts
+func (r ObjectiveRequest) WaitForObjectiveToStart() {
+	<-r.objectiveStarted
+}
+
+// ObjectiveResponse is the type returned across the API in response to the ObjectiveRequest.
+type ObjectiveResponse struct {
+	Id        protocols.ObjectiveId
+	ChannelId types.Destination
+}
+
+// Response computes and returns the appropriate response from the request.
+func (r ObjectiveRequest) Response(myAddress types.Address, chainId *big.Int) ObjectiveResponse {
+	return ObjectiveResponse{
+		Id:        r.Id(myAddress, chainId),
+		ChannelId: r.ChannelId,
+	}
+}
@@ -0,0 +1,297 @@
+package ledgertopup
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+const (
+	ledgerDeposit = 10
+	topUpAmount   = 5
+)
+
+// network runs a ledger top up between a leader and a follower, standing in for their engines and the chain.
+type network struct {
+	t      *testing.T
+	actors []testactors.Actor
+	// ledgers holds every actor's copy of the ledger channel
+	ledgers    map[types.Address]*consensus_channel.ConsensusChannel
+	objectives map[types.Address]*Objective
+	inbox      []protocols.Message
+	// deposits are the deposits submitted by every actor
+	deposits map[types.Address][]protocols.ChainTransaction
+}
+
+func newNetwork(t *testing.T, leader, follower testactors.Actor) *network {
+	n := &network{
+		t:          t,
+		actors:     []testactors.Actor{leader, follower},
+		ledgers:    make(map[types.Address]*consensus_channel.ConsensusChannel),
+		objectives: make(map[types.Address]*Objective),
+		deposits:   make(map[types.Address][]protocols.ChainTransaction),
+	}
+	n.ledgers[leader.Address()] = prepareConsensusChannel(0, leader, follower)
+	n.ledgers[follower.Address()] = prepareConsensusChannel(1, leader, follower)
+
+	return n
+}
+
+// prepareConsensusChannel returns the given role's copy of a funded ledger channel between the leader and the follower.
+func prepareConsensusChannel(role uint, leader, follower testactors.Actor) *consensus_channel.ConsensusChannel {
+	fp := state.FixedPart{
+		Participants:      []types.Address{leader.Address(), follower.Address()},
+		ChannelNonce:      1,
+		ChallengeDuration: 45,
+	}
+	lo := *consensus_channel.NewLedgerOutcome(
+		types.Address{},
+		consensus_channel.NewBalance(leader.Destination(), big.NewInt(ledgerDeposit)),
+		consensus_channel.NewBalance(follower.Destination(), big.NewInt(ledgerDeposit)),
+		[]consensus_channel.Guarantee{},
+	)
+	vars := consensus_channel.Vars{Outcome: lo, TurnNum: 1}
+
+	sigs := [2]state.Signature{}
+	for i, a := range []testactors.Actor{leader, follower} {
+		sig, err := vars.AsState(fp).Sign(a.PrivateKey)
+		if err != nil {
+			panic(err)
+		}
+		sigs[i] = sig
+	}
+
+	var cc consensus_channel.ConsensusChannel
+	var err error
+	if role == 0 {
+		cc, err = consensus_channel.NewLeaderChannel(fp, 1, lo, sigs)
+	} else {
+		cc, err = consensus_channel.NewFollowerChannel(fp, 1, lo, sigs)
+	}
+	if err != nil {
+		panic(err)
+	}
+	cc.OnChainFunding = types.Funds{types.Address{}: big.NewInt(2 * ledgerDeposit)}
+
+	return &cc
+}
+
+func (n *network) getConsensusChannel(me types.Address) GetConsensusChannel {
+	return func(channelId types.Destination) (*consensus_channel.ConsensusChannel, error) {
+		ledger, ok := n.ledgers[me]
+		if !ok || ledger.Id != channelId {
+			return nil, errors.New("no such ledger channel")
+		}
+		return ledger.Clone(), nil
+	}
+}
+
+func (n *network) actor(address types.Address) testactors.Actor {
+	for _, a := range n.actors {
+		if a.Address() == address {
+			return a
+		}
+	}
+	n.t.Fatalf("unknown actor %s", address)
+	return testactors.Actor{}
+}
+
+// start creates the objective of the depositor.
+func (n *network) start(depositor testactors.Actor) {
+	ledgerId := n.ledgers[depositor.Address()].Id
+	request := NewObjectiveRequest(ledgerId, types.Address{}, big.NewInt(topUpAmount), 1)
+	o, err := NewObjective(request, true, depositor.Address(), n.getConsensusChannel(depositor.Address()))
+	if err != nil {
+		n.t.Fatal(err)
+	}
+	n.store(depositor.Address(), &o)
+}
+
+// store replaces the actor's objective, and its copy of the ledger channel.
+func (n *network) store(address types.Address, o protocols.Objective) {
+	lto := o.(*Objective)
+	n.objectives[address] = lto
+	n.ledgers[address] = lto.Ledger
+}
+
+// crank cranks every objective which is in progress, and queues the messages it sends.
+func (n *network) crank() {
+	for _, a := range n.actors {
+		o, ok := n.objectives[a.Address()]
+		if !ok || o.Status != protocols.Approved {
+			continue
+		}
+
+		updated, sideEffects, _, err := o.Crank(&a.PrivateKey)
+		if err != nil {
+			n.t.Fatalf("%s could not progress the top up: %v", a.Name, err)
+		}
+		n.store(a.Address(), updated)
+		n.inbox = append(n.inbox, sideEffects.MessagesToSend...)
+		n.deposits[a.Address()] = append(n.deposits[a.Address()], sideEffects.TransactionsToSubmit...)
+	}
+}
+
+// deliver delivers the queued messages, returning the number of messages delivered.
+func (n *network) deliver() int {
+	inbox := n.inbox
+	n.inbox = nil
+
+	for _, message := range inbox {
+		recipient := n.actor(message.To)
+
+		for _, payload := range message.ObjectivePayloads {
+			o, ok := n.objectives[recipient.Address()]
+			if !ok {
+				constructed, err := ConstructObjectiveFromPayload(payload, true, n.getConsensusChannel(recipient.Address()))
+				if err != nil {
+					n.t.Fatal(err)
+				}
+				o = &constructed
+			}
+			updated, err := o.Update(payload)
+			if err != nil {
+				n.t.Fatal(err)
+			}
+			n.store(recipient.Address(), updated)
+		}
+
+		for _, sp := range message.LedgerProposals {
+			updated, err := n.objectives[recipient.Address()].ReceiveProposal(sp)
+			if err != nil {
+				n.t.Fatal(err)
+			}
+			n.store(recipient.Address(), updated.(protocols.Objective))
+		}
+	}
+
+	return len(inbox)
+}
+
+func (n *network) run() {
+	for i := 0; i < 100; i++ {
+		n.crank()
+		if n.deliver() == 0 && len(n.inbox) == 0 {
+			return
+		}
+	}
+	n.t.Fatal("the top up did not settle down")
+}
+
+// mineDeposits lets both actors observe the deposits, as the engine does when the deposit events arrive.
+func (n *network) mineDeposits() {
+	deposited := big.NewInt(0)
+	for _, transactions := range n.deposits {
+		for _, tx := range transactions {
+			deposit := tx.(protocols.DepositTransaction)
+			deposited.Add(deposited, deposit.Deposit[types.Address{}])
+		}
+	}
+
+	for _, a := range n.actors {
+		o := n.objectives[a.Address()]
+		o.Ledger.OnChainFunding = types.Funds{types.Address{}: new(big.Int).Add(big.NewInt(2*ledgerDeposit), deposited)}
+	}
+}
+
+// balances returns the balances of the leader and the follower, as seen by both of them.
+func (n *network) balances() (leaderBalance, followerBalance *big.Int) {
+	n.t.Helper()
+
+	leader, follower := n.actors[0], n.actors[1]
+	leaderView, followerView := n.ledgers[leader.Address()], n.ledgers[follower.Address()]
+	if leaderView.ConsensusTurnNum() != followerView.ConsensusTurnNum() {
+		n.t.Fatalf("the leader and the follower disagree on the ledger channel: turn %d and turn %d", leaderView.ConsensusTurnNum(), followerView.ConsensusTurnNum())
+	}
+
+	outcome := leaderView.ConsensusVars().AsState(leaderView.FixedPart()).Outcome[0]
+	return outcome.Allocations[0].Amount, outcome.Allocations[1].Amount
+}
+
+func TestLedgerTopUp(t *testing.T) {
+	for _, depositorRole := range []uint{0, 1} {
+		alice, bob := testactors.Alice, testactors.Bob
+		n := newNetwork(t, alice, bob)
+		depositor := n.actors[depositorRole]
+
+		n.start(depositor)
+		n.run()
+
+		if len(n.deposits[depositor.Address()]) != 1 {
+			t.Fatalf("expected %s to submit a deposit, got %d transactions", depositor.Name, len(n.deposits[depositor.Address()]))
+		}
+		for _, o := range n.objectives {
+			if o.Status == protocols.Completed {
+				t.Errorf("expected the top up not to complete before the deposit is observed")
+			}
+		}
+
+		n.mineDeposits()
+		n.run()
+
+		for _, a := range n.actors {
+			o := n.objectives[a.Address()]
+			if o.Status != protocols.Completed {
+				t.Errorf("%s's top up has status %v, wanted %v", a.Name, o.Status, protocols.Completed)
+			}
+			if len(o.Ledger.ProposalQueue()) != 0 {
+				t.Errorf("%s's ledger channel has pending proposals", a.Name)
+			}
+		}
+
+		wantBalances := []int64{ledgerDeposit, ledgerDeposit}
+		wantBalances[depositorRole] += topUpAmount
+		leaderBalance, followerBalance := n.balances()
+		if leaderBalance.Int64() != wantBalances[0] || followerBalance.Int64() != wantBalances[1] {
+			t.Errorf("%s topped up: got balances %v and %v, wanted %v", depositor.Name, leaderBalance, followerBalance, wantBalances)
+		}
+	}
+}
+
+func TestLedgerTopUpWaitsForApproval(t *testing.T) {
+	alice, bob := testactors.Alice, testactors.Bob
+	n := newNetwork(t, alice, bob)
+
+	n.start(bob)
+	n.crank()
+
+	o := n.objectives[bob.Address()]
+	_, sideEffects, waitingFor, err := o.Crank(&bob.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if waitingFor != WaitingForApproval || len(sideEffects.TransactionsToSubmit) != 0 {
+		t.Errorf("expected the depositor to wait for approval without depositing, got %s and %d transactions", waitingFor, len(sideEffects.TransactionsToSubmit))
+	}
+}
+
+func TestNewObjectiveRejectsInvalidTopUps(t *testing.T) {
+	alice, bob, irene := testactors.Alice, testactors.Bob, testactors.Irene
+	n := newNetwork(t, alice, bob)
+	ledgerId := n.ledgers[alice.Address()].Id
+
+	tests := []struct {
+		name      string
+		depositor types.Address
+		asset     common.Address
+		amount    *big.Int
+		want      error
+	}{
+		{"zero amount", alice.Address(), types.Address{}, big.NewInt(0), ErrInvalidAmount},
+		{"unknown asset", alice.Address(), common.HexToAddress("0x01"), big.NewInt(topUpAmount), ErrUnknownAsset},
+		{"not a participant", irene.Address(), types.Address{}, big.NewInt(topUpAmount), ErrNotParticipant},
+	}
+	for _, tc := range tests {
+		request := NewObjectiveRequest(ledgerId, tc.asset, tc.amount, 1)
+		if _, err := NewObjective(request, true, tc.depositor, n.getConsensusChannel(alice.Address())); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package ledgertopup
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// jsonObjective replaces the ledgertopup.Objective's ledger pointer with
+// the ledger's ID, making jsonObjective suitable for serialization
+type jsonObjective struct {
+	Status               protocols.ObjectiveStatus
+	Ledger               types.Destination
+	Depositor            types.Address
+	Asset                common.Address
+	Amount               *big.Int
+	Nonce                uint64
+	FundingTarget        *big.Int
+	RequestSent          bool
+	ApprovalSent         bool
+	CounterpartyApproved bool
+	DepositSubmitted     bool
+	ResizeProposed       bool
+	ResizeAccepted       bool
+}
+
+// MarshalJSON returns a JSON representation of the Objective
+// NOTE: Marshal -> Unmarshal is a lossy process. All ledger data
+// (other than Id) from the field Ledger is discarded
+func (o *Objective) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonObjective{
+		Status:               o.Status,
+		Ledger:               o.Ledger.Id,
+		Depositor:            o.Depositor,
+		Asset:                o.Asset,
+		Amount:               o.Amount,
+		Nonce:                o.Nonce,
+		FundingTarget:        o.FundingTarget,
+		RequestSent:          o.requestSent,
+		ApprovalSent:         o.approvalSent,
+		CounterpartyApproved: o.counterpartyApproved,
+		DepositSubmitted:     o.depositSubmitted,
+		ResizeProposed:       o.resizeProposed,
+		ResizeAccepted:       o.resizeAccepted,
+	})
+}
+
+// UnmarshalJSON populates the calling Objective with the
+// json-encoded data
+// NOTE: Marshal -> Unmarshal is a lossy process. All ledger data
+// (other than Id) from the field Ledger is discarded
+func (o *Objective) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var jsonO jsonObjective
+	if err := json.Unmarshal(data, &jsonO); err != nil {
+		return err
+	}
+
+	o.Status = jsonO.Status
+	o.Ledger = &consensus_channel.ConsensusChannel{Id: jsonO.Ledger}
+	o.Depositor = jsonO.Depositor
+	o.Asset = jsonO.Asset
+	o.Amount = jsonO.Amount
+	o.Nonce = jsonO.Nonce
+	o.FundingTarget = jsonO.FundingTarget
+	o.requestSent = jsonO.RequestSent
+	o.approvalSent = jsonO.ApprovalSent
+	o.counterpartyApproved = jsonO.CounterpartyApproved
+	o.depositSubmitted = jsonO.DepositSubmitted
+	o.resizeProposed = jsonO.ResizeProposed
+	o.resizeAccepted = jsonO.ResizeAccepted
+
+	return nil
+}
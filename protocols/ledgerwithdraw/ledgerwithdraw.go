@@ -0,0 +1,655 @@
+// Package ledgerwithdraw implements a protocol to withdraw part of a participant's balance from an open ledger channel.
+//
+// The adjudicator only pays out of finalized channels, so the withdrawal splices the ledger channel: the participants
+// agree a successor ledger channel holding the remaining funds, and then finalize the original ledger channel with an
+// outcome that pays the withdrawal out and transfers everything else into the successor. The successor takes over
+// as soon as the final state is supported, so the participants can keep funding virtual channels while the
+// withdrawal is settled on chain.
+//
+// The ConsensusChannel of the ledger channel keeps governing it until the successor state is supported. It is then
+// retired, and a Channel governs the ledger channel until it is defunded. If the withdrawal is rejected or fails
+// before we sign the final state, the ConsensusChannel is restored.
+package ledgerwithdraw // import "github.com/statechannels/go-nitro/protocols/ledgerwithdraw"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/directdefund"
+	"github.com/statechannels/go-nitro/types"
+)
+
+const (
+	WaitingForSuccessor    protocols.WaitingFor = "WaitingForSuccessor"
+	WaitingForLedger       protocols.WaitingFor = "WaitingForLedger" // the ConsensusChannel of the ledger channel to be retired
+	WaitingForFinalization protocols.WaitingFor = "WaitingForFinalization"
+	WaitingForWithdraw     protocols.WaitingFor = "WaitingForWithdraw"
+	WaitingForNothing      protocols.WaitingFor = "WaitingForNothing" // Finished
+)
+
+const (
+	RequestPayload     protocols.PayloadType = "RequestPayload"
+	SignedStatePayload protocols.PayloadType = "SignedStatePayload"
+)
+
+const ObjectivePrefix = "LedgerWithdraw-"
+
+const (
+	ErrLedgerNotFound    = types.ConstError("could not find ledger channel")
+	ErrInvalidAmount     = types.ConstError("withdrawal amount must be positive")
+	ErrInsufficientFunds = types.ConstError("withdrawal exceeds the participant's balance")
+	ErrUpdateInProgress  = types.ConstError("can only withdraw from a ledger channel without pending proposals")
+	ErrNotParticipant    = types.ConstError("withdrawer is not a participant of the ledger channel")
+	ErrLedgerInUse       = types.ConstError("cannot withdraw from a ledger channel which funds objectives in progress")
+	ErrLedgerChanged     = types.ConstError("the ledger channel has been updated since the withdrawal was agreed")
+)
+
+// GetConsensusChannel describes functions which return a ConsensusChannel ledger channel for a channel id.
+type GetConsensusChannel func(channelId types.Destination) (ledger *consensus_channel.ConsensusChannel, err error)
+
+// Objective is a cache of data computed by reading from the store. It stores (potentially) infinite data
+type Objective struct {
+	Status protocols.ObjectiveStatus
+	// C is the ledger channel being withdrawn from, which is governed by a Channel until it is defunded
+	C *channel.Channel
+	// Successor is the first state of the ledger channel which takes over the remaining funds
+	Successor  state.SignedState
+	Withdrawer types.Address
+	Asset      common.Address
+	Amount     *big.Int
+
+	finalTurnNum uint64
+	// successorFunding is what the withdrawAll transaction transferred into the successor, which is not announced by a deposit event
+	successorFunding types.Funds
+
+	requestSent                  bool
+	ledgerRetired                bool
+	withdrawTransactionSubmitted bool
+	withdrawalOverdue            bool
+}
+
+// NewObjective creates a new ledger withdraw objective from a given request.
+func NewObjective(request ObjectiveRequest, preApprove bool, myAddress types.Address, getConsensusChannel GetConsensusChannel) (Objective, error) {
+	cc, err := getConsensusChannel(request.ChannelId)
+	if err != nil {
+		return Objective{}, fmt.Errorf("%w %s: %w", ErrLedgerNotFound, request.ChannelId, err)
+	}
+	return newObjective(*cc, myAddress, request.Asset, request.Amount, request.Nonce, preApprove)
+}
+
+// ConstructObjectiveFromPayload takes in a request payload from the withdrawer and constructs an objective from it.
+func ConstructObjectiveFromPayload(p protocols.ObjectivePayload, preApprove bool, getConsensusChannel GetConsensusChannel) (Objective, error) {
+	if p.Type != RequestPayload {
+		return Objective{}, fmt.Errorf("expected a %s, got a %s", RequestPayload, p.Type)
+	}
+
+	r := requestPayload{}
+	if err := json.Unmarshal(p.PayloadData, &r); err != nil {
+		return Objective{}, fmt.Errorf("could not unmarshal withdraw request: %w", err)
+	}
+
+	cc, err := getConsensusChannel(r.ChannelId)
+	if err != nil {
+		return Objective{}, fmt.Errorf("%w %s: %w", ErrLedgerNotFound, r.ChannelId, err)
+	}
+	// The withdrawer splices the ledger channel as of its latest consensus state, which must also be ours
+	if cc.ConsensusTurnNum() != r.TurnNum {
+		return Objective{}, fmt.Errorf("withdraw request is for turn %d, but the ledger channel is at turn %d", r.TurnNum, cc.ConsensusTurnNum())
+	}
+
+	o, err := newObjective(*cc, r.Withdrawer, r.Asset, r.Amount, r.Nonce, preApprove)
+	if err != nil {
+		return Objective{}, err
+	}
+	if o.isWithdrawer() {
+		return Objective{}, errors.New("received a withdraw request for a withdrawal of our own")
+	}
+	if o.Id() != p.ObjectiveId {
+		return Objective{}, fmt.Errorf("payload objective id %s does not match the withdraw request %s", p.ObjectiveId, o.Id())
+	}
+
+	return o, nil
+}
+
+func newObjective(cc consensus_channel.ConsensusChannel, withdrawer types.Address, asset common.Address, amount *big.Int, nonce uint64, preApprove bool) (Objective, error) {
+	if len(cc.ProposalQueue()) != 0 {
+		return Objective{}, ErrUpdateInProgress
+	}
+	if amount == nil || amount.Sign() <= 0 {
+		return Objective{}, ErrInvalidAmount
+	}
+
+	isParticipant := false
+	for _, p := range cc.Participants() {
+		isParticipant = isParticipant || p == withdrawer
+	}
+	if !isParticipant {
+		return Objective{}, ErrNotParticipant
+	}
+
+	c, err := directdefund.CreateChannelFromConsensusChannel(cc)
+	if err != nil {
+		return Objective{}, fmt.Errorf("could not create Channel from ConsensusChannel; %w", err)
+	}
+
+	current := cc.ConsensusVars().AsState(cc.FixedPart())
+
+	// The successor has the same outcome as the ledger channel, less the withdrawal
+	successorOutcome := current.Outcome.Clone()
+	debited := false
+	for _, assetExit := range successorOutcome {
+		if assetExit.Asset != asset {
+			continue
+		}
+		for _, allocation := range assetExit.Allocations {
+			if allocation.Destination == types.AddressToDestination(withdrawer) {
+				if allocation.Amount.Cmp(amount) < 0 {
+					return Objective{}, ErrInsufficientFunds
+				}
+				allocation.Amount.Sub(allocation.Amount, amount)
+				debited = true
+			}
+		}
+	}
+	if !debited {
+		return Objective{}, fmt.Errorf("%w: no balance of asset %s", ErrInsufficientFunds, asset)
+	}
+
+	successor := state.StateFromFixedAndVariablePart(
+		SuccessorFixedPart(cc.FixedPart(), nonce),
+		state.VariablePart{Outcome: successorOutcome, TurnNum: channel.PostFundTurnNum},
+	)
+
+	o := Objective{
+		C:            c,
+		Successor:    state.NewSignedState(successor),
+		Withdrawer:   withdrawer,
+		Asset:        asset,
+		Amount:       new(big.Int).Set(amount),
+		finalTurnNum: current.TurnNum + 1,
+	}
+	if preApprove {
+		o.Status = protocols.Approved
+	} else {
+		o.Status = protocols.Unapproved
+	}
+
+	return o, nil
+}
+
+// SuccessorFixedPart returns the fixed part of the ledger channel which succeeds the ledger channel with the given fixed part.
+func SuccessorFixedPart(fp state.FixedPart, nonce uint64) state.FixedPart {
+	successor := fp.Clone()
+	successor.ChannelNonce = nonce
+	return successor
+}
+
+// Id returns the unique id of the objective.
+func (o *Objective) Id() protocols.ObjectiveId {
+	return protocols.ObjectiveId(ObjectivePrefix + o.C.Id.String())
+}
+
+// SuccessorId returns the id of the ledger channel which holds the remaining funds.
+func (o *Objective) SuccessorId() types.Destination {
+	return o.Successor.State().ChannelId()
+}
+
+// Approve returns an approved copy of the objective.
+func (o *Objective) Approve() protocols.Objective {
+	updated := o.clone()
+	// todo: consider case of o.Status == Rejected
+	updated.Status = protocols.Approved
+
+	return &updated
+}
+
+// Reject returns a rejected copy of the objective, and a message informing the counterparty.
+func (o *Objective) Reject() (protocols.Objective, protocols.SideEffects) {
+	updated := o.clone()
+	updated.Status = protocols.Rejected
+
+	messages := protocols.CreateRejectionNoticeMessage(o.Id(), o.counterparty())
+	sideEffects := protocols.SideEffects{MessagesToSend: messages}
+	return &updated, sideEffects
+}
+
+// OwnsChannel returns the ledger channel being withdrawn from.
+func (o *Objective) OwnsChannel() types.Destination {
+	return o.C.Id
+}
+
+// GetStatus returns the status of the objective.
+func (o *Objective) GetStatus() protocols.ObjectiveStatus {
+	return o.Status
+}
+
+// Related returns the ledger channel being withdrawn from, which needs to be stored along with the objective.
+func (o *Objective) Related() []protocols.Storable {
+	return []protocols.Storable{o.C}
+}
+
+// Update receives a protocols.ObjectivePayload and applies it to a copy of the objective.
+func (o *Objective) Update(p protocols.ObjectivePayload) (protocols.Objective, error) {
+	if o.Id() != p.ObjectiveId {
+		return o, fmt.Errorf("event and objective Ids do not match: %s and %s respectively", string(p.ObjectiveId), string(o.Id()))
+	}
+
+	updated := o.clone()
+
+	switch p.Type {
+	case RequestPayload:
+		// The counterparty constructs the objective from the request, so there is nothing to update
+		return &updated, nil
+	case SignedStatePayload:
+	default:
+		return o, fmt.Errorf("unexpected payload type %s", p.Type)
+	}
+
+	ss := state.SignedState{}
+	if err := json.Unmarshal(p.PayloadData, &ss); err != nil {
+		return o, fmt.Errorf("could not unmarshal signed state: %w", err)
+	}
+
+	switch ss.State().ChannelId() {
+	case o.SuccessorId():
+		if err := updated.Successor.Merge(ss); err != nil {
+			return o, fmt.Errorf("could not add signatures to the successor state: %w", err)
+		}
+	case o.C.Id:
+		final, err := updated.finalState()
+		if err != nil {
+			return o, err
+		}
+		if !ss.State().Equal(final) {
+			return o, errors.New("received an unexpected final state")
+		}
+		updated.C.AddSignedState(ss)
+	default:
+		return o, fmt.Errorf("signed state is for an unexpected channel %s", ss.State().ChannelId())
+	}
+
+	return &updated, nil
+}
+
+// Crank inspects the extended state and declares a list of Effects to be executed.
+func (o *Objective) Crank(secretKey *[]byte) (protocols.Objective, protocols.SideEffects, protocols.WaitingFor, error) {
+	updated := o.clone()
+	sideEffects := protocols.SideEffects{}
+
+	if updated.Status != protocols.Approved {
+		return &updated, sideEffects, WaitingForNothing, protocols.ErrNotApproved
+	}
+
+	// The successor is signed before the final state, so that the remaining funds are never
+	// transferred to a ledger channel without a supported state
+	if !updated.Successor.HasSignatureForParticipant(updated.C.MyIndex) {
+		sig, err := updated.Successor.State().Sign(*secretKey)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not sign successor state: %w", err)
+		}
+		if err := updated.Successor.AddSignature(sig); err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not add signature to successor state: %w", err)
+		}
+
+		message := protocols.Message{To: updated.counterparty()}
+		if updated.isWithdrawer() && !updated.requestSent {
+			payload, err := protocols.CreateObjectivePayload(updated.Id(), RequestPayload, updated.request())
+			if err != nil {
+				return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not create request payload: %w", err)
+			}
+			message.ObjectivePayloads = append(message.ObjectivePayloads, payload)
+			updated.requestSent = true
+		}
+		payload, err := protocols.CreateObjectivePayload(updated.Id(), SignedStatePayload, updated.Successor)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not create payload: %w", err)
+		}
+		message.ObjectivePayloads = append(message.ObjectivePayloads, payload)
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, message)
+	}
+	if !updated.Successor.HasAllSignatures() {
+		return &updated, sideEffects, WaitingForSuccessor, nil
+	}
+	// The engine retires the ConsensusChannel of the ledger channel, so that it cannot be updated once we sign the final state
+	if !updated.ledgerRetired {
+		return &updated, sideEffects, WaitingForLedger, nil
+	}
+
+	if !updated.C.FinalSignedByMe() {
+		final, err := updated.finalState()
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, err
+		}
+		ss, err := updated.C.SignAndAddState(final, secretKey)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not sign final state: %w", err)
+		}
+		messages, err := protocols.CreateObjectivePayloadMessage(updated.Id(), ss, SignedStatePayload, updated.counterparty())
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not create payload message: %w", err)
+		}
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, messages...)
+	}
+	if !updated.C.FinalCompleted() {
+		return &updated, sideEffects, WaitingForFinalization, nil
+	}
+
+	if updated.C.OnChain.Holdings.IsNonZero() {
+		// The withdrawer submits the withdrawAll transaction, which also transfers the remaining funds into the successor.
+		// The counterparty submits it instead once the withdrawal is overdue, since the successor is unfunded until then.
+		if (updated.isWithdrawer() || updated.withdrawalOverdue) && !updated.withdrawTransactionSubmitted {
+			final, err := updated.C.LatestSupportedSignedState()
+			if err != nil {
+				return o, protocols.SideEffects{}, WaitingForNothing, err
+			}
+			sideEffects.TransactionsToSubmit = append(sideEffects.TransactionsToSubmit, protocols.NewWithdrawAllTransaction(updated.C.Id, final))
+			updated.withdrawTransactionSubmitted = true
+		}
+		return &updated, sideEffects, WaitingForWithdraw, nil
+	}
+
+	updated.Status = protocols.Completed
+	return &updated, sideEffects, WaitingForNothing, nil
+}
+
+// SuccessorReady returns true once the ledger channel being withdrawn from has a supported final state,
+// from which point the successor governs the remaining funds.
+func (o *Objective) SuccessorReady() bool {
+	return o.Successor.HasAllSignatures() && o.C.FinalCompleted()
+}
+
+// CreateSuccessorConsensusChannel creates the ConsensusChannel which succeeds the ledger channel being withdrawn from.
+// It is funded by what the withdrawAll transaction has transferred into it so far.
+func (o *Objective) CreateSuccessorConsensusChannel() (*consensus_channel.ConsensusChannel, error) {
+	if !o.SuccessorReady() {
+		return nil, fmt.Errorf("expected the withdrawal from channel %s to be finalized", o.C.Id)
+	}
+
+	con, err := newConsensusChannel(o.Successor, o.C.MyIndex, o.successorFunding.Clone())
+	if err != nil {
+		return nil, fmt.Errorf("could not create successor consensus channel: %w", err)
+	}
+	return con, nil
+}
+
+// AwaitingLedgerRetirement returns true once the successor state is supported, until the ConsensusChannel of the
+// ledger channel being withdrawn from is retired.
+func (o *Objective) AwaitingLedgerRetirement() bool {
+	return o.Status == protocols.Approved && o.Successor.HasAllSignatures() && !o.ledgerRetired
+}
+
+// RetireLedger returns a copy of the objective which goes on to sign the final state, given the ConsensusChannel of the
+// ledger channel being withdrawn from. The ledger channel must still be at the consensus state spliced by the withdrawal.
+// The caller is responsible for destroying the ConsensusChannel.
+func (o *Objective) RetireLedger(ledger *consensus_channel.ConsensusChannel) (protocols.Objective, error) {
+	if ledger.Id != o.C.Id {
+		return o, fmt.Errorf("expected ledger channel %s, got %s", o.C.Id, ledger.Id)
+	}
+	if ledger.ConsensusTurnNum() != o.finalTurnNum-1 || len(ledger.ProposalQueue()) != 0 {
+		return o, fmt.Errorf("%w: expected turn %d without pending proposals, got turn %d with %d pending proposals",
+			ErrLedgerChanged, o.finalTurnNum-1, ledger.ConsensusTurnNum(), len(ledger.ProposalQueue()))
+	}
+
+	updated := o.clone()
+	updated.ledgerRetired = true
+	return &updated, nil
+}
+
+// LedgerRestorable returns true if the ConsensusChannel of the ledger channel being withdrawn from has been retired,
+// but we have not signed the final state. The ConsensusChannel should then be restored if the withdrawal is abandoned.
+func (o *Objective) LedgerRestorable() bool {
+	return o.ledgerRetired && !o.C.FinalSignedByMe()
+}
+
+// RestoreLedger recreates the ConsensusChannel of the ledger channel being withdrawn from, at the consensus state
+// spliced by the withdrawal.
+func (o *Objective) RestoreLedger() (*consensus_channel.ConsensusChannel, error) {
+	if !o.LedgerRestorable() {
+		return nil, fmt.Errorf("cannot restore ledger channel %s, which is not retired or whose final state we have signed", o.C.Id)
+	}
+	latest, ok := o.C.OffChain.SignedStateForTurnNum[o.finalTurnNum-1]
+	if !ok {
+		return nil, fmt.Errorf("could not find the consensus state of channel %s", o.C.Id)
+	}
+
+	con, err := newConsensusChannel(latest, o.C.MyIndex, o.C.OnChain.Holdings.Clone())
+	if err != nil {
+		return nil, fmt.Errorf("could not restore consensus channel: %w", err)
+	}
+	return con, nil
+}
+
+// RecordSuccessorFunding returns a copy of the objective which records the funds of the given asset transferred into the
+// successor, when the ledger channel's holdings were paid out from the given amount, along with the amount transferred.
+// The transfer is recorded once per asset; the amount transferred by a repeated payout is zero.
+func (o *Objective) RecordSuccessorFunding(asset common.Address, held *big.Int) (*Objective, *big.Int, error) {
+	transferred := big.NewInt(0)
+	if _, recorded := o.successorFunding[asset]; recorded {
+		return o, transferred, nil
+	}
+
+	final, err := o.C.LatestSupportedState()
+	if err != nil {
+		return o, nil, err
+	}
+	if !final.IsFinal {
+		return o, nil, fmt.Errorf("expected channel %s to be finalized", o.C.Id)
+	}
+
+	// The adjudicator pays allocations out in order, for as long as the holdings last
+	remaining := big.NewInt(0)
+	if held != nil {
+		remaining.Set(held)
+	}
+	successorId := o.SuccessorId()
+	for _, assetExit := range final.Outcome {
+		if assetExit.Asset != asset {
+			continue
+		}
+		for _, allocation := range assetExit.Allocations {
+			payout := allocation.Amount
+			if payout.Cmp(remaining) > 0 {
+				payout = remaining
+			}
+			if allocation.Destination == successorId {
+				transferred.Add(transferred, payout)
+			}
+			remaining = new(big.Int).Sub(remaining, payout)
+		}
+	}
+
+	updated := o.clone()
+	updated.successorFunding = updated.successorFunding.Add(types.Funds{asset: transferred})
+	return &updated, transferred, nil
+}
+
+// AwaitingWithdrawal returns true if the final state is supported, and the withdrawAll transaction has not been mined.
+func (o *Objective) AwaitingWithdrawal() bool {
+	return o.Status == protocols.Approved && o.C.FinalCompleted() && o.C.OnChain.Holdings.IsNonZero()
+}
+
+// MarkWithdrawalOverdue returns a copy of the objective in which the counterparty of the withdrawer also submits the withdrawAll transaction.
+func (o *Objective) MarkWithdrawalOverdue() *Objective {
+	updated := o.clone()
+	updated.withdrawalOverdue = true
+	return &updated
+}
+
+// newConsensusChannel creates the ConsensusChannel governed by the given supported state.
+func newConsensusChannel(ss state.SignedState, myIndex uint, funding types.Funds) (*consensus_channel.ConsensusChannel, error) {
+	leaderSig, err := ss.GetParticipantSignature(uint(consensus_channel.Leader))
+	if err != nil {
+		return nil, fmt.Errorf("could not get leader signature: %w", err)
+	}
+	followerSig, err := ss.GetParticipantSignature(uint(consensus_channel.Follower))
+	if err != nil {
+		return nil, fmt.Errorf("could not get follower signature: %w", err)
+	}
+	signatures := [2]state.Signature{leaderSig, followerSig}
+
+	s := ss.State()
+	outcomes, err := consensus_channel.FromExit(s.Outcome)
+	if err != nil {
+		return nil, fmt.Errorf("could not create ledger outcome from channel exit: %w", err)
+	}
+
+	var con consensus_channel.ConsensusChannel
+	if myIndex == uint(consensus_channel.Leader) {
+		con, err = consensus_channel.NewLeaderChannel(s.FixedPart(), s.TurnNum, outcomes, signatures)
+	} else {
+		con, err = consensus_channel.NewFollowerChannel(s.FixedPart(), s.TurnNum, outcomes, signatures)
+	}
+	if err != nil {
+		return nil, err
+	}
+	con.OnChainFunding = funding
+
+	return &con, nil
+}
+
+// finalState returns the final state of the ledger channel being withdrawn from, which pays out
+// the withdrawal and transfers the remaining funds into the successor.
+func (o *Objective) finalState() (state.State, error) {
+	latest, ok := o.C.OffChain.SignedStateForTurnNum[o.finalTurnNum-1]
+	if !ok {
+		return state.State{}, fmt.Errorf("could not find the consensus state of channel %s", o.C.Id)
+	}
+
+	final := latest.State().Clone()
+	final.TurnNum = o.finalTurnNum
+	final.IsFinal = true
+
+	successorId := o.SuccessorId()
+	for i, assetExit := range final.Outcome {
+		remaining := assetExit.TotalAllocated()
+		allocations := outcome.Allocations{}
+		if assetExit.Asset == o.Asset {
+			allocations = append(allocations, outcome.Allocation{
+				Destination: types.AddressToDestination(o.Withdrawer),
+				Amount:      new(big.Int).Set(o.Amount),
+			})
+			remaining.Sub(remaining, o.Amount)
+		}
+		allocations = append(allocations, outcome.Allocation{Destination: successorId, Amount: remaining})
+		final.Outcome[i].Allocations = allocations
+	}
+
+	return final, nil
+}
+
+func (o *Objective) isWithdrawer() bool {
+	return o.C.Participants[o.C.MyIndex] == o.Withdrawer
+}
+
+func (o *Objective) counterparty() types.Address {
+	return o.C.Participants[1-o.C.MyIndex]
+}
+
+func (o *Objective) request() requestPayload {
+	return requestPayload{
+		ChannelId:  o.C.Id,
+		Withdrawer: o.Withdrawer,
+		Asset:      o.Asset,
+		Amount:     o.Amount,
+		Nonce:      o.Successor.State().ChannelNonce,
+		TurnNum:    o.finalTurnNum - 1,
+	}
+}
+
+// clone returns a deep copy of the receiver.
+func (o *Objective) clone() Objective {
+	clone := Objective{}
+	clone.Status = o.Status
+	clone.C = o.C.Clone()
+	clone.Successor = o.Successor.Clone()
+	clone.Withdrawer = o.Withdrawer
+	clone.Asset = o.Asset
+	clone.Amount = new(big.Int).Set(o.Amount)
+	clone.finalTurnNum = o.finalTurnNum
+	clone.successorFunding = o.successorFunding.Clone()
+
+	clone.requestSent = o.requestSent
+	clone.ledgerRetired = o.ledgerRetired
+	clone.withdrawTransactionSubmitted = o.withdrawTransactionSubmitted
+	clone.withdrawalOverdue = o.withdrawalOverdue
+
+	return clone
+}
+
+// IsLedgerWithdrawObjective inspects an objective id and returns true if the objective id is for a ledger withdraw objective.
+func IsLedgerWithdrawObjective(id protocols.ObjectiveId) bool {
+	return strings.HasPrefix(string(id), ObjectivePrefix)
+}
+
+// requestPayload is sent by the withdrawer to the counterparty, which constructs the objective from it
+type requestPayload struct {
+	ChannelId  types.Destination
+	Withdrawer types.Address
+	Asset      common.Address
+	Amount     *big.Int
+	Nonce      uint64
+	// TurnNum is the turn of the consensus state being spliced
+	TurnNum uint64
+}
+
+// ObjectiveRequest represents a request to create a new ledger withdraw objective.
+type ObjectiveRequest struct {
+	ChannelId types.Destination
+	Asset     common.Address
+	Amount    *big.Int
+	// Nonce is the channel nonce of the successor ledger channel
+	Nonce            uint64
+	objectiveStarted chan struct{}
+}
+
+// NewObjectiveRequest creates a new ObjectiveRequest.
+func NewObjectiveRequest(channelId types.Destination, asset common.Address, amount *big.Int, nonce uint64) ObjectiveRequest {
+	return ObjectiveRequest{
+		ChannelId:        channelId,
+		Asset:            asset,
+		Amount:           amount,
+		Nonce:            nonce,
+		objectiveStarted: make(chan struct{}),
+	}
+}
+
+// Id returns the objective id for the request.
+func (r ObjectiveRequest) Id(myAddress types.Address, chainId *big.Int) protocols.ObjectiveId {
+	return protocols.ObjectiveId(ObjectivePrefix + r.ChannelId.String())
+}
+
+// SignalObjectiveStarted is used by the engine to signal the objective has been started.
+func (r ObjectiveRequest) SignalObjectiveStarted() {
+	close(r.objectiveStarted)
+}
+
+// WaitForObjectiveToStart blocks until the objective starts
+func (r ObjectiveRequest) WaitForObjectiveToStart() {
+	<-r.objectiveStarted
+}
+
+// ObjectiveResponse is the type returned across the API in response to the ObjectiveRequest.
+type ObjectiveResponse struct {
+	Id        protocols.ObjectiveId
+	ChannelId types.Destination
+	// SuccessorId is the ledger channel which holds the remaining funds once the withdrawal is made
+	SuccessorId types.Destination
+}
+
+// Response computes and returns the appropriate response from the request, given the fixed part of the ledger channel.
+func (r ObjectiveRequest) Response(myAddress types.Address, chainId *big.Int, ledger state.FixedPart) ObjectiveResponse {
+	return ObjectiveResponse{
+		Id:          r.Id(myAddress, chainId),
+		ChannelId:   r.ChannelId,
+		SuccessorId: SuccessorFixedPart(ledger, r.Nonce).ChannelId(),
+	}
+}
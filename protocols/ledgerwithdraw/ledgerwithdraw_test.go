@@ -0,0 +1,422 @@
+package ledgerwithdraw
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+const (
+	ledgerDeposit  = 10
+	withdrawAmount = 3
+	ledgerTurnNum  = 1
+)
+
+// network runs a ledger withdrawal between a leader and a follower, standing in for their engines and the chain.
+type network struct {
+	t      *testing.T
+	actors []testactors.Actor
+	// ledgers holds every actor's copy of the ConsensusChannel, until it is retired
+	ledgers    map[types.Address]*consensus_channel.ConsensusChannel
+	objectives map[types.Address]*Objective
+	inbox      []protocols.Message
+	// transactions are the transactions submitted by every actor
+	transactions map[types.Address][]protocols.ChainTransaction
+	// offline actors neither send nor receive messages
+	offline map[types.Address]bool
+}
+
+func newNetwork(t *testing.T, leader, follower testactors.Actor) *network {
+	n := &network{
+		t:            t,
+		actors:       []testactors.Actor{leader, follower},
+		ledgers:      make(map[types.Address]*consensus_channel.ConsensusChannel),
+		objectives:   make(map[types.Address]*Objective),
+		transactions: make(map[types.Address][]protocols.ChainTransaction),
+		offline:      make(map[types.Address]bool),
+	}
+	n.ledgers[leader.Address()] = prepareConsensusChannel(0, ledgerTurnNum, leader, follower)
+	n.ledgers[follower.Address()] = prepareConsensusChannel(1, ledgerTurnNum, leader, follower)
+
+	return n
+}
+
+// prepareConsensusChannel returns the given role's copy of a funded ledger channel between the leader and the follower, at the given turn.
+func prepareConsensusChannel(role uint, turnNum uint64, leader, follower testactors.Actor) *consensus_channel.ConsensusChannel {
+	fp := state.FixedPart{
+		Participants:      []types.Address{leader.Address(), follower.Address()},
+		ChannelNonce:      1,
+		ChallengeDuration: 45,
+	}
+	lo := *consensus_channel.NewLedgerOutcome(
+		types.Address{},
+		consensus_channel.NewBalance(leader.Destination(), big.NewInt(ledgerDeposit)),
+		consensus_channel.NewBalance(follower.Destination(), big.NewInt(ledgerDeposit)),
+		[]consensus_channel.Guarantee{},
+	)
+	vars := consensus_channel.Vars{Outcome: lo, TurnNum: turnNum}
+
+	sigs := [2]state.Signature{}
+	for i, a := range []testactors.Actor{leader, follower} {
+		sig, err := vars.AsState(fp).Sign(a.PrivateKey)
+		if err != nil {
+			panic(err)
+		}
+		sigs[i] = sig
+	}
+
+	var cc consensus_channel.ConsensusChannel
+	var err error
+	if role == 0 {
+		cc, err = consensus_channel.NewLeaderChannel(fp, turnNum, lo, sigs)
+	} else {
+		cc, err = consensus_channel.NewFollowerChannel(fp, turnNum, lo, sigs)
+	}
+	if err != nil {
+		panic(err)
+	}
+	cc.OnChainFunding = types.Funds{types.Address{}: big.NewInt(2 * ledgerDeposit)}
+
+	return &cc
+}
+
+func (n *network) getConsensusChannel(me types.Address) GetConsensusChannel {
+	return func(channelId types.Destination) (*consensus_channel.ConsensusChannel, error) {
+		ledger, ok := n.ledgers[me]
+		if !ok || ledger.Id != channelId {
+			return nil, errors.New("no such ledger channel")
+		}
+		return ledger.Clone(), nil
+	}
+}
+
+func (n *network) actor(address types.Address) testactors.Actor {
+	for _, a := range n.actors {
+		if a.Address() == address {
+			return a
+		}
+	}
+	n.t.Fatalf("unknown actor %s", address)
+	return testactors.Actor{}
+}
+
+// start creates the objective of the withdrawer.
+func (n *network) start(withdrawer testactors.Actor) {
+	ledgerId := n.ledgers[withdrawer.Address()].Id
+	request := NewObjectiveRequest(ledgerId, types.Address{}, big.NewInt(withdrawAmount), 2)
+	o, err := NewObjective(request, true, withdrawer.Address(), n.getConsensusChannel(withdrawer.Address()))
+	if err != nil {
+		n.t.Fatal(err)
+	}
+	n.objectives[withdrawer.Address()] = &o
+}
+
+// crank cranks every objective which is in progress, retiring the ConsensusChannel like the engine once the successor is supported.
+func (n *network) crank() {
+	for _, a := range n.actors {
+		o, ok := n.objectives[a.Address()]
+		if !ok || n.offline[a.Address()] || o.Status != protocols.Approved {
+			continue
+		}
+
+		updated, sideEffects, _, err := o.Crank(&a.PrivateKey)
+		if err != nil {
+			n.t.Fatalf("%s could not progress the withdrawal: %v", a.Name, err)
+		}
+		lwo := updated.(*Objective)
+		if lwo.AwaitingLedgerRetirement() {
+			retired, err := lwo.RetireLedger(n.ledgers[a.Address()])
+			if err != nil {
+				n.t.Fatalf("%s could not retire the ledger channel: %v", a.Name, err)
+			}
+			delete(n.ledgers, a.Address())
+
+			var more protocols.SideEffects
+			updated, more, _, err = retired.Crank(&a.PrivateKey)
+			if err != nil {
+				n.t.Fatalf("%s could not progress the withdrawal: %v", a.Name, err)
+			}
+			sideEffects.Merge(more)
+		}
+
+		n.objectives[a.Address()] = updated.(*Objective)
+		n.inbox = append(n.inbox, sideEffects.MessagesToSend...)
+		n.transactions[a.Address()] = append(n.transactions[a.Address()], sideEffects.TransactionsToSubmit...)
+	}
+}
+
+// deliver delivers the queued messages, returning the number of messages delivered.
+func (n *network) deliver() int {
+	inbox := n.inbox
+	n.inbox = nil
+
+	delivered := 0
+	for _, message := range inbox {
+		if n.offline[message.To] || n.offline[message.From] {
+			continue
+		}
+		delivered++
+		recipient := n.actor(message.To)
+
+		for _, payload := range message.ObjectivePayloads {
+			o, ok := n.objectives[recipient.Address()]
+			if !ok {
+				constructed, err := ConstructObjectiveFromPayload(payload, true, n.getConsensusChannel(recipient.Address()))
+				if err != nil {
+					n.t.Fatal(err)
+				}
+				o = &constructed
+			}
+			updated, err := o.Update(payload)
+			if err != nil {
+				n.t.Fatal(err)
+			}
+			n.objectives[recipient.Address()] = updated.(*Objective)
+		}
+
+		for range message.RejectedObjectives {
+			rejected, _ := n.objectives[recipient.Address()].Reject()
+			n.objectives[recipient.Address()] = rejected.(*Objective)
+		}
+	}
+
+	return delivered
+}
+
+// runUntil cranks the objectives and delivers their messages until the condition holds, or no more messages are sent.
+func (n *network) runUntil(condition func() bool) {
+	for i := 0; i < 100; i++ {
+		n.crank()
+		if n.deliver() == 0 && len(n.inbox) == 0 {
+			return
+		}
+		if condition() {
+			return
+		}
+	}
+	n.t.Fatal("the withdrawal did not settle down")
+}
+
+func (n *network) run() {
+	n.runUntil(func() bool { return false })
+}
+
+// mineWithdrawal pays out the ledger channel as the adjudicator would, and lets both actors observe the payout.
+func (n *network) mineWithdrawal(held *big.Int) {
+	for _, a := range n.actors {
+		o := n.objectives[a.Address()]
+		funded, _, err := o.RecordSuccessorFunding(types.Address{}, held)
+		if err != nil {
+			n.t.Fatal(err)
+		}
+		funded.C.OnChain.Holdings = types.Funds{}
+		n.objectives[a.Address()] = funded
+	}
+}
+
+func TestLedgerWithdraw(t *testing.T) {
+	alice, bob := testactors.Alice, testactors.Bob
+	n := newNetwork(t, alice, bob)
+
+	n.start(bob)
+	n.run()
+
+	if len(n.ledgers) != 0 {
+		t.Errorf("expected both ConsensusChannels to be retired")
+	}
+	if len(n.transactions[bob.Address()]) != 1 || len(n.transactions[alice.Address()]) != 0 {
+		t.Fatalf("expected only the withdrawer to submit the withdrawAll transaction, got %d and %d transactions",
+			len(n.transactions[bob.Address()]), len(n.transactions[alice.Address()]))
+	}
+	if _, ok := n.transactions[bob.Address()][0].(protocols.WithdrawAllTransaction); !ok {
+		t.Errorf("expected a withdrawAll transaction, got %T", n.transactions[bob.Address()][0])
+	}
+
+	n.mineWithdrawal(big.NewInt(2 * ledgerDeposit))
+	n.run()
+
+	for _, a := range n.actors {
+		o := n.objectives[a.Address()]
+		if o.Status != protocols.Completed {
+			t.Errorf("%s's withdrawal has status %v, wanted %v", a.Name, o.Status, protocols.Completed)
+		}
+
+		successor, err := o.CreateSuccessorConsensusChannel()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := big.NewInt(2*ledgerDeposit - withdrawAmount); successor.OnChainFunding[types.Address{}].Cmp(want) != 0 {
+			t.Errorf("%s's successor is funded with %v, wanted %v", a.Name, successor.OnChainFunding[types.Address{}], want)
+		}
+		outcome := successor.ConsensusVars().AsState(successor.FixedPart()).Outcome[0]
+		if outcome.Allocations[0].Amount.Int64() != ledgerDeposit || outcome.Allocations[1].Amount.Int64() != ledgerDeposit-withdrawAmount {
+			t.Errorf("%s's successor has unexpected balances: %+v", a.Name, outcome.Allocations)
+		}
+	}
+}
+
+func TestLedgerRetiredOnceSuccessorIsSupported(t *testing.T) {
+	alice, bob := testactors.Alice, testactors.Bob
+	n := newNetwork(t, alice, bob)
+
+	// Alice goes offline, so Bob never receives her signature on the successor
+	n.offline[alice.Address()] = true
+	n.start(bob)
+	n.run()
+
+	o := n.objectives[bob.Address()]
+	if o.AwaitingLedgerRetirement() || o.ledgerRetired {
+		t.Error("expected the ledger channel not to be retired before the successor is supported")
+	}
+	if _, ok := n.ledgers[bob.Address()]; !ok {
+		t.Error("expected Bob's ConsensusChannel to govern the ledger channel")
+	}
+	if o.C.FinalSignedByMe() {
+		t.Error("expected Bob not to sign the final state before the successor is supported")
+	}
+}
+
+func TestRetireLedgerRejectsUpdatedLedger(t *testing.T) {
+	alice, bob := testactors.Alice, testactors.Bob
+	n := newNetwork(t, alice, bob)
+
+	n.start(bob)
+	n.runUntil(func() bool {
+		o, ok := n.objectives[bob.Address()]
+		return ok && o.AwaitingLedgerRetirement()
+	})
+
+	// Bob's ledger channel was updated in the meantime
+	o := n.objectives[bob.Address()]
+	updated := prepareConsensusChannel(1, ledgerTurnNum+1, alice, bob)
+	if _, err := o.RetireLedger(updated); !errors.Is(err, ErrLedgerChanged) {
+		t.Errorf("expected %v, got %v", ErrLedgerChanged, err)
+	}
+}
+
+func TestRestoreLedger(t *testing.T) {
+	alice, bob := testactors.Alice, testactors.Bob
+	n := newNetwork(t, alice, bob)
+	original := n.ledgers[bob.Address()].Clone()
+
+	n.start(bob)
+	n.runUntil(func() bool {
+		o, ok := n.objectives[bob.Address()]
+		return ok && o.AwaitingLedgerRetirement()
+	})
+
+	o := n.objectives[bob.Address()]
+	if o.LedgerRestorable() {
+		t.Error("expected a ledger channel which has not been retired not to be restorable")
+	}
+	retired, err := o.RetireLedger(n.ledgers[bob.Address()])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob abandons the withdrawal before signing the final state
+	rejected, _ := retired.Reject()
+	lwo := rejected.(*Objective)
+	if !lwo.LedgerRestorable() {
+		t.Fatal("expected the retired ledger channel to be restorable")
+	}
+	restored, err := lwo.RestoreLedger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Id != original.Id || restored.ConsensusTurnNum() != original.ConsensusTurnNum() || restored.IsLeader() {
+		t.Errorf("restored ledger channel %s at turn %d, wanted %s at turn %d", restored.Id, restored.ConsensusTurnNum(), original.Id, original.ConsensusTurnNum())
+	}
+	if !restored.ConsensusVars().AsState(restored.FixedPart()).Equal(original.ConsensusVars().AsState(original.FixedPart())) {
+		t.Error("restored ledger channel has a different consensus state")
+	}
+	if !restored.OnChainFunding.Equal(original.OnChainFunding) {
+		t.Errorf("restored ledger channel is funded with %v, wanted %v", restored.OnChainFunding, original.OnChainFunding)
+	}
+
+	// once Bob has signed the final state, Alice may finalize the ledger channel
+	cranked, _, _, err := retired.Crank(&bob.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cranked.(*Objective).LedgerRestorable() {
+		t.Error("expected a ledger channel whose final state we signed not to be restorable")
+	}
+}
+
+func TestOverdueWithdrawal(t *testing.T) {
+	alice, bob := testactors.Alice, testactors.Bob
+	n := newNetwork(t, alice, bob)
+
+	// Bob goes offline once the withdrawal is finalized, without submitting the withdrawAll transaction
+	n.start(bob)
+	n.runUntil(func() bool {
+		o, ok := n.objectives[bob.Address()]
+		return ok && o.SuccessorReady()
+	})
+	n.offline[bob.Address()] = true
+	n.transactions[bob.Address()] = nil
+	n.run()
+
+	o := n.objectives[alice.Address()]
+	if !o.AwaitingWithdrawal() {
+		t.Fatal("expected Alice to be waiting for the withdrawal")
+	}
+	if len(n.transactions[alice.Address()]) != 0 {
+		t.Fatal("expected Alice not to submit the withdrawAll transaction before it is overdue")
+	}
+
+	n.objectives[alice.Address()] = o.MarkWithdrawalOverdue()
+	n.run()
+	if len(n.transactions[alice.Address()]) != 1 {
+		t.Fatalf("expected Alice to submit the overdue withdrawAll transaction, got %d transactions", len(n.transactions[alice.Address()]))
+	}
+
+	n.run()
+	if len(n.transactions[alice.Address()]) != 1 {
+		t.Errorf("expected Alice to submit the withdrawAll transaction once, got %d transactions", len(n.transactions[alice.Address()]))
+	}
+}
+
+func TestSuccessorFundingFollowsPayout(t *testing.T) {
+	alice, bob := testactors.Alice, testactors.Bob
+	n := newNetwork(t, alice, bob)
+
+	n.start(bob)
+	n.run()
+
+	// the ledger channel is underfunded, and the withdrawal is paid out first
+	o := n.objectives[alice.Address()]
+	funded, transferred, err := o.RecordSuccessorFunding(types.Address{}, big.NewInt(ledgerDeposit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := big.NewInt(ledgerDeposit - withdrawAmount); transferred.Cmp(want) != 0 {
+		t.Errorf("transferred %v into the successor, wanted %v", transferred, want)
+	}
+
+	// a repeated payout does not fund the successor twice
+	_, transferred, err = funded.RecordSuccessorFunding(types.Address{}, big.NewInt(ledgerDeposit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transferred.Sign() != 0 {
+		t.Errorf("transferred %v into the successor on a repeated payout, wanted 0", transferred)
+	}
+
+	// the payout of another asset is not transferred into the successor
+	_, transferred, err = funded.RecordSuccessorFunding(common.HexToAddress("0x01"), big.NewInt(ledgerDeposit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transferred.Sign() != 0 {
+		t.Errorf("transferred %v of an unknown asset into the successor, wanted 0", transferred)
+	}
+}
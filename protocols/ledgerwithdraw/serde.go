@@ -0,0 +1,80 @@
+package ledgerwithdraw
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// jsonObjective replaces the ledgerwithdraw.Objective's channel pointer with
+// the channel's ID, making jsonObjective suitable for serialization
+type jsonObjective struct {
+	Status                       protocols.ObjectiveStatus
+	C                            types.Destination
+	Successor                    state.SignedState
+	Withdrawer                   types.Address
+	Asset                        common.Address
+	Amount                       *big.Int
+	FinalTurnNum                 uint64
+	SuccessorFunding             types.Funds
+	RequestSent                  bool
+	LedgerRetired                bool
+	WithdrawTransactionSubmitted bool
+	WithdrawalOverdue            bool
+}
+
+// MarshalJSON returns a JSON representation of the Objective
+// NOTE: Marshal -> Unmarshal is a lossy process. All channel data
+// (other than Id) from the field C is discarded
+func (o *Objective) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonObjective{
+		Status:                       o.Status,
+		C:                            o.C.Id,
+		Successor:                    o.Successor,
+		Withdrawer:                   o.Withdrawer,
+		Asset:                        o.Asset,
+		Amount:                       o.Amount,
+		FinalTurnNum:                 o.finalTurnNum,
+		SuccessorFunding:             o.successorFunding,
+		RequestSent:                  o.requestSent,
+		LedgerRetired:                o.ledgerRetired,
+		WithdrawTransactionSubmitted: o.withdrawTransactionSubmitted,
+		WithdrawalOverdue:            o.withdrawalOverdue,
+	})
+}
+
+// UnmarshalJSON populates the calling Objective with the
+// json-encoded data
+// NOTE: Marshal -> Unmarshal is a lossy process. All channel data
+// (other than Id) from the field C is discarded
+func (o *Objective) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var jsonO jsonObjective
+	if err := json.Unmarshal(data, &jsonO); err != nil {
+		return err
+	}
+
+	o.Status = jsonO.Status
+	o.C = &channel.Channel{}
+	o.C.Id = jsonO.C
+	o.Successor = jsonO.Successor
+	o.Withdrawer = jsonO.Withdrawer
+	o.Asset = jsonO.Asset
+	o.Amount = jsonO.Amount
+	o.finalTurnNum = jsonO.FinalTurnNum
+	o.successorFunding = jsonO.SuccessorFunding
+	o.requestSent = jsonO.RequestSent
+	o.ledgerRetired = jsonO.LedgerRetired
+	o.withdrawTransactionSubmitted = jsonO.WithdrawTransactionSubmitted
+	o.withdrawalOverdue = jsonO.WithdrawalOverdue
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/statechannels/go-nitro/channel/consensus_channel"
 	"github.com/statechannels/go-nitro/payments"
+	"github.com/statechannels/go-nitro/routing"
 	"github.com/statechannels/go-nitro/types"
 )
 
@@ -49,6 +50,8 @@ type Message struct {
 	Payments []payments.Voucher
 	// RejectedObjectives is a collection of objectives that have been rejected.
 	RejectedObjectives []ObjectiveId
+	// CapacityAnnouncements contains a collection of signed announcements of ledger channel capacity, made by the sender to find routes for virtual channels.
+	CapacityAnnouncements []routing.CapacityAnnouncement `json:",omitempty"`
 }
 
 // Serialize serializes the message into a string.
@@ -70,9 +73,12 @@ func GetProposalObjectiveId(p consensus_channel.Proposal, channelType types.Chan
 	case "AddProposal":
 		{
 			var prefix string
-			if channelType == types.Swap {
+			switch channelType {
+			case types.Swap:
 				prefix = "SwapFund-"
-			} else {
+			case types.Rebalance:
+				prefix = "Rebalance-"
+			default:
 				prefix = "VirtualFund-"
 			}
 			channelId := p.ToAdd.Guarantee.Target().String()
@@ -82,15 +88,22 @@ func GetProposalObjectiveId(p consensus_channel.Proposal, channelType types.Chan
 	case "RemoveProposal":
 		{
 			var prefix string
-			if channelType == types.Swap {
+			switch channelType {
+			case types.Swap:
 				prefix = "SwapDefund-"
-			} else {
+			case types.Rebalance:
+				prefix = "Rebalance-"
+			default:
 				prefix = "VirtualDefund-"
 			}
 			channelId := p.ToRemove.Target.String()
 			return ObjectiveId(prefix + channelId), nil
 
 		}
+	case "ResizeProposal":
+		{
+			return ObjectiveId("LedgerTopUp-" + p.ToResize.Target.String()), nil
+		}
 	default:
 		{
 			return "", errors.New("invalid proposal type")
@@ -143,6 +156,16 @@ func CreateVoucherMessage(voucher payments.Voucher, recipients ...types.Address)
 	return messages
 }
 
+// CreateCapacityAnnouncementMessage returns a message containing the capacity announcements for each of the recipients provided.
+func CreateCapacityAnnouncementMessage(announcements []routing.CapacityAnnouncement, recipients ...types.Address) []Message {
+	messages := make([]Message, len(recipients))
+	for i, recipient := range recipients {
+		messages[i] = Message{To: recipient, CapacityAnnouncements: announcements}
+	}
+
+	return messages
+}
+
 // DeserializeMessage deserializes the passed string into a protocols.Message.
 func DeserializeMessage(s string) (Message, error) {
 	msg := Message{}
@@ -162,6 +185,8 @@ type MessageSummary struct {
 	Payments []PaymentSummary
 	// RejectedObjectives is a collection of objectives that have been rejected.
 	RejectedObjectives []string
+	// CapacityAnnouncements is the number of capacity announcements in the message.
+	CapacityAnnouncements int
 }
 
 // ObjectivePayloadSummary is a summary of an objective payload suitable for logging.
@@ -229,6 +254,8 @@ func (m Message) Summarize(getChannelById getChannelByIdFn) MessageSummary {
 	for i, o := range m.RejectedObjectives {
 		s.RejectedObjectives[i] = string(o)
 	}
+
+	s.CapacityAnnouncements = len(m.CapacityAnnouncements)
 	return s
 }
 
@@ -0,0 +1,763 @@
+// Package multiswap implements an off-chain protocol to execute a route of swaps.
+//
+// A route is a sequence of legs, each of which is a swap in a different swap channel. For example a node can swap
+// token A for token B with an intermediary, who in turn swaps token B for token C with a node the initiator is not
+// directly connected to. The participants of the route are the parties to its legs.
+//
+// The swaps are executed by a two-phase commit over the signatures of the swaps:
+//
+//  1. Prepare: each participant signs the swap of every leg it is a party to, and sends the route with its signatures to
+//     the other participants. A signed swap is a vote for the route, but it does not move any funds.
+//  2. Commit: once every leg of the route carries the signatures of both of its parties, the legs are committed from the
+//     end of the route backwards. A participant signs the updated state of the swap channel of a leg only once every
+//     later leg of the route has been committed, and sends the signature to the counterparty of the leg. A leg is
+//     committed once the updated state of its channel is supported, at which point its parties send the supported
+//     states before and after the swap to the other participants, as proof of the commit.
+//
+// No participant signs an updated state until every participant has voted for the route, so no leg commits unless all
+// legs have been agreed. A participant which rejects the route before voting aborts it for everyone.
+//
+// Since neither party to a leg signs its updated state before holding the proof that the legs downstream of it have
+// been committed, a participant cannot collect the leg it gains from and withhold its signature on a later leg it pays
+// into. A participant which stops signing can only leave the legs upstream of it uncommitted, in which case the legs
+// downstream of it stay committed. The proof shows that the parties to a downstream leg have signed its update, but not
+// that they have not signed a later state which reverses it: the route is atomic as long as the parties to a leg do not
+// collude against the other participants.
+package multiswap // import "github.com/statechannels/go-nitro/protocols/multiswap"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/payments"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/protocols/swap"
+	"github.com/statechannels/go-nitro/types"
+)
+
+const (
+	WaitingForVotes   protocols.WaitingFor = "WaitingForVotes"
+	WaitingForCommit  protocols.WaitingFor = "WaitingForCommit"
+	WaitingForNothing protocols.WaitingFor = "WaitingForNothing" // Finished
+)
+
+const (
+	RoutePayload        protocols.PayloadType = "RoutePayload"
+	StateSigPayload     protocols.PayloadType = "StateSigPayload"
+	CommittedLegPayload protocols.PayloadType = "CommittedLegPayload"
+)
+
+const ObjectivePrefix = "MultiSwap-"
+
+const (
+	ErrInvalidRoute   = types.ConstError("invalid swap route")
+	ErrNotParticipant = types.ConstError("not a party to any leg of the route")
+)
+
+type GetChannelByIdFunction func(id types.Destination) (channel *channel.Channel, ok bool)
+
+// Leg is a swap in one of the swap channels of a route
+type Leg struct {
+	Swap payments.Swap
+	// Sender gives AmountIn of TokenIn to the Receiver in exchange for AmountOut of TokenOut
+	Sender   types.Address
+	Receiver types.Address
+}
+
+// NewLeg returns a leg in which the sender swaps amountIn of tokenIn for amountOut of tokenOut with the receiver, in the given swap channel.
+// The parties do not vote for the leg after its expiry.
+func NewLeg(channelId types.Destination, sender, receiver types.Address, tokenIn, tokenOut common.Address, amountIn, amountOut *big.Int, nonce uint64, expiry uint64) Leg {
+	return Leg{
+		Swap:     payments.NewSwap(channelId, tokenIn, tokenOut, amountIn, amountOut, nonce, expiry),
+		Sender:   sender,
+		Receiver: receiver,
+	}
+}
+
+// hasVoteFrom returns true if the swap of the leg carries a valid signature of the given party.
+func (l Leg) hasVoteFrom(party types.Address) bool {
+	for _, sig := range l.Swap.Sigs {
+		if signer, err := l.Swap.RecoverSigner(sig); err == nil && signer == party {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAllVotes returns true if the swap of the leg has been signed by both of its parties.
+func (l Leg) hasAllVotes() bool {
+	return l.hasVoteFrom(l.Sender) && l.hasVoteFrom(l.Receiver)
+}
+
+func (l Leg) clone() Leg {
+	return Leg{Swap: l.Swap.Clone(), Sender: l.Sender, Receiver: l.Receiver}
+}
+
+// StateSig is the signature of a participant on the updated state of the swap channel of a leg
+type StateSig struct {
+	Leg uint
+	Sig state.Signature
+}
+
+// CommittedLeg is the proof that a leg has been committed, which the parties to the leg send to the other participants of the route
+type CommittedLeg struct {
+	Leg uint
+	// Supported is the supported state of the swap channel of the leg before the swap
+	Supported state.SignedState
+	// Updated is the state which applies the swap to Supported, signed by the parties to the leg
+	Updated state.SignedState
+}
+
+// Objective is a cache of data computed by reading from the store. It stores (potentially) infinite data
+type Objective struct {
+	Status protocols.ObjectiveStatus
+	Legs   []Leg
+
+	// C are the swap channels of the legs we are a party to, keyed by leg index
+	C map[uint]*channel.SwapChannel
+	// StateSigs are the signatures on the updated states of the swap channels in C, keyed by leg index and then by participant index
+	StateSigs map[uint]map[uint]state.Signature
+	// Committed records the legs which have been committed: the legs in C whose swap channel has been updated, and the
+	// other legs for which we hold a proof of the commit
+	Committed map[uint]bool
+}
+
+// NewObjective creates a new multiswap objective from a given request.
+func NewObjective(request ObjectiveRequest, preApprove bool, myAddress types.Address, getChannel GetChannelByIdFunction) (Objective, error) {
+	if len(request.Legs) == 0 || request.Legs[0].Sender != myAddress {
+		return Objective{}, fmt.Errorf("%w: the initiator must send the first leg", ErrInvalidRoute)
+	}
+
+	return newObjective(preApprove, request.Legs, myAddress, getChannel)
+}
+
+// ConstructObjectiveFromPayload takes in a message and constructs an objective from it.
+// It accepts the message, myAddress, and a function to to retrieve swap channels from a store.
+func ConstructObjectiveFromPayload(
+	p protocols.ObjectivePayload,
+	preApprove bool,
+	myAddress types.Address,
+	getChannel GetChannelByIdFunction,
+) (Objective, error) {
+	if p.Type != RoutePayload {
+		return Objective{}, fmt.Errorf("expected a %s, got a %s", RoutePayload, p.Type)
+	}
+
+	legs, err := getRoutePayload(p.PayloadData)
+	if err != nil {
+		return Objective{}, err
+	}
+	if len(legs) > 0 && legs[0].Sender == myAddress {
+		return Objective{}, errors.New("the initiator should not construct objectives from peer messages")
+	}
+
+	o, err := newObjective(preApprove, legs, myAddress, getChannel)
+	if err != nil {
+		return Objective{}, err
+	}
+	if o.Id() != p.ObjectiveId {
+		return Objective{}, fmt.Errorf("payload objective id %s does not match the route %s", p.ObjectiveId, o.Id())
+	}
+
+	return o, nil
+}
+
+// newObjective initiates an Objective from the legs of a route, which are stripped of any signatures.
+func newObjective(preApprove bool, legs []Leg, myAddress types.Address, getChannel GetChannelByIdFunction) (Objective, error) {
+	if err := validateRoute(legs); err != nil {
+		return Objective{}, err
+	}
+
+	o := Objective{
+		Legs:      make([]Leg, len(legs)),
+		C:         make(map[uint]*channel.SwapChannel),
+		StateSigs: make(map[uint]map[uint]state.Signature),
+		Committed: make(map[uint]bool),
+	}
+	for i, leg := range legs {
+		o.Legs[i] = leg.clone()
+		o.Legs[i].Swap.Sigs = make(map[uint]state.Signature, 2)
+
+		if leg.Sender != myAddress && leg.Receiver != myAddress {
+			continue
+		}
+
+		c, ok := getChannel(leg.Swap.ChannelId)
+		if !ok {
+			return Objective{}, fmt.Errorf("swap channel %s of leg %d not found", leg.Swap.ChannelId, i)
+		}
+		if c.Type != types.Swap {
+			return Objective{}, fmt.Errorf("channel %s of leg %d is not a swap channel", c.Id, i)
+		}
+		sc := &channel.SwapChannel{Channel: *c}
+		if !isPartyTo(sc, leg.Sender) || !isPartyTo(sc, leg.Receiver) {
+			return Objective{}, fmt.Errorf("%w: the parties to leg %d are not the participants of swap channel %s", ErrInvalidRoute, i, c.Id)
+		}
+
+		uIndex := uint(i)
+		o.C[uIndex] = sc
+		o.StateSigs[uIndex] = make(map[uint]state.Signature, 2)
+
+		senderIndex, err := indexInAllocations(sc, leg.Sender)
+		if err != nil {
+			return Objective{}, err
+		}
+		if !swap.IsValidSwap(sc.LatestSupportedSwapChannelState(), leg.Swap, senderIndex) {
+			return Objective{}, fmt.Errorf("leg %d: %w", i, swap.ErrInvalidSwap)
+		}
+	}
+	if len(o.C) == 0 {
+		return Objective{}, ErrNotParticipant
+	}
+
+	if preApprove {
+		o.Status = protocols.Approved
+	} else {
+		o.Status = protocols.Unapproved
+	}
+
+	return o, nil
+}
+
+// validateRoute checks that every leg of the route swaps non-negative amounts between two distinct parties, in a channel no other leg uses.
+func validateRoute(legs []Leg) error {
+	if len(legs) < 2 {
+		return fmt.Errorf("%w: a route has at least two legs", ErrInvalidRoute)
+	}
+
+	channels := make(map[types.Destination]bool, len(legs))
+	for i, leg := range legs {
+		if leg.Sender == leg.Receiver || leg.Sender == (types.Address{}) || leg.Receiver == (types.Address{}) {
+			return fmt.Errorf("%w: leg %d needs two distinct parties", ErrInvalidRoute, i)
+		}
+		if channels[leg.Swap.ChannelId] {
+			return fmt.Errorf("%w: swap channel %s is used by more than one leg", ErrInvalidRoute, leg.Swap.ChannelId)
+		}
+		channels[leg.Swap.ChannelId] = true
+
+		ex := leg.Swap.Exchange
+		if ex.AmountIn == nil || ex.AmountOut == nil || ex.AmountIn.Sign() < 0 || ex.AmountOut.Sign() < 0 {
+			return fmt.Errorf("%w: leg %d swaps a negative amount", ErrInvalidRoute, i)
+		}
+		if leg.Swap.Id != leg.Swap.SwapId() {
+			return fmt.Errorf("%w: leg %d has an incorrect swap id", ErrInvalidRoute, i)
+		}
+	}
+
+	return nil
+}
+
+// RouteId returns an identifier of the route which commits to the swaps and the parties of its legs.
+func RouteId(legs []Leg) types.Destination {
+	encoded := []byte{}
+	for _, leg := range legs {
+		encoded = append(encoded, leg.Swap.SwapId().Bytes()...)
+		encoded = append(encoded, leg.Sender.Bytes()...)
+		encoded = append(encoded, leg.Receiver.Bytes()...)
+	}
+
+	return types.Destination(crypto.Keccak256Hash(encoded))
+}
+
+// Id returns the objective id.
+func (o *Objective) Id() protocols.ObjectiveId {
+	return protocols.ObjectiveId(ObjectivePrefix + RouteId(o.Legs).String())
+}
+
+// Approve returns an approved copy of the objective.
+func (o *Objective) Approve() protocols.Objective {
+	updated := o.clone()
+	// todo: consider case of s.Status == Rejected
+	updated.Status = protocols.Approved
+
+	return &updated
+}
+
+// Reject returns a rejected copy of the objective.
+func (o *Objective) Reject() (protocols.Objective, protocols.SideEffects) {
+	updated := o.clone()
+	updated.Status = protocols.Rejected
+
+	messages := protocols.CreateRejectionNoticeMessage(o.Id(), o.otherParticipants()...)
+	sideEffects := protocols.SideEffects{MessagesToSend: messages}
+	return &updated, sideEffects
+}
+
+// OwnsChannel returns the channel that the objective is funding.
+func (o *Objective) OwnsChannel() types.Destination {
+	// Like a single swap, a route of swaps does not own any channel
+	return types.Destination{}
+}
+
+// GetStatus returns the status of the objective.
+func (o *Objective) GetStatus() protocols.ObjectiveStatus {
+	return o.Status
+}
+
+// Related returns the swap channels of the legs we are a party to, which need to be stored along with the objective.
+func (o *Objective) Related() []protocols.Storable {
+	ret := []protocols.Storable{}
+	for _, i := range o.myLegs() {
+		ret = append(ret, o.C[i])
+	}
+	return ret
+}
+
+// MyLegs returns the legs we are a party to as swap objectives, which are completed if the leg has been committed.
+func (o *Objective) MyLegs() []*swap.Objective {
+	legs := []*swap.Objective{}
+	for _, i := range o.myLegs() {
+		legs = append(legs, o.legObjective(i))
+	}
+	return legs
+}
+
+// Update receives a protocols.ObjectivePayload and applies it to a copy of the objective.
+func (o *Objective) Update(p protocols.ObjectivePayload) (protocols.Objective, error) {
+	if o.Id() != p.ObjectiveId {
+		return o, fmt.Errorf("event and objective Ids do not match: %s and %s respectively", string(p.ObjectiveId), string(o.Id()))
+	}
+
+	updated := o.clone()
+
+	switch p.Type {
+	case RoutePayload:
+		legs, err := getRoutePayload(p.PayloadData)
+		if err != nil {
+			return o, err
+		}
+		if len(legs) != len(updated.Legs) {
+			return o, fmt.Errorf("route has %d legs, expected %d", len(legs), len(updated.Legs))
+		}
+
+		for i, leg := range legs {
+			expected := updated.Legs[i]
+			if !expected.Swap.Equal(leg.Swap) || expected.Sender != leg.Sender || expected.Receiver != leg.Receiver {
+				return o, fmt.Errorf("leg %d does not match", i)
+			}
+			for index, sig := range leg.Swap.Sigs {
+				signer, err := leg.Swap.RecoverSigner(sig)
+				if err != nil || (signer != leg.Sender && signer != leg.Receiver) {
+					return o, fmt.Errorf("leg %d carries a signature of a non-party", i)
+				}
+				updated.Legs[i].Swap.Sigs[index] = sig
+			}
+		}
+
+	case StateSigPayload:
+		ss := StateSig{}
+		if err := json.Unmarshal(p.PayloadData, &ss); err != nil {
+			return o, fmt.Errorf("could not unmarshal state signature: %w", err)
+		}
+		c, ok := updated.C[ss.Leg]
+		if !ok {
+			return o, fmt.Errorf("state signature is for leg %d, which we are not a party to", ss.Leg)
+		}
+		if updated.Committed[ss.Leg] {
+			// The leg has already been committed, so the signature is stale
+			return &updated, nil
+		}
+
+		s, err := updated.legObjective(ss.Leg).GetUpdatedSwapState()
+		if err != nil {
+			return o, err
+		}
+		signer, err := s.RecoverSigner(ss.Sig)
+		if err != nil {
+			return o, fmt.Errorf("error in recovering counter party address %w", err)
+		}
+		counterpartyIndex := 1 - c.MyIndex
+		if signer != c.Participants[counterpartyIndex] {
+			return o, fmt.Errorf("state signature for leg %d is not from the counterparty", ss.Leg)
+		}
+		updated.StateSigs[ss.Leg][counterpartyIndex] = ss.Sig
+
+	case CommittedLegPayload:
+		cl := CommittedLeg{}
+		if err := json.Unmarshal(p.PayloadData, &cl); err != nil {
+			return o, fmt.Errorf("could not unmarshal committed leg: %w", err)
+		}
+		if int(cl.Leg) >= len(updated.Legs) {
+			return o, fmt.Errorf("committed leg %d is not part of the route", cl.Leg)
+		}
+		if _, ok := updated.C[cl.Leg]; ok || updated.Committed[cl.Leg] {
+			// We commit the legs we are a party to ourselves
+			return &updated, nil
+		}
+		if err := updated.Legs[cl.Leg].verifyCommit(cl); err != nil {
+			return o, fmt.Errorf("invalid proof of the commit of leg %d: %w", cl.Leg, err)
+		}
+		updated.Committed[cl.Leg] = true
+
+	default:
+		return o, fmt.Errorf("unexpected payload type %s", p.Type)
+	}
+
+	return &updated, nil
+}
+
+// Crank inspects the extended state and declares a list of Effects to be executed.
+func (o *Objective) Crank(secretKey *[]byte) (protocols.Objective, protocols.SideEffects, protocols.WaitingFor, error) {
+	updated := o.clone()
+	sideEffects := protocols.SideEffects{}
+
+	if updated.Status != protocols.Approved {
+		return &updated, sideEffects, WaitingForNothing, protocols.ErrNotApproved
+	}
+
+	// We do not vote for a route once one of our legs has expired, and abandon it instead
+	for _, i := range updated.myLegs() {
+		_, signed := updated.Legs[i].Swap.Sigs[updated.C[i].MyIndex]
+		if !signed && updated.Legs[i].Swap.IsExpired(time.Now()) {
+			rejected, sideEffects := updated.Reject()
+			return rejected, sideEffects, WaitingForNothing, nil
+		}
+	}
+
+	// Prepare: we vote for the route by signing the swaps of our legs
+	voted := false
+	for _, i := range updated.myLegs() {
+		c := updated.C[i]
+		if _, ok := updated.Legs[i].Swap.Sigs[c.MyIndex]; ok {
+			continue
+		}
+		sig, err := updated.Legs[i].Swap.Sign(*secretKey)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("error signing swap of leg %d: %w", i, err)
+		}
+		updated.Legs[i].Swap.AddSignature(sig, c.MyIndex)
+		voted = true
+	}
+	if voted {
+		messages, err := protocols.CreateObjectivePayloadMessage(updated.Id(), updated.Legs, RoutePayload, updated.otherParticipants()...)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not create payload message %w", err)
+		}
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, messages...)
+	}
+
+	for _, leg := range updated.Legs {
+		if !leg.hasAllVotes() {
+			return &updated, sideEffects, WaitingForVotes, nil
+		}
+	}
+
+	// Commit: every participant has voted for the route, so we sign the updated states of our swap channels, starting
+	// from the end of the route. We do not sign the update of a leg before all the legs after it have been committed.
+	myLegs := updated.myLegs()
+	for k := len(myLegs) - 1; k >= 0; k-- {
+		i := myLegs[k]
+		if updated.Committed[i] || !updated.downstreamCommitted(i) {
+			continue
+		}
+		c := updated.C[i]
+		lo := updated.legObjective(i)
+
+		if _, ok := updated.StateSigs[i][c.MyIndex]; !ok {
+			s, err := lo.GetUpdatedSwapState()
+			if err != nil {
+				return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("error creating updated swap channel state of leg %d: %w", i, err)
+			}
+			sig, err := s.Sign(*secretKey)
+			if err != nil {
+				return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("error signing swap channel state of leg %d: %w", i, err)
+			}
+			updated.StateSigs[i][c.MyIndex] = sig
+
+			messages, err := protocols.CreateObjectivePayloadMessage(updated.Id(), StateSig{Leg: i, Sig: sig}, StateSigPayload, c.Participants[1-c.MyIndex])
+			if err != nil {
+				return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not create payload message %w", err)
+			}
+			sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, messages...)
+		}
+
+		if len(updated.StateSigs[i]) < len(c.Participants) {
+			continue
+		}
+		supported := c.LatestSupportedSignedSwapChannelState()
+		if err := lo.UpdateSwapChannelState(); err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("error updating swap channel of leg %d: %w", i, err)
+		}
+		updated.Committed[i] = true
+
+		messages, err := protocols.CreateObjectivePayloadMessage(updated.Id(), CommittedLeg{Leg: i, Supported: supported, Updated: c.LatestSupportedSignedSwapChannelState()}, CommittedLegPayload, updated.nonParties(i)...)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("could not create payload message %w", err)
+		}
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, messages...)
+	}
+
+	for _, i := range updated.myLegs() {
+		if !updated.Committed[i] {
+			return &updated, sideEffects, WaitingForCommit, nil
+		}
+	}
+
+	updated.Status = protocols.Completed
+	return &updated, sideEffects, WaitingForNothing, nil
+}
+
+// legObjective returns a swap objective for the leg with the given index, which shares the swap channel and state signatures of the receiver.
+// The leg must be one we are a party to.
+func (o *Objective) legObjective(i uint) *swap.Objective {
+	c := o.C[i]
+	leg := o.Legs[i]
+	senderIndex, _ := indexInAllocations(c, leg.Sender) // checked on construction
+
+	lo := &swap.Objective{
+		Status:          o.Status,
+		C:               c,
+		Swap:            leg.Swap,
+		StateSigs:       o.StateSigs[i],
+		SwapStatus:      types.PendingConfirmation,
+		SwapSenderIndex: senderIndex,
+	}
+	if o.Committed[i] {
+		lo.Status = protocols.Completed
+		lo.SwapStatus = types.Accepted
+	} else if o.Status == protocols.Rejected {
+		lo.SwapStatus = types.Rejected
+	}
+
+	return lo
+}
+
+// downstreamCommitted returns true if every leg after the leg with the given index has been committed.
+func (o *Objective) downstreamCommitted(i uint) bool {
+	for j := int(i) + 1; j < len(o.Legs); j++ {
+		if !o.Committed[uint(j)] {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyCommit checks that the proof commits the leg: its updated state applies the swap to the supported state of the
+// swap channel of the leg, and both states are signed by the parties to the leg.
+func (l Leg) verifyCommit(cl CommittedLeg) error {
+	supported := cl.Supported.State()
+	if supported.ChannelId() != l.Swap.ChannelId {
+		return fmt.Errorf("the proof is for channel %s, not %s", supported.ChannelId(), l.Swap.ChannelId)
+	}
+	if len(supported.Participants) < 2 || len(supported.Outcome) == 0 {
+		return errors.New("the proof is not for a swap channel")
+	}
+	first, last := supported.Participants[0], supported.Participants[len(supported.Participants)-1]
+	if !(first == l.Sender && last == l.Receiver) && !(first == l.Receiver && last == l.Sender) {
+		return errors.New("the parties to the leg are not the participants of the swap channel")
+	}
+
+	senderIndex := -1
+	for i, allocation := range supported.Outcome[0].Allocations {
+		if allocation.Destination == types.AddressToDestination(l.Sender) {
+			senderIndex = i
+		}
+	}
+	if senderIndex < 0 || len(supported.Outcome[0].Allocations) != 2 {
+		return errors.New("the sender has no allocation in the swap channel")
+	}
+	if !swap.IsValidSwap(supported, l.Swap, uint(senderIndex)) {
+		return swap.ErrInvalidSwap
+	}
+	if !cl.Updated.State().Equal(swap.UpdatedSwapState(supported, l.Swap, uint(senderIndex))) {
+		return errors.New("the updated state does not apply the swap")
+	}
+
+	for _, ss := range []state.SignedState{cl.Supported, cl.Updated} {
+		if !hasSwapParticipantSignatures(ss) {
+			return fmt.Errorf("state with turn number %d is not signed by the parties to the leg", ss.State().TurnNum)
+		}
+	}
+	return nil
+}
+
+// hasSwapParticipantSignatures returns true if the state carries valid signatures of the first and last participants of its channel.
+func hasSwapParticipantSignatures(ss state.SignedState) bool {
+	s := ss.State()
+	for _, index := range []uint{0, uint(len(s.Participants) - 1)} {
+		sig, err := ss.GetParticipantSignature(index)
+		if err != nil {
+			return false
+		}
+		signer, err := s.RecoverSigner(sig)
+		if err != nil || signer != s.Participants[index] {
+			return false
+		}
+	}
+	return true
+}
+
+// myLegs returns the indices of the legs we are a party to, in order.
+func (o *Objective) myLegs() []uint {
+	indices := []uint{}
+	for i := range o.Legs {
+		if _, ok := o.C[uint(i)]; ok {
+			indices = append(indices, uint(i))
+		}
+	}
+	return indices
+}
+
+// Participants returns the parties to the legs of the route, in the order they first appear.
+func (o *Objective) Participants() []types.Address {
+	participants := []types.Address{}
+	seen := make(map[types.Address]bool)
+	for _, leg := range o.Legs {
+		for _, p := range []types.Address{leg.Sender, leg.Receiver} {
+			if !seen[p] {
+				seen[p] = true
+				participants = append(participants, p)
+			}
+		}
+	}
+	return participants
+}
+
+func (o *Objective) otherParticipants() []types.Address {
+	var me types.Address
+	for _, c := range o.C {
+		me = c.Participants[c.MyIndex]
+	}
+
+	others := []types.Address{}
+	for _, p := range o.Participants() {
+		if p != me {
+			others = append(others, p)
+		}
+	}
+	return others
+}
+
+// nonParties returns the participants of the route who are not a party to the leg with the given index.
+func (o *Objective) nonParties(i uint) []types.Address {
+	others := []types.Address{}
+	for _, p := range o.Participants() {
+		if p != o.Legs[i].Sender && p != o.Legs[i].Receiver {
+			others = append(others, p)
+		}
+	}
+	return others
+}
+
+// clone returns a deep copy of the receiver.
+func (o *Objective) clone() Objective {
+	clone := Objective{}
+	clone.Status = o.Status
+
+	clone.Legs = make([]Leg, len(o.Legs))
+	for i, leg := range o.Legs {
+		clone.Legs[i] = leg.clone()
+	}
+
+	clone.C = make(map[uint]*channel.SwapChannel, len(o.C))
+	for i, c := range o.C {
+		clone.C[i] = c.Clone()
+	}
+
+	clone.StateSigs = make(map[uint]map[uint]state.Signature, len(o.StateSigs))
+	for i, sigs := range o.StateSigs {
+		clone.StateSigs[i] = make(map[uint]state.Signature, len(sigs))
+		for j, sig := range sigs {
+			clone.StateSigs[i][j] = sig
+		}
+	}
+
+	clone.Committed = make(map[uint]bool, len(o.Committed))
+	for i, committed := range o.Committed {
+		clone.Committed[i] = committed
+	}
+
+	return clone
+}
+
+// isPartyTo returns true if the address is a participant of the swap channel.
+func isPartyTo(c *channel.SwapChannel, address types.Address) bool {
+	for _, p := range c.Participants {
+		if p == address {
+			return true
+		}
+	}
+	return false
+}
+
+// indexInAllocations returns the index of the participant's allocations in the swap channel's outcome.
+func indexInAllocations(c *channel.SwapChannel, participant types.Address) (uint, error) {
+	s := c.LatestSupportedSwapChannelState()
+	for i, allocation := range s.Outcome[0].Allocations {
+		if allocation.Destination == types.AddressToDestination(participant) {
+			return uint(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unable to find participant's address (%s) in the allocations", participant)
+}
+
+func getRoutePayload(b []byte) ([]Leg, error) {
+	legs := []Leg{}
+	if err := json.Unmarshal(b, &legs); err != nil {
+		return nil, fmt.Errorf("could not unmarshal route: %w", err)
+	}
+	return legs, nil
+}
+
+// IsMultiSwapObjective inspects an objective id and returns true if the objective id is for a multiswap objective.
+func IsMultiSwapObjective(id protocols.ObjectiveId) bool {
+	return strings.HasPrefix(string(id), ObjectivePrefix)
+}
+
+// ObjectiveRequest represents a request to create a new multiswap objective.
+type ObjectiveRequest struct {
+	// Legs are the swaps of the route. We send the first leg.
+	Legs             []Leg
+	objectiveStarted chan struct{}
+}
+
+// NewObjectiveRequest creates a new ObjectiveRequest.
+func NewObjectiveRequest(legs []Leg) ObjectiveRequest {
+	return ObjectiveRequest{
+		Legs:             legs,
+		objectiveStarted: make(chan struct{}),
+	}
+}
+
+// Id returns the objective id for the request.
+func (r ObjectiveRequest) Id(myAddress types.Address, chainId *big.Int) protocols.ObjectiveId {
+	return protocols.ObjectiveId(ObjectivePrefix + RouteId(r.Legs).String())
+}
+
+// SignalObjectiveStarted is used by the engine to signal the objective has been started.
+func (r ObjectiveRequest) SignalObjectiveStarted() {
+	close(r.objectiveStarted)
+}
+
+// WaitForObjectiveToStart blocks until the objective starts
+func (r ObjectiveRequest) WaitForObjectiveToStart() {
+	<-r.objectiveStarted
+}
+
+// ObjectiveResponse is the type returned across the API in response to the ObjectiveRequest.
+type ObjectiveResponse struct {
+	Id protocols.ObjectiveId
+	// SwapIds are the ids of the swaps of the legs, in order
+	SwapIds []types.Destination
+}
+
+// Response computes and returns the appropriate response from the request.
+func (r ObjectiveRequest) Response() ObjectiveResponse {
+	swapIds := make([]types.Destination, len(r.Legs))
+	for i, leg := range r.Legs {
+		swapIds[i] = leg.Swap.Id
+	}
+
+	return ObjectiveResponse{
+		Id:      protocols.ObjectiveId(ObjectivePrefix + RouteId(r.Legs).String()),
+		SwapIds: swapIds,
+	}
+}
@@ -0,0 +1,391 @@
+package multiswap
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+var (
+	eth   = common.Address{}
+	token = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+)
+
+const (
+	swapChannelDeposit = 1000
+	amountIn           = 100
+	amountOut          = 50
+)
+
+// network runs a route of swaps between actors who have a swap channel with the next one, delivering the messages between their objectives.
+type network struct {
+	t      *testing.T
+	actors []testactors.Actor
+	// channels holds every actor's copy of its swap channels
+	channels   map[types.Address]map[types.Destination]*channel.SwapChannel
+	legs       []Leg
+	objectives map[types.Address]*Objective
+	inbox      []protocols.Message
+	// withheld are the legs whose updated state the given actor sends neither its signature on, nor the proof of its commit
+	withheld map[types.Address]uint
+}
+
+// newNetwork opens a swap channel between every actor and the next one, and prepares a route in which every actor
+// pays the next one amountIn of ether for amountOut of tokens.
+func newNetwork(t *testing.T, actors ...testactors.Actor) *network {
+	n := &network{
+		t:          t,
+		actors:     actors,
+		channels:   make(map[types.Address]map[types.Destination]*channel.SwapChannel),
+		objectives: make(map[types.Address]*Objective),
+		withheld:   make(map[types.Address]uint),
+	}
+	for _, a := range actors {
+		n.channels[a.Address()] = make(map[types.Destination]*channel.SwapChannel)
+	}
+
+	for i := 0; i < len(actors)-1; i++ {
+		sender, receiver := actors[i], actors[i+1]
+		channels := prepareSwapChannel(uint64(i), sender, receiver)
+		for j, a := range []testactors.Actor{sender, receiver} {
+			n.channels[a.Address()][channels[j].Id] = channels[j]
+		}
+		n.legs = append(n.legs, NewLeg(channels[0].Id, sender.Address(), receiver.Address(), eth, token, big.NewInt(amountIn), big.NewInt(amountOut), 1, 0))
+	}
+
+	return n
+}
+
+// prepareSwapChannel returns both parties' copies of a funded swap channel between them.
+func prepareSwapChannel(nonce uint64, a, b testactors.Actor) [2]*channel.SwapChannel {
+	exit := func(asset common.Address) outcome.SingleAssetExit {
+		return outcome.SingleAssetExit{
+			Asset: asset,
+			Allocations: outcome.Allocations{
+				{Destination: a.Destination(), Amount: big.NewInt(swapChannelDeposit)},
+				{Destination: b.Destination(), Amount: big.NewInt(swapChannelDeposit)},
+			},
+		}
+	}
+	s := state.State{
+		Participants:      []types.Address{a.Address(), b.Address()},
+		ChannelNonce:      nonce,
+		ChallengeDuration: 45,
+		Outcome:           outcome.Exit{exit(eth), exit(token)},
+		TurnNum:           channel.PreFundTurnNum,
+	}
+
+	postfund := s.Clone()
+	postfund.TurnNum = channel.PostFundTurnNum
+	ss := state.NewSignedState(postfund)
+	for _, signer := range []testactors.Actor{a, b} {
+		sig, err := postfund.Sign(signer.PrivateKey)
+		if err != nil {
+			panic(err)
+		}
+		if err := ss.AddSignature(sig); err != nil {
+			panic(err)
+		}
+	}
+
+	channels := [2]*channel.SwapChannel{}
+	for i := range channels {
+		c, err := channel.NewSwapChannel(s, uint(i))
+		if err != nil {
+			panic(err)
+		}
+		if !c.AddSignedState(ss) {
+			panic("could not add the postfund state")
+		}
+		channels[i] = c
+	}
+	return channels
+}
+
+func (n *network) getChannel(me types.Address) GetChannelByIdFunction {
+	return func(id types.Destination) (*channel.Channel, bool) {
+		c, ok := n.channels[me][id]
+		if !ok {
+			return nil, false
+		}
+		return &c.Clone().Channel, true
+	}
+}
+
+func (n *network) actor(address types.Address) testactors.Actor {
+	for _, a := range n.actors {
+		if a.Address() == address {
+			return a
+		}
+	}
+	n.t.Fatalf("unknown actor %s", address)
+	return testactors.Actor{}
+}
+
+// start creates the multiswap objective of the first actor, who sends the first leg of the route.
+func (n *network) start() {
+	initiator := n.actors[0]
+	o, err := NewObjective(NewObjectiveRequest(n.legs), true, initiator.Address(), n.getChannel(initiator.Address()))
+	if err != nil {
+		n.t.Fatal(err)
+	}
+	n.objectives[initiator.Address()] = &o
+}
+
+// store replaces the actor's objective, and its copies of the swap channels.
+func (n *network) store(address types.Address, o protocols.Objective) {
+	mso := o.(*Objective)
+	n.objectives[address] = mso
+	for _, c := range mso.C {
+		n.channels[address][c.Id] = c.Clone()
+	}
+}
+
+// crank cranks every objective which is in progress, and queues the messages it sends.
+func (n *network) crank() {
+	for _, a := range n.actors {
+		o, ok := n.objectives[a.Address()]
+		if !ok || o.Status != protocols.Approved {
+			continue
+		}
+
+		updated, sideEffects, _, err := o.Crank(&a.PrivateKey)
+		if err != nil {
+			n.t.Fatalf("%s could not progress the route: %v", a.Name, err)
+		}
+		n.store(a.Address(), updated)
+		for _, message := range sideEffects.MessagesToSend {
+			message.From = a.Address()
+			n.inbox = append(n.inbox, message)
+		}
+		n.checkCommitOrder()
+	}
+}
+
+// deliver delivers the queued messages, returning the number of messages delivered.
+func (n *network) deliver() int {
+	inbox := n.inbox
+	n.inbox = nil
+
+	delivered := 0
+	for _, message := range inbox {
+		recipient := n.actor(message.To)
+
+		for _, payload := range message.ObjectivePayloads {
+			if n.isWithheld(message.From, payload) {
+				continue
+			}
+			delivered++
+
+			o, ok := n.objectives[recipient.Address()]
+			if !ok {
+				constructed, err := ConstructObjectiveFromPayload(payload, true, recipient.Address(), n.getChannel(recipient.Address()))
+				if err != nil {
+					n.t.Fatal(err)
+				}
+				o = &constructed
+			}
+			updated, err := o.Update(payload)
+			if err != nil {
+				n.t.Fatal(err)
+			}
+			n.store(recipient.Address(), updated)
+			n.checkCommitOrder()
+		}
+	}
+
+	return delivered
+}
+
+// isWithheld returns true if the payload carries the sender's signature on the updated state of a leg it withholds, or the proof of its commit.
+func (n *network) isWithheld(sender types.Address, payload protocols.ObjectivePayload) bool {
+	leg, ok := n.withheld[sender]
+	if !ok {
+		return false
+	}
+
+	var withheld struct{ Leg uint }
+	switch payload.Type {
+	case StateSigPayload, CommittedLegPayload:
+		if err := json.Unmarshal(payload.PayloadData, &withheld); err != nil {
+			n.t.Fatal(err)
+		}
+	default:
+		return false
+	}
+	return withheld.Leg == leg
+}
+
+// run cranks the objectives and delivers their messages until no more messages are sent.
+func (n *network) run() {
+	for i := 0; i < 100; i++ {
+		n.crank()
+		if n.deliver() == 0 && len(n.inbox) == 0 {
+			return
+		}
+	}
+	n.t.Fatal("the route did not settle down")
+}
+
+// isCommitted returns true if the swap channel of the leg supports the swap, in the copy of either of its parties.
+func (n *network) isCommitted(i int) bool {
+	leg := n.legs[i]
+	for _, party := range []types.Address{leg.Sender, leg.Receiver} {
+		if n.channels[party][leg.Swap.ChannelId].LatestSupportedSwapChannelState().TurnNum > channel.PostFundTurnNum {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCommitOrder fails the test if a leg has been committed before a later leg of the route.
+func (n *network) checkCommitOrder() {
+	n.t.Helper()
+	for i := range n.legs {
+		for j := i + 1; j < len(n.legs); j++ {
+			if n.isCommitted(i) && !n.isCommitted(j) {
+				n.t.Fatalf("leg %d was committed before leg %d", i, j)
+			}
+		}
+	}
+}
+
+// checkBalances checks that both parties to the leg agree on the ether held by the sender and the receiver in its swap channel.
+func (n *network) checkBalances(i int, wantSender, wantReceiver int64) {
+	n.t.Helper()
+	leg := n.legs[i]
+	for _, party := range []types.Address{leg.Sender, leg.Receiver} {
+		s := n.channels[party][leg.Swap.ChannelId].LatestSupportedSwapChannelState()
+		allocations := s.Outcome[0].Allocations
+		if allocations[0].Amount.Cmp(big.NewInt(wantSender)) != 0 || allocations[1].Amount.Cmp(big.NewInt(wantReceiver)) != 0 {
+			n.t.Errorf("%s's swap channel of leg %d allocates %v and %v wei, wanted %d and %d", n.actor(party).Name, i, allocations[0].Amount, allocations[1].Amount, wantSender, wantReceiver)
+		}
+	}
+}
+
+func TestMultiSwap(t *testing.T) {
+	alice, irene, ivan, bob := testactors.Alice, testactors.Irene, testactors.Ivan, testactors.Bob
+	n := newNetwork(t, alice, irene, ivan, bob)
+
+	n.start()
+	n.run()
+
+	for _, a := range n.actors {
+		o := n.objectives[a.Address()]
+		if o.Status != protocols.Completed {
+			t.Errorf("%s's route has status %v, wanted %v", a.Name, o.Status, protocols.Completed)
+		}
+		for _, leg := range o.MyLegs() {
+			if leg.GetStatus() != protocols.Completed {
+				t.Errorf("%s's leg in channel %s has status %v, wanted %v", a.Name, leg.C.Id, leg.GetStatus(), protocols.Completed)
+			}
+		}
+	}
+	for i := range n.legs {
+		n.checkBalances(i, swapChannelDeposit-amountIn, swapChannelDeposit+amountIn)
+	}
+}
+
+func TestMultiSwapWithWithholdingIntermediary(t *testing.T) {
+	alice, irene, ivan, bob := testactors.Alice, testactors.Irene, testactors.Ivan, testactors.Bob
+	n := newNetwork(t, alice, irene, ivan, bob)
+
+	// Ivan is paid by Irene in the second leg, and withholds his signature on the last leg, in which he pays Bob
+	n.withheld[ivan.Address()] = 2
+	n.start()
+	n.run()
+
+	for _, a := range []testactors.Actor{alice, irene, bob} {
+		if o := n.objectives[a.Address()]; o.Status == protocols.Completed {
+			t.Errorf("%s's route completed without Ivan paying Bob", a.Name)
+		}
+	}
+	for i := range n.legs[:2] {
+		n.checkBalances(i, swapChannelDeposit, swapChannelDeposit)
+	}
+	lastLeg := n.channels[bob.Address()][n.legs[2].Swap.ChannelId].LatestSupportedSwapChannelState()
+	if lastLeg.TurnNum != channel.PostFundTurnNum {
+		t.Errorf("Bob's swap channel supports turn %d, wanted the postfund", lastLeg.TurnNum)
+	}
+
+	// Irene does not sign the leg she pays Ivan in, so Ivan cannot commit it either
+	irenesIndex := n.channels[ivan.Address()][n.legs[1].Swap.ChannelId].MyIndex ^ 1
+	if _, ok := n.objectives[ivan.Address()].StateSigs[1][irenesIndex]; ok {
+		t.Error("Irene signed the update of the second leg before the last leg was committed")
+	}
+}
+
+func TestMultiSwapRejectsInvalidProofOfCommit(t *testing.T) {
+	alice, irene, ivan, bob := testactors.Alice, testactors.Irene, testactors.Ivan, testactors.Bob
+	n := newNetwork(t, alice, irene, ivan, bob)
+	n.start()
+	o := n.objectives[alice.Address()]
+
+	// the last leg is committed between Ivan and Bob
+	last := n.legs[2]
+	c := n.channels[ivan.Address()][last.Swap.ChannelId]
+	supported := c.LatestSupportedSignedSwapChannelState()
+	sign := func(s state.State, signers ...testactors.Actor) state.SignedState {
+		ss := state.NewSignedState(s)
+		for _, signer := range signers {
+			sig, err := s.Sign(signer.PrivateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ss.AddSignature(sig); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return ss
+	}
+	update := func(amountIn int64) state.State {
+		s := supported.State().Clone()
+		s.Outcome[0].Allocations[0].Amount = big.NewInt(swapChannelDeposit - amountIn)
+		s.Outcome[0].Allocations[1].Amount = big.NewInt(swapChannelDeposit + amountIn)
+		s.Outcome[1].Allocations[0].Amount = big.NewInt(swapChannelDeposit + amountOut)
+		s.Outcome[1].Allocations[1].Amount = big.NewInt(swapChannelDeposit - amountOut)
+		s.TurnNum++
+		return s
+	}
+
+	testCases := []struct {
+		name  string
+		proof CommittedLeg
+		valid bool
+	}{
+		{"valid", CommittedLeg{Leg: 2, Supported: supported, Updated: sign(update(amountIn), ivan, bob)}, true},
+		{"not signed by the receiver", CommittedLeg{Leg: 2, Supported: supported, Updated: sign(update(amountIn), ivan)}, false},
+		{"not the swap of the leg", CommittedLeg{Leg: 2, Supported: supported, Updated: sign(update(1), ivan, bob)}, false},
+		{"not the channel of the leg", CommittedLeg{Leg: 1, Supported: supported, Updated: sign(update(amountIn), ivan, bob)}, false},
+		{"not a leg of the route", CommittedLeg{Leg: 3, Supported: supported, Updated: sign(update(amountIn), ivan, bob)}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.proof)
+			if err != nil {
+				t.Fatal(err)
+			}
+			updated, err := o.Update(protocols.ObjectivePayload{ObjectiveId: o.Id(), Type: CommittedLegPayload, PayloadData: data})
+			if tc.valid {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !updated.(*Objective).Committed[tc.proof.Leg] || o.Committed[tc.proof.Leg] {
+					t.Errorf("expected the proof to commit leg %d of a copy of the objective", tc.proof.Leg)
+				}
+				return
+			}
+			if err == nil {
+				t.Error("expected an error updating the route with an invalid proof of commit")
+			}
+		})
+	}
+}
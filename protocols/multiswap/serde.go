@@ -0,0 +1,71 @@
+package multiswap
+
+import (
+	"encoding/json"
+
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// jsonObjective replaces the multiswap.Objective's channel pointers with
+// the channels' IDs, making jsonObjective suitable for serialization
+type jsonObjective struct {
+	Status    protocols.ObjectiveStatus
+	Legs      []Leg
+	C         map[uint]types.Destination
+	StateSigs map[uint]map[uint]state.Signature
+	Committed map[uint]bool
+}
+
+// MarshalJSON returns a JSON representation of the Objective
+// NOTE: Marshal -> Unmarshal is a lossy process. All channel data
+// (other than Id) from the field C is discarded
+func (o *Objective) MarshalJSON() ([]byte, error) {
+	channels := make(map[uint]types.Destination, len(o.C))
+	for i, c := range o.C {
+		channels[i] = c.Id
+	}
+
+	return json.Marshal(jsonObjective{
+		Status:    o.Status,
+		Legs:      o.Legs,
+		C:         channels,
+		StateSigs: o.StateSigs,
+		Committed: o.Committed,
+	})
+}
+
+// UnmarshalJSON populates the calling Objective with the
+// json-encoded data
+// NOTE: Marshal -> Unmarshal is a lossy process. All channel data
+// (other than Id) from the field C is discarded
+func (o *Objective) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var jsonO jsonObjective
+	if err := json.Unmarshal(data, &jsonO); err != nil {
+		return err
+	}
+
+	o.Status = jsonO.Status
+	o.Legs = jsonO.Legs
+	o.C = make(map[uint]*channel.SwapChannel, len(jsonO.C))
+	for i, id := range jsonO.C {
+		o.C[i] = &channel.SwapChannel{}
+		o.C[i].Id = id
+	}
+	o.StateSigs = jsonO.StateSigs
+	if o.StateSigs == nil {
+		o.StateSigs = make(map[uint]map[uint]state.Signature)
+	}
+	o.Committed = jsonO.Committed
+	if o.Committed == nil {
+		o.Committed = make(map[uint]bool)
+	}
+
+	return nil
+}
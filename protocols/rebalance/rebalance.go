@@ -0,0 +1,753 @@
+// Package rebalance implements an off-chain protocol to move liquidity between two of a node's ledger channels.
+//
+// The participants form a cycle which starts with the initiator: each participant pays the next one the same amount
+// in the ledger channel they share, and the last participant pays the initiator. Every participant pays and is paid
+// the same amount, so the only effect is that the initiator's balance moves from its ledger channel with the first
+// peer to its ledger channel with the last peer.
+//
+// The payments are made with the guarantees used to fund virtual channels. Every ledger channel in the cycle first
+// locks the amount in a guarantee targeting the rebalance channel R, and each participant signs R's postfund state
+// once both of its ledger channels are locked. R is run by the ConsensusApp and has one exit per hop of the cycle,
+// which allocates the amount to the payer of that hop in the prefund and postfund states. Once the postfund is
+// complete every participant signs the settlement state, in which every exit allocates the amount to the payee.
+// Nobody releases a guarantee before the settlement state is signed by every participant, and the guarantees are
+// then removed in favour of the payees.
+//
+// Every guarantee can be reclaimed on chain against R's outcome, so a peer which stops cooperating cannot steal the
+// amount: any participant may finalize R with its latest supported state and reclaim its guarantees. Before the
+// settlement state is supported that refunds every payer, and afterwards it pays every payee, which leaves each
+// participant where it would be had the rebalance been abandoned or completed off chain.
+//
+// A rebalance which is rejected or abandoned before we sign the settlement state unlocks its guarantees by refunding
+// them to the payers. Once we have signed the settlement state we can no longer be sure that it is not supported,
+// so we wait for the rebalance to complete, or resolve it on chain.
+package rebalance // import "github.com/statechannels/go-nitro/protocols/rebalance"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+const (
+	WaitingForCompletePrefund  protocols.WaitingFor = "WaitingForCompletePrefund"
+	WaitingForLocks            protocols.WaitingFor = "WaitingForLocks"
+	WaitingForCompletePostFund protocols.WaitingFor = "WaitingForCompletePostFund"
+	WaitingForSettlement       protocols.WaitingFor = "WaitingForSettlement"
+	WaitingForRelease          protocols.WaitingFor = "WaitingForRelease"
+	WaitingForNothing          protocols.WaitingFor = "WaitingForNothing" // Finished
+)
+
+const (
+	SignedStatePayload protocols.PayloadType = "SignedStatePayload"
+)
+
+const ObjectivePrefix = "Rebalance-"
+
+// SettlementTurnNum is the turn number of the state of the rebalance channel which pays every payee.
+const SettlementTurnNum uint64 = 2
+
+const (
+	ErrLedgerNotFound    = types.ConstError("could not find ledger channel")
+	ErrInvalidAmount     = types.ConstError("rebalance amount must be positive")
+	ErrInvalidCycle      = types.ConstError("a rebalance needs at least two distinct peers")
+	ErrInsufficientFunds = types.ConstError("rebalance amount exceeds the balance of the ledger channel")
+	ErrCommitted         = types.ConstError("the rebalance cannot be unlocked once the settlement state is signed")
+)
+
+// GetTwoPartyConsensusLedgerFunction describes functions which return a ConsensusChannel ledger channel between
+// the calling client and the given counterparty, if such a channel exists.
+type GetTwoPartyConsensusLedgerFunction func(counterparty types.Address) (ledger *consensus_channel.ConsensusChannel, ok bool)
+
+// Objective is a cache of data computed by reading from the store. It stores (potentially) infinite data
+type Objective struct {
+	Status protocols.ObjectiveStatus
+	// R is the rebalance channel. Its participants form the cycle, and it is the target of the guarantees which lock the payments.
+	// It is never funded on chain, but its outcome decides how the guarantees are reclaimed in a dispute.
+	R *channel.Channel
+
+	// ToMyLeft is the ledger channel with the previous participant in the cycle, who pays us
+	ToMyLeft *consensus_channel.ConsensusChannel
+	// ToMyRight is the ledger channel with the next participant in the cycle, whom we pay
+	ToMyRight *consensus_channel.ConsensusChannel
+}
+
+// NewObjective creates a new rebalance objective from a given request.
+func NewObjective(request ObjectiveRequest, preApprove bool, myAddress types.Address, getTwoPartyConsensusLedger GetTwoPartyConsensusLedgerFunction) (Objective, error) {
+	if request.Amount == nil || request.Amount.Sign() <= 0 {
+		return Objective{}, ErrInvalidAmount
+	}
+
+	o, err := newObjective(preApprove, request.initialState(myAddress), myAddress, request.AppDefinition, getTwoPartyConsensusLedger)
+	if err != nil {
+		return Objective{}, err
+	}
+
+	if balance(o.ToMyRight, myAddress, request.Asset).Cmp(request.Amount) < 0 {
+		return Objective{}, fmt.Errorf("%w %s", ErrInsufficientFunds, o.ToMyRight.Id)
+	}
+
+	return o, nil
+}
+
+// ConstructObjectiveFromPayload takes in a message and constructs an objective from it.
+// It accepts the message, myAddress, the address of the ConsensusApp which must run the rebalance channel, and a function to to retrieve ledgers from a store.
+func ConstructObjectiveFromPayload(
+	p protocols.ObjectivePayload,
+	preApprove bool,
+	myAddress types.Address,
+	consensusAppAddress types.Address,
+	getTwoPartyConsensusLedger GetTwoPartyConsensusLedgerFunction,
+) (Objective, error) {
+	if p.Type != SignedStatePayload {
+		return Objective{}, fmt.Errorf("expected a %s, got a %s", SignedStatePayload, p.Type)
+	}
+
+	ss := state.SignedState{}
+	if err := json.Unmarshal(p.PayloadData, &ss); err != nil {
+		return Objective{}, fmt.Errorf("could not unmarshal signed state: %w", err)
+	}
+
+	initialState := ss.State()
+	if initialState.TurnNum != channel.PreFundTurnNum {
+		return Objective{}, fmt.Errorf("expected a prefund state, got turn %d", initialState.TurnNum)
+	}
+	if len(initialState.Participants) > 0 && initialState.Participants[0] == myAddress {
+		return Objective{}, errors.New("participant[0] should not construct objectives from peer messages")
+	}
+
+	o, err := newObjective(preApprove, initialState, myAddress, consensusAppAddress, getTwoPartyConsensusLedger)
+	if err != nil {
+		return Objective{}, err
+	}
+	if o.Id() != p.ObjectiveId {
+		return Objective{}, fmt.Errorf("payload objective id %s does not match the rebalance %s", p.ObjectiveId, o.Id())
+	}
+
+	return o, nil
+}
+
+// newObjective initiates an Objective from the initial state of the rebalance channel.
+func newObjective(preApprove bool, initialState state.State, myAddress types.Address, consensusAppAddress types.Address, getTwoPartyConsensusLedger GetTwoPartyConsensusLedgerFunction) (Objective, error) {
+	if err := validateInitialState(initialState, consensusAppAddress); err != nil {
+		return Objective{}, err
+	}
+
+	participants := initialState.Participants
+	myIndex := -1
+	for i, p := range participants {
+		if p == myAddress {
+			myIndex = i
+		}
+	}
+	if myIndex == -1 {
+		return Objective{}, errors.New("not a participant in the rebalance")
+	}
+
+	r, err := channel.New(initialState, uint(myIndex), types.Rebalance)
+	if err != nil {
+		return Objective{}, err
+	}
+
+	n := len(participants)
+	leftOfMe := participants[(myIndex+n-1)%n]
+	rightOfMe := participants[(myIndex+1)%n]
+
+	left, ok := getTwoPartyConsensusLedger(leftOfMe)
+	if !ok {
+		return Objective{}, fmt.Errorf("%w between %s and %s", ErrLedgerNotFound, leftOfMe, myAddress)
+	}
+	right, ok := getTwoPartyConsensusLedger(rightOfMe)
+	if !ok {
+		return Objective{}, fmt.Errorf("%w between %s and %s", ErrLedgerNotFound, myAddress, rightOfMe)
+	}
+
+	o := Objective{
+		R:         r,
+		ToMyLeft:  left,
+		ToMyRight: right,
+	}
+	if preApprove {
+		o.Status = protocols.Approved
+	} else {
+		o.Status = protocols.Unapproved
+	}
+
+	return o, nil
+}
+
+// validateInitialState checks that the state describes a cycle of distinct participants, each of which pays the same positive amount of a single asset
+// to the next participant, in a channel run by the ConsensusApp.
+func validateInitialState(s state.State, consensusAppAddress types.Address) error {
+	participants := s.Participants
+	if len(participants) < 3 {
+		return ErrInvalidCycle
+	}
+	for i := range participants {
+		for j := i + 1; j < len(participants); j++ {
+			if participants[i] == participants[j] {
+				return ErrInvalidCycle
+			}
+		}
+	}
+
+	if s.AppDefinition != consensusAppAddress {
+		return fmt.Errorf("the rebalance channel must be run by the ConsensusApp %s, not %s", consensusAppAddress, s.AppDefinition)
+	}
+
+	if len(s.Outcome) != len(participants) {
+		return errors.New("a rebalance has one exit for every payment in the cycle")
+	}
+	n := len(participants)
+	for i, exit := range s.Outcome {
+		if exit.Asset != s.Outcome[0].Asset {
+			return errors.New("a rebalance moves a single asset")
+		}
+		if len(exit.Allocations) != 2 {
+			return fmt.Errorf("exit %d should allocate to the payer and the payee", i)
+		}
+		payer, payee := exit.Allocations[0], exit.Allocations[1]
+		if payer.Destination != types.AddressToDestination(participants[i]) || payee.Destination != types.AddressToDestination(participants[(i+1)%n]) {
+			return fmt.Errorf("exit %d does not correspond to the payment from participant %d to the next participant", i, i)
+		}
+		if payer.AllocationType != outcome.SimpleAllocationType || payee.AllocationType != outcome.SimpleAllocationType {
+			return fmt.Errorf("exit %d should only contain simple allocations", i)
+		}
+		if payer.Amount == nil || payer.Amount.Sign() <= 0 || payer.Amount.Cmp(s.Outcome[0].Allocations[0].Amount) != 0 {
+			return ErrInvalidAmount
+		}
+		if payee.Amount == nil || payee.Amount.Sign() != 0 {
+			return fmt.Errorf("exit %d should not allocate to the payee before the settlement", i)
+		}
+	}
+
+	return nil
+}
+
+// Id returns the objective id.
+func (o *Objective) Id() protocols.ObjectiveId {
+	return protocols.ObjectiveId(ObjectivePrefix + o.R.Id.String())
+}
+
+// Approve returns an approved copy of the objective.
+func (o *Objective) Approve() protocols.Objective {
+	updated := o.clone()
+	// todo: consider case of s.Status == Rejected
+	updated.Status = protocols.Approved
+
+	return &updated
+}
+
+// Reject returns a rejected copy of the objective.
+func (o *Objective) Reject() (protocols.Objective, protocols.SideEffects) {
+	updated := o.clone()
+	updated.Status = protocols.Rejected
+
+	messages := protocols.CreateRejectionNoticeMessage(o.Id(), o.otherParticipants()...)
+	sideEffects := protocols.SideEffects{MessagesToSend: messages}
+	return &updated, sideEffects
+}
+
+// OwnsChannel returns the rebalance channel.
+func (o *Objective) OwnsChannel() types.Destination {
+	return o.R.Id
+}
+
+// GetStatus returns the status of the objective.
+func (o *Objective) GetStatus() protocols.ObjectiveStatus {
+	return o.Status
+}
+
+// Related returns the rebalance channel and the ledger channels, which need to be stored along with the objective.
+func (o *Objective) Related() []protocols.Storable {
+	return []protocols.Storable{o.R, o.ToMyLeft, o.ToMyRight}
+}
+
+// Asset returns the asset which is moved by the rebalance.
+func (o *Objective) Asset() common.Address {
+	return o.R.PreFundState().Outcome[0].Asset
+}
+
+// Amount returns the amount which every participant pays to the next one.
+func (o *Objective) Amount() *big.Int {
+	return new(big.Int).Set(o.R.PreFundState().Outcome[0].Allocations[0].Amount)
+}
+
+// SettlementState returns the state of the rebalance channel in which every exit allocates the amount to the payee.
+func (o *Objective) SettlementState() state.State {
+	s := o.R.PostFundState().Clone()
+	s.TurnNum = SettlementTurnNum
+	for _, exit := range s.Outcome {
+		exit.Allocations[0].Amount, exit.Allocations[1].Amount = exit.Allocations[1].Amount, exit.Allocations[0].Amount
+	}
+	return s
+}
+
+// Committed returns true if we have signed the settlement state. A committed rebalance can no longer be unlocked,
+// since the settlement state may be supported by the other participants.
+func (o *Objective) Committed() bool {
+	ss, ok := o.R.OffChain.SignedStateForTurnNum[SettlementTurnNum]
+	return ok && ss.HasSignatureForParticipant(o.R.MyIndex)
+}
+
+// settlementComplete returns true if every participant has signed the settlement state.
+func (o *Objective) settlementComplete() bool {
+	ss, ok := o.R.OffChain.SignedStateForTurnNum[SettlementTurnNum]
+	return ok && ss.HasAllSignatures()
+}
+
+// expectedState returns the state of the rebalance channel with the given turn number, if there is one.
+func (o *Objective) expectedState(turnNum uint64) (state.State, bool) {
+	switch turnNum {
+	case channel.PreFundTurnNum:
+		return o.R.PreFundState(), true
+	case channel.PostFundTurnNum:
+		return o.R.PostFundState(), true
+	case SettlementTurnNum:
+		return o.SettlementState(), true
+	default:
+		return state.State{}, false
+	}
+}
+
+// Update receives a protocols.ObjectivePayload and applies it to a copy of the objective.
+func (o *Objective) Update(p protocols.ObjectivePayload) (protocols.Objective, error) {
+	if o.Id() != p.ObjectiveId {
+		return o, fmt.Errorf("event and objective Ids do not match: %s and %s respectively", string(p.ObjectiveId), string(o.Id()))
+	}
+	if p.Type != SignedStatePayload {
+		return o, fmt.Errorf("unexpected payload type %s", p.Type)
+	}
+
+	ss := state.SignedState{}
+	if err := json.Unmarshal(p.PayloadData, &ss); err != nil {
+		return o, fmt.Errorf("could not unmarshal signed state: %w", err)
+	}
+	if ss.State().ChannelId() != o.R.Id {
+		return o, fmt.Errorf("signed state is for an unexpected channel %s", ss.State().ChannelId())
+	}
+	if expected, ok := o.expectedState(ss.State().TurnNum); !ok || !ss.State().Equal(expected) {
+		return o, fmt.Errorf("signed state with turn %d is not a state of the rebalance", ss.State().TurnNum)
+	}
+
+	updated := o.clone()
+	updated.R.AddSignedState(ss)
+
+	return &updated, nil
+}
+
+// ReceiveProposal receives a signed proposal for one of the ledger channels and applies it to a copy of the objective.
+func (o *Objective) ReceiveProposal(sp consensus_channel.SignedProposal) (protocols.ProposalReceiver, error) {
+	pId, err := protocols.GetProposalObjectiveId(sp.Proposal, types.Rebalance)
+	if err != nil {
+		return o, err
+	}
+	if o.Id() != pId {
+		return o, fmt.Errorf("sp and objective Ids do not match: %s and %s respectively", string(pId), string(o.Id()))
+	}
+
+	updated := o.clone()
+
+	switch sp.Proposal.LedgerID {
+	case updated.ToMyLeft.Id:
+		err = updated.ToMyLeft.Receive(sp)
+	case updated.ToMyRight.Id:
+		err = updated.ToMyRight.Receive(sp)
+	default:
+		return o, fmt.Errorf("signed proposal is not addressed to a known ledger connection %+v", sp)
+	}
+	// Ignore stale or future proposals
+	if err != nil && !errors.Is(err, consensus_channel.ErrInvalidTurnNum) {
+		return o, fmt.Errorf("error incorporating signed proposal %+v into objective: %w", sp, err)
+	}
+
+	return &updated, nil
+}
+
+// Crank inspects the extended state and declares a list of Effects to be executed.
+func (o *Objective) Crank(secretKey *[]byte) (protocols.Objective, protocols.SideEffects, protocols.WaitingFor, error) {
+	updated := o.clone()
+	sideEffects := protocols.SideEffects{}
+
+	if updated.Status != protocols.Approved {
+		return &updated, sideEffects, WaitingForNothing, protocols.ErrNotApproved
+	}
+
+	// Prefunding: every participant agrees to the rebalance
+	if !updated.R.PreFundSignedByMe() {
+		ss, err := updated.R.SignAndAddPrefund(secretKey)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, err
+		}
+		messages, err := protocols.CreateObjectivePayloadMessage(updated.Id(), ss, SignedStatePayload, updated.otherParticipants()...)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, err
+		}
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, messages...)
+	}
+	if !updated.R.PreFundComplete() {
+		return &updated, sideEffects, WaitingForCompletePrefund, nil
+	}
+
+	// Locking: both of our ledger channels lock the payment in a guarantee, and we say so by signing the postfund
+	if !updated.R.PostFundSignedByMe() {
+		for _, ledger := range []*consensus_channel.ConsensusChannel{updated.ToMyLeft, updated.ToMyRight} {
+			if updated.isLocked(ledger) {
+				continue
+			}
+			ledgerSideEffects, err := updated.lock(ledger, secretKey)
+			if err != nil {
+				return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("error locking funds in ledger channel %s: %w", ledger.Id, err)
+			}
+			sideEffects.Merge(ledgerSideEffects)
+		}
+		if !updated.isLocked(updated.ToMyLeft) || !updated.isLocked(updated.ToMyRight) {
+			return &updated, sideEffects, WaitingForLocks, nil
+		}
+
+		ss, err := updated.R.SignAndAddPostfund(secretKey)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, err
+		}
+		messages, err := protocols.CreateObjectivePayloadMessage(updated.Id(), ss, SignedStatePayload, updated.otherParticipants()...)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, err
+		}
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, messages...)
+	}
+	// A complete postfund means that every ledger channel in the cycle is locked
+	if !updated.R.PostFundComplete() {
+		return &updated, sideEffects, WaitingForCompletePostFund, nil
+	}
+
+	// Settlement: every participant agrees that every payee is paid, which commits us to the rebalance
+	if !updated.Committed() {
+		ss, err := updated.R.SignAndAddState(updated.SettlementState(), secretKey)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, err
+		}
+		messages, err := protocols.CreateObjectivePayloadMessage(updated.Id(), ss, SignedStatePayload, updated.otherParticipants()...)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, err
+		}
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, messages...)
+	}
+	// Until the settlement is supported, R may still be finalized on chain with an outcome which refunds the payers
+	if !updated.settlementComplete() {
+		return &updated, sideEffects, WaitingForSettlement, nil
+	}
+
+	// Release: the guarantees are removed in favour of the payees, which is how R's supported outcome would reclaim them
+	for _, ledger := range []*consensus_channel.ConsensusChannel{updated.ToMyLeft, updated.ToMyRight} {
+		if !ledger.IncludesTarget(updated.R.Id) {
+			continue
+		}
+		ledgerSideEffects, err := updated.release(ledger, secretKey)
+		if err != nil {
+			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("error releasing funds in ledger channel %s: %w", ledger.Id, err)
+		}
+		sideEffects.Merge(ledgerSideEffects)
+	}
+	if updated.ToMyLeft.IncludesTarget(updated.R.Id) || updated.ToMyRight.IncludesTarget(updated.R.Id) {
+		return &updated, sideEffects, WaitingForRelease, nil
+	}
+
+	updated.Status = protocols.Completed
+	return &updated, sideEffects, WaitingForNothing, nil
+}
+
+// guarantee returns the guarantee which locks the payment in the given ledger channel, which is paid by the
+// participant on the left of the ledger channel to the participant on its right.
+func (o *Objective) guarantee(ledger *consensus_channel.ConsensusChannel) consensus_channel.Guarantee {
+	me := o.R.Participants[o.R.MyIndex]
+	n := uint(len(o.R.Participants))
+
+	payer, payee := me, o.R.Participants[(o.R.MyIndex+1)%n]
+	if ledger.Id == o.ToMyLeft.Id {
+		payer, payee = o.R.Participants[(o.R.MyIndex+n-1)%n], me
+	}
+
+	return consensus_channel.NewGuarantee(o.Amount(), o.R.Id, types.AddressToDestination(payer), types.AddressToDestination(payee))
+}
+
+// isLocked returns true if the ledger channel includes the guarantee which locks the payment.
+func (o *Objective) isLocked(ledger *consensus_channel.ConsensusChannel) bool {
+	return ledger.Includes(o.guarantee(ledger), o.Asset())
+}
+
+// lock updates the ledger channel to lock the payment in a guarantee, which is entirely deposited by the payer.
+func (o *Objective) lock(ledger *consensus_channel.ConsensusChannel, sk *[]byte) (protocols.SideEffects, error) {
+	g := o.guarantee(ledger)
+	proposal := consensus_channel.NewAddProposal(ledger.Id, g, o.Amount(), o.Asset())
+
+	proposed, err := ledger.IsProposed(g, o.Asset())
+	if err != nil {
+		return protocols.SideEffects{}, err
+	}
+	proposedNext, err := ledger.IsProposedNext(g, o.Asset())
+	if err != nil {
+		return protocols.SideEffects{}, err
+	}
+
+	return updateLedger(ledger, proposal, proposed, proposedNext, sk)
+}
+
+// Unlock returns a copy of a rejected objective which removes its guarantees from the ledger channels in favour of the payers.
+// The leader of a ledger channel proposes the refund of any guarantee which is included or proposed, and the follower signs
+// the proposals which add and refund the guarantee as they reach the front of the proposal queue.
+//
+// An error is returned if the objective has not been rejected, or if we have signed the settlement state.
+func (o *Objective) Unlock(secretKey *[]byte) (protocols.Objective, protocols.SideEffects, error) {
+	if o.Status != protocols.Rejected {
+		return o, protocols.SideEffects{}, errors.New("only a rejected rebalance can be unlocked")
+	}
+	if o.Committed() {
+		return o, protocols.SideEffects{}, ErrCommitted
+	}
+
+	updated := o.clone()
+	sideEffects := protocols.SideEffects{}
+
+	for _, ledger := range []*consensus_channel.ConsensusChannel{updated.ToMyLeft, updated.ToMyRight} {
+		ledgerSideEffects, err := updated.refund(ledger, secretKey)
+		if err != nil {
+			return o, protocols.SideEffects{}, fmt.Errorf("error refunding funds in ledger channel %s: %w", ledger.Id, err)
+		}
+		sideEffects.Merge(ledgerSideEffects)
+	}
+
+	return &updated, sideEffects, nil
+}
+
+// refund updates the ledger channel to remove the guarantee in favour of the payer.
+func (o *Objective) refund(ledger *consensus_channel.ConsensusChannel, sk *[]byte) (protocols.SideEffects, error) {
+	g := o.guarantee(ledger)
+	refund := consensus_channel.NewRemoveProposal(ledger.Id, o.R.Id, o.Amount(), o.Asset())
+
+	if ledger.IsLeader() {
+		if ledger.HasRemovalBeenProposed(o.R.Id, o.Asset()) {
+			return protocols.SideEffects{}, nil
+		}
+		proposed, err := ledger.IsProposed(g, o.Asset())
+		if err != nil {
+			return protocols.SideEffects{}, err
+		}
+		if !proposed && !ledger.Includes(g, o.Asset()) {
+			return protocols.SideEffects{}, nil
+		}
+		return updateLedger(ledger, refund, false, false, sk)
+	}
+
+	// The guarantee must be added before it can be refunded, so we sign the addition of a guarantee which was proposed before the rebalance was rejected
+	sideEffects := protocols.SideEffects{}
+	for {
+		add := consensus_channel.NewAddProposal(ledger.Id, g, o.Amount(), o.Asset())
+		var next consensus_channel.Proposal
+		switch {
+		case isProposedNext(ledger, add):
+			next = add
+		case isProposedNext(ledger, refund):
+			next = refund
+		default:
+			return sideEffects, nil
+		}
+		ledgerSideEffects, err := updateLedger(ledger, next, true, true, sk)
+		if err != nil {
+			return protocols.SideEffects{}, err
+		}
+		sideEffects.Merge(ledgerSideEffects)
+	}
+}
+
+// release updates the ledger channel to remove the guarantee in favour of the payee.
+func (o *Objective) release(ledger *consensus_channel.ConsensusChannel, sk *[]byte) (protocols.SideEffects, error) {
+	proposal := consensus_channel.NewRemoveProposal(ledger.Id, o.R.Id, big.NewInt(0), o.Asset())
+
+	proposed := ledger.HasRemovalBeenProposed(o.R.Id, o.Asset())
+
+	return updateLedger(ledger, proposal, proposed, isProposedNext(ledger, proposal), sk)
+}
+
+// isProposedNext returns true if the proposal is at the front of the ledger channel's proposal queue.
+// Unlike HasRemovalBeenProposedNext, it tells a refund of the guarantee apart from its release.
+func isProposedNext(ledger *consensus_channel.ConsensusChannel, proposal consensus_channel.Proposal) bool {
+	queue := ledger.ProposalQueue()
+	return len(queue) > 0 && queue[0].Proposal.Equal(&proposal)
+}
+
+// updateLedger proposes the proposal if we lead the ledger channel, and otherwise signs it once it is next in the proposal queue.
+func updateLedger(ledger *consensus_channel.ConsensusChannel, proposal consensus_channel.Proposal, proposed, proposedNext bool, sk *[]byte) (protocols.SideEffects, error) {
+	sideEffects := protocols.SideEffects{}
+
+	if ledger.IsLeader() {
+		if proposed {
+			return sideEffects, nil
+		}
+		if _, err := ledger.Propose(proposal, *sk); err != nil {
+			return protocols.SideEffects{}, fmt.Errorf("error proposing ledger update: %w", err)
+		}
+
+		// Since the proposal queue is constructed with consecutive turn numbers, we can pass it straight in
+		// to create a valid message with ordered proposals:
+		message := protocols.CreateSignedProposalMessage(ledger.Follower(), ledger.ProposalQueue()...)
+		sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, message)
+
+		return sideEffects, nil
+	}
+
+	if !proposedNext {
+		return sideEffects, nil
+	}
+	sp, err := ledger.SignNextProposal(proposal, *sk)
+	if err != nil {
+		return protocols.SideEffects{}, fmt.Errorf("could not sign proposal: %w", err)
+	}
+	// ledger sideEffect
+	if proposals := ledger.ProposalQueue(); len(proposals) != 0 {
+		sideEffects.ProposalsToProcess = append(sideEffects.ProposalsToProcess, proposals[0].Proposal)
+	}
+	// messaging sideEffect
+	message := protocols.CreateSignedProposalMessage(ledger.Leader(), sp)
+	sideEffects.MessagesToSend = append(sideEffects.MessagesToSend, message)
+
+	return sideEffects, nil
+}
+
+func (o *Objective) otherParticipants() []types.Address {
+	others := make([]types.Address, 0)
+	for i, p := range o.R.Participants {
+		if uint(i) != o.R.MyIndex {
+			others = append(others, p)
+		}
+	}
+	return others
+}
+
+// clone returns a deep copy of the receiver.
+func (o *Objective) clone() Objective {
+	clone := Objective{}
+	clone.Status = o.Status
+	clone.R = o.R.Clone()
+	clone.ToMyLeft = o.ToMyLeft.Clone()
+	clone.ToMyRight = o.ToMyRight.Clone()
+
+	return clone
+}
+
+// balance returns the participant's balance of the asset in the ledger channel.
+func balance(ledger *consensus_channel.ConsensusChannel, participant types.Address, asset common.Address) *big.Int {
+	for _, assetExit := range ledger.ConsensusVars().AsState(ledger.FixedPart()).Outcome {
+		if assetExit.Asset != asset {
+			continue
+		}
+		for _, allocation := range assetExit.Allocations {
+			if allocation.Destination == types.AddressToDestination(participant) {
+				return allocation.Amount
+			}
+		}
+	}
+	return big.NewInt(0)
+}
+
+// IsRebalanceObjective inspects an objective id and returns true if the objective id is for a rebalance objective.
+func IsRebalanceObjective(id protocols.ObjectiveId) bool {
+	return strings.HasPrefix(string(id), ObjectivePrefix)
+}
+
+// ObjectiveRequest represents a request to create a new rebalance objective.
+type ObjectiveRequest struct {
+	// Peers are the other participants of the cycle, in payment order. We pay the first peer, and the last peer pays us.
+	Peers  []types.Address
+	Asset  common.Address
+	Amount *big.Int
+	// AppDefinition is the address of the ConsensusApp, which runs the rebalance channel if it is finalized on chain
+	AppDefinition     types.Address
+	ChallengeDuration uint32
+	Nonce             uint64
+	objectiveStarted  chan struct{}
+}
+
+// NewObjectiveRequest creates a new ObjectiveRequest.
+func NewObjectiveRequest(peers []types.Address, asset common.Address, amount *big.Int, appDefinition types.Address, challengeDuration uint32, nonce uint64) ObjectiveRequest {
+	return ObjectiveRequest{
+		Peers:             peers,
+		Asset:             asset,
+		Amount:            amount,
+		AppDefinition:     appDefinition,
+		ChallengeDuration: challengeDuration,
+		Nonce:             nonce,
+		objectiveStarted:  make(chan struct{}),
+	}
+}
+
+// Id returns the objective id for the request.
+func (r ObjectiveRequest) Id(myAddress types.Address, chainId *big.Int) protocols.ObjectiveId {
+	return protocols.ObjectiveId(ObjectivePrefix + r.initialState(myAddress).ChannelId().String())
+}
+
+// SignalObjectiveStarted is used by the engine to signal the objective has been started.
+func (r ObjectiveRequest) SignalObjectiveStarted() {
+	close(r.objectiveStarted)
+}
+
+// WaitForObjectiveToStart blocks until the objective starts
+func (r ObjectiveRequest) WaitForObjectiveToStart() {
+	<-r.objectiveStarted
+}
+
+// ObjectiveResponse is the type returned across the API in response to the ObjectiveRequest.
+type ObjectiveResponse struct {
+	Id        protocols.ObjectiveId
+	ChannelId types.Destination
+}
+
+// Response computes and returns the appropriate response from the request.
+func (r ObjectiveRequest) Response(myAddress types.Address) ObjectiveResponse {
+	channelId := r.initialState(myAddress).ChannelId()
+
+	return ObjectiveResponse{
+		Id:        protocols.ObjectiveId(ObjectivePrefix + channelId.String()),
+		ChannelId: channelId,
+	}
+}
+
+// initialState returns the prefund state of the rebalance channel, which has an exit for the payment from every participant
+// of the cycle to the next one, allocating the amount to the payer.
+func (r ObjectiveRequest) initialState(myAddress types.Address) state.State {
+	participants := append([]types.Address{myAddress}, r.Peers...)
+
+	exit := outcome.Exit{}
+	for i, p := range participants {
+		amount := big.NewInt(0)
+		if r.Amount != nil {
+			amount.Set(r.Amount)
+		}
+		payee := participants[(i+1)%len(participants)]
+		exit = append(exit, outcome.SingleAssetExit{
+			Asset: r.Asset,
+			Allocations: outcome.Allocations{
+				{Destination: types.AddressToDestination(p), Amount: amount},
+				{Destination: types.AddressToDestination(payee), Amount: big.NewInt(0)},
+			},
+		})
+	}
+
+	return state.State{
+		Participants:      participants,
+		ChannelNonce:      r.Nonce,
+		AppDefinition:     r.AppDefinition,
+		ChallengeDuration: r.ChallengeDuration,
+		Outcome:           exit,
+		TurnNum:           channel.PreFundTurnNum,
+	}
+}
@@ -0,0 +1,438 @@
+package rebalance
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+var consensusApp = common.HexToAddress("0x00000000000000000000000000000000000c0a99")
+
+const (
+	ledgerDeposit   = 10
+	rebalanceAmount = 4
+)
+
+// network runs a rebalance between actors which pay each other in a cycle, delivering the messages between their objectives.
+type network struct {
+	t      *testing.T
+	actors []testactors.Actor
+	// ledgers holds every actor's copy of its ledger channels, keyed by the counterparty
+	ledgers    map[types.Address]map[types.Address]*consensus_channel.ConsensusChannel
+	objectives map[types.Address]*Objective
+	inbox      []protocols.Message
+	// offline actors neither send nor receive messages
+	offline map[types.Address]bool
+}
+
+// newNetwork opens a ledger channel between every actor and the next one, led by the actor which comes first.
+func newNetwork(t *testing.T, actors ...testactors.Actor) *network {
+	n := &network{
+		t:          t,
+		actors:     actors,
+		ledgers:    make(map[types.Address]map[types.Address]*consensus_channel.ConsensusChannel),
+		objectives: make(map[types.Address]*Objective),
+		offline:    make(map[types.Address]bool),
+	}
+	for _, a := range actors {
+		n.ledgers[a.Address()] = make(map[types.Address]*consensus_channel.ConsensusChannel)
+	}
+
+	for i := range actors {
+		leader, follower := actors[i], actors[(i+1)%len(actors)]
+		if i == len(actors)-1 {
+			leader, follower = follower, leader
+		}
+		n.ledgers[leader.Address()][follower.Address()] = prepareConsensusChannel(0, leader, follower)
+		n.ledgers[follower.Address()][leader.Address()] = prepareConsensusChannel(1, leader, follower)
+	}
+
+	return n
+}
+
+// prepareConsensusChannel returns the given role's copy of a funded ledger channel between the leader and the follower.
+func prepareConsensusChannel(role uint, leader, follower testactors.Actor) *consensus_channel.ConsensusChannel {
+	fp := state.FixedPart{
+		Participants:      []types.Address{leader.Address(), follower.Address()},
+		AppDefinition:     consensusApp,
+		ChallengeDuration: 45,
+	}
+	lo := *consensus_channel.NewLedgerOutcome(
+		types.Address{},
+		consensus_channel.NewBalance(leader.Destination(), big.NewInt(ledgerDeposit)),
+		consensus_channel.NewBalance(follower.Destination(), big.NewInt(ledgerDeposit)),
+		[]consensus_channel.Guarantee{},
+	)
+	vars := consensus_channel.Vars{Outcome: lo, TurnNum: 1}
+
+	sigs := [2]state.Signature{}
+	for i, a := range []testactors.Actor{leader, follower} {
+		sig, err := vars.AsState(fp).Sign(a.PrivateKey)
+		if err != nil {
+			panic(err)
+		}
+		sigs[i] = sig
+	}
+
+	var cc consensus_channel.ConsensusChannel
+	var err error
+	if role == 0 {
+		cc, err = consensus_channel.NewLeaderChannel(fp, 1, lo, sigs)
+	} else {
+		cc, err = consensus_channel.NewFollowerChannel(fp, 1, lo, sigs)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	return &cc
+}
+
+func (n *network) getLedger(me types.Address) GetTwoPartyConsensusLedgerFunction {
+	return func(counterparty types.Address) (*consensus_channel.ConsensusChannel, bool) {
+		ledger, ok := n.ledgers[me][counterparty]
+		if !ok {
+			return nil, false
+		}
+		return ledger.Clone(), true
+	}
+}
+
+func (n *network) actor(address types.Address) testactors.Actor {
+	for _, a := range n.actors {
+		if a.Address() == address {
+			return a
+		}
+	}
+	n.t.Fatalf("unknown actor %s", address)
+	return testactors.Actor{}
+}
+
+// start creates the rebalance objective of the first actor, which pays the second actor and is paid by the last one.
+func (n *network) start() {
+	initiator := n.actors[0]
+	peers := []types.Address{}
+	for _, a := range n.actors[1:] {
+		peers = append(peers, a.Address())
+	}
+
+	request := NewObjectiveRequest(peers, types.Address{}, big.NewInt(rebalanceAmount), consensusApp, 45, 1)
+	o, err := NewObjective(request, true, initiator.Address(), n.getLedger(initiator.Address()))
+	if err != nil {
+		n.t.Fatal(err)
+	}
+	n.objectives[initiator.Address()] = &o
+}
+
+// store replaces the actor's objective, and its copies of the ledger channels.
+func (n *network) store(address types.Address, o protocols.Objective) {
+	ro := o.(*Objective)
+	n.objectives[address] = ro
+	for _, ledger := range []*consensus_channel.ConsensusChannel{ro.ToMyLeft, ro.ToMyRight} {
+		participants := ledger.Participants()
+		counterparty := participants[1-ledger.MyIndex]
+		n.ledgers[address][counterparty] = ledger
+	}
+}
+
+// crank cranks every objective which is in progress, or unlocks it if it was rejected, and queues the messages it sends.
+func (n *network) crank() {
+	for _, a := range n.actors {
+		o, ok := n.objectives[a.Address()]
+		if !ok || n.offline[a.Address()] {
+			continue
+		}
+
+		var updated protocols.Objective
+		var sideEffects protocols.SideEffects
+		var err error
+		switch o.Status {
+		case protocols.Approved:
+			updated, sideEffects, _, err = o.Crank(&a.PrivateKey)
+		case protocols.Rejected:
+			updated, sideEffects, err = o.Unlock(&a.PrivateKey)
+		default:
+			continue
+		}
+		if err != nil {
+			n.t.Fatalf("%s could not progress the rebalance: %v", a.Name, err)
+		}
+		n.store(a.Address(), updated)
+		n.inbox = append(n.inbox, sideEffects.MessagesToSend...)
+	}
+}
+
+// deliver delivers the queued messages, returning the number of messages delivered.
+func (n *network) deliver() int {
+	inbox := n.inbox
+	n.inbox = nil
+
+	delivered := 0
+	for _, message := range inbox {
+		if n.offline[message.To] || n.offline[message.From] {
+			continue
+		}
+		delivered++
+		recipient := n.actor(message.To)
+
+		for _, payload := range message.ObjectivePayloads {
+			o, ok := n.objectives[recipient.Address()]
+			if !ok {
+				constructed, err := ConstructObjectiveFromPayload(payload, true, recipient.Address(), consensusApp, n.getLedger(recipient.Address()))
+				if err != nil {
+					n.t.Fatal(err)
+				}
+				o = &constructed
+			}
+			if o.Status == protocols.Rejected {
+				continue
+			}
+			updated, err := o.Update(payload)
+			if err != nil {
+				n.t.Fatal(err)
+			}
+			n.store(recipient.Address(), updated)
+		}
+
+		for _, sp := range message.LedgerProposals {
+			updated, err := n.objectives[recipient.Address()].ReceiveProposal(sp)
+			if err != nil {
+				n.t.Fatal(err)
+			}
+			n.store(recipient.Address(), updated.(protocols.Objective))
+		}
+
+		for range message.RejectedObjectives {
+			o := n.objectives[recipient.Address()]
+			if o.Status == protocols.Rejected || o.Committed() {
+				continue
+			}
+			rejected, _ := o.Reject()
+			n.store(recipient.Address(), rejected)
+		}
+	}
+
+	return delivered
+}
+
+// runUntil cranks the objectives and delivers their messages until the condition holds, or no more messages are sent.
+func (n *network) runUntil(condition func() bool) {
+	for i := 0; i < 100; i++ {
+		n.crank()
+		if n.deliver() == 0 && len(n.inbox) == 0 {
+			return
+		}
+		if condition() {
+			return
+		}
+	}
+	n.t.Fatal("the rebalance did not settle down")
+}
+
+func (n *network) run() {
+	n.runUntil(func() bool { return false })
+}
+
+// balances returns the balances of the ledger channel between a and b, as seen by both of them.
+func (n *network) balances(a, b testactors.Actor) (aBalance, bBalance *big.Int) {
+	n.t.Helper()
+
+	aView, bView := n.ledgers[a.Address()][b.Address()], n.ledgers[b.Address()][a.Address()]
+	if aView.ConsensusTurnNum() != bView.ConsensusTurnNum() {
+		n.t.Fatalf("%s and %s disagree on the ledger channel: turn %d and turn %d", a.Name, b.Name, aView.ConsensusTurnNum(), bView.ConsensusTurnNum())
+	}
+
+	return balance(aView, a.Address(), types.Address{}), balance(aView, b.Address(), types.Address{})
+}
+
+func (n *network) checkBalances(payer, payee testactors.Actor, wantPayer, wantPayee int64) {
+	n.t.Helper()
+
+	payerBalance, payeeBalance := n.balances(payer, payee)
+	if payerBalance.Cmp(big.NewInt(wantPayer)) != 0 || payeeBalance.Cmp(big.NewInt(wantPayee)) != 0 {
+		n.t.Errorf("ledger channel between %s and %s: got balances %v and %v, wanted %d and %d", payer.Name, payee.Name, payerBalance, payeeBalance, wantPayer, wantPayee)
+	}
+}
+
+// isLocked returns true if the actor's copies of its ledger channels both include the guarantee for the rebalance.
+func (n *network) isLocked(a testactors.Actor) bool {
+	o, ok := n.objectives[a.Address()]
+	return ok && o.isLocked(o.ToMyLeft) && o.isLocked(o.ToMyRight)
+}
+
+func TestRebalance(t *testing.T) {
+	alice, irene, bob := testactors.Alice, testactors.Irene, testactors.Bob
+	n := newNetwork(t, alice, irene, bob)
+
+	n.start()
+	n.run()
+
+	for _, a := range n.actors {
+		o := n.objectives[a.Address()]
+		if o.Status != protocols.Completed {
+			t.Errorf("%s's rebalance has status %v, wanted %v", a.Name, o.Status, protocols.Completed)
+		}
+		supported, err := o.R.LatestSupportedState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !supported.Equal(o.SettlementState()) {
+			t.Errorf("%s's rebalance channel does not support the settlement state", a.Name)
+		}
+		for _, ledger := range []*consensus_channel.ConsensusChannel{o.ToMyLeft, o.ToMyRight} {
+			if ledger.IncludesTarget(o.R.Id) || len(ledger.ProposalQueue()) != 0 {
+				t.Errorf("%s's ledger channel %s still has proposals or a guarantee for the rebalance", a.Name, ledger.Id)
+			}
+		}
+	}
+
+	n.checkBalances(alice, irene, ledgerDeposit-rebalanceAmount, ledgerDeposit+rebalanceAmount)
+	n.checkBalances(irene, bob, ledgerDeposit-rebalanceAmount, ledgerDeposit+rebalanceAmount)
+	n.checkBalances(bob, alice, ledgerDeposit-rebalanceAmount, ledgerDeposit+rebalanceAmount)
+}
+
+func TestRebalanceChannelIsReclaimable(t *testing.T) {
+	alice, irene, bob := testactors.Alice, testactors.Irene, testactors.Bob
+	n := newNetwork(t, alice, irene, bob)
+	n.start()
+	o := n.objectives[alice.Address()]
+
+	prefund := o.R.PreFundState()
+	if prefund.AppDefinition != consensusApp {
+		t.Errorf("the rebalance channel is run by %s, wanted the ConsensusApp %s", prefund.AppDefinition, consensusApp)
+	}
+
+	// every guarantee is reclaimed against the exit for its hop, which allocates to the guarantee's left (the payer) and right (the payee)
+	participants := []testactors.Actor{alice, irene, bob}
+	for i, exit := range prefund.Outcome {
+		payer, payee := participants[i], participants[(i+1)%len(participants)]
+		if len(exit.Allocations) != 2 || exit.Allocations[0].Destination != payer.Destination() || exit.Allocations[1].Destination != payee.Destination() {
+			t.Fatalf("exit %d does not allocate to %s and %s: %+v", i, payer.Name, payee.Name, exit.Allocations)
+		}
+		if exit.Allocations[0].Amount.Int64() != rebalanceAmount || exit.Allocations[1].Amount.Sign() != 0 {
+			t.Errorf("exit %d of the prefund state should refund the payer: %+v", i, exit.Allocations)
+		}
+
+		settlement := o.SettlementState().Outcome[i]
+		if settlement.Allocations[0].Amount.Sign() != 0 || settlement.Allocations[1].Amount.Int64() != rebalanceAmount {
+			t.Errorf("exit %d of the settlement state should pay the payee: %+v", i, settlement.Allocations)
+		}
+	}
+
+	payload := protocols.ObjectivePayload{ObjectiveId: o.Id(), Type: SignedStatePayload}
+	ss := state.NewSignedState(prefund)
+	var err error
+	if payload.PayloadData, err = ss.MarshalJSON(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ConstructObjectiveFromPayload(payload, true, irene.Address(), common.Address{}, n.getLedger(irene.Address())); err == nil {
+		t.Error("expected an error constructing a rebalance which is not run by the ConsensusApp")
+	}
+}
+
+func TestRebalanceRejectsUnexpectedStates(t *testing.T) {
+	alice, irene, bob := testactors.Alice, testactors.Irene, testactors.Bob
+	n := newNetwork(t, alice, irene, bob)
+	n.start()
+	o := n.objectives[alice.Address()]
+
+	// a settlement which pays Irene twice over
+	settlement := o.SettlementState()
+	settlement.Outcome[0].Allocations[1].Amount = big.NewInt(2 * rebalanceAmount)
+	sig, err := settlement.Sign(irene.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := state.NewSignedState(settlement)
+	if err := ss.AddSignature(sig); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ss.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := o.Update(protocols.ObjectivePayload{ObjectiveId: o.Id(), Type: SignedStatePayload, PayloadData: data}); err == nil {
+		t.Error("expected an error updating the rebalance with a state which is not the settlement")
+	}
+}
+
+func TestRebalanceWithUncooperativePeer(t *testing.T) {
+	alice, irene, bob := testactors.Alice, testactors.Irene, testactors.Bob
+	n := newNetwork(t, alice, irene, bob)
+
+	// Bob goes offline once every ledger channel is locked, without signing the settlement
+	n.start()
+	n.runUntil(func() bool {
+		o, ok := n.objectives[bob.Address()]
+		return ok && o.R.PostFundComplete()
+	})
+	n.offline[bob.Address()] = true
+	n.run()
+
+	for _, a := range []testactors.Actor{alice, irene} {
+		o := n.objectives[a.Address()]
+		if o.Status == protocols.Completed {
+			t.Errorf("%s's rebalance completed without Bob", a.Name)
+		}
+		if !n.isLocked(a) {
+			t.Errorf("%s released a guarantee before the settlement was supported", a.Name)
+		}
+
+		// R can only be finalized with a state which refunds the payers, which is how the guarantees would be reclaimed on chain
+		supported, err := o.R.LatestSupportedState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if supported.TurnNum != channel.PostFundTurnNum {
+			t.Errorf("%s's rebalance channel supports turn %d, wanted the postfund", a.Name, supported.TurnNum)
+		}
+
+		// having signed the settlement, they must not refund the guarantees either
+		rejected, _ := o.Reject()
+		if _, _, err := rejected.(*Objective).Unlock(&a.PrivateKey); !errors.Is(err, ErrCommitted) {
+			t.Errorf("%s unlocked a rebalance with a signed settlement: %v", a.Name, err)
+		}
+	}
+
+	n.checkBalances(alice, irene, ledgerDeposit-rebalanceAmount, ledgerDeposit)
+	n.checkBalances(irene, bob, ledgerDeposit-rebalanceAmount, ledgerDeposit)
+}
+
+func TestAbandonedRebalanceUnlocksGuarantees(t *testing.T) {
+	alice, irene, bob := testactors.Alice, testactors.Irene, testactors.Bob
+	n := newNetwork(t, alice, irene, bob)
+
+	// Irene abandons the rebalance once her ledger channels are locked, before she signs the postfund
+	n.start()
+	n.runUntil(func() bool { return n.isLocked(irene) })
+
+	rejected, sideEffects := n.objectives[irene.Address()].Reject()
+	n.store(irene.Address(), rejected)
+	n.inbox = append(n.inbox, sideEffects.MessagesToSend...)
+	n.run()
+
+	for _, a := range n.actors {
+		o := n.objectives[a.Address()]
+		if o.Status != protocols.Rejected {
+			t.Errorf("%s's rebalance has status %v, wanted %v", a.Name, o.Status, protocols.Rejected)
+		}
+		for _, ledger := range []*consensus_channel.ConsensusChannel{o.ToMyLeft, o.ToMyRight} {
+			if ledger.IncludesTarget(o.R.Id) || len(ledger.ProposalQueue()) != 0 {
+				t.Errorf("%s's ledger channel %s still has proposals or a guarantee for the rebalance", a.Name, ledger.Id)
+			}
+		}
+	}
+
+	n.checkBalances(alice, irene, ledgerDeposit, ledgerDeposit)
+	n.checkBalances(irene, bob, ledgerDeposit, ledgerDeposit)
+	n.checkBalances(bob, alice, ledgerDeposit, ledgerDeposit)
+}
@@ -0,0 +1,54 @@
+package rebalance
+
+import (
+	"encoding/json"
+
+	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// jsonObjective replaces the rebalance.Objective's channel pointers with
+// the channels' IDs, making jsonObjective suitable for serialization
+type jsonObjective struct {
+	Status    protocols.ObjectiveStatus
+	R         types.Destination
+	ToMyLeft  types.Destination
+	ToMyRight types.Destination
+}
+
+// MarshalJSON returns a JSON representation of the Objective
+// NOTE: Marshal -> Unmarshal is a lossy process. All channel data
+// (other than Id) from the fields R, ToMyLeft and ToMyRight is discarded
+func (o *Objective) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonObjective{
+		Status:    o.Status,
+		R:         o.R.Id,
+		ToMyLeft:  o.ToMyLeft.Id,
+		ToMyRight: o.ToMyRight.Id,
+	})
+}
+
+// UnmarshalJSON populates the calling Objective with the
+// json-encoded data
+// NOTE: Marshal -> Unmarshal is a lossy process. All channel data
+// (other than Id) from the fields R, ToMyLeft and ToMyRight is discarded
+func (o *Objective) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var jsonO jsonObjective
+	if err := json.Unmarshal(data, &jsonO); err != nil {
+		return err
+	}
+
+	o.Status = jsonO.Status
+	o.R = &channel.Channel{}
+	o.R.Id = jsonO.R
+	o.ToMyLeft = &consensus_channel.ConsensusChannel{Id: jsonO.ToMyLeft}
+	o.ToMyRight = &consensus_channel.ConsensusChannel{Id: jsonO.ToMyRight}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/statechannels/go-nitro/channel"
@@ -29,9 +30,14 @@ const (
 
 const ObjectivePrefix = "Swap-"
 
+// DEFAULT_SWAP_EXPIRY is how long the counterparty has to accept a swap, unless the swap sets its own expiry
+const DEFAULT_SWAP_EXPIRY = 5 * time.Minute
+
 var (
-	ErrInvalidSwap error = errors.New("invalid swap")
-	ErrSwapExists  error = errors.New("swap already exists")
+	ErrInvalidSwap   error = errors.New("invalid swap")
+	ErrSwapExists    error = errors.New("swap already exists")
+	ErrSwapExpired   error = errors.New("swap has expired")
+	ErrNotCancelable error = errors.New("swap cannot be cancelled")
 )
 
 type SwapPayload struct {
@@ -49,6 +55,9 @@ type Objective struct {
 	SwapStatus types.SwapStatus
 	// Index of participant who initiated the swap in allocations array
 	SwapSenderIndex uint
+	// CancellationPending is set when we cancel a swap, until the counterparty acknowledges the cancellation.
+	// Until then the counterparty may still accept the swap, so no other swap can be made in the swap channel.
+	CancellationPending bool
 }
 
 // NewObjective creates a new swap objective from a given request.
@@ -244,6 +253,11 @@ func (o *Objective) Crank(secretKey *[]byte) (protocols.Objective, protocols.Sid
 		return &updated, sideEffects, WaitingForNothing, err
 	}
 
+	// Swap receiver rejects the swap if it expired before we signed it
+	if updated.SwapSenderIndex != myIndex && !updated.HasSignatureForParticipant() && updated.Swap.IsExpired(time.Now()) {
+		updated.SwapStatus = types.Rejected
+	}
+
 	// Swap receiver checks whether to accept or reject
 	if updated.SwapSenderIndex != myIndex && updated.SwapStatus != types.Accepted {
 		if updated.SwapStatus == types.PendingConfirmation {
@@ -333,33 +347,37 @@ func (o *Objective) UpdateSwapChannelState() error {
 }
 
 func (o *Objective) GetUpdatedSwapState() (state.State, error) {
-	tokenIn := o.Swap.Exchange.TokenIn
-	tokenOut := o.Swap.Exchange.TokenOut
+	return UpdatedSwapState(o.C.LatestSupportedSwapChannelState(), o.Swap, o.SwapSenderIndex), nil
+}
+
+// UpdatedSwapState returns the state which follows s once the swap has been applied to its outcome.
+func UpdatedSwapState(s state.State, swap payments.Swap, swapSenderIndex uint) state.State {
+	tokenIn := swap.Exchange.TokenIn
+	tokenOut := swap.Exchange.TokenOut
 
-	s := o.C.LatestSupportedSwapChannelState()
 	updateSupportedState := s.Clone()
 	updateOutcome := updateSupportedState.Outcome.Clone()
 
 	for _, assetOutcome := range updateOutcome {
 
-		swapSenderAllocation := assetOutcome.Allocations[o.SwapSenderIndex]
-		swapReceiverAllocation := assetOutcome.Allocations[1-o.SwapSenderIndex]
+		swapSenderAllocation := assetOutcome.Allocations[swapSenderIndex]
+		swapReceiverAllocation := assetOutcome.Allocations[1-swapSenderIndex]
 
 		if assetOutcome.Asset == tokenIn {
 
-			swapSenderAllocation.Amount.Sub(swapSenderAllocation.Amount, o.Swap.Exchange.AmountIn)
-			swapReceiverAllocation.Amount.Add(swapReceiverAllocation.Amount, o.Swap.Exchange.AmountIn)
+			swapSenderAllocation.Amount.Sub(swapSenderAllocation.Amount, swap.Exchange.AmountIn)
+			swapReceiverAllocation.Amount.Add(swapReceiverAllocation.Amount, swap.Exchange.AmountIn)
 		}
 
 		if assetOutcome.Asset == tokenOut {
-			swapSenderAllocation.Amount.Add(swapSenderAllocation.Amount, o.Swap.Exchange.AmountOut)
-			swapReceiverAllocation.Amount.Sub(swapReceiverAllocation.Amount, o.Swap.Exchange.AmountOut)
+			swapSenderAllocation.Amount.Add(swapSenderAllocation.Amount, swap.Exchange.AmountOut)
+			swapReceiverAllocation.Amount.Sub(swapReceiverAllocation.Amount, swap.Exchange.AmountOut)
 		}
 	}
 
 	updateSupportedState.Outcome = updateOutcome
 	updateSupportedState.TurnNum++
-	return updateSupportedState, nil
+	return updateSupportedState
 }
 
 func (o *Objective) Related() []protocols.Storable {
@@ -374,6 +392,7 @@ func (o *Objective) clone() Objective {
 	clone.Status = o.Status
 	clone.SwapSenderIndex = o.SwapSenderIndex
 	clone.SwapStatus = o.SwapStatus
+	clone.CancellationPending = o.CancellationPending
 	clone.C = o.C.Clone()
 	clone.Swap = o.Swap.Clone()
 
@@ -406,6 +425,87 @@ func (o *Objective) AcceptSwap() {
 	o.SwapStatus = types.Accepted
 }
 
+// IsSender returns true if we initiated the swap
+func (o *Objective) IsSender() bool {
+	myIndex, err := MyIndexInAllocations(o.C)
+	return err == nil && myIndex == o.SwapSenderIndex
+}
+
+// Cancel returns a rejected copy of a swap we initiated which the counterparty has not accepted yet, and notifies the counterparty.
+//
+// The counterparty may accept the swap before it learns of the cancellation, in which case it holds both signatures on the updated state.
+// The swap is then reinstated when its acceptance arrives (see Reinstate). Otherwise the counterparty acknowledges the cancellation
+// with a rejection of its own (see AcknowledgeCancellation). The cancellation is pending until either arrives.
+func (o *Objective) Cancel() (protocols.Objective, protocols.SideEffects, error) {
+	if o.Status != protocols.Approved || o.SwapStatus != types.PendingConfirmation || !o.IsSender() {
+		return o, protocols.SideEffects{}, fmt.Errorf("%w: swap %s", ErrNotCancelable, o.Swap.Id)
+	}
+
+	updated := o.clone()
+	updated.CancellationPending = true
+	rejected, sideEffects := updated.Reject()
+	return rejected, sideEffects, nil
+}
+
+// AcknowledgeCancellation returns a copy of a swap we cancelled, once the counterparty has rejected it too.
+// The counterparty can then no longer accept the swap, so the swap channel is free for new swaps.
+func (o *Objective) AcknowledgeCancellation() (protocols.Objective, bool) {
+	if !o.CancellationPending {
+		return o, false
+	}
+
+	updated := o.clone()
+	updated.CancellationPending = false
+	return &updated, true
+}
+
+// Reinstate returns an approved copy of a swap we initiated and cancelled, or which expired, if the payload shows
+// that the counterparty accepted the swap before it learned of the cancellation.
+// Since the counterparty already holds both signatures on the updated state, we must apply the swap too.
+//
+// A swap is never reinstated once the cancellation has been acknowledged, or once the swap channel has moved on
+// from the state the swap was made on, since the signatures on the updated state would not be valid anymore.
+func (o *Objective) Reinstate(raw protocols.ObjectivePayload) (protocols.Objective, bool) {
+	if o.Status != protocols.Rejected || !o.IsSender() || !o.CancellationPending {
+		return o, false
+	}
+
+	swapPayload, err := getSwapPayload(raw.PayloadData)
+	if err != nil || swapPayload.SwapStatus != types.Accepted {
+		return o, false
+	}
+
+	incoming := swapPayload.Swap
+	if incoming.Id != o.Swap.Id || incoming.SwapId() != o.Swap.Id {
+		return o, false
+	}
+	mySig, ok := incoming.Sigs[o.C.MyIndex]
+	if !ok {
+		return o, false
+	}
+	signer, err := incoming.RecoverSigner(mySig)
+	if err != nil || signer != o.C.Participants[o.C.MyIndex] {
+		return o, false
+	}
+
+	// Our signature on the updated state is only valid if no other swap has been applied to the swap channel since we signed it
+	updatedState, err := o.GetUpdatedSwapState()
+	if err != nil {
+		return o, false
+	}
+	stateSigner, err := updatedState.RecoverSigner(o.StateSigs[o.C.MyIndex])
+	if err != nil || stateSigner != o.C.Participants[o.C.MyIndex] {
+		return o, false
+	}
+
+	updated := o.clone()
+	updated.Status = protocols.Approved
+	updated.SwapStatus = types.PendingConfirmation
+	updated.CancellationPending = false
+	updated.Swap = incoming.Clone()
+	return &updated, true
+}
+
 type jsonObjective struct {
 	Status          protocols.ObjectiveStatus
 	C               types.Destination
@@ -414,6 +514,8 @@ type jsonObjective struct {
 	SwapStatus      types.SwapStatus
 	Nonce           uint64
 	StateSigs       map[uint]state.Signature
+
+	CancellationPending bool
 }
 
 func (o Objective) MarshalJSON() ([]byte, error) {
@@ -424,6 +526,8 @@ func (o Objective) MarshalJSON() ([]byte, error) {
 		SwapStatus:      o.SwapStatus,
 		SwapSenderIndex: o.SwapSenderIndex,
 		StateSigs:       o.StateSigs,
+
+		CancellationPending: o.CancellationPending,
 	}
 	return json.Marshal(jsonSO)
 }
@@ -444,6 +548,7 @@ func (o *Objective) UnmarshalJSON(data []byte) error {
 	o.Swap = payments.Swap{}
 	o.Swap.Id = jsonSo.Swap
 	o.StateSigs = jsonSo.StateSigs
+	o.CancellationPending = jsonSo.CancellationPending
 
 	o.C = &channel.SwapChannel{}
 	o.C.Id = jsonSo.C
@@ -497,8 +602,8 @@ type ObjectiveRequest struct {
 }
 
 // NewObjectiveRequest creates a new ObjectiveRequest.
-func NewObjectiveRequest(channelId types.Destination, tokenIn common.Address, tokenOut common.Address, amountIn *big.Int, amountOut *big.Int, fixedPart state.FixedPart, nonce uint64) ObjectiveRequest {
-	swap := payments.NewSwap(channelId, tokenIn, tokenOut, amountIn, amountOut, nonce)
+func NewObjectiveRequest(channelId types.Destination, tokenIn common.Address, tokenOut common.Address, amountIn *big.Int, amountOut *big.Int, fixedPart state.FixedPart, nonce uint64, expiry uint64) ObjectiveRequest {
+	swap := payments.NewSwap(channelId, tokenIn, tokenOut, amountIn, amountOut, nonce, expiry)
 	return ObjectiveRequest{
 		swap:             swap,
 		objectiveStarted: make(chan struct{}),
@@ -524,6 +629,7 @@ func (r ObjectiveRequest) WaitForObjectiveToStart() {
 type ObjectiveResponse struct {
 	Id        protocols.ObjectiveId
 	ChannelId types.Destination
+	SwapId    types.Destination
 }
 
 // Response computes and returns the appropriate response from the request.
@@ -531,6 +637,7 @@ func (r ObjectiveRequest) Response() ObjectiveResponse {
 	return ObjectiveResponse{
 		Id:        protocols.ObjectiveId(ObjectivePrefix + r.swap.Id.String()),
 		ChannelId: r.swap.ChannelId,
+		SwapId:    r.swap.Id,
 	}
 }
 
@@ -546,6 +653,11 @@ func (o *Objective) counterPartyIndexInParticipants() int {
 	return -1
 }
 
+// CounterParty returns the address of the other party to the swap
+func (o *Objective) CounterParty() common.Address {
+	return o.counterPartyAddress()
+}
+
 func (o *Objective) counterPartyAddress() common.Address {
 	counterPartyIndex := o.counterPartyIndexInParticipants()
 
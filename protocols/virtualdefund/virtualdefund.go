@@ -407,7 +407,10 @@ func (o *Objective) isBob() bool {
 	return int(o.MyRole) == len(o.V.Participants)-1
 }
 
-// ledgerProposal generates a ledger proposal to remove the guarantee for V for ledger
+// ledgerProposal generates a ledger proposal to remove the guarantee for V for ledger.
+//
+// The left participant of the ledger receives Alice's final balance, and the right participant the rest of the guarantee,
+// as they would if the guarantee were reclaimed on chain. The intermediary fees were paid when the guarantee was added.
 func (o *Objective) ledgerProposal(ledger *consensus_channel.ConsensusChannel) consensus_channel.Proposal {
 	left := o.finalState().Outcome[0].Allocations[0].Amount
 
@@ -420,14 +423,15 @@ func (o *Objective) ledgerProposal(ledger *consensus_channel.ConsensusChannel) c
 func (o *Objective) updateLedgerToRemoveGuarantee(ledger *consensus_channel.ConsensusChannel, sk *[]byte) (protocols.SideEffects, error) {
 	var sideEffects protocols.SideEffects
 
-	proposed := ledger.HasRemovalBeenProposed(o.VId(), o.ledgerProposal(ledger).ToRemove.AssetAddress)
+	proposal := o.ledgerProposal(ledger)
+	proposed := ledger.HasRemovalBeenProposed(o.VId(), proposal.ToRemove.AssetAddress)
 
 	if ledger.IsLeader() {
 		if proposed { // If we've already proposed a remove proposal we can return
 			return protocols.SideEffects{}, nil
 		}
 
-		_, err := ledger.Propose(o.ledgerProposal(ledger), *sk)
+		_, err := ledger.Propose(proposal, *sk)
 		if err != nil {
 			return protocols.SideEffects{}, fmt.Errorf("error proposing ledger update: %w", err)
 		}
@@ -441,9 +445,9 @@ func (o *Objective) updateLedgerToRemoveGuarantee(ledger *consensus_channel.Cons
 
 	} else {
 		// If the proposal is next in the queue we accept it
-		proposedNext := ledger.HasRemovalBeenProposedNext(o.VId(), o.ledgerProposal(ledger).ToRemove.AssetAddress)
+		proposedNext := ledger.HasRemovalBeenProposedNext(o.VId(), proposal.ToRemove.AssetAddress)
 		if proposedNext {
-			sp, err := ledger.SignNextProposal(o.ledgerProposal(ledger), *sk)
+			sp, err := ledger.SignNextProposal(proposal, *sk)
 			if err != nil {
 				return protocols.SideEffects{}, fmt.Errorf("could not sign proposal: %w", err)
 			}
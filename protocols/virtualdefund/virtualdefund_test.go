@@ -233,3 +233,145 @@ func TestApproveReject(t *testing.T) {
 		t.Errorf("Expected to send 2 messages")
 	}
 }
+
+func TestLedgerProposalWithIntermediaryFees(t *testing.T) {
+	data := generateTestData()
+	vId := data.vFinal.ChannelId()
+	fee := big.NewInt(2)
+
+	appData, err := channel.EncodeIntermediaryFees([]channel.IntermediaryFee{{Intermediary: irene.Address(), Amount: fee}})
+	testhelpers.Ok(t, err)
+	vInitial := data.vInitial.Clone()
+	vInitial.AppData = appData
+
+	getChannel, getConsensusChannel := generateStoreGetters(irene.Role, vId, vInitial)
+	o, err := NewObjective(NewObjectiveRequest(vId), false, irene.Address(), nil, getChannel, getConsensusChannel)
+	testhelpers.Ok(t, err)
+	o.V.OffChain.SignedStateForTurnNum[FinalTurnNum] = state.NewSignedState(data.vFinal)
+
+	// Alice paid Irene's fee when the guarantee was added, so the guarantees are removed as they would be reclaimed on chain:
+	// the left participant of each ledger receives Alice's final balance
+	want := big.NewInt(int64(data.finalAliceAmount))
+	for _, ledger := range []*consensus_channel.ConsensusChannel{o.ToMyLeft, o.ToMyRight} {
+		proposal := o.ledgerProposal(ledger)
+		if proposal.ToRemove.LeftAmount.Cmp(want) != 0 {
+			t.Errorf("ledger %s: expected the left participant to receive %s, got %s", ledger.Id, want, proposal.ToRemove.LeftAmount)
+		}
+	}
+}
+
+func TestMultiHopUnwind(t *testing.T) {
+	paths := [][]ta.Actor{
+		{alice, irene, ta.Ivan, bob},
+		{alice, irene, ta.Ivan, ta.Brian, bob},
+	}
+	for _, path := range paths {
+		t.Run(fmt.Sprintf("unwinding over %d intermediaries", len(path)-2), testMultiHopUnwind(path))
+	}
+}
+
+// testMultiHopUnwind defunds a virtual channel funded over the given path by cranking every participant's objective
+// and delivering their ledger proposals, and checks that the guarantee is removed from every ledger
+func testMultiHopUnwind(path []ta.Actor) func(t *testing.T) {
+	return func(t *testing.T) {
+		data := generateTestData()
+		participants := []types.Address{}
+		for _, a := range path {
+			participants = append(participants, a.Address())
+		}
+		vFinal := data.vFinal.Clone()
+		vFinal.Participants = participants
+		vId := vFinal.ChannelId()
+
+		finalState := state.NewSignedState(vFinal)
+		for _, a := range path {
+			testhelpers.SignState(&finalState, &a.PrivateKey)
+		}
+
+		// ledgers[i] holds the copies of the ledger between path[i] and path[i+1] of its left (leader) and right (follower) participants
+		ledgers := make([][2]*consensus_channel.ConsensusChannel, len(path)-1)
+		for i := range ledgers {
+			guarantee := generateGuarantee(path[i], path[i+1], vId)
+			ledgers[i] = [2]*consensus_channel.ConsensusChannel{
+				prepareConsensusChannel(uint(consensus_channel.Leader), path[i], path[i+1], guarantee),
+				prepareConsensusChannel(uint(consensus_channel.Follower), path[i], path[i+1], guarantee),
+			}
+		}
+
+		objectives := make(map[types.Address]*Objective)
+		for i, my := range path {
+			getChannel := func(types.Destination) (*channel.Channel, bool) {
+				c, err := channel.New(vFinal, uint(i), types.Virtual)
+				return c, err == nil
+			}
+			getConsensusChannel := func(counterparty types.Address) (*consensus_channel.ConsensusChannel, bool) {
+				if i > 0 && counterparty == path[i-1].Address() {
+					return ledgers[i-1][1], true
+				}
+				if i < len(path)-1 && counterparty == path[i+1].Address() {
+					return ledgers[i][0], true
+				}
+				return nil, false
+			}
+
+			o, err := NewObjective(NewObjectiveRequest(vId), true, my.Address(), big.NewInt(int64(data.paid)), getChannel, getConsensusChannel)
+			testhelpers.Ok(t, err)
+			if !o.V.AddSignedState(finalState.Clone()) {
+				t.Fatalf("could not add the final state for %s", my.Name)
+			}
+			objectives[my.Address()] = &o
+		}
+
+		completed := func() bool {
+			for _, o := range objectives {
+				if o.Status != protocols.Completed {
+					return false
+				}
+			}
+			return true
+		}
+		for round := 0; !completed(); round++ {
+			if round == len(path) {
+				t.Fatalf("expected the guarantees to be unwound within %d rounds", len(path))
+			}
+
+			messages := []protocols.Message{}
+			for _, my := range path {
+				if objectives[my.Address()].Status == protocols.Completed {
+					continue
+				}
+				updated, se, _, err := objectives[my.Address()].Crank(&my.PrivateKey)
+				testhelpers.Ok(t, err)
+				objectives[my.Address()] = updated.(*Objective)
+				messages = append(messages, se.MessagesToSend...)
+			}
+
+			for _, message := range messages {
+				for _, sp := range message.LedgerProposals {
+					updated, err := objectives[message.To].ReceiveProposal(sp)
+					testhelpers.Ok(t, err)
+					objectives[message.To] = updated.(*Objective)
+				}
+			}
+		}
+
+		// Every ledger has unwound the guarantee as it would be reclaimed on chain: the left participant receives Alice's final balance,
+		// and the right participant the rest of the guarantee
+		for i := range ledgers {
+			left, right := objectives[path[i].Address()], objectives[path[i+1].Address()]
+			for _, ledger := range []*consensus_channel.ConsensusChannel{left.ToMyRight, right.ToMyLeft} {
+				if ledger.IncludesTarget(vId) {
+					t.Fatalf("expected the guarantee to be removed from the ledger between %s and %s", path[i].Name, path[i+1].Name)
+				}
+				vars := ledger.ConsensusVars()
+				lo := vars.Outcome[0]
+				if want := consensus_channel.NewBalance(path[i].Destination(), big.NewInt(int64(data.finalAliceAmount))); !lo.Leader().Equal(want) {
+					t.Fatalf("expected %s to receive %d in the ledger with %s, got %+v", path[i].Name, data.finalAliceAmount, path[i+1].Name, lo.Leader())
+				}
+				if want := consensus_channel.NewBalance(path[i+1].Destination(), big.NewInt(int64(data.finalBobAmount))); !lo.Follower().Equal(want) {
+					t.Fatalf("expected %s to receive %d in the ledger with %s, got %+v", path[i+1].Name, data.finalBobAmount, path[i].Name, lo.Follower())
+				}
+			}
+		}
+	}
+}
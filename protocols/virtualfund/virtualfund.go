@@ -41,6 +41,8 @@ type GuaranteeInfo struct {
 	LeftAmount           types.Funds
 	RightAmount          types.Funds
 	GuaranteeDestination types.Destination
+	// Fee is the total fee of the intermediaries to the right of the ledger channel, which Left pays Right when the guarantee is added
+	Fee *big.Int `json:",omitempty"`
 }
 type Connection struct {
 	Channel       *consensus_channel.ConsensusChannel
@@ -48,7 +50,7 @@ type Connection struct {
 }
 
 // insertGuaranteeInfo mutates the receiver Connection struct.
-func (c *Connection) insertGuaranteeInfo(a0 types.Funds, b0 types.Funds, vId types.Destination, left types.Destination, right types.Destination) error {
+func (c *Connection) insertGuaranteeInfo(a0 types.Funds, b0 types.Funds, vId types.Destination, left types.Destination, right types.Destination, fee *big.Int) error {
 	guaranteeInfo := GuaranteeInfo{
 		Left:                 left,
 		Right:                right,
@@ -56,6 +58,9 @@ func (c *Connection) insertGuaranteeInfo(a0 types.Funds, b0 types.Funds, vId typ
 		RightAmount:          b0,
 		GuaranteeDestination: vId,
 	}
+	if fee.Sign() > 0 {
+		guaranteeInfo.Fee = fee
+	}
 
 	// Check that the guarantee metadata can be encoded. This allows us to avoid clunky error-return-chains for getExpectedGuarantees
 	metadata := outcome.GuaranteeMetadata{
@@ -181,6 +186,11 @@ func NewObjective(request ObjectiveRequest, preApprove bool, myAddress types.Add
 
 	var leftCC *consensus_channel.ConsensusChannel
 
+	appData, err := channel.EncodeIntermediaryFees(request.IntermediaryFees)
+	if err != nil {
+		return Objective{}, fmt.Errorf("error encoding intermediary fees: %w", err)
+	}
+
 	participants := []types.Address{myAddress}
 	participants = append(participants, request.Intermediaries...)
 	participants = append(participants, request.CounterParty)
@@ -193,6 +203,7 @@ func NewObjective(request ObjectiveRequest, preApprove bool, myAddress types.Add
 			Outcome:           request.Outcome,
 			TurnNum:           0,
 			AppDefinition:     request.AppDefinition,
+			AppData:           appData,
 			IsFinal:           false,
 		},
 		myAddress,
@@ -240,6 +251,14 @@ func constructFromState(
 
 	init.V = v
 
+	fees, err := v.IntermediaryFees()
+	if err != nil {
+		return Objective{}, err
+	}
+	if err := v.ValidateIntermediaryFees(fees); err != nil {
+		return Objective{}, fmt.Errorf("invalid intermediary fees: %w", err)
+	}
+
 	init.n = uint(len(initialStateOfV.Participants)) - 2 // NewSingleHopVirtualChannel will error unless there are at least 3 participants
 
 	init.a0 = make(map[types.Address]*big.Int)
@@ -281,6 +300,7 @@ func constructFromState(
 			init.V.Id,
 			types.AddressToDestination(init.V.Participants[init.MyRole-1]),
 			types.AddressToDestination(init.V.Participants[init.MyRole]),
+			channel.FeesToTheRightOf(init.V.Participants, fees, init.MyRole-1),
 		)
 		if err != nil {
 			return Objective{}, err
@@ -301,6 +321,7 @@ func constructFromState(
 			init.V.Id,
 			types.AddressToDestination(init.V.Participants[init.MyRole]),
 			types.AddressToDestination(init.V.Participants[init.MyRole+1]),
+			channel.FeesToTheRightOf(init.V.Participants, fees, init.MyRole),
 		)
 		if err != nil {
 			return Objective{}, err
@@ -467,7 +488,11 @@ func (o *Objective) Crank(secretKey *[]byte) (protocols.Objective, protocols.Sid
 		sideEffects.Merge(ledgerSideEffects)
 	}
 
-	if !updated.isBob() && !updated.ToMyRight.IsFundingTheTarget() {
+	// An intermediary which pays fees in the ledger channel to its right only adds the guarantee there once it has been
+	// paid its own fees in the ledger channel to its left
+	paysFeesBeforeBeingPaid := !updated.isAlice() && !updated.isBob() && updated.ToMyRight.paysFee() && !updated.ToMyLeft.IsFundingTheTarget()
+
+	if !updated.isBob() && !updated.ToMyRight.IsFundingTheTarget() && !paysFeesBeforeBeingPaid {
 		ledgerSideEffects, err := updated.updateLedgerWithGuarantee(*updated.ToMyRight, secretKey)
 		if err != nil {
 			return o, protocols.SideEffects{}, WaitingForNothing, fmt.Errorf("%w: %w", ErrUpdatingLedgerFunding, err)
@@ -674,10 +699,16 @@ func (c *Connection) expectedProposal() consensus_channel.Proposal {
 	// Use first asset from outcome array for creating virtual channel
 	consensusState := c.Channel.SupportedSignedState().State()
 	proposal := consensus_channel.NewAddProposal(c.Channel.Id, g, leftAmount, consensusState.Outcome[0].Asset)
+	proposal.ToAdd.Fee = c.GuaranteeInfo.Fee
 
 	return proposal
 }
 
+// paysFee returns true if the left participant of the ledger channel pays a fee when the guarantee is added.
+func (c *Connection) paysFee() bool {
+	return c.GuaranteeInfo.Fee != nil && c.GuaranteeInfo.Fee.Sign() > 0
+}
+
 // proposeLedgerUpdate will propose a ledger update to the channel by crafting a new state
 func (o *Objective) proposeLedgerUpdate(connection Connection, sk *[]byte) (protocols.SideEffects, error) {
 	ledger := connection.Channel
@@ -779,7 +810,10 @@ type ObjectiveRequest struct {
 	Outcome           outcome.Exit
 	Nonce             uint64
 	AppDefinition     types.Address
-	objectiveStarted  chan struct{}
+	// IntermediaryFees are the fees Alice agrees to pay the intermediaries, which are paid in the ledger channels as the
+	// guarantees for the channel are added (see channel.IntermediaryFee).
+	IntermediaryFees []channel.IntermediaryFee `json:",omitempty"`
+	objectiveStarted chan struct{}
 }
 
 // NewObjectiveRequest creates a new ObjectiveRequest.
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/consensus_channel"
 	"github.com/statechannels/go-nitro/channel/state"
 	"github.com/statechannels/go-nitro/channel/state/outcome"
 	"github.com/statechannels/go-nitro/internal/testactors"
@@ -148,3 +149,101 @@ func TestApproveReject(t *testing.T) {
 		t.Errorf("Expected to send two messages")
 	}
 }
+
+func TestNewMultiHop(t *testing.T) {
+	path := []testactors.Actor{testactors.Alice, testactors.Irene, testactors.Ivan, testactors.Bob}
+
+	vPreFund := newTestData().vPreFund
+	vPreFund.Participants = []types.Address{}
+	for _, a := range path {
+		vPreFund.Participants = append(vPreFund.Participants, a.Address())
+	}
+	vId := vPreFund.ChannelId()
+	amount := vPreFund.Outcome[0].TotalAllocated()
+
+	// ledger returns the ledger channel between the given adjacent participants, or nil if there is none
+	ledger := func(left, right int) *consensus_channel.ConsensusChannel {
+		if left < 0 || right >= len(path) {
+			return nil
+		}
+		return prepareConsensusChannel(uint(consensus_channel.Leader), path[left], path[right], path[left])
+	}
+
+	for i, a := range path {
+		t.Run(fmt.Sprintf("Testing new as %v", a.Name), func(t *testing.T) {
+			o, err := constructFromState(false, vPreFund, a.Address(), ledger(i-1, i), ledger(i, i+1))
+			testhelpers.Ok(t, err)
+
+			if o.n != 2 || o.MyRole != uint(i) {
+				t.Fatalf("expected 2 intermediaries and role %d, got %d and %d", i, o.n, o.MyRole)
+			}
+
+			// Every ledger along the path guarantees V's total to its participants
+			for _, c := range []struct {
+				connection  *Connection
+				left, right int
+			}{{o.ToMyLeft, i - 1, i}, {o.ToMyRight, i, i + 1}} {
+				if c.left < 0 || c.right >= len(path) {
+					testhelpers.Assert(t, c.connection == nil, "expected no connection")
+					continue
+				}
+				want := consensus_channel.NewGuarantee(amount, vId, path[c.left].Destination(), path[c.right].Destination())
+				got, _ := c.connection.getExpectedGuaranteeAndAsset()
+				if diff := compareGuarantees(want, got); diff != "" {
+					t.Errorf("guarantee mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestMultiHopIntermediaryFees(t *testing.T) {
+	path := []testactors.Actor{testactors.Alice, testactors.Irene, testactors.Ivan, testactors.Bob}
+
+	vPreFund := newTestData().vPreFund
+	vPreFund.Participants = []types.Address{}
+	for _, a := range path {
+		vPreFund.Participants = append(vPreFund.Participants, a.Address())
+	}
+	appData, err := channel.EncodeIntermediaryFees([]channel.IntermediaryFee{
+		{Intermediary: testactors.Irene.Address(), Amount: big.NewInt(2)},
+		{Intermediary: testactors.Ivan.Address(), Amount: big.NewInt(1)},
+	})
+	testhelpers.Ok(t, err)
+	vPreFund.AppData = appData
+
+	ledger := func(left, right int) *consensus_channel.ConsensusChannel {
+		if left < 0 || right >= len(path) {
+			return nil
+		}
+		return prepareConsensusChannel(uint(consensus_channel.Leader), path[left], path[right], path[left])
+	}
+
+	// The ledger between Alice and Irene pays both fees, the ledger between Irene and Ivan pays Ivan's, and the ledger between Ivan and Bob pays none
+	wantFees := []int64{3, 1, 0}
+	for i, a := range path {
+		t.Run(fmt.Sprintf("Testing fees as %v", a.Name), func(t *testing.T) {
+			o, err := constructFromState(false, vPreFund, a.Address(), ledger(i-1, i), ledger(i, i+1))
+			testhelpers.Ok(t, err)
+
+			for _, c := range []struct {
+				connection *Connection
+				left       int
+			}{{o.ToMyLeft, i - 1}, {o.ToMyRight, i}} {
+				if c.connection == nil {
+					continue
+				}
+				fee := c.connection.expectedProposal().ToAdd.Fee
+				if fee == nil {
+					fee = big.NewInt(0)
+				}
+				if fee.Int64() != wantFees[c.left] {
+					t.Errorf("ledger %d: expected the guarantee to be added with a fee of %d, got %s", c.left, wantFees[c.left], fee)
+				}
+				if c.connection.paysFee() != (wantFees[c.left] > 0) {
+					t.Errorf("ledger %d: expected paysFee to be %t", c.left, wantFees[c.left] > 0)
+				}
+			}
+		})
+	}
+}
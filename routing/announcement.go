@@ -0,0 +1,110 @@
+// Package routing finds paths through the network of ledger channels, along which virtual channels can be funded.
+package routing // import "github.com/statechannels/go-nitro/routing"
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	nitroAbi "github.com/statechannels/go-nitro/abi"
+	"github.com/statechannels/go-nitro/channel/state"
+	nitroCrypto "github.com/statechannels/go-nitro/crypto"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// A CapacityAnnouncement is a statement, signed by From, of how much it can lock in guarantees for virtual channels
+// in its ledger channel with To, and of the fee it charges to intermediate virtual channels through that ledger channel.
+//
+// Announcements are sent to the peers we have ledger channels with, who relay the announcements which are new to them to theirs.
+type CapacityAnnouncement struct {
+	From  types.Address
+	To    types.Address
+	Asset common.Address
+	// Capacity is the balance of From in the ledger channel, which is not yet locked in guarantees
+	Capacity *big.Int
+	// FlatFee and FeeBasisPoints make up the fee From charges as an intermediary: a flat amount plus basis points of the virtual channel's total
+	FlatFee        *big.Int
+	FeeBasisPoints uint64
+	// Timestamp is when the announcement was made, in unix milliseconds. Newer announcements replace older ones.
+	Timestamp uint64
+	Signature state.Signature
+}
+
+// Fee returns the fee From charges to intermediate a virtual channel with the given total
+func (a *CapacityAnnouncement) Fee(total *big.Int) *big.Int {
+	fee := big.NewInt(0).Mul(total, new(big.Int).SetUint64(a.FeeBasisPoints))
+	fee.Div(fee, big.NewInt(10_000))
+	if a.FlatFee != nil {
+		fee.Add(fee, a.FlatFee)
+	}
+
+	return fee
+}
+
+// ErrInvalidAnnouncement is returned for announcements which are malformed, and can be neither hashed nor verified
+var ErrInvalidAnnouncement = errors.New("invalid capacity announcement")
+
+// maxUint256 is the largest amount which can be encoded in an announcement
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// validate returns an error if the amounts of the announcement cannot be encoded
+func (a *CapacityAnnouncement) validate() error {
+	if a.Capacity == nil || a.Capacity.Sign() < 0 || a.Capacity.Cmp(maxUint256) > 0 {
+		return fmt.Errorf("%w: capacity must be a uint256", ErrInvalidAnnouncement)
+	}
+	if a.FlatFee == nil || a.FlatFee.Sign() < 0 || a.FlatFee.Cmp(maxUint256) > 0 {
+		return fmt.Errorf("%w: flat fee must be a uint256", ErrInvalidAnnouncement)
+	}
+
+	return nil
+}
+
+func (a *CapacityAnnouncement) Hash() (types.Bytes32, error) {
+	if err := a.validate(); err != nil {
+		return types.Bytes32{}, err
+	}
+
+	encoded, err := abi.Arguments{
+		{Type: nitroAbi.Address},
+		{Type: nitroAbi.Address},
+		{Type: nitroAbi.Address},
+		{Type: nitroAbi.Uint256},
+		{Type: nitroAbi.Uint256},
+		{Type: nitroAbi.Uint256},
+		{Type: nitroAbi.Uint256},
+	}.Pack(a.From, a.To, a.Asset, a.Capacity, a.FlatFee, new(big.Int).SetUint64(a.FeeBasisPoints), new(big.Int).SetUint64(a.Timestamp))
+	if err != nil {
+		return types.Bytes32{}, fmt.Errorf("failed to encode capacity announcement: %w", err)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+func (a *CapacityAnnouncement) Sign(pk []byte) error {
+	hash, err := a.Hash()
+	if err != nil {
+		return err
+	}
+
+	sig, err := nitroCrypto.SignEthereumMessage(hash.Bytes(), pk)
+	if err != nil {
+		return err
+	}
+
+	a.Signature = sig
+
+	return nil
+}
+
+func (a *CapacityAnnouncement) RecoverSigner() (types.Address, error) {
+	if len(a.Signature.R) != 32 || len(a.Signature.S) != 32 || (a.Signature.V != 27 && a.Signature.V != 28 && a.Signature.V > 1) {
+		return types.Address{}, fmt.Errorf("%w: malformed signature", ErrInvalidAnnouncement)
+	}
+	h, err := a.Hash()
+	if err != nil {
+		return types.Address{}, err
+	}
+	return nitroCrypto.RecoverEthereumMessageSigner(h[:], a.Signature)
+}
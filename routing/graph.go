@@ -0,0 +1,173 @@
+package routing
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// ANNOUNCEMENT_TTL is how long after it was made an announcement is used to find routes
+const ANNOUNCEMENT_TTL = 10 * time.Minute
+
+// MAX_ANNOUNCEMENT_CLOCK_SKEW is how far in the future an announcement may be timestamped. Later announcements are ignored,
+// since they would otherwise keep replacing the announcements made after them.
+const MAX_ANNOUNCEMENT_CLOCK_SKEW = time.Minute
+
+// ErrNoRoute is returned when no path of ledger channels has enough capacity to fund a virtual channel
+var ErrNoRoute = errors.New("no route with enough capacity")
+
+type edgeKey struct {
+	from, to types.Address
+	asset    common.Address
+}
+
+// Graph is a directed graph of the ledger channels in the network. Each edge is the latest capacity announcement
+// made by one participant of a ledger channel, for one asset.
+type Graph struct {
+	mu    sync.RWMutex
+	edges map[edgeKey]CapacityAnnouncement
+}
+
+// Route is a path of ledger channels from a payer to a payee
+type Route struct {
+	Intermediaries []types.Address
+	// Fees are the fees charged by the intermediaries, as announced by them. Intermediaries which charge no fee are omitted.
+	Fees map[types.Address]*big.Int
+}
+
+// NewGraph returns an empty Graph
+func NewGraph() *Graph {
+	return &Graph{edges: make(map[edgeKey]CapacityAnnouncement)}
+}
+
+// Update adds the announcement to the graph, replacing any older announcement of the same ledger channel and asset.
+// It returns false if the announcement has expired, is timestamped too far in the future, or is no newer than the one already in the graph.
+//
+// Update does not check the signature on the announcement.
+func (g *Graph) Update(a CapacityAnnouncement) bool {
+	now := time.Now()
+	if expired(a, now) || a.Timestamp > uint64(now.Add(MAX_ANNOUNCEMENT_CLOCK_SKEW).UnixMilli()) {
+		return false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := edgeKey{a.From, a.To, a.Asset}
+	if existing, ok := g.edges[key]; ok && existing.Timestamp >= a.Timestamp {
+		return false
+	}
+	g.edges[key] = a
+
+	return true
+}
+
+// Announcements returns the announcements in the graph which have not expired
+func (g *Graph) Announcements() []CapacityAnnouncement {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	announcements := make([]CapacityAnnouncement, 0, len(g.edges))
+	for key, a := range g.edges {
+		if expired(a, now) {
+			delete(g.edges, key)
+			continue
+		}
+		announcements = append(announcements, a)
+	}
+
+	return announcements
+}
+
+// FindRoute returns the cheapest route from the payer to the payee, along which every ledger channel can lock the amount
+// of the asset for a virtual channel. Routes are compared by the total fee charged by their intermediaries, and then by their length.
+//
+// Routes have at least one intermediary, since a virtual channel cannot be funded by a single ledger channel.
+func (g *Graph) FindRoute(from, to types.Address, asset common.Address, amount *big.Int) (Route, error) {
+	if from == to {
+		return Route{}, ErrNoRoute
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	now := time.Now()
+	outgoing := make(map[types.Address][]CapacityAnnouncement)
+	for _, a := range g.edges {
+		direct := a.From == from && a.To == to
+		if !direct && a.Asset == asset && a.Capacity.Cmp(amount) >= 0 && !expired(a, now) {
+			outgoing[a.From] = append(outgoing[a.From], a)
+		}
+	}
+
+	// Dijkstra's algorithm, where the weight of an edge is the fee charged by its announcer (unless it is the payer)
+	costs := map[types.Address]cost{from: {fee: big.NewInt(0)}}
+	previous := make(map[types.Address]CapacityAnnouncement)
+	visited := make(map[types.Address]bool)
+	for {
+		var next types.Address
+		found := false
+		for node, c := range costs {
+			if !visited[node] && (!found || c.less(costs[next])) {
+				next, found = node, true
+			}
+		}
+		if !found {
+			return Route{}, ErrNoRoute
+		}
+		if next == to {
+			break
+		}
+		visited[next] = true
+
+		for _, a := range outgoing[next] {
+			fee := big.NewInt(0)
+			if next != from {
+				fee = a.Fee(amount)
+			}
+			c := cost{fee: fee.Add(fee, costs[next].fee), hops: costs[next].hops + 1}
+			if existing, ok := costs[a.To]; !ok || c.less(existing) {
+				costs[a.To] = c
+				previous[a.To] = a
+			}
+		}
+	}
+
+	// Walk back from the payee, collecting the ledger channels along the route
+	path := []CapacityAnnouncement{}
+	for node := to; node != from; node = previous[node].From {
+		path = append([]CapacityAnnouncement{previous[node]}, path...)
+	}
+
+	route := Route{Fees: make(map[types.Address]*big.Int)}
+	for _, a := range path[1:] {
+		route.Intermediaries = append(route.Intermediaries, a.From)
+		if fee := a.Fee(amount); fee.Sign() > 0 {
+			route.Fees[a.From] = fee
+		}
+	}
+
+	return route, nil
+}
+
+// cost is the cost of a path: the total fee of its intermediaries and its number of hops
+type cost struct {
+	fee  *big.Int
+	hops int
+}
+
+func (c cost) less(other cost) bool {
+	if cmp := c.fee.Cmp(other.fee); cmp != 0 {
+		return cmp < 0
+	}
+	return c.hops < other.hops
+}
+
+func expired(a CapacityAnnouncement, now time.Time) bool {
+	return now.Sub(time.UnixMilli(int64(a.Timestamp))) > ANNOUNCEMENT_TTL
+}
@@ -0,0 +1,191 @@
+package routing
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/go-cmp/cmp"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/types"
+)
+
+var (
+	alice = testactors.Alice.Address()
+	bob   = testactors.Bob.Address()
+	irene = testactors.Irene.Address()
+	ivan  = testactors.Ivan.Address()
+	brian = testactors.Brian.Address()
+)
+
+func announcement(from, to types.Address, capacity int64, flatFee int64) CapacityAnnouncement {
+	return CapacityAnnouncement{
+		From:      from,
+		To:        to,
+		Capacity:  big.NewInt(capacity),
+		FlatFee:   big.NewInt(flatFee),
+		Timestamp: uint64(time.Now().UnixMilli()),
+	}
+}
+
+func TestFindRoute(t *testing.T) {
+	amount := big.NewInt(100)
+
+	testCases := []struct {
+		name          string
+		announcements []CapacityAnnouncement
+		want          Route
+		wantErr       error
+	}{
+		{
+			name: "single intermediary",
+			announcements: []CapacityAnnouncement{
+				announcement(alice, irene, 100, 0), announcement(irene, bob, 100, 3),
+			},
+			want: Route{Intermediaries: []types.Address{irene}, Fees: map[types.Address]*big.Int{irene: big.NewInt(3)}},
+		},
+		{
+			name: "cheapest route is longer",
+			announcements: []CapacityAnnouncement{
+				announcement(alice, irene, 100, 0), announcement(irene, bob, 100, 10),
+				announcement(alice, ivan, 100, 0), announcement(ivan, brian, 100, 2), announcement(brian, bob, 100, 3),
+			},
+			want: Route{Intermediaries: []types.Address{ivan, brian}, Fees: map[types.Address]*big.Int{ivan: big.NewInt(2), brian: big.NewInt(3)}},
+		},
+		{
+			name: "shortest route when fees are equal",
+			announcements: []CapacityAnnouncement{
+				announcement(alice, irene, 100, 0), announcement(irene, bob, 100, 0),
+				announcement(alice, ivan, 100, 0), announcement(ivan, brian, 100, 0), announcement(brian, bob, 100, 0),
+			},
+			want: Route{Intermediaries: []types.Address{irene}, Fees: map[types.Address]*big.Int{}},
+		},
+		{
+			name: "ledger channels without enough capacity are avoided",
+			announcements: []CapacityAnnouncement{
+				announcement(alice, irene, 100, 0), announcement(irene, bob, 99, 0),
+				announcement(alice, ivan, 100, 0), announcement(ivan, bob, 100, 5),
+			},
+			want: Route{Intermediaries: []types.Address{ivan}, Fees: map[types.Address]*big.Int{ivan: big.NewInt(5)}},
+		},
+		{
+			name: "capacity is directional",
+			announcements: []CapacityAnnouncement{
+				announcement(alice, irene, 100, 0), announcement(bob, irene, 100, 0),
+			},
+			wantErr: ErrNoRoute,
+		},
+		{
+			name: "a direct ledger channel is not a route",
+			announcements: []CapacityAnnouncement{
+				announcement(alice, bob, 100, 0),
+			},
+			wantErr: ErrNoRoute,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGraph()
+			for _, a := range tc.announcements {
+				g.Update(a)
+			}
+
+			got, err := g.FindRoute(alice, bob, common.Address{}, amount)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(big.Int{})); diff != "" {
+				t.Fatalf("route mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	g := NewGraph()
+
+	older := announcement(alice, irene, 100, 0)
+	newer := announcement(alice, irene, 50, 0)
+	newer.Timestamp = older.Timestamp + 1
+
+	if !g.Update(newer) {
+		t.Fatal("expected the announcement to be added")
+	}
+	if g.Update(older) {
+		t.Fatal("expected an older announcement not to replace a newer one")
+	}
+
+	expired := announcement(irene, bob, 100, 0)
+	expired.Timestamp = uint64(time.Now().Add(-ANNOUNCEMENT_TTL - time.Minute).UnixMilli())
+	if g.Update(expired) {
+		t.Fatal("expected an expired announcement to be ignored")
+	}
+
+	future := announcement(irene, bob, 100, 0)
+	future.Timestamp = uint64(time.Now().Add(MAX_ANNOUNCEMENT_CLOCK_SKEW + time.Minute).UnixMilli())
+	if g.Update(future) {
+		t.Fatal("expected an announcement timestamped too far in the future to be ignored")
+	}
+
+	if diff := cmp.Diff([]CapacityAnnouncement{newer}, g.Announcements(), cmp.AllowUnexported(big.Int{})); diff != "" {
+		t.Fatalf("announcements mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSignAnnouncement(t *testing.T) {
+	a := announcement(alice, irene, 100, 1)
+	if err := a.Sign(testactors.Alice.PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := a.RecoverSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer != alice {
+		t.Fatalf("expected the announcement to be signed by %s, got %s", alice, signer)
+	}
+
+	a.Capacity = big.NewInt(1000)
+	if signer, _ := a.RecoverSigner(); signer == alice {
+		t.Fatal("expected a modified announcement not to recover its original signer")
+	}
+}
+
+func TestInvalidAnnouncement(t *testing.T) {
+	signed := announcement(alice, irene, 100, 1)
+	if err := signed.Sign(testactors.Alice.PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	noCapacity := signed
+	noCapacity.Capacity = nil
+	noFee := signed
+	noFee.FlatFee = nil
+	negativeCapacity := signed
+	negativeCapacity.Capacity = big.NewInt(-1)
+	shortSignature := signed
+	shortSignature.Signature.R = shortSignature.Signature.R[:31]
+	noSignature := signed
+	noSignature.Signature = state.Signature{}
+
+	for name, a := range map[string]CapacityAnnouncement{
+		"nil capacity":      noCapacity,
+		"nil flat fee":      noFee,
+		"negative capacity": negativeCapacity,
+		"short signature":   shortSignature,
+		"no signature":      noSignature,
+	} {
+		if _, err := a.RecoverSigner(); !errors.Is(err, ErrInvalidAnnouncement) {
+			t.Errorf("%s: expected %v, got %v", name, ErrInvalidAnnouncement, err)
+		}
+	}
+
+	if err := noCapacity.Sign(testactors.Alice.PrivateKey); !errors.Is(err, ErrInvalidAnnouncement) {
+		t.Errorf("expected %v signing an announcement without a capacity, got %v", ErrInvalidAnnouncement, err)
+	}
+}
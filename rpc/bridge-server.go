@@ -90,8 +90,8 @@ func (brs *BridgeRpcServer) registerHandlers() (err error) {
 					}
 				}
 
-				brs.bridge.CounterChallenge(req.ChannelId, req.Action, l2SignedState)
-				return req, nil
+				err := brs.bridge.CounterChallenge(req.ChannelId, req.Action, l2SignedState)
+				return req, err
 			})
 		case serde.GetSignedStateMethod:
 			return processRequest(brs.BaseRpcServer, permRead, requestData, func(req serde.GetSignedStateRequest) (string, error) {
@@ -165,7 +165,7 @@ func (brs *BridgeRpcServer) registerHandlers() (err error) {
 			})
 		case serde.GetPendingBridgeTxsMethod:
 			return processRequest(brs.BaseRpcServer, permSign, requestData, func(req serde.GetPendingBridgeTxsRequest) (string, error) {
-				pendingBridgeTxs := brs.bridge.GetPendingBridgeTxs(req.ChannelId)
+				pendingBridgeTxs, err := brs.bridge.GetPendingBridgeTxs(req.ChannelId)
 				if err != nil {
 					return "", err
 				}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/statechannels/go-nitro/channel/state"
 	"github.com/statechannels/go-nitro/channel/state/outcome"
 	"github.com/statechannels/go-nitro/internal/logging"
 	"github.com/statechannels/go-nitro/internal/safesync"
@@ -19,6 +20,9 @@ import (
 	"github.com/statechannels/go-nitro/protocols/bridgeddefund"
 	"github.com/statechannels/go-nitro/protocols/directdefund"
 	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/swapdefund"
+	"github.com/statechannels/go-nitro/protocols/swapfund"
 	"github.com/statechannels/go-nitro/protocols/virtualdefund"
 	"github.com/statechannels/go-nitro/protocols/virtualfund"
 	"github.com/statechannels/go-nitro/rand"
@@ -26,6 +30,7 @@ import (
 	"github.com/statechannels/go-nitro/rpc/transport"
 	"github.com/statechannels/go-nitro/rpc/transport/http"
 	"github.com/statechannels/go-nitro/types"
+	"github.com/statechannels/go-nitro/watchtower"
 )
 
 // RpcClientApi provides various functions to make RPC API calls to a nitro RPC server
@@ -48,6 +53,10 @@ type RpcClientApi interface {
 	// CreatePaymentChannel creates a new virtual payment channel with the specified intermediaries, counterparty, ChallengeDuration, and outcome
 	CreatePaymentChannel(intermediaries []types.Address, counterparty types.Address, ChallengeDuration uint32, outcome outcome.Exit) (virtualfund.ObjectiveResponse, error)
 
+	// CreatePaymentChannelAuto creates a new virtual payment channel with the counterparty, in which we deposit the amount of the asset.
+	// The node chooses the intermediaries by finding the cheapest route with enough capacity.
+	CreatePaymentChannelAuto(counterparty types.Address, asset common.Address, amount uint64, ChallengeDuration uint32) (virtualfund.ObjectiveResponse, error)
+
 	// ClosePaymentChannel attempts to close the payment channel with the specified channelId
 	ClosePaymentChannel(id types.Destination) (protocols.ObjectiveId, error)
 
@@ -57,6 +66,9 @@ type RpcClientApi interface {
 	// GetAllLedgerChannels returns information about all ledger channels
 	GetAllLedgerChannels() ([]query.LedgerChannelInfo, error)
 
+	// GetAllSwapChannels returns information about all swap channels
+	GetAllSwapChannels() ([]query.SwapChannelInfo, error)
+
 	// GetPaymentChannelsByLedger returns all active payment channels for a given ledger channel
 	GetPaymentChannelsByLedger(ledgerId types.Destination) ([]query.PaymentChannelInfo, error)
 
@@ -81,11 +93,53 @@ type RpcClientApi interface {
 	// PaymentChannelUpdatesChan returns a channel that receives payment channel updates for the given payment channel id
 	PaymentChannelUpdatesChan(paymentChannelId types.Destination) <-chan query.PaymentChannelInfo
 
+	// SwapChannelUpdatesChan returns a channel that receives swap channel updates for the given swap channel id
+	SwapChannelUpdatesChan(swapChannelId types.Destination) <-chan query.SwapChannelInfo
+
 	ValidateVoucher(voucherHash common.Hash, signerAddress common.Address, value uint64) (serde.ValidateVoucherResponse, error)
 
+	// GetObjectiveInfo returns the status of the objective with the given id, and what it is waiting for
+	GetObjectiveInfo(id protocols.ObjectiveId) (query.ObjectiveInfo, error)
+
+	// ListObjectives returns the status of every objective with the given status, or of every objective if the status is empty
+	ListObjectives(status query.ObjectiveStatus) ([]query.ObjectiveInfo, error)
+
+	// CreateSwapChannel creates a new swap channel with the specified intermediaries, counterparty, ChallengeDuration, and outcome
+	CreateSwapChannel(intermediaries []types.Address, counterparty types.Address, ChallengeDuration uint32, outcome outcome.Exit) (swapfund.ObjectiveResponse, error)
+	// CloseSwapChannel attempts to close the swap channel with the specified channelId
+	CloseSwapChannel(id types.Destination) (protocols.ObjectiveId, error)
+	// GetSwapChannel returns the swap channel information for the given channelId
+	GetSwapChannel(id types.Destination) (query.SwapChannelInfo, error)
+	// SwapAssets proposes to the counterparty of the swap channel to swap amountIn of tokenIn for amountOut of tokenOut.
+	// The response carries the id of the proposed swap.
+	SwapAssets(channelId types.Destination, tokenIn, tokenOut common.Address, amountIn, amountOut uint64) (serde.SwapInitiateResponse, error)
+	// ConfirmSwap accepts or rejects the swap proposed by the counterparty
+	ConfirmSwap(swapId types.Destination, action types.SwapStatus) error
+	// GetPendingSwap returns the swap awaiting confirmation in the swap channel, or nil if there is none
+	GetPendingSwap(channelId types.Destination) (*payments.Swap, error)
+	// GetRecentSwaps returns the most recent swaps of the swap channel
+	GetRecentSwaps(channelId types.Destination) ([]payments.Swap, error)
+	// SwapUpdatesChan returns a channel that receives the swaps of the given swap channel as they are proposed
+	SwapUpdatesChan(swapChannelId types.Destination) <-chan query.SwapInfo
+	// SwapAlongRoute executes the swaps of the legs. The first leg must be sent by the node.
+	// The legs are committed from the end of the route backwards, so no leg commits unless the legs after it have.
+	SwapAlongRoute(legs []serde.SwapRouteLeg) (multiswap.ObjectiveResponse, error)
+	// CancelSwap cancels a swap initiated by the node which has not been accepted yet
+	CancelSwap(swapId types.Destination) error
+	// ListSwaps returns a page of the swaps which have come to an end and match the filter, ordered by the time they were recorded
+	ListSwaps(filter query.SwapFilter, offset, limit uint) (query.SwapHistory, error)
+
 	CloseBridgeChannel(id types.Destination) (protocols.ObjectiveId, error)
 
 	CreatedMirrorChannel() <-chan types.Destination
+
+	// RegisterWatchedChannel hands the latest supported state of a channel over to a watchtower, which defends the channel on chain while the node is offline.
+	// The proof and the challenger signature are optional, see watchtower.WatchedChannel.
+	RegisterWatchedChannel(signedState state.SignedState, proof []state.SignedState, challengerSig state.Signature) (types.Destination, error)
+	// GetWatchedChannel returns the channel with the given id watched by a watchtower
+	GetWatchedChannel(id types.Destination) (watchtower.WatchedChannel, error)
+	// GetAllWatchedChannels returns every channel watched by a watchtower
+	GetAllWatchedChannels() ([]watchtower.WatchedChannel, error)
 }
 
 // rpcClient is the implementation
@@ -95,6 +149,8 @@ type rpcClient struct {
 	createdMirrorChannels chan types.Destination
 	ledgerChannelUpdates  *safesync.Map[chan query.LedgerChannelInfo]
 	paymentChannelUpdates *safesync.Map[chan query.PaymentChannelInfo]
+	swapChannelUpdates    *safesync.Map[chan query.SwapChannelInfo]
+	swapUpdates           *safesync.Map[chan query.SwapInfo]
 	cancel                context.CancelFunc
 	routineTracker        *sync.WaitGroup
 	nodeAddress           common.Address
@@ -116,6 +172,8 @@ func NewRpcClient(trans transport.Requester) (RpcClientApi, error) {
 		completedObjectives:   &safesync.Map[chan struct{}]{},
 		ledgerChannelUpdates:  &safesync.Map[chan query.LedgerChannelInfo]{},
 		paymentChannelUpdates: &safesync.Map[chan query.PaymentChannelInfo]{},
+		swapChannelUpdates:    &safesync.Map[chan query.SwapChannelInfo]{},
+		swapUpdates:           &safesync.Map[chan query.SwapInfo]{},
 		cancel:                cancel,
 		routineTracker:        &sync.WaitGroup{},
 		nodeAddress:           common.Address{},
@@ -200,6 +258,13 @@ func (rc *rpcClient) CreatePaymentChannel(intermediaries []types.Address, counte
 	return waitForAuthorizedRequest[virtualfund.ObjectiveRequest, virtualfund.ObjectiveResponse](rc, serde.CreatePaymentChannelRequestMethod, objReq)
 }
 
+// CreatePaymentChannelAuto creates a new virtual payment channel through intermediaries chosen by the node
+func (rc *rpcClient) CreatePaymentChannelAuto(counterparty types.Address, asset common.Address, amount uint64, ChallengeDuration uint32) (virtualfund.ObjectiveResponse, error) {
+	req := serde.CreatePaymentChannelAutoRequest{Counterparty: counterparty, Asset: asset, Amount: amount, ChallengeDuration: ChallengeDuration}
+
+	return waitForAuthorizedRequest[serde.CreatePaymentChannelAutoRequest, virtualfund.ObjectiveResponse](rc, serde.CreatePaymentChannelAutoRequestMethod, req)
+}
+
 // ClosePaymentChannel attempts to close the payment channel with supplied id
 func (rc *rpcClient) ClosePaymentChannel(id types.Destination) (protocols.ObjectiveId, error) {
 	objReq := virtualdefund.NewObjectiveRequest(
@@ -219,6 +284,106 @@ func (rc *rpcClient) GetAllLedgerChannels() ([]query.LedgerChannelInfo, error) {
 	return waitForAuthorizedRequest[serde.NoPayloadRequest, []query.LedgerChannelInfo](rc, serde.GetAllLedgerChannelsMethod, struct{}{})
 }
 
+// GetAllSwapChannels returns all swap channels
+func (rc *rpcClient) GetAllSwapChannels() ([]query.SwapChannelInfo, error) {
+	return waitForAuthorizedRequest[serde.NoPayloadRequest, []query.SwapChannelInfo](rc, serde.GetAllSwapChannelsMethod, struct{}{})
+}
+
+// GetObjectiveInfo returns the status of the objective with the given id, and what it is waiting for
+func (rc *rpcClient) GetObjectiveInfo(id protocols.ObjectiveId) (query.ObjectiveInfo, error) {
+	req := serde.GetObjectiveInfoRequest{ObjectiveId: id}
+
+	return waitForAuthorizedRequest[serde.GetObjectiveInfoRequest, query.ObjectiveInfo](rc, serde.GetObjectiveInfoRequestMethod, req)
+}
+
+// ListObjectives returns the status of every objective with the given status, or of every objective if the status is empty
+func (rc *rpcClient) ListObjectives(status query.ObjectiveStatus) ([]query.ObjectiveInfo, error) {
+	req := serde.ListObjectivesRequest{Status: status}
+
+	return waitForAuthorizedRequest[serde.ListObjectivesRequest, []query.ObjectiveInfo](rc, serde.ListObjectivesRequestMethod, req)
+}
+
+// CreateSwapChannel creates a new swap channel funded by the ledger channels with the intermediaries, or with the counterparty if there are none
+func (rc *rpcClient) CreateSwapChannel(intermediaries []types.Address, counterparty types.Address, ChallengeDuration uint32, outcome outcome.Exit) (swapfund.ObjectiveResponse, error) {
+	objReq := swapfund.NewObjectiveRequest(
+		intermediaries,
+		counterparty,
+		ChallengeDuration,
+		outcome,
+		rand.Uint64(),
+		common.Address{})
+
+	return waitForAuthorizedRequest[swapfund.ObjectiveRequest, swapfund.ObjectiveResponse](rc, serde.CreateSwapChannelRequestMethod, objReq)
+}
+
+// CloseSwapChannel attempts to close the swap channel with supplied id
+func (rc *rpcClient) CloseSwapChannel(id types.Destination) (protocols.ObjectiveId, error) {
+	objReq := swapdefund.NewObjectiveRequest(id)
+
+	return waitForAuthorizedRequest[swapdefund.ObjectiveRequest, protocols.ObjectiveId](rc, serde.CloseSwapChannelRequestMethod, objReq)
+}
+
+// GetSwapChannel returns the swap channel with the supplied id
+func (rc *rpcClient) GetSwapChannel(id types.Destination) (query.SwapChannelInfo, error) {
+	req := serde.GetSwapChannelRequest{Id: id}
+
+	return waitForAuthorizedRequest[serde.GetSwapChannelRequest, query.SwapChannelInfo](rc, serde.GetSwapChannelRequestMethod, req)
+}
+
+// SwapAssets proposes a swap of the assets in the swap channel to the counterparty
+func (rc *rpcClient) SwapAssets(channelId types.Destination, tokenIn, tokenOut common.Address, amountIn, amountOut uint64) (serde.SwapInitiateResponse, error) {
+	req := serde.SwapInitiateRequest{
+		SwapAssetsData: serde.SwapAssetsData{TokenIn: tokenIn, TokenOut: tokenOut, AmountIn: amountIn, AmountOut: amountOut},
+		Channel:        channelId,
+	}
+
+	return waitForAuthorizedRequest[serde.SwapInitiateRequest, serde.SwapInitiateResponse](rc, serde.SwapInitiateRequestMethod, req)
+}
+
+// ConfirmSwap accepts or rejects the swap with the supplied id
+func (rc *rpcClient) ConfirmSwap(swapId types.Destination, action types.SwapStatus) error {
+	req := serde.ConfirmSwapRequest{SwapId: swapId, Action: action}
+
+	_, err := waitForAuthorizedRequest[serde.ConfirmSwapRequest, serde.ConfirmSwapRequest](rc, serde.ConfirmSwapRequestMethod, req)
+	return err
+}
+
+// GetPendingSwap returns the swap awaiting confirmation in the swap channel with the supplied id
+func (rc *rpcClient) GetPendingSwap(channelId types.Destination) (*payments.Swap, error) {
+	req := serde.GetSwapChannelRequest{Id: channelId}
+
+	return waitForAuthorizedRequest[serde.GetSwapChannelRequest, serde.GetPendingSwapResponse](rc, serde.GetPendingSwapRequestMethod, req)
+}
+
+// GetRecentSwaps returns the most recent swaps of the swap channel with the supplied id
+func (rc *rpcClient) GetRecentSwaps(channelId types.Destination) ([]payments.Swap, error) {
+	req := serde.GetSwapChannelRequest{Id: channelId}
+
+	return waitForAuthorizedRequest[serde.GetSwapChannelRequest, serde.GetRecentSwapsResponse](rc, serde.GetRecentSwapsRequestMethod, req)
+}
+
+// SwapAlongRoute executes the swaps of the legs, committing them from the end of the route backwards
+func (rc *rpcClient) SwapAlongRoute(legs []serde.SwapRouteLeg) (multiswap.ObjectiveResponse, error) {
+	req := serde.SwapAlongRouteRequest{Legs: legs}
+
+	return waitForAuthorizedRequest[serde.SwapAlongRouteRequest, multiswap.ObjectiveResponse](rc, serde.SwapAlongRouteRequestMethod, req)
+}
+
+// CancelSwap cancels a swap initiated by the node which has not been accepted yet
+func (rc *rpcClient) CancelSwap(swapId types.Destination) error {
+	req := serde.CancelSwapRequest{SwapId: swapId}
+
+	_, err := waitForAuthorizedRequest[serde.CancelSwapRequest, serde.CancelSwapRequest](rc, serde.CancelSwapRequestMethod, req)
+	return err
+}
+
+// ListSwaps returns a page of the swaps which have come to an end and match the filter, ordered by the time they were recorded
+func (rc *rpcClient) ListSwaps(filter query.SwapFilter, offset, limit uint) (query.SwapHistory, error) {
+	req := serde.ListSwapsRequest{Filter: filter, Offset: offset, Limit: limit}
+
+	return waitForAuthorizedRequest[serde.ListSwapsRequest, query.SwapHistory](rc, serde.ListSwapsRequestMethod, req)
+}
+
 // GetPaymentChannelsByLedger returns all active payment channels for a given ledger channel
 func (rc *rpcClient) GetPaymentChannelsByLedger(ledgerId types.Destination) ([]query.PaymentChannelInfo, error) {
 	return waitForAuthorizedRequest[serde.GetPaymentChannelsByLedgerRequest, []query.PaymentChannelInfo](rc, serde.GetPaymentChannelsByLedgerMethod, serde.GetPaymentChannelsByLedgerRequest{LedgerId: ledgerId})
@@ -303,6 +468,34 @@ func (rc *rpcClient) subscribeToNotifications(ctx context.Context, notificationC
 				}
 				c, _ := rc.paymentChannelUpdates.LoadOrStore(string(rpcRequest.Params.Payload.ID.String()), make(chan query.PaymentChannelInfo, 100))
 				c <- rpcRequest.Params.Payload
+			case serde.SwapUpdated:
+				rpcRequest := serde.JsonRpcSpecificRequest[query.SwapInfo]{}
+				err := json.Unmarshal(data, &rpcRequest)
+				rc.logger.Debug("Received notification", "method", method, "data", rpcRequest)
+				if err != nil {
+					panic(err)
+				}
+				c, _ := rc.swapUpdates.LoadOrStore(string(rpcRequest.Params.Payload.ChannelId.String()), make(chan query.SwapInfo, 100))
+
+				// use a nonblocking send, as every swap is notified and no one may be listening
+				select {
+				case c <- rpcRequest.Params.Payload:
+				default:
+				}
+			case serde.SwapChannelUpdated:
+				rpcRequest := serde.JsonRpcSpecificRequest[query.SwapChannelInfo]{}
+				err := json.Unmarshal(data, &rpcRequest)
+				rc.logger.Debug("Received notification", "method", method, "data", rpcRequest)
+				if err != nil {
+					panic(err)
+				}
+				c, _ := rc.swapChannelUpdates.LoadOrStore(string(rpcRequest.Params.Payload.ID.String()), make(chan query.SwapChannelInfo, 100))
+
+				// use a nonblocking send, as every swap updates the channel and no one may be listening
+				select {
+				case c <- rpcRequest.Params.Payload:
+				default:
+				}
 			case serde.MirrorChannelCreated:
 				rpcRequest := serde.JsonRpcSpecificRequest[types.Destination]{}
 				err := json.Unmarshal(data, &rpcRequest)
@@ -339,6 +532,18 @@ func (rc *rpcClient) PaymentChannelUpdatesChan(paymentChannelId types.Destinatio
 	return c
 }
 
+// SwapUpdatesChan returns a chan that receives the swaps of a swap channel.
+func (rc *rpcClient) SwapUpdatesChan(swapChannelId types.Destination) <-chan query.SwapInfo {
+	c, _ := rc.swapUpdates.LoadOrStore(string(swapChannelId.String()), make(chan query.SwapInfo, 100))
+	return c
+}
+
+// SwapChannelUpdatesChan returns a chan that receives swap channel updates.
+func (rc *rpcClient) SwapChannelUpdatesChan(swapChannelId types.Destination) <-chan query.SwapChannelInfo {
+	c, _ := rc.swapChannelUpdates.LoadOrStore(string(swapChannelId.String()), make(chan query.SwapChannelInfo, 100))
+	return c
+}
+
 // WaitForRequestNoAuth calls waitForRequest with an empty auth token
 func WaitForRequestNoAuth[T serde.RequestPayload, U serde.ResponsePayload](rc *rpcClient, method serde.RequestMethod, requestData T) (U, error) {
 	return waitForRequest[T, U](rc, method, requestData, "")
@@ -420,3 +625,17 @@ func getNotificationMethod(raw []byte) (serde.NotificationMethod, error) {
 func (rc *rpcClient) CreatedMirrorChannel() <-chan types.Destination {
 	return rc.createdMirrorChannels
 }
+
+func (rc *rpcClient) RegisterWatchedChannel(signedState state.SignedState, proof []state.SignedState, challengerSig state.Signature) (types.Destination, error) {
+	req := serde.RegisterWatchedChannelRequest{SignedState: signedState, Proof: proof, ChallengerSig: challengerSig}
+	return waitForAuthorizedRequest[serde.RegisterWatchedChannelRequest, types.Destination](rc, serde.RegisterWatchedChannelMethod, req)
+}
+
+func (rc *rpcClient) GetWatchedChannel(id types.Destination) (watchtower.WatchedChannel, error) {
+	req := serde.GetWatchedChannelRequest{Id: id}
+	return waitForAuthorizedRequest[serde.GetWatchedChannelRequest, watchtower.WatchedChannel](rc, serde.GetWatchedChannelMethod, req)
+}
+
+func (rc *rpcClient) GetAllWatchedChannels() ([]watchtower.WatchedChannel, error) {
+	return waitForAuthorizedRequest[serde.NoPayloadRequest, serde.GetAllWatchedChannelsResponse](rc, serde.GetAllWatchedChannelsMethod, serde.NoPayloadRequest{})
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"log/slog"
 	"math/big"
+	"time"
 
 	"github.com/statechannels/go-nitro/channel/state"
 	"github.com/statechannels/go-nitro/internal/logging"
 	nitro "github.com/statechannels/go-nitro/node"
+	"github.com/statechannels/go-nitro/node/notifier"
 	"github.com/statechannels/go-nitro/node/query"
 	"github.com/statechannels/go-nitro/payments"
 	"github.com/statechannels/go-nitro/paymentsmanager"
@@ -17,10 +19,13 @@ import (
 	"github.com/statechannels/go-nitro/protocols/bridgeddefund"
 	"github.com/statechannels/go-nitro/protocols/directdefund"
 	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
+	"github.com/statechannels/go-nitro/protocols/swap"
 	"github.com/statechannels/go-nitro/protocols/swapdefund"
 	"github.com/statechannels/go-nitro/protocols/swapfund"
 	"github.com/statechannels/go-nitro/protocols/virtualdefund"
 	"github.com/statechannels/go-nitro/protocols/virtualfund"
+	"github.com/statechannels/go-nitro/rand"
 	"github.com/statechannels/go-nitro/rpc/serde"
 	"github.com/statechannels/go-nitro/rpc/transport"
 	"github.com/statechannels/go-nitro/types"
@@ -73,11 +78,12 @@ func NewNodeRpcServer(nitroNode *nitro.Node, paymentManager paymentsmanager.Paym
 	// If these channels are initialized in another go routine,
 	// the server can send an update before the channels are initialized.
 	completedObjChan := nrs.node.CompletedObjectives()
-	ledgerUpdateChan := nrs.node.LedgerUpdates()
-	paymentUpdateChan := nrs.node.PaymentUpdates()
-	swapUpdateChan := nrs.node.SwapUpdates()
+	ledgerUpdateChan := nrs.node.SubscribeLedgerUpdates(ctx, notifier.SubscriptionOpts{})
+	paymentUpdateChan := nrs.node.SubscribePaymentUpdates(ctx, notifier.SubscriptionOpts{})
+	swapUpdateChan := nrs.node.SubscribeSwapUpdates(ctx, notifier.SubscriptionOpts{})
+	swapChannelUpdateChan := nrs.node.SubscribeSwapChannelUpdates(ctx, notifier.SubscriptionOpts{})
 
-	go nrs.sendNotifications(ctx, completedObjChan, ledgerUpdateChan, paymentUpdateChan, swapUpdateChan)
+	go nrs.sendNotifications(ctx, completedObjChan, ledgerUpdateChan, paymentUpdateChan, swapUpdateChan, swapChannelUpdateChan)
 
 	err := nrs.registerHandlers()
 	if err != nil {
@@ -173,7 +179,15 @@ func (nrs *NodeRpcServer) registerHandlers() (err error) {
 			})
 		case serde.CreatePaymentChannelRequestMethod:
 			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req virtualfund.ObjectiveRequest) (virtualfund.ObjectiveResponse, error) {
-				return nrs.node.CreatePaymentChannel(req.Intermediaries, req.CounterParty, req.ChallengeDuration, req.Outcome)
+				return nrs.node.CreatePaymentChannelWithFees(req.Intermediaries, req.CounterParty, req.ChallengeDuration, req.Outcome, req.IntermediaryFees)
+			})
+		case serde.CreatePaymentChannelAutoRequestMethod:
+			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.CreatePaymentChannelAutoRequest) (virtualfund.ObjectiveResponse, error) {
+				if err := serde.ValidateCreatePaymentChannelAutoRequest(req); err != nil {
+					return virtualfund.ObjectiveResponse{}, err
+				}
+
+				return nrs.node.CreatePaymentChannelAuto(req.Counterparty, req.Asset, new(big.Int).SetUint64(req.Amount), req.ChallengeDuration)
 			})
 		case serde.ClosePaymentChannelRequestMethod:
 			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req virtualdefund.ObjectiveRequest) (protocols.ObjectiveId, error) {
@@ -184,32 +198,12 @@ func (nrs *NodeRpcServer) registerHandlers() (err error) {
 				return nrs.node.GetNodeInfo(), nil
 			})
 		case serde.GetPendingSwapRequestMethod:
-			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.GetSwapChannelRequest) (string, error) {
-				swap, err := nrs.node.GetPendingSwapByChannelId(req.Id)
-				if err != nil {
-					return "", err
-				}
-
-				swapJson, err := json.Marshal(swap)
-				if err != nil {
-					return "", err
-				}
-
-				return string(swapJson), nil
+			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.GetSwapChannelRequest) (serde.GetPendingSwapResponse, error) {
+				return nrs.node.GetPendingSwapByChannelId(req.Id)
 			})
 		case serde.GetRecentSwapsRequestMethod:
-			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.GetSwapChannelRequest) (string, error) {
-				swaps, err := nrs.node.GetRecentSwapsByChannelId(req.Id)
-				if err != nil {
-					return "", err
-				}
-
-				swapJson, err := json.Marshal(swaps)
-				if err != nil {
-					return "", err
-				}
-
-				return string(swapJson), nil
+			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.GetSwapChannelRequest) (serde.GetRecentSwapsResponse, error) {
+				return nrs.node.GetRecentSwapsByChannelId(req.Id)
 			})
 		case serde.PayRequestMethod:
 			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.PaymentRequest) (serde.PaymentRequest, error) {
@@ -221,19 +215,43 @@ func (nrs *NodeRpcServer) registerHandlers() (err error) {
 				return req, err
 			})
 		case serde.SwapInitiateRequestMethod:
-			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.SwapInitiateRequest) (serde.SwapInitiateRequest, error) {
+			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.SwapInitiateRequest) (serde.SwapInitiateResponse, error) {
 				if err := serde.ValidateSwapInitiateRequest(req); err != nil {
-					return serde.SwapInitiateRequest{}, err
+					return serde.SwapInitiateResponse{}, err
 				}
 
-				_, err := nrs.node.SwapAssets(req.Channel, req.SwapAssetsData.TokenIn, req.SwapAssetsData.TokenOut, big.NewInt(int64(req.SwapAssetsData.AmountIn)), big.NewInt(int64(req.SwapAssetsData.AmountOut)))
-				return req, err
+				res, err := nrs.node.SwapAssets(req.Channel, req.SwapAssetsData.TokenIn, req.SwapAssetsData.TokenOut, big.NewInt(int64(req.SwapAssetsData.AmountIn)), big.NewInt(int64(req.SwapAssetsData.AmountOut)))
+				return serde.SwapInitiateResponse{SwapInitiateRequest: req, SwapId: res.SwapId}, err
+			})
+		case serde.SwapAlongRouteRequestMethod:
+			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.SwapAlongRouteRequest) (multiswap.ObjectiveResponse, error) {
+				if err := serde.ValidateSwapAlongRouteRequest(req); err != nil {
+					return multiswap.ObjectiveResponse{}, err
+				}
+
+				nonce := rand.Uint64()
+				expiry := uint64(time.Now().Add(swap.DEFAULT_SWAP_EXPIRY).Unix())
+				legs := make([]multiswap.Leg, len(req.Legs))
+				for i, leg := range req.Legs {
+					data := leg.SwapAssetsData
+					legs[i] = multiswap.NewLeg(leg.Channel, leg.Sender, leg.Receiver, data.TokenIn, data.TokenOut, big.NewInt(int64(data.AmountIn)), big.NewInt(int64(data.AmountOut)), nonce, expiry)
+				}
+				return nrs.node.SwapAlongRoute(legs)
 			})
 		case serde.ConfirmSwapRequestMethod:
 			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.ConfirmSwapRequest) (serde.ConfirmSwapRequest, error) {
 				err := nrs.node.ConfirmSwap(req.SwapId, req.Action)
 				return req, err
 			})
+		case serde.CancelSwapRequestMethod:
+			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.CancelSwapRequest) (serde.CancelSwapRequest, error) {
+				if err := serde.ValidateCancelSwapRequest(req); err != nil {
+					return serde.CancelSwapRequest{}, err
+				}
+
+				err := nrs.node.CancelSwap(req.SwapId)
+				return req, err
+			})
 		case serde.GetPaymentChannelRequestMethod:
 			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.GetPaymentChannelRequest) (query.PaymentChannelInfo, error) {
 				if err := serde.ValidateGetPaymentChannelRequest(req); err != nil {
@@ -242,9 +260,9 @@ func (nrs *NodeRpcServer) registerHandlers() (err error) {
 				return nrs.node.GetPaymentChannel(req.Id)
 			})
 		case serde.GetSwapChannelRequestMethod:
-			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.GetSwapChannelRequest) (string, error) {
+			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.GetSwapChannelRequest) (query.SwapChannelInfo, error) {
 				if err := serde.ValidateGetSwapChannelRequest(req); err != nil {
-					return "", err
+					return query.SwapChannelInfo{}, err
 				}
 				return nrs.node.GetSwapChannel(req.Id)
 			})
@@ -256,6 +274,38 @@ func (nrs *NodeRpcServer) registerHandlers() (err error) {
 			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.NoPayloadRequest) ([]query.LedgerChannelInfo, error) {
 				return nrs.node.GetAllLedgerChannels()
 			})
+		case serde.GetAllSwapChannelsMethod:
+			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.NoPayloadRequest) ([]query.SwapChannelInfo, error) {
+				return nrs.node.GetAllSwapChannels()
+			})
+		case serde.GetObjectiveInfoRequestMethod:
+			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.GetObjectiveInfoRequest) (query.ObjectiveInfo, error) {
+				if err := serde.ValidateGetObjectiveInfoRequest(req); err != nil {
+					return query.ObjectiveInfo{}, err
+				}
+
+				return nrs.node.GetObjectiveInfo(req.ObjectiveId)
+			})
+		case serde.ListObjectivesRequestMethod:
+			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.ListObjectivesRequest) ([]query.ObjectiveInfo, error) {
+				if err := serde.ValidateListObjectivesRequest(req); err != nil {
+					return []query.ObjectiveInfo{}, err
+				}
+
+				return nrs.node.ListObjectives(req.Status)
+			})
+		case serde.ListSwapsRequestMethod:
+			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.ListSwapsRequest) (query.SwapHistory, error) {
+				if err := serde.ValidateListSwapsRequest(req); err != nil {
+					return query.SwapHistory{}, err
+				}
+
+				limit := req.Limit
+				if limit == 0 {
+					limit = serde.MaxListSwapsLimit
+				}
+				return nrs.node.ListSwaps(req.Filter, req.Offset, limit)
+			})
 		case serde.GetPaymentChannelsByLedgerMethod:
 			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.GetPaymentChannelsByLedgerRequest) ([]query.PaymentChannelInfo, error) {
 				if err := serde.ValidateGetPaymentChannelsByLedgerRequest(req); err != nil {
@@ -277,8 +327,8 @@ func (nrs *NodeRpcServer) registerHandlers() (err error) {
 					}
 				}
 
-				nrs.node.CounterChallenge(req.ChannelId, req.Action, l2SignedState)
-				return req, nil
+				err := nrs.node.CounterChallenge(req.ChannelId, req.Action, l2SignedState)
+				return req, err
 			})
 		case serde.ValidateVoucherRequestMethod:
 			return processRequest(nrs.BaseRpcServer, permRead, requestData, func(req serde.ValidateVoucherRequest) (serde.ValidateVoucherResponse, error) {
@@ -306,8 +356,8 @@ func (nrs *NodeRpcServer) registerHandlers() (err error) {
 			})
 		case serde.RetryObjectiveTxMethod:
 			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.RetryObjectiveTxRequest) (protocols.ObjectiveId, error) {
-				nrs.node.RetryObjectiveTx(req.ObjectiveId)
-				return req.ObjectiveId, nil
+				err := nrs.node.RetryObjectiveTx(req.ObjectiveId)
+				return req.ObjectiveId, err
 			})
 		case serde.GetObjectiveMethod:
 			return processRequest(nrs.BaseRpcServer, permSign, requestData, func(req serde.GetObjectiveRequest) (string, error) {
@@ -338,6 +388,7 @@ func (rs *NodeRpcServer) sendNotifications(ctx context.Context,
 	ledgerUpdatesChan <-chan query.LedgerChannelInfo,
 	paymentUpdatesChan <-chan query.PaymentChannelInfo,
 	swapUpdatesChan <-chan query.SwapInfo,
+	swapChannelUpdatesChan <-chan query.SwapChannelInfo,
 ) {
 	defer rs.wg.Done()
 	for {
@@ -384,6 +435,16 @@ func (rs *NodeRpcServer) sendNotifications(ctx context.Context,
 			if err != nil {
 				panic(err)
 			}
+		case swapChannelInfo, ok := <-swapChannelUpdatesChan:
+			if !ok {
+				rs.logger.Warn("SwapChannelUpdates channel closed, exiting sendNotifications")
+				return
+			}
+
+			err := sendNotification(rs.BaseRpcServer, serde.SwapChannelUpdated, swapChannelInfo)
+			if err != nil {
+				panic(err)
+			}
 		}
 	}
 }
@@ -3,49 +3,59 @@ package serde
 import (
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/statechannels/go-nitro/channel/state"
 	"github.com/statechannels/go-nitro/node/query"
 	"github.com/statechannels/go-nitro/payments"
 	"github.com/statechannels/go-nitro/protocols"
 	"github.com/statechannels/go-nitro/protocols/bridgeddefund"
 	"github.com/statechannels/go-nitro/protocols/directdefund"
 	"github.com/statechannels/go-nitro/protocols/directfund"
+	"github.com/statechannels/go-nitro/protocols/multiswap"
 	"github.com/statechannels/go-nitro/protocols/swapdefund"
 	"github.com/statechannels/go-nitro/protocols/swapfund"
 	"github.com/statechannels/go-nitro/protocols/virtualdefund"
 	"github.com/statechannels/go-nitro/protocols/virtualfund"
 	"github.com/statechannels/go-nitro/types"
+	"github.com/statechannels/go-nitro/watchtower"
 )
 
 type RequestMethod string
 
 const (
-	GetAuthTokenMethod                RequestMethod = "get_auth_token"
-	GetAddressMethod                  RequestMethod = "get_address"
-	VersionMethod                     RequestMethod = "version"
-	CreateLedgerChannelRequestMethod  RequestMethod = "create_ledger_channel"
-	CloseLedgerChannelRequestMethod   RequestMethod = "close_ledger_channel"
-	CloseBridgeChannelRequestMethod   RequestMethod = "close_bridge_channel"
-	MirrorBridgedDefundRequestMethod  RequestMethod = "mirror_bridged_defund"
-	CreateSwapChannelRequestMethod    RequestMethod = "create_swap_channel"
-	CreatePaymentChannelRequestMethod RequestMethod = "create_payment_channel"
-	ClosePaymentChannelRequestMethod  RequestMethod = "close_payment_channel"
-	CloseSwapChannelRequestMethod     RequestMethod = "close_swap_channel"
-	PayRequestMethod                  RequestMethod = "pay"
-	SwapInitiateRequestMethod         RequestMethod = "swap_initiate"
-	ConfirmSwapRequestMethod          RequestMethod = "confirm_swap"
-	GetPaymentChannelRequestMethod    RequestMethod = "get_payment_channel"
-	GetSwapChannelRequestMethod       RequestMethod = "get_swap_channel"
-	GetVoucherRequestMethod           RequestMethod = "get_voucher"
-	GetLedgerChannelRequestMethod     RequestMethod = "get_ledger_channel"
-	GetPaymentChannelsByLedgerMethod  RequestMethod = "get_payment_channels_by_ledger"
-	GetAllLedgerChannelsMethod        RequestMethod = "get_all_ledger_channels"
-	GetNodeInfoRequestMethod          RequestMethod = "get_node_info"
-	GetPendingSwapRequestMethod       RequestMethod = "get_pending_swap"
-	GetRecentSwapsRequestMethod       RequestMethod = "get_recent_swaps"
-	CreateVoucherRequestMethod        RequestMethod = "create_voucher"
-	ReceiveVoucherRequestMethod       RequestMethod = "receive_voucher"
-	CounterChallengeRequestMethod     RequestMethod = "counter_challenge"
-	ValidateVoucherRequestMethod      RequestMethod = "validate_voucher"
+	GetAuthTokenMethod                    RequestMethod = "get_auth_token"
+	GetAddressMethod                      RequestMethod = "get_address"
+	VersionMethod                         RequestMethod = "version"
+	CreateLedgerChannelRequestMethod      RequestMethod = "create_ledger_channel"
+	CloseLedgerChannelRequestMethod       RequestMethod = "close_ledger_channel"
+	CloseBridgeChannelRequestMethod       RequestMethod = "close_bridge_channel"
+	MirrorBridgedDefundRequestMethod      RequestMethod = "mirror_bridged_defund"
+	CreateSwapChannelRequestMethod        RequestMethod = "create_swap_channel"
+	CreatePaymentChannelRequestMethod     RequestMethod = "create_payment_channel"
+	CreatePaymentChannelAutoRequestMethod RequestMethod = "create_payment_channel_auto"
+	ClosePaymentChannelRequestMethod      RequestMethod = "close_payment_channel"
+	CloseSwapChannelRequestMethod         RequestMethod = "close_swap_channel"
+	PayRequestMethod                      RequestMethod = "pay"
+	SwapInitiateRequestMethod             RequestMethod = "swap_initiate"
+	ConfirmSwapRequestMethod              RequestMethod = "confirm_swap"
+	SwapAlongRouteRequestMethod           RequestMethod = "swap_along_route"
+	CancelSwapRequestMethod               RequestMethod = "cancel_swap"
+	GetPaymentChannelRequestMethod        RequestMethod = "get_payment_channel"
+	GetSwapChannelRequestMethod           RequestMethod = "get_swap_channel"
+	GetVoucherRequestMethod               RequestMethod = "get_voucher"
+	GetLedgerChannelRequestMethod         RequestMethod = "get_ledger_channel"
+	GetPaymentChannelsByLedgerMethod      RequestMethod = "get_payment_channels_by_ledger"
+	GetAllLedgerChannelsMethod            RequestMethod = "get_all_ledger_channels"
+	GetAllSwapChannelsMethod              RequestMethod = "get_all_swap_channels"
+	GetNodeInfoRequestMethod              RequestMethod = "get_node_info"
+	GetPendingSwapRequestMethod           RequestMethod = "get_pending_swap"
+	GetRecentSwapsRequestMethod           RequestMethod = "get_recent_swaps"
+	CreateVoucherRequestMethod            RequestMethod = "create_voucher"
+	ReceiveVoucherRequestMethod           RequestMethod = "receive_voucher"
+	CounterChallengeRequestMethod         RequestMethod = "counter_challenge"
+	ValidateVoucherRequestMethod          RequestMethod = "validate_voucher"
+	GetObjectiveInfoRequestMethod         RequestMethod = "get_objective_info"
+	ListObjectivesRequestMethod           RequestMethod = "list_objectives"
+	ListSwapsRequestMethod                RequestMethod = "list_swaps"
 
 	// Bridge methods
 	GetAllL2ChannelsRequestMethod RequestMethod = "get_all_l2_channels"
@@ -54,6 +64,11 @@ const (
 
 	GetSignedStateMethod RequestMethod = "get_signed_state"
 
+	// Watchtower methods
+	RegisterWatchedChannelMethod RequestMethod = "register_watched_channel"
+	GetWatchedChannelMethod      RequestMethod = "get_watched_channel"
+	GetAllWatchedChannelsMethod  RequestMethod = "get_all_watched_channels"
+
 	// Chain reorgs workaround methods
 	GetObjectiveMethod     RequestMethod = "get_objective"
 	RetryObjectiveTxMethod RequestMethod = "retry_objective_tx"
@@ -68,6 +83,7 @@ const (
 	PaymentChannelUpdated NotificationMethod = "payment_channel_updated"
 	MirrorChannelCreated  NotificationMethod = "mirror_channel_created"
 	SwapUpdated           NotificationMethod = "swap_updated"
+	SwapChannelUpdated    NotificationMethod = "swap_channel_updated"
 )
 
 type NotificationOrRequest interface {
@@ -84,6 +100,15 @@ type PaymentRequest struct {
 	Channel types.Destination
 }
 
+// CreatePaymentChannelAutoRequest creates a payment channel with the counterparty, in which we deposit the amount of the asset,
+// through intermediaries chosen by the node
+type CreatePaymentChannelAutoRequest struct {
+	Counterparty      types.Address
+	Asset             common.Address
+	Amount            uint64
+	ChallengeDuration uint32
+}
+
 type SwapAssetsData struct {
 	TokenIn   common.Address
 	TokenOut  common.Address
@@ -96,11 +121,36 @@ type SwapInitiateRequest struct {
 	Channel        types.Destination
 }
 
+// SwapInitiateResponse echoes the swap initiate request along with the id of the proposed swap
+type SwapInitiateResponse struct {
+	SwapInitiateRequest
+	SwapId types.Destination
+}
+
+// SwapRouteLeg is a swap in one of the swap channels of a route, in which the Sender swaps with the Receiver
+type SwapRouteLeg struct {
+	SwapAssetsData SwapAssetsData
+	Channel        types.Destination
+	Sender         common.Address
+	Receiver       common.Address
+}
+
+// SwapAlongRouteRequest executes the swaps of the legs. The first leg must be sent by the node.
+// The legs are committed from the end of the route backwards, so no leg commits unless the legs after it have.
+type SwapAlongRouteRequest struct {
+	Legs []SwapRouteLeg
+}
+
 type ConfirmSwapRequest struct {
 	SwapId types.Destination
 	Action types.SwapStatus
 }
 
+// CancelSwapRequest cancels a swap initiated by the node which has not been accepted yet
+type CancelSwapRequest struct {
+	SwapId types.Destination
+}
+
 type MirrorBridgedDefundRequest struct {
 	ChannelId                types.Destination
 	StringifiedL2SignedState string
@@ -149,6 +199,26 @@ type GetObjectiveRequest struct {
 	L2          bool
 }
 
+type GetObjectiveInfoRequest struct {
+	ObjectiveId protocols.ObjectiveId
+}
+
+// ListObjectivesRequest lists the objectives with the given status, or every objective if the status is empty
+type ListObjectivesRequest struct {
+	Status query.ObjectiveStatus
+}
+
+// MaxListSwapsLimit is the largest page of swaps returned by a single ListSwapsRequest
+const MaxListSwapsLimit = 1000
+
+// ListSwapsRequest lists a page of the swaps which have come to an end and match the filter.
+// At most Limit swaps are returned after skipping the first Offset swaps; a zero Limit returns a page of MaxListSwapsLimit swaps.
+type ListSwapsRequest struct {
+	Filter query.SwapFilter
+	Offset uint
+	Limit  uint
+}
+
 type GetL2ObjectiveFromL1Request struct {
 	L1ObjectiveId protocols.ObjectiveId
 }
@@ -165,23 +235,40 @@ type GetSignedStateRequest struct {
 	Id types.Destination
 }
 
+// RegisterWatchedChannelRequest hands the latest supported state of a channel over to the watchtower.
+// Proof and ChallengerSig are optional, see watchtower.WatchedChannel.
+type RegisterWatchedChannelRequest struct {
+	SignedState   state.SignedState
+	Proof         []state.SignedState
+	ChallengerSig state.Signature
+}
+
+type GetWatchedChannelRequest struct {
+	Id types.Destination
+}
+
 type RequestPayload interface {
 	directfund.ObjectiveRequest |
 		directdefund.ObjectiveRequest |
 		MirrorBridgedDefundRequest |
 		virtualfund.ObjectiveRequest |
+		CreatePaymentChannelAutoRequest |
 		virtualdefund.ObjectiveRequest |
 		swapfund.ObjectiveRequest |
 		swapdefund.ObjectiveRequest |
 		AuthRequest |
 		PaymentRequest |
 		SwapInitiateRequest |
+		SwapAlongRouteRequest |
 		ConfirmSwapRequest |
+		CancelSwapRequest |
 		GetLedgerChannelRequest |
 		GetPaymentChannelRequest |
 		GetSwapChannelRequest |
 		GetPaymentChannelsByLedgerRequest |
 		GetSignedStateRequest |
+		RegisterWatchedChannelRequest |
+		GetWatchedChannelRequest |
 		GetVoucherRequest |
 		NoPayloadRequest |
 		payments.Voucher |
@@ -191,6 +278,9 @@ type RequestPayload interface {
 		RetryObjectiveTxRequest |
 		RetryTxRequest |
 		GetObjectiveRequest |
+		GetObjectiveInfoRequest |
+		ListObjectivesRequest |
+		ListSwapsRequest |
 		GetL2ObjectiveFromL1Request |
 		GetPendingBridgeTxsRequest
 }
@@ -200,6 +290,7 @@ type NotificationPayload interface {
 		query.PaymentChannelInfo |
 		query.LedgerChannelInfo |
 		query.SwapInfo |
+		query.SwapChannelInfo |
 		types.Destination
 }
 
@@ -217,7 +308,12 @@ type JsonRpcSpecificRequest[T RequestPayload | NotificationPayload] struct {
 
 type (
 	GetAllLedgersResponse              = []query.LedgerChannelInfo
+	GetAllSwapChannelsResponse         = []query.SwapChannelInfo
 	GetPaymentChannelsByLedgerResponse = []query.PaymentChannelInfo
+	ListObjectivesResponse             = []query.ObjectiveInfo
+	GetPendingSwapResponse             = *payments.Swap
+	GetRecentSwapsResponse             = []payments.Swap
+	GetAllWatchedChannelsResponse      = []watchtower.WatchedChannel
 )
 
 type ValidateVoucherResponse struct {
@@ -230,15 +326,25 @@ type ResponsePayload interface {
 		protocols.ObjectiveId |
 		virtualfund.ObjectiveResponse |
 		swapfund.ObjectiveResponse |
+		multiswap.ObjectiveResponse |
 		PaymentRequest |
-		SwapInitiateRequest |
+		SwapInitiateResponse |
 		ConfirmSwapRequest |
+		CancelSwapRequest |
 		query.PaymentChannelInfo |
 		query.LedgerChannelInfo |
 		query.SwapChannelInfo |
 		GetAllLedgersResponse |
+		GetAllSwapChannelsResponse |
 		GetPaymentChannelsByLedgerResponse |
+		query.ObjectiveInfo |
+		ListObjectivesResponse |
+		query.SwapHistory |
 		payments.Voucher |
+		GetPendingSwapResponse |
+		GetRecentSwapsResponse |
+		watchtower.WatchedChannel |
+		GetAllWatchedChannelsResponse |
 		common.Address |
 		string |
 		payments.ReceiveVoucherSummary |
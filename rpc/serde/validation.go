@@ -1,6 +1,7 @@
 package serde
 
 import (
+	"github.com/statechannels/go-nitro/node/query"
 	"github.com/statechannels/go-nitro/types"
 )
 
@@ -14,6 +15,16 @@ func ValidatePaymentRequest(req PaymentRequest) error {
 	return nil
 }
 
+func ValidateCreatePaymentChannelAutoRequest(req CreatePaymentChannelAutoRequest) error {
+	if req.Amount == 0 {
+		return InvalidParamsError
+	}
+	if (req.Counterparty == types.Address{}) {
+		return InvalidParamsError
+	}
+	return nil
+}
+
 func ValidateSwapInitiateRequest(req SwapInitiateRequest) error {
 	if (req.Channel == types.Destination{}) {
 		return InvalidParamsError
@@ -21,6 +32,25 @@ func ValidateSwapInitiateRequest(req SwapInitiateRequest) error {
 	return nil
 }
 
+func ValidateSwapAlongRouteRequest(req SwapAlongRouteRequest) error {
+	if len(req.Legs) < 2 {
+		return InvalidParamsError
+	}
+	for _, leg := range req.Legs {
+		if (leg.Channel == types.Destination{}) || (leg.Sender == types.Address{}) || (leg.Receiver == types.Address{}) {
+			return InvalidParamsError
+		}
+	}
+	return nil
+}
+
+func ValidateCancelSwapRequest(req CancelSwapRequest) error {
+	if (req.SwapId == types.Destination{}) {
+		return InvalidParamsError
+	}
+	return nil
+}
+
 func ValidateGetPaymentChannelRequest(req GetPaymentChannelRequest) error {
 	if (req.Id == types.Destination{}) {
 		return InvalidParamsError
@@ -48,3 +78,43 @@ func ValidateGetSignedStateRequest(req GetSignedStateRequest) error {
 	}
 	return nil
 }
+
+func ValidateRegisterWatchedChannelRequest(req RegisterWatchedChannelRequest) error {
+	if len(req.SignedState.State().Participants) == 0 {
+		return InvalidParamsError
+	}
+	return nil
+}
+
+func ValidateGetWatchedChannelRequest(req GetWatchedChannelRequest) error {
+	if (req.Id == types.Destination{}) {
+		return InvalidParamsError
+	}
+	return nil
+}
+
+func ValidateGetObjectiveInfoRequest(req GetObjectiveInfoRequest) error {
+	if req.ObjectiveId == "" {
+		return InvalidParamsError
+	}
+	return nil
+}
+
+func ValidateListObjectivesRequest(req ListObjectivesRequest) error {
+	switch req.Status {
+	case "", query.ObjectiveUnapproved, query.ObjectiveApproved, query.ObjectiveRejected, query.ObjectiveCompleted, query.ObjectiveFailed:
+		return nil
+	default:
+		return InvalidParamsError
+	}
+}
+
+func ValidateListSwapsRequest(req ListSwapsRequest) error {
+	if req.Limit > MaxListSwapsLimit {
+		return InvalidParamsError
+	}
+	if !req.Filter.From.IsZero() && !req.Filter.To.IsZero() && req.Filter.To.Before(req.Filter.From) {
+		return InvalidParamsError
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package rpc
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/statechannels/go-nitro/internal/logging"
+	"github.com/statechannels/go-nitro/rpc/serde"
+	"github.com/statechannels/go-nitro/rpc/transport"
+	"github.com/statechannels/go-nitro/types"
+	"github.com/statechannels/go-nitro/watchtower"
+)
+
+type WatchtowerRpcServer struct {
+	*BaseRpcServer
+	watchtower *watchtower.Watchtower
+}
+
+func NewWatchtowerRpcServer(watchtower *watchtower.Watchtower, trans transport.Responder) (*WatchtowerRpcServer, error) {
+	baseRpcServer := NewBaseRpcServer(trans)
+
+	wrs := &WatchtowerRpcServer{
+		baseRpcServer,
+		watchtower,
+	}
+
+	wrs.logger = logging.LoggerWithAddress(slog.Default(), watchtower.GetAddress())
+
+	err := wrs.registerHandlers()
+	if err != nil {
+		return nil, err
+	}
+
+	return wrs, nil
+}
+
+func (wrs *WatchtowerRpcServer) Close() error {
+	err := wrs.BaseRpcServer.Close()
+	if err != nil {
+		return err
+	}
+
+	return wrs.watchtower.Close()
+}
+
+func (wrs *WatchtowerRpcServer) registerHandlers() (err error) {
+	handlerV1 := func(requestData []byte) []byte {
+		if !json.Valid(requestData) {
+			wrs.logger.Error("request is not valid json")
+			errRes := serde.NewJsonRpcErrorResponse(0, serde.ParseError)
+			return marshalResponse(errRes)
+		}
+
+		jsonrpcReq, errRes := validateJsonrpcRequest(requestData)
+		wrs.logger.Debug("Rpc server received request", "request", jsonrpcReq)
+		if errRes != nil {
+			wrs.logger.Error("could not validate jsonrpc request")
+
+			return errRes
+		}
+
+		switch serde.RequestMethod(jsonrpcReq.Method) {
+		case serde.GetAuthTokenMethod:
+			return processRequest(wrs.BaseRpcServer, permNone, requestData, func(req serde.AuthRequest) (string, error) {
+				return generateAuthToken(req.Id, allPermissions)
+			})
+		case serde.RegisterWatchedChannelMethod:
+			return processRequest(wrs.BaseRpcServer, permSign, requestData, func(req serde.RegisterWatchedChannelRequest) (types.Destination, error) {
+				if err := serde.ValidateRegisterWatchedChannelRequest(req); err != nil {
+					return types.Destination{}, err
+				}
+
+				return wrs.watchtower.RegisterChannel(req.SignedState, req.Proof, req.ChallengerSig)
+			})
+		case serde.GetWatchedChannelMethod:
+			return processRequest(wrs.BaseRpcServer, permRead, requestData, func(req serde.GetWatchedChannelRequest) (watchtower.WatchedChannel, error) {
+				if err := serde.ValidateGetWatchedChannelRequest(req); err != nil {
+					return watchtower.WatchedChannel{}, err
+				}
+
+				return wrs.watchtower.GetWatchedChannel(req.Id)
+			})
+		case serde.GetAllWatchedChannelsMethod:
+			return processRequest(wrs.BaseRpcServer, permRead, requestData, func(req serde.NoPayloadRequest) (serde.GetAllWatchedChannelsResponse, error) {
+				return wrs.watchtower.GetWatchedChannels()
+			})
+		case serde.GetAddressMethod:
+			return processRequest(wrs.BaseRpcServer, permNone, requestData, func(req serde.NoPayloadRequest) (string, error) {
+				return wrs.watchtower.GetAddress().Hex(), nil
+			})
+		default:
+			errRes := serde.NewJsonRpcErrorResponse(jsonrpcReq.Id, serde.MethodNotFoundError)
+			return marshalResponse(errRes)
+		}
+	}
+
+	err = wrs.transport.RegisterRequestHandler("v1", handlerV1)
+	return err
+}
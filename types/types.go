@@ -69,4 +69,5 @@ const (
 	Ledger ChannelType = iota
 	Virtual
 	Swap
+	Rebalance
 )
@@ -0,0 +1,157 @@
+package watchtower
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/statechannels/go-nitro/types"
+	"github.com/tidwall/buntdb"
+)
+
+const (
+	WATCHTOWER_DURABLE_STORE_SUB_DIR = "watchtower"
+
+	lastBlockNumSeenKey = "lastBlockNumSeen"
+)
+
+type DurableStore struct {
+	watchedChannels  *buntdb.DB
+	lastBlockNumSeen *buntdb.DB
+	folder           string // the folder where the store's data is stored
+}
+
+func NewDurableStore(folder string, config buntdb.Config) (*DurableStore, error) {
+	dataFolder := filepath.Join(folder, WATCHTOWER_DURABLE_STORE_SUB_DIR)
+	ds := DurableStore{}
+
+	err := os.MkdirAll(dataFolder, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.folder = dataFolder
+
+	ds.watchedChannels, err = ds.openDB("watched_channels", config)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.lastBlockNumSeen, err = ds.openDB("last_block_num_seen", config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ds, nil
+}
+
+func (ds *DurableStore) openDB(name string, config buntdb.Config) (*buntdb.DB, error) {
+	db, err := buntdb.Open(fmt.Sprintf("%s/%s.db", ds.folder, name))
+	if err != nil {
+		return nil, err
+	}
+	err = db.SetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (ds *DurableStore) SetWatchedChannel(wc WatchedChannel) error {
+	wcJson, err := json.Marshal(wc)
+	if err != nil {
+		return err
+	}
+
+	return ds.watchedChannels.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(wc.ChannelId().String(), string(wcJson), nil)
+		return err
+	})
+}
+
+func (ds *DurableStore) GetWatchedChannel(channelId types.Destination) (wc WatchedChannel, err error) {
+	var wcJson string
+
+	err = ds.watchedChannels.View(func(tx *buntdb.Tx) error {
+		var err error
+		wcJson, err = tx.Get(channelId.String())
+		return err
+	})
+	if err != nil {
+		return wc, err
+	}
+
+	err = json.Unmarshal([]byte(wcJson), &wc)
+	return wc, err
+}
+
+func (ds *DurableStore) GetWatchedChannels() ([]WatchedChannel, error) {
+	watchedChannels := []WatchedChannel{}
+	var unmarshErr error
+
+	err := ds.watchedChannels.View(func(tx *buntdb.Tx) error {
+		return tx.Ascend("", func(key, wcJson string) bool {
+			var wc WatchedChannel
+			unmarshErr = json.Unmarshal([]byte(wcJson), &wc)
+			if unmarshErr != nil {
+				return false
+			}
+
+			watchedChannels = append(watchedChannels, wc)
+			return true
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return watchedChannels, unmarshErr
+}
+
+func (ds *DurableStore) DestroyWatchedChannel(channelId types.Destination) error {
+	return ds.watchedChannels.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(channelId.String())
+		if errors.Is(err, buntdb.ErrNotFound) {
+			return nil
+		}
+		return err
+	})
+}
+
+// GetLastBlockNumSeen retrieves the last blockchain block processed by the watchtower
+func (ds *DurableStore) GetLastBlockNumSeen() (uint64, error) {
+	var result uint64
+	err := ds.lastBlockNumSeen.View(func(tx *buntdb.Tx) error {
+		val, err := tx.Get(lastBlockNumSeenKey)
+		if err != nil {
+			if errors.Is(err, buntdb.ErrNotFound) {
+				result = 0
+				return nil
+			}
+			return err
+		}
+		result, err = strconv.ParseUint(val, 10, 64)
+		return err
+	})
+	return result, err
+}
+
+// SetLastBlockNumSeen sets the last blockchain block processed by the watchtower
+func (ds *DurableStore) SetLastBlockNumSeen(blockNumber uint64) error {
+	return ds.lastBlockNumSeen.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(lastBlockNumSeenKey, strconv.FormatUint(blockNumber, 10), nil)
+		return err
+	})
+}
+
+func (ds *DurableStore) Close() error {
+	err := ds.watchedChannels.Close()
+	if err != nil {
+		return err
+	}
+
+	return ds.lastBlockNumSeen.Close()
+}
@@ -0,0 +1,99 @@
+package watchtower_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/go-cmp/cmp"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/internal/testhelpers"
+	"github.com/statechannels/go-nitro/watchtower"
+	"github.com/tidwall/buntdb"
+)
+
+func TestSetGetWatchedChannels(t *testing.T) {
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	durableStore, err := watchtower.NewDurableStore(dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wc := watchtower.WatchedChannel{
+		SignedState:          signedByBoth(t, testState(common.Address{}, 1, 5)),
+		Proof:                []state.SignedState{},
+		ChallengeTurnNum:     3,
+		ChallengeFinalizesAt: 100,
+	}
+
+	err = durableStore.SetWatchedChannel(wc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := durableStore.GetWatchedChannel(wc.ChannelId())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wc, got, cmp.AllowUnexported(state.SignedState{}, big.Int{})); diff != "" {
+		t.Fatalf("fetched result different than expected %s", diff)
+	}
+
+	all, err := durableStore.GetWatchedChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]watchtower.WatchedChannel{wc}, all, cmp.AllowUnexported(state.SignedState{}, big.Int{})); diff != "" {
+		t.Fatalf("fetched result different than expected %s", diff)
+	}
+
+	err = durableStore.DestroyWatchedChannel(wc.ChannelId())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = durableStore.GetWatchedChannel(wc.ChannelId())
+	if !errors.Is(err, buntdb.ErrNotFound) {
+		t.Fatalf("expected the destroyed channel not to be found, got %v", err)
+	}
+}
+
+func TestSetGetLastBlockNumSeen(t *testing.T) {
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	durableStore, err := watchtower.NewDurableStore(dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blockNum, err := durableStore.GetLastBlockNumSeen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blockNum != 0 {
+		t.Fatalf("expected no block to have been seen, got %d", blockNum)
+	}
+
+	err = durableStore.SetLastBlockNumSeen(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = durableStore.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The block number survives a restart
+	durableStore, err = watchtower.NewDurableStore(dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockNum, err = durableStore.GetLastBlockNumSeen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blockNum != 42 {
+		t.Fatalf("expected block 42 to have been seen, got %d", blockNum)
+	}
+}
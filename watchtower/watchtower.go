@@ -0,0 +1,314 @@
+// Package watchtower defends channels on chain on behalf of nodes which may be offline,
+// by answering challenges registered with a stale state with the latest state handed over by the node.
+package watchtower // import "github.com/statechannels/go-nitro/watchtower"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/internal/logging"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+	"github.com/tidwall/buntdb"
+)
+
+var (
+	ErrStaleState         = types.ConstError("watchtower: state is older than the watched state")
+	ErrUnsignedState      = types.ConstError("watchtower: state is not signed by any participant")
+	ErrUnsupportedState   = types.ConstError("watchtower: state is not supported")
+	ErrChallengeFinalized = types.ConstError("watchtower: challenge has already finalized")
+)
+
+// WatchedChannel is a channel defended by the watchtower, along with the latest supported state handed over by the node
+type WatchedChannel struct {
+	// SignedState is the latest supported state of the channel
+	SignedState state.SignedState
+	// Proof is submitted along with SignedState to support it, e.g. the postfund state of a virtual channel whose latest state carries a voucher
+	Proof []state.SignedState
+	// ChallengerSig, if set, makes the watchtower answer a stale challenge with a challenge of its own rather than a checkpoint
+	ChallengerSig state.Signature
+	// ChallengeTurnNum is the turn number of the state in the challenge registered on chain
+	ChallengeTurnNum uint64
+	// ChallengeFinalizesAt is the time at which the challenge registered on chain finalizes, it is zero if there is no ongoing challenge
+	ChallengeFinalizesAt uint64
+}
+
+func (wc WatchedChannel) ChannelId() types.Destination {
+	return wc.SignedState.ChannelId()
+}
+
+// hasStaleChallenge returns true if a challenge is ongoing on chain with a state older than the watched state
+func (wc WatchedChannel) hasStaleChallenge() bool {
+	return wc.ChallengeFinalizesAt != 0 && wc.ChallengeTurnNum < wc.SignedState.State().TurnNum
+}
+
+type Watchtower struct {
+	address      common.Address
+	store        *DurableStore
+	chainService chainservice.ChainService
+	logger       *slog.Logger
+
+	// mu serializes the updates of watched channels by chain events and API calls
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+// New creates a watchtower which defends the channels in the store by submitting transactions with the chain service.
+// The address is the one of the account used by the chain service.
+func New(address common.Address, chainService chainservice.ChainService, store *DurableStore) *Watchtower {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := Watchtower{
+		address:      address,
+		store:        store,
+		chainService: chainService,
+		logger:       logging.LoggerWithAddress(slog.Default(), address),
+		cancel:       cancel,
+		wg:           &sync.WaitGroup{},
+	}
+
+	w.wg.Add(1)
+	go w.run(ctx)
+
+	return &w
+}
+
+func (w *Watchtower) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	for {
+		var err error
+		select {
+		case event, ok := <-w.chainService.EventEngineFeed():
+			if !ok {
+				return
+			}
+			err = w.handleChainEvent(event)
+
+		case droppedEvent, ok := <-w.chainService.DroppedEventEngineFeed():
+			if !ok {
+				return
+			}
+			err = w.handleDroppedEvent(droppedEvent)
+
+		case <-ctx.Done():
+			return
+		}
+
+		if err != nil {
+			w.logger.Error("error in run loop", "error", err)
+		}
+	}
+}
+
+// handleChainEvent keeps track of the challenges registered against watched channels and answers the stale ones
+func (w *Watchtower) handleChainEvent(event chainservice.Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.store.SetLastBlockNumSeen(event.Block().BlockNum)
+	if err != nil {
+		return err
+	}
+
+	wc, err := w.store.GetWatchedChannel(event.ChannelID())
+	if errors.Is(err, buntdb.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch e := event.(type) {
+	case chainservice.ChallengeRegisteredEvent:
+		candidate, err := e.SignedState(wc.SignedState.State().FixedPart())
+		if err != nil {
+			return err
+		}
+
+		wc.ChallengeTurnNum = candidate.State().TurnNum
+		wc.ChallengeFinalizesAt = e.FinalizesAt.Uint64()
+		w.logger.Info("Challenge registered against watched channel", "channelId", wc.ChannelId(), "challengeTurnNum", wc.ChallengeTurnNum, "watchedTurnNum", wc.SignedState.State().TurnNum)
+
+	case chainservice.ChallengeClearedEvent:
+		wc.ChallengeFinalizesAt = 0
+
+	case chainservice.ConcludedEvent:
+		w.logger.Info("Watched channel concluded", "channelId", wc.ChannelId())
+		return w.store.DestroyWatchedChannel(wc.ChannelId())
+
+	default:
+		return nil
+	}
+
+	err = w.store.SetWatchedChannel(wc)
+	if err != nil {
+		return err
+	}
+
+	if wc.hasStaleChallenge() {
+		return w.defend(wc)
+	}
+
+	return nil
+}
+
+// handleDroppedEvent resubmits the defence of a channel if the transaction answering its challenge was dropped from the chain
+func (w *Watchtower) handleDroppedEvent(droppedEvent protocols.DroppedEventInfo) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wc, err := w.store.GetWatchedChannel(droppedEvent.ChannelId)
+	if errors.Is(err, buntdb.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if wc.hasStaleChallenge() {
+		w.logger.Warn("Defence of channel dropped from chain, resubmitting", "channelId", wc.ChannelId(), "txHash", droppedEvent.TxHash)
+		return w.defend(wc)
+	}
+
+	return nil
+}
+
+// defend answers the stale challenge registered against the channel with the watched state,
+// either with a checkpoint or with a challenge of its own if a challenger signature was handed over
+func (w *Watchtower) defend(wc WatchedChannel) error {
+	lastConfirmedBlock, err := w.chainService.GetBlockByNumber(new(big.Int).SetUint64(w.chainService.GetLastConfirmedBlockNum()))
+	if err != nil {
+		return err
+	}
+	if lastConfirmedBlock.Time() >= wc.ChallengeFinalizesAt {
+		return fmt.Errorf("could not defend channel %s: %w", wc.ChannelId(), ErrChallengeFinalized)
+	}
+
+	var tx protocols.ChainTransaction
+	if wc.ChallengerSig.IsEmpty() {
+		tx = protocols.NewCheckpointTransaction(wc.ChannelId(), wc.SignedState, wc.Proof)
+	} else {
+		tx = protocols.NewChallengeTransaction(wc.ChannelId(), wc.SignedState, wc.Proof, wc.ChallengerSig)
+	}
+
+	w.logger.Info("Defending channel against stale challenge", "channelId", wc.ChannelId(), "challengeTurnNum", wc.ChallengeTurnNum, "watchedTurnNum", wc.SignedState.State().TurnNum, "tx", fmt.Sprintf("%T", tx))
+	_, err = w.chainService.SendTransaction(tx)
+	return err
+}
+
+// RegisterChannel starts watching the channel of the signed state, or updates the state of a channel which is already watched.
+// The state must be supported, i.e. accepted by the adjudicator along with the proof, so that it can be used to defend the channel.
+// The proof and the challenger signature are optional, see WatchedChannel.
+func (w *Watchtower) RegisterChannel(signedState state.SignedState, proof []state.SignedState, challengerSig state.Signature) (types.Destination, error) {
+	channelId := signedState.ChannelId()
+	err := validateSignatures(signedState)
+	if err != nil {
+		return channelId, err
+	}
+	err = w.validateSupport(signedState, proof)
+	if err != nil {
+		return channelId, fmt.Errorf("could not watch channel %s at turn %d: %w", channelId, signedState.State().TurnNum, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wc, err := w.store.GetWatchedChannel(channelId)
+	isNewChannel := errors.Is(err, buntdb.ErrNotFound)
+	if err != nil && !isNewChannel {
+		return channelId, err
+	}
+	if !isNewChannel && signedState.State().TurnNum < wc.SignedState.State().TurnNum {
+		return channelId, fmt.Errorf("could not update channel %s to turn %d: %w", channelId, signedState.State().TurnNum, ErrStaleState)
+	}
+
+	// The challenges registered before the channel was handed over have not been tracked, so they are read from the adjudicator
+	if isNewChannel {
+		wc.ChallengeTurnNum, wc.ChallengeFinalizesAt, err = w.chainService.GetChallengeStatus(channelId)
+		if err != nil {
+			return channelId, err
+		}
+	}
+
+	wc.SignedState = signedState
+	wc.Proof = proof
+	wc.ChallengerSig = challengerSig
+	err = w.store.SetWatchedChannel(wc)
+	if err != nil {
+		return channelId, err
+	}
+	w.logger.Debug("Watching channel", "channelId", channelId, "turnNum", signedState.State().TurnNum)
+
+	// A challenge may already have been registered with an older state
+	if wc.hasStaleChallenge() {
+		return channelId, w.defend(wc)
+	}
+
+	return channelId, nil
+}
+
+// validateSupport checks that the adjudicator accepts the signed state along with the proof
+func (w *Watchtower) validateSupport(signedState state.SignedState, proof []state.SignedState) error {
+	supported, reason, err := w.chainService.StateIsSupported(signedState, proof)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return fmt.Errorf("%w: %s", ErrUnsupportedState, reason)
+	}
+	return nil
+}
+
+// validateSignatures checks that the signed state has at least one signature, and that every signature is from a participant
+func validateSignatures(signedState state.SignedState) error {
+	verified := state.NewSignedState(signedState.State())
+	signed := false
+	for _, sig := range signedState.Signatures() {
+		if sig.IsEmpty() {
+			continue
+		}
+
+		err := verified.AddSignature(sig)
+		if err != nil {
+			return err
+		}
+		signed = true
+	}
+
+	if !signed {
+		return ErrUnsignedState
+	}
+	return nil
+}
+
+func (w *Watchtower) GetWatchedChannel(channelId types.Destination) (WatchedChannel, error) {
+	return w.store.GetWatchedChannel(channelId)
+}
+
+func (w *Watchtower) GetWatchedChannels() ([]WatchedChannel, error) {
+	return w.store.GetWatchedChannels()
+}
+
+func (w *Watchtower) GetAddress() common.Address {
+	return w.address
+}
+
+func (w *Watchtower) Close() error {
+	w.cancel()
+	w.wg.Wait()
+
+	err := w.chainService.Close()
+	if err != nil {
+		return err
+	}
+
+	return w.store.Close()
+}
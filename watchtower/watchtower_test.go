@@ -0,0 +1,243 @@
+package watchtower_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/statechannels/go-nitro/internal/testactors"
+	"github.com/statechannels/go-nitro/internal/testhelpers"
+	"github.com/statechannels/go-nitro/node/engine/chainservice"
+	NitroAdjudicator "github.com/statechannels/go-nitro/node/engine/chainservice/adjudicator"
+	"github.com/statechannels/go-nitro/protocols"
+	"github.com/statechannels/go-nitro/types"
+	"github.com/statechannels/go-nitro/watchtower"
+	"github.com/tidwall/buntdb"
+)
+
+var (
+	alice = testactors.Alice
+	bob   = testactors.Bob
+)
+
+// testState returns a ledger channel state between Alice and Bob
+func testState(appDefinition common.Address, nonce uint64, turnNum uint64) state.State {
+	return state.State{
+		Participants:      []types.Address{alice.Address(), bob.Address()},
+		ChannelNonce:      nonce,
+		AppDefinition:     appDefinition,
+		ChallengeDuration: 1000, // much longer than the duration of the test
+		AppData:           []byte{},
+		Outcome: outcome.Exit{outcome.SingleAssetExit{
+			Asset: types.Address{},
+			Allocations: outcome.Allocations{
+				{Destination: alice.Destination(), Amount: big.NewInt(int64(10 - turnNum))},
+				{Destination: bob.Destination(), Amount: big.NewInt(int64(turnNum))},
+			},
+		}},
+		TurnNum: turnNum,
+	}
+}
+
+// signedByBoth returns the state signed by Alice and Bob
+func signedByBoth(t *testing.T, s state.State) state.SignedState {
+	ss := state.NewSignedState(s)
+	for _, actor := range []testactors.Actor{alice, bob} {
+		sig, err := s.Sign(actor.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ss.AddSignature(sig); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ss
+}
+
+// waitForEvent waits for the next event of type T emitted for the channel
+func waitForEvent[T chainservice.Event](t *testing.T, feed <-chan chainservice.Event, channelId types.Destination) T {
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case event := <-feed:
+			if e, ok := event.(T); ok && e.ChannelID() == channelId {
+				return e
+			}
+		case <-timeout:
+			var e T
+			t.Fatalf("timed out waiting for a %T event in channel %s", e, channelId)
+			return e
+		}
+	}
+}
+
+// waitForWatchedChannel waits until the channel watched by the watchtower satisfies the condition
+func waitForWatchedChannel(t *testing.T, w *watchtower.Watchtower, channelId types.Destination, condition func(watchtower.WatchedChannel) bool) {
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		wc, err := w.GetWatchedChannel(channelId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if condition(wc) {
+			return
+		}
+	}
+	t.Fatalf("watched channel %s did not reach the expected state", channelId)
+}
+
+func TestWatchtower(t *testing.T) {
+	sim, bindings, ethAccounts, err := chainservice.SetupSimulatedBackend(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+
+	// Alice goes on chain with stale states, while the watchtower defends the channels on behalf of Bob
+	aliceChain, err := chainservice.NewSimulatedBackendChainService(sim, bindings, ethAccounts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aliceChain.Close()
+
+	watchtowerChain, err := chainservice.NewSimulatedBackendChainService(sim, bindings, ethAccounts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataFolder, cleanup := testhelpers.GenerateTempStoreFolder()
+	defer cleanup()
+	store, err := watchtower.NewDurableStore(dataFolder, buntdb.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := watchtower.New(ethAccounts[1].From, watchtowerChain, store)
+	defer w.Close()
+
+	challenge := func(stale state.State) {
+		challengerSig, err := NitroAdjudicator.SignChallengeMessage(stale, alice.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = aliceChain.SendTransaction(protocols.NewChallengeTransaction(stale.ChannelId(), signedByBoth(t, stale), []state.SignedState{}, challengerSig))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	challengeCleared := func(wc watchtower.WatchedChannel) bool {
+		return wc.ChallengeTurnNum == 1 && wc.ChallengeFinalizesAt == 0
+	}
+
+	t.Run("a stale challenge is cleared by a checkpoint", func(t *testing.T) {
+		latest := signedByBoth(t, testState(bindings.ConsensusApp.Address, 1, 5))
+		channelId, err := w.RegisterChannel(latest, nil, state.Signature{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The watchtower refuses to go back to an older state
+		if _, err := w.RegisterChannel(signedByBoth(t, testState(bindings.ConsensusApp.Address, 1, 1)), nil, state.Signature{}); !errors.Is(err, watchtower.ErrStaleState) {
+			t.Fatalf("expected the registration of an older state to fail, got %v", err)
+		}
+
+		challenge(testState(bindings.ConsensusApp.Address, 1, 1))
+		waitForEvent[chainservice.ChallengeClearedEvent](t, aliceChain.EventEngineFeed(), channelId)
+		waitForWatchedChannel(t, w, channelId, challengeCleared)
+	})
+
+	t.Run("a stale challenge is countered by a challenge with the challenger signature", func(t *testing.T) {
+		latest := testState(bindings.ConsensusApp.Address, 2, 5)
+		challengerSig, err := NitroAdjudicator.SignChallengeMessage(latest, bob.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		channelId, err := w.RegisterChannel(signedByBoth(t, latest), nil, challengerSig)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		challenge(testState(bindings.ConsensusApp.Address, 2, 1))
+		waitForEvent[chainservice.ChallengeRegisteredEvent](t, aliceChain.EventEngineFeed(), channelId)
+		counter := waitForEvent[chainservice.ChallengeRegisteredEvent](t, aliceChain.EventEngineFeed(), channelId)
+		counterState, err := counter.SignedState(latest.FixedPart())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if counterState.State().TurnNum != latest.TurnNum {
+			t.Fatalf("expected the challenge to be countered with turn %d, got %d", latest.TurnNum, counterState.State().TurnNum)
+		}
+		waitForWatchedChannel(t, w, channelId, func(wc watchtower.WatchedChannel) bool {
+			return wc.ChallengeTurnNum == latest.TurnNum && wc.ChallengeFinalizesAt != 0
+		})
+	})
+
+	t.Run("a challenge registered before the latest state was handed over is cleared", func(t *testing.T) {
+		channelId, err := w.RegisterChannel(signedByBoth(t, testState(bindings.ConsensusApp.Address, 3, 1)), nil, state.Signature{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		challenge(testState(bindings.ConsensusApp.Address, 3, 1))
+		waitForWatchedChannel(t, w, channelId, func(wc watchtower.WatchedChannel) bool {
+			return wc.ChallengeTurnNum == 1 && wc.ChallengeFinalizesAt != 0
+		})
+
+		_, err = w.RegisterChannel(signedByBoth(t, testState(bindings.ConsensusApp.Address, 3, 5)), nil, state.Signature{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		waitForEvent[chainservice.ChallengeClearedEvent](t, aliceChain.EventEngineFeed(), channelId)
+		waitForWatchedChannel(t, w, channelId, challengeCleared)
+	})
+
+	t.Run("a challenge registered before the channel was handed over is cleared", func(t *testing.T) {
+		stale := testState(bindings.ConsensusApp.Address, 5, 1)
+		challenge(stale)
+		waitForEvent[chainservice.ChallengeRegisteredEvent](t, aliceChain.EventEngineFeed(), stale.ChannelId())
+
+		channelId, err := w.RegisterChannel(signedByBoth(t, testState(bindings.ConsensusApp.Address, 5, 5)), nil, state.Signature{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		waitForEvent[chainservice.ChallengeClearedEvent](t, aliceChain.EventEngineFeed(), channelId)
+		waitForWatchedChannel(t, w, channelId, challengeCleared)
+	})
+
+	t.Run("a state which is not supported is refused", func(t *testing.T) {
+		latest := signedByBoth(t, testState(bindings.ConsensusApp.Address, 6, 1))
+		channelId, err := w.RegisterChannel(latest, nil, state.Signature{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// A later state signed by Bob only is not supported by the ConsensusApp, so it does not replace the latest supported state
+		signedByBob := state.NewSignedState(testState(bindings.ConsensusApp.Address, 6, 5))
+		sig, err := signedByBob.State().Sign(bob.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := signedByBob.AddSignature(sig); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.RegisterChannel(signedByBob, nil, state.Signature{}); !errors.Is(err, watchtower.ErrUnsupportedState) {
+			t.Fatalf("expected a state signed by Bob only to be refused, got %v", err)
+		}
+
+		wc, err := w.GetWatchedChannel(channelId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wc.SignedState.State().TurnNum != latest.State().TurnNum {
+			t.Fatalf("expected the watched state to remain at turn %d, got %d", latest.State().TurnNum, wc.SignedState.State().TurnNum)
+		}
+	})
+
+	t.Run("an unsigned state is refused", func(t *testing.T) {
+		unsigned := state.NewSignedState(testState(bindings.ConsensusApp.Address, 4, 5))
+		if _, err := w.RegisterChannel(unsigned, nil, state.Signature{}); !errors.Is(err, watchtower.ErrUnsignedState) {
+			t.Fatalf("expected an unsigned state to be refused, got %v", err)
+		}
+	})
+}